@@ -0,0 +1,173 @@
+package anim
+
+import (
+	"time"
+)
+
+// Direction 角色朝向
+type Direction int
+
+const (
+	DirDown Direction = iota
+	DirUp
+	DirLeft
+	DirRight
+)
+
+// String 返回朝向名称，用于拼接动画名
+func (d Direction) String() string {
+	switch d {
+	case DirUp:
+		return "up"
+	case DirLeft:
+		return "left"
+	case DirRight:
+		return "right"
+	}
+	return "down"
+}
+
+// DirectionFromVector 根据移动方向计算朝向，斜向移动时以水平方向为准
+func DirectionFromVector(dx, dy float64) (Direction, bool) {
+	switch {
+	case dx < 0:
+		return DirLeft, true
+	case dx > 0:
+		return DirRight, true
+	case dy < 0:
+		return DirUp, true
+	case dy > 0:
+		return DirDown, true
+	}
+	return DirDown, false
+}
+
+// State 动画状态机的状态
+type State int
+
+const (
+	StateIdle   State = iota // 站立
+	StateWalk                // 行走
+	StateAttack              // 攻击（播放完后回到站立或行走）
+)
+
+// String 返回状态名称，用于拼接动画名
+func (s State) String() string {
+	switch s {
+	case StateWalk:
+		return "walk"
+	case StateAttack:
+		return "attack"
+	}
+	return "idle"
+}
+
+// Animator 由移动方向驱动的角色动画状态机
+// 动画按 "状态_朝向" 命名（如 walk_left），找不到时回退到只有状态名的动画（如 idle）
+type Animator struct {
+	sheet  *Sheet
+	player Player
+	state  State
+	facing Direction
+	moving bool          // 最近一次输入是否在移动
+	played time.Duration // 攻击动画已播放的时间，循环的攻击动画播放一遍后结束
+}
+
+// NewAnimator 创建动画状态机，初始为朝下站立
+func NewAnimator(sheet *Sheet) *Animator {
+	a := &Animator{sheet: sheet}
+	a.playState()
+	return a
+}
+
+// SetMovement 设置本帧的移动方向，静止时传入 (0, 0)
+func (a *Animator) SetMovement(dx, dy float64) {
+	dir, moving := DirectionFromVector(dx, dy)
+	a.moving = moving
+	if a.state == StateAttack {
+		return // 攻击动画不会被移动打断
+	}
+	if moving {
+		a.facing = dir
+		a.state = StateWalk
+	} else {
+		a.state = StateIdle
+	}
+	a.playState()
+}
+
+// Attack 开始攻击动画；正在攻击或精灵图中没有攻击动画时返回 false
+func (a *Animator) Attack() bool {
+	if a.state == StateAttack || a.stateClip(StateAttack, a.facing) == nil {
+		return false
+	}
+	a.state = StateAttack
+	a.played = 0
+	a.playState()
+	return true
+}
+
+// Update 推进动画，返回期间触发的帧事件
+func (a *Animator) Update(dt time.Duration) []string {
+	events := a.player.Update(dt)
+	if a.state != StateAttack {
+		return events
+	}
+	a.played += dt
+	if clip := a.player.Clip(); a.player.Finished() || (clip.Loop && a.played >= clip.Duration()) {
+		if a.moving {
+			a.state = StateWalk
+		} else {
+			a.state = StateIdle
+		}
+		a.playState()
+	}
+	return events
+}
+
+// playState 播放当前状态和朝向对应的动画
+func (a *Animator) playState() {
+	clip := a.clipFor(a.state, a.facing)
+	if clip == nil {
+		return
+	}
+	if a.state == StateAttack {
+		a.player.Restart(clip)
+		return
+	}
+	a.player.Play(clip)
+}
+
+// clipFor 查找状态和朝向对应的动画，没有时用站立动画
+func (a *Animator) clipFor(state State, dir Direction) *Clip {
+	if clip := a.stateClip(state, dir); clip != nil {
+		return clip
+	}
+	return a.stateClip(StateIdle, dir)
+}
+
+// stateClip 查找状态和朝向对应的动画，没有朝向专属的动画时用只有状态名的动画，都没有时返回 nil
+func (a *Animator) stateClip(state State, dir Direction) *Clip {
+	if clip, ok := a.sheet.Clip(state.String() + "_" + dir.String()); ok {
+		return clip
+	}
+	if clip, ok := a.sheet.Clip(state.String()); ok {
+		return clip
+	}
+	return nil
+}
+
+// Frame 返回当前应绘制的帧
+func (a *Animator) Frame() Frame {
+	return a.player.Frame()
+}
+
+// State 返回当前状态
+func (a *Animator) State() State {
+	return a.state
+}
+
+// Facing 返回当前朝向
+func (a *Animator) Facing() Direction {
+	return a.facing
+}
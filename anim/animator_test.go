@@ -0,0 +1,179 @@
+package anim
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+const frameTime = 100 * time.Millisecond
+
+// testSheet 每段动画两帧、每帧 frameTime；attack 在第 1 帧触发 hit
+func testSheet(attack bool, attackLoop bool) *Sheet {
+	sheet := &Sheet{Clips: map[string]*Clip{
+		"idle":       GridClip("idle", 16, 16, 0, 0, 2, frameTime, true),
+		"walk_left":  GridClip("walk_left", 16, 16, 1, 0, 2, frameTime, true),
+		"walk_right": GridClip("walk_right", 16, 16, 2, 0, 2, frameTime, true),
+	}}
+	if attack {
+		clip := GridClip("attack", 16, 16, 3, 0, 2, frameTime, attackLoop)
+		clip.AddEvent(1, "hit")
+		sheet.Clips["attack"] = clip
+	}
+	return sheet
+}
+
+// run 推进动画 n 帧，每帧 dt，返回所有触发的事件
+func run(a *Animator, n int, dt time.Duration) []string {
+	var events []string
+	for range n {
+		events = append(events, a.Update(dt)...)
+	}
+	return events
+}
+
+func TestAnimatorMovement(t *testing.T) {
+	a := NewAnimator(testSheet(true, false))
+	if a.State() != StateIdle || a.Facing() != DirDown {
+		t.Fatalf("初始状态 = %v %v", a.State(), a.Facing())
+	}
+	a.SetMovement(-1, 0)
+	if a.State() != StateWalk || a.Facing() != DirLeft || a.player.Clip().Name != "walk_left" {
+		t.Fatalf("向左走 = %v %v %s", a.State(), a.Facing(), a.player.Clip().Name)
+	}
+	// 没有 walk_up 时回退到 idle，朝向仍然更新
+	a.SetMovement(0, -1)
+	if a.State() != StateWalk || a.Facing() != DirUp || a.player.Clip().Name != "idle" {
+		t.Fatalf("向上走 = %v %v %s", a.State(), a.Facing(), a.player.Clip().Name)
+	}
+	a.SetMovement(0, 0)
+	if a.State() != StateIdle || a.Facing() != DirUp {
+		t.Fatalf("停下 = %v %v", a.State(), a.Facing())
+	}
+}
+
+func TestAnimatorAttack(t *testing.T) {
+	a := NewAnimator(testSheet(true, false))
+	a.SetMovement(1, 0)
+	if !a.Attack() || a.State() != StateAttack {
+		t.Fatal("应开始攻击")
+	}
+	if a.Attack() {
+		t.Fatal("攻击中不能再次攻击")
+	}
+	// 攻击中移动不打断动画，也不改变朝向
+	a.SetMovement(-1, 0)
+	if a.State() != StateAttack || a.Facing() != DirRight {
+		t.Fatalf("攻击被移动打断: %v %v", a.State(), a.Facing())
+	}
+	events := run(a, 3, frameTime)
+	if !slices.Equal(events, []string{"hit"}) {
+		t.Fatalf("事件 = %v，应只有一次 hit", events)
+	}
+	// 动画结束后回到行走，并采用攻击期间最后的移动方向
+	if a.State() != StateWalk {
+		t.Fatalf("攻击结束后状态 = %v", a.State())
+	}
+	a.SetMovement(-1, 0)
+	if a.Facing() != DirLeft {
+		t.Fatalf("攻击结束后朝向 = %v", a.Facing())
+	}
+	a.SetMovement(0, 0)
+	if !a.Attack() {
+		t.Fatal("攻击结束后应能再次攻击")
+	}
+	run(a, 3, frameTime)
+	if a.State() != StateIdle {
+		t.Fatalf("静止时攻击结束后状态 = %v", a.State())
+	}
+}
+
+// TestAnimatorNoAttackClip 精灵图没有攻击动画时不能攻击，也不会卡在攻击状态
+func TestAnimatorNoAttackClip(t *testing.T) {
+	a := NewAnimator(testSheet(false, false))
+	if a.Attack() {
+		t.Fatal("没有攻击动画时 Attack 应返回 false")
+	}
+	a.SetMovement(1, 0)
+	if a.State() != StateWalk || a.Facing() != DirRight {
+		t.Fatalf("状态 = %v %v", a.State(), a.Facing())
+	}
+}
+
+// TestAnimatorLoopingAttack 循环的攻击动画播放一遍后结束
+func TestAnimatorLoopingAttack(t *testing.T) {
+	a := NewAnimator(testSheet(true, true))
+	if !a.Attack() {
+		t.Fatal("应开始攻击")
+	}
+	events := run(a, 1, frameTime)
+	if a.State() != StateAttack || !slices.Equal(events, []string{"hit"}) {
+		t.Fatalf("播放一半: %v %v", a.State(), events)
+	}
+	run(a, 1, frameTime)
+	if a.State() != StateIdle {
+		t.Fatalf("播放一遍后状态 = %v", a.State())
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	clip := GridClip("walk", 16, 16, 0, 0, 3, frameTime, true)
+	clip.AddEvent(0, "footstep")
+	clip.AddEvent(2, "footstep")
+	var p Player
+	p.Play(clip)
+	// 第一帧的事件在第一次更新时触发，跨过多帧时每帧的事件都会触发
+	if events := p.Update(0); !slices.Equal(events, []string{"footstep"}) {
+		t.Fatalf("第一帧事件 = %v", events)
+	}
+	if events := p.Update(2 * frameTime); !slices.Equal(events, []string{"footstep"}) || p.Frame().Rect.Min.X != 32 {
+		t.Fatalf("跨两帧 = %v，当前帧 %v", events, p.Frame().Rect)
+	}
+	if events := p.Update(frameTime); !slices.Equal(events, []string{"footstep"}) || p.Frame().Rect.Min.X != 0 || p.Finished() {
+		t.Fatalf("循环回到第一帧 = %v", events)
+	}
+	// 同一段动画不会重新开始，Restart 会
+	p.Update(frameTime / 2)
+	p.Play(clip)
+	if p.elapsed != frameTime/2 {
+		t.Fatal("Play 同一段动画不应重新开始")
+	}
+	p.Restart(clip)
+	if p.elapsed != 0 || p.frame != 0 {
+		t.Fatal("Restart 应从头播放")
+	}
+
+	once := GridClip("attack", 16, 16, 0, 0, 2, frameTime, false)
+	p.Play(once)
+	p.Update(5 * frameTime)
+	if !p.Finished() || p.Frame().Rect.Min.X != 16 {
+		t.Fatalf("非循环动画应停在最后一帧，当前 %v", p.Frame().Rect)
+	}
+}
+
+func TestParseAseprite(t *testing.T) {
+	sheet, err := ParseAseprite([]byte(`{
+		"frames": {
+			"a": {"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "duration": 100},
+			"b": {"frame": {"x": 16, "y": 0, "w": 16, "h": 16}, "duration": 100},
+			"c": {"frame": {"x": 32, "y": 0, "w": 16, "h": 16}, "duration": 100}
+		},
+		"meta": {"image": "sheet.png", "frameTags": [
+			{"name": "walk_down", "from": 0, "to": 2, "direction": "pingpong", "data": "footstep@0"},
+			{"name": "attack", "from": 0, "to": 1, "direction": "reverse", "data": "hit@1"}
+		]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk, attack := sheet.Clips["walk_down"], sheet.Clips["attack"]
+	if !walk.Loop || len(walk.Frames) != 4 || walk.Frames[3].Rect.Min.X != 16 || walk.Frames[0].Events[0] != "footstep" {
+		t.Fatalf("walk_down = %+v", walk)
+	}
+	if attack.Loop || attack.Frames[0].Rect.Min.X != 16 || attack.Frames[1].Events[0] != "hit" {
+		t.Fatalf("attack = %+v", attack)
+	}
+	if _, err := ParseAseprite([]byte(`{"frames": [], "meta": {"frameTags": [{"name": "x", "from": 0, "to": 1}]}}`)); err == nil {
+		t.Fatal("帧范围越界应报错")
+	}
+}
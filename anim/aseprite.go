@@ -0,0 +1,163 @@
+package anim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// asepriteFrame Aseprite 导出 JSON 中的一帧
+type asepriteFrame struct {
+	Frame struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"frame"`
+	Duration int `json:"duration"` // 毫秒
+}
+
+// asepriteTag Aseprite 的帧标签，每个标签对应一段动画
+type asepriteTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"` // forward、reverse、pingpong
+	Data      string `json:"data"`      // 用户数据，约定格式 "event@帧序号,event@帧序号"
+}
+
+// asepriteFile Aseprite 导出的 JSON（支持 Array 和 Hash 两种帧格式）
+type asepriteFile struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string        `json:"image"`
+		FrameTags []asepriteTag `json:"frameTags"`
+	} `json:"meta"`
+}
+
+// LoadAseprite 读取 Aseprite 导出的 JSON，精灵图路径相对于 JSON 文件所在目录
+func LoadAseprite(path string) (*Sheet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := ParseAseprite(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if sheet.Image != "" {
+		sheet.Image = filepath.Join(filepath.Dir(path), sheet.Image)
+	}
+	return sheet, nil
+}
+
+// ParseAseprite 解析 Aseprite 导出的 JSON
+// 每个帧标签生成一段动画；名称以 idle 或 walk 开头的动画循环播放，其余只播放一次
+func ParseAseprite(data []byte) (*Sheet, error) {
+	var file asepriteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	frames, err := parseAsepriteFrames(file.Frames)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{Image: file.Meta.Image, Clips: map[string]*Clip{}}
+	for _, tag := range file.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("anim: 标签 %s 的帧范围 %d-%d 越界", tag.Name, tag.From, tag.To)
+		}
+		clip := &Clip{
+			Name: tag.Name,
+			Loop: strings.HasPrefix(tag.Name, "idle") || strings.HasPrefix(tag.Name, "walk"),
+		}
+		for _, i := range tagOrder(tag) {
+			clip.Frames = append(clip.Frames, frames[i])
+		}
+		if err := addTagEvents(clip, tag.Data); err != nil {
+			return nil, fmt.Errorf("anim: 标签 %s: %w", tag.Name, err)
+		}
+		sheet.Clips[clip.Name] = clip
+	}
+	return sheet, nil
+}
+
+// parseAsepriteFrames 解析帧列表；Hash 格式需要保持 JSON 中的键顺序
+func parseAsepriteFrames(raw json.RawMessage) ([]Frame, error) {
+	var list []asepriteFrame
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if _, err := dec.Token(); err != nil { // {
+			return nil, err
+		}
+		for dec.More() {
+			if _, err := dec.Token(); err != nil { // 帧名称
+				return nil, err
+			}
+			var f asepriteFrame
+			if err := dec.Decode(&f); err != nil {
+				return nil, err
+			}
+			list = append(list, f)
+		}
+	}
+
+	frames := make([]Frame, len(list))
+	for i, f := range list {
+		frames[i] = Frame{
+			Rect:     image.Rect(f.Frame.X, f.Frame.Y, f.Frame.X+f.Frame.W, f.Frame.Y+f.Frame.H),
+			Duration: time.Duration(f.Duration) * time.Millisecond,
+		}
+	}
+	return frames, nil
+}
+
+// tagOrder 按标签的播放方向展开帧序号
+func tagOrder(tag asepriteTag) []int {
+	var order []int
+	for i := tag.From; i <= tag.To; i++ {
+		order = append(order, i)
+	}
+	switch tag.Direction {
+	case "reverse":
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	case "pingpong":
+		for i := tag.To - 1; i > tag.From; i-- {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// addTagEvents 解析标签用户数据中的帧事件，如 "footstep@0,footstep@2"
+func addTagEvents(clip *Clip, data string) error {
+	if strings.TrimSpace(data) == "" {
+		return nil
+	}
+	for _, part := range strings.Split(data, ",") {
+		name, frame, ok := strings.Cut(strings.TrimSpace(part), "@")
+		if !ok {
+			return fmt.Errorf("事件 %q 缺少 @帧序号", part)
+		}
+		index, err := strconv.Atoi(frame)
+		if err != nil || index < 0 || index >= len(clip.Frames) {
+			return fmt.Errorf("事件 %q 的帧序号无效", part)
+		}
+		clip.AddEvent(index, name)
+	}
+	return nil
+}
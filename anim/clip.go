@@ -0,0 +1,143 @@
+package anim
+
+import (
+	"image"
+	"time"
+)
+
+// Frame 动画中的一帧
+type Frame struct {
+	Rect     image.Rectangle // 在精灵图中的区域
+	Duration time.Duration   // 持续时间
+	Events   []string        // 进入该帧时触发的事件（如 footstep、hit）
+}
+
+// Clip 一段动画，如 idle、walk_left、attack_up
+type Clip struct {
+	Name   string
+	Frames []Frame
+	Loop   bool // 播放完后是否从头循环
+}
+
+// Duration 返回整段动画的时长
+func (c *Clip) Duration() time.Duration {
+	var d time.Duration
+	for _, f := range c.Frames {
+		d += f.Duration
+	}
+	return d
+}
+
+// AddEvent 给第 frame 帧添加事件
+func (c *Clip) AddEvent(frame int, event string) {
+	if frame < 0 || frame >= len(c.Frames) {
+		return
+	}
+	c.Frames[frame].Events = append(c.Frames[frame].Events, event)
+}
+
+// GridClip 从按网格排列的精灵图中取一行连续的帧生成动画
+func GridClip(name string, frameW, frameH, row, col, count int, duration time.Duration, loop bool) *Clip {
+	c := &Clip{Name: name, Loop: loop}
+	for i := 0; i < count; i++ {
+		x := (col + i) * frameW
+		y := row * frameH
+		c.Frames = append(c.Frames, Frame{
+			Rect:     image.Rect(x, y, x+frameW, y+frameH),
+			Duration: duration,
+		})
+	}
+	return c
+}
+
+// Sheet 一张精灵图及其包含的所有动画
+type Sheet struct {
+	Image string           // 精灵图文件路径
+	Clips map[string]*Clip // 动画名称 -> 动画
+}
+
+// Clip 按名称查找动画
+func (s *Sheet) Clip(name string) (*Clip, bool) {
+	c, ok := s.Clips[name]
+	return c, ok
+}
+
+// Player 播放单段动画，记录当前帧和已播放时间
+type Player struct {
+	clip     *Clip
+	frame    int
+	elapsed  time.Duration // 当前帧已播放的时间
+	finished bool          // 非循环动画是否已播放完
+	entered  bool          // 是否已触发第一帧的事件
+}
+
+// Play 切换到指定动画；正在播放同一段动画时不会重新开始
+func (p *Player) Play(clip *Clip) {
+	if p.clip == clip {
+		return
+	}
+	p.Restart(clip)
+}
+
+// Restart 从头播放指定动画
+func (p *Player) Restart(clip *Clip) {
+	p.clip = clip
+	p.frame = 0
+	p.elapsed = 0
+	p.finished = false
+	p.entered = false
+}
+
+// Clip 返回当前动画
+func (p *Player) Clip() *Clip {
+	return p.clip
+}
+
+// Finished 非循环动画是否已播放到最后一帧结束
+func (p *Player) Finished() bool {
+	return p.finished
+}
+
+// Update 推进动画时间，返回期间进入的各帧触发的事件
+func (p *Player) Update(dt time.Duration) []string {
+	if p.clip == nil || len(p.clip.Frames) == 0 {
+		return nil
+	}
+	var events []string
+	if !p.entered {
+		p.entered = true
+		events = append(events, p.clip.Frames[0].Events...)
+	}
+	if p.finished {
+		return events
+	}
+
+	p.elapsed += dt
+	for {
+		cur := p.clip.Frames[p.frame]
+		if cur.Duration <= 0 || p.elapsed < cur.Duration {
+			break
+		}
+		p.elapsed -= cur.Duration
+		if p.frame == len(p.clip.Frames)-1 {
+			if !p.clip.Loop {
+				p.finished = true
+				p.elapsed = 0
+				break
+			}
+			p.frame = 0
+		} else {
+			p.frame++
+		}
+		events = append(events, p.clip.Frames[p.frame].Events...)
+	}
+	return events
+}
+
+// Frame 返回当前帧
+func (p *Player) Frame() Frame {
+	if p.clip == nil || len(p.clip.Frames) == 0 {
+		return Frame{}
+	}
+	return p.clip.Frames[p.frame]
+}
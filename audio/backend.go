@@ -0,0 +1,70 @@
+package audio
+
+// Voice 一路声音的播放控制
+type Voice interface {
+	Play()
+	Pause()
+	IsPlaying() bool
+	Rewind() error
+	SetVolume(volume float64)
+	SetPan(pan float64) // 声像：-1 为最左，0 为居中，1 为最右
+	Close() error
+}
+
+// Backend 音频输出设备
+type Backend interface {
+	// NewStream 创建流式播放的声音（用于背景音乐），loop 为真时无缝循环
+	NewStream(clip *Clip, loop bool) (Voice, error)
+	// NewSound 创建一次性播放的声音（用于音效），解码结果可以缓存复用
+	NewSound(clip *Clip) (Voice, error)
+}
+
+// NullBackend 不输出任何声音的设备，记录所有创建的声音，用于测试和无声卡环境
+type NullBackend struct {
+	Voices []*NullVoice // 按创建顺序记录的所有声音
+}
+
+// NullVoice NullBackend 创建的声音，只记录状态
+type NullVoice struct {
+	Clip    *Clip   // 对应的音频
+	Loop    bool    // 是否循环
+	Playing bool    // 是否正在播放
+	Volume  float64 // 最近一次设置的音量
+	Pan     float64 // 最近一次设置的声像
+	Rewinds int     // 被倒回开头的次数
+	Closed  bool    // 是否已关闭
+}
+
+// NewStream 实现 Backend
+func (b *NullBackend) NewStream(clip *Clip, loop bool) (Voice, error) {
+	v := &NullVoice{Clip: clip, Loop: loop, Volume: 1}
+	b.Voices = append(b.Voices, v)
+	return v, nil
+}
+
+// NewSound 实现 Backend
+func (b *NullBackend) NewSound(clip *Clip) (Voice, error) {
+	return b.NewStream(clip, false)
+}
+
+func (v *NullVoice) Play()                    { v.Playing = true }
+func (v *NullVoice) Pause()                   { v.Playing = false }
+func (v *NullVoice) IsPlaying() bool          { return v.Playing }
+func (v *NullVoice) SetVolume(volume float64) { v.Volume = volume }
+func (v *NullVoice) SetPan(pan float64)       { v.Pan = pan }
+
+func (v *NullVoice) Rewind() error {
+	v.Rewinds++
+	return nil
+}
+
+func (v *NullVoice) Close() error {
+	v.Playing = false
+	v.Closed = true
+	return nil
+}
+
+// Finish 模拟一次性声音播放结束
+func (v *NullVoice) Finish() {
+	v.Playing = false
+}
@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format 音频文件编码格式
+type Format int
+
+const (
+	FormatWAV Format = iota // WAV（PCM）
+	FormatOGG               // Ogg Vorbis
+	FormatMP3               // MP3
+)
+
+// String 返回格式名称
+func (f Format) String() string {
+	switch f {
+	case FormatWAV:
+		return "wav"
+	case FormatOGG:
+		return "ogg"
+	case FormatMP3:
+		return "mp3"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// FormatFromPath 根据文件扩展名判断音频格式
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		return FormatWAV, nil
+	case ".ogg":
+		return FormatOGG, nil
+	case ".mp3":
+		return FormatMP3, nil
+	}
+	return 0, fmt.Errorf("audio: 不支持的音频格式 %s", path)
+}
+
+// Clip 一段尚未解码的音频数据（音乐按需流式解码，音效首次播放时解码并缓存）
+type Clip struct {
+	Name   string // 音频名称，播放时用它引用
+	Format Format // 编码格式
+	Data   []byte // 原始文件内容
+}
+
+// LoadClip 从文件读取音频
+func LoadClip(name, path string) (*Clip, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Clip{Name: name, Format: format, Data: data}, nil
+}
@@ -0,0 +1,144 @@
+// Package ebitenaudio 用 ebiten 的音频上下文实现 audio.Backend，负责解码 WAV、Ogg、MP3 和左右声像；
+// 放在子包中，audio 包本身只剩引擎逻辑，用 audio.NullBackend 就能在没有声卡和 cgo 的环境中测试
+package ebitenaudio
+
+import (
+	"Game/audio"
+	"bytes"
+	"fmt"
+	ebaudio "github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// SampleRate 音频上下文的采样率
+const SampleRate = 44100
+
+// pcmStream 解码后的 16 位立体声 PCM 流
+type pcmStream interface {
+	io.ReadSeeker
+	Length() int64
+}
+
+// Backend 基于 ebiten 音频上下文的输出设备
+type Backend struct {
+	context *ebaudio.Context
+	decoded map[*audio.Clip][]byte // 已解码的音效 PCM 缓存
+}
+
+// NewBackend 创建 ebiten 音频设备（整个程序只能创建一次音频上下文）
+func NewBackend() *Backend {
+	return &Backend{
+		context: ebaudio.NewContext(SampleRate),
+		decoded: map[*audio.Clip][]byte{},
+	}
+}
+
+// decode 按格式把音频解码成目标采样率的 PCM 流
+func decode(clip *audio.Clip) (pcmStream, error) {
+	src := bytes.NewReader(clip.Data)
+	switch clip.Format {
+	case audio.FormatWAV:
+		return wav.DecodeWithSampleRate(SampleRate, src)
+	case audio.FormatOGG:
+		return vorbis.DecodeWithSampleRate(SampleRate, src)
+	case audio.FormatMP3:
+		return mp3.DecodeWithSampleRate(SampleRate, src)
+	}
+	return nil, fmt.Errorf("audio: %s 的格式 %v 无法解码", clip.Name, clip.Format)
+}
+
+// panner 按声像调整 16 位立体声 PCM 的左右声道
+// 播放器在音频线程中读取数据，所以声像用原子变量保存
+type panner struct {
+	src io.ReadSeeker
+	pan atomic.Uint64 // math.Float64bits(pan)
+}
+
+// Read 实现 io.Reader，每次只读取完整的采样帧（左右声道各 2 字节）
+func (p *panner) Read(b []byte) (int, error) {
+	n, err := io.ReadFull(p.src, b[:len(b)/4*4])
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	n = n / 4 * 4
+
+	pan := math.Float64frombits(p.pan.Load())
+	if pan == 0 {
+		return n, err
+	}
+	left, right := audio.PanGains(pan)
+	for i := 0; i < n; i += 4 {
+		scaleSample(b[i:i+2], left)
+		scaleSample(b[i+2:i+4], right)
+	}
+	return n, err
+}
+
+// Seek 实现 io.Seeker，供播放器倒回开头
+func (p *panner) Seek(offset int64, whence int) (int64, error) {
+	return p.src.Seek(offset, whence)
+}
+
+// scaleSample 把一个小端 16 位采样乘以增益
+func scaleSample(b []byte, gain float64) {
+	v := int16(uint16(b[0]) | uint16(b[1])<<8)
+	v = int16(float64(v) * gain)
+	b[0] = byte(v)
+	b[1] = byte(uint16(v) >> 8)
+}
+
+// ebitenVoice 带声像控制的 ebiten 播放器
+type ebitenVoice struct {
+	*ebaudio.Player
+	panner *panner
+}
+
+// SetPan 实现 audio.Voice
+func (v *ebitenVoice) SetPan(pan float64) {
+	v.panner.pan.Store(math.Float64bits(pan))
+}
+
+// newVoice 用声像处理包装 PCM 流并创建播放器
+func (b *Backend) newVoice(src io.ReadSeeker) (audio.Voice, error) {
+	p := &panner{src: src}
+	player, err := b.context.NewPlayer(p)
+	if err != nil {
+		return nil, err
+	}
+	return &ebitenVoice{Player: player, panner: p}, nil
+}
+
+// NewStream 实现 audio.Backend：边播放边解码，循环时使用 InfiniteLoop 无缝衔接
+func (b *Backend) NewStream(clip *audio.Clip, loop bool) (audio.Voice, error) {
+	stream, err := decode(clip)
+	if err != nil {
+		return nil, err
+	}
+	var src io.ReadSeeker = stream
+	if loop {
+		src = ebaudio.NewInfiniteLoop(stream, stream.Length())
+	}
+	return b.newVoice(src)
+}
+
+// NewSound 实现 audio.Backend：首次播放时整段解码，之后复用解码结果
+func (b *Backend) NewSound(clip *audio.Clip) (audio.Voice, error) {
+	pcm, ok := b.decoded[clip]
+	if !ok {
+		stream, err := decode(clip)
+		if err != nil {
+			return nil, err
+		}
+		pcm, err = io.ReadAll(stream)
+		if err != nil {
+			return nil, fmt.Errorf("audio: 解码 %s 失败: %w", clip.Name, err)
+		}
+		b.decoded[clip] = pcm
+	}
+	return b.newVoice(bytes.NewReader(pcm))
+}
@@ -0,0 +1,332 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownClip 播放了未加载的音频
+var ErrUnknownClip = errors.New("audio: 未加载的音频")
+
+// Bus 音量总线，同一总线上的声音共享一个音量
+type Bus int
+
+const (
+	BusMusic Bus = iota // 背景音乐
+	BusSFX              // 音效
+	busCount
+)
+
+// DefaultMaxVoices 每个音效最多同时播放的声音数
+const DefaultMaxVoices = 4
+
+// track 一首正在播放（或淡出中）的背景音乐
+type track struct {
+	name      string
+	voice     Voice
+	gain      float64 // 淡入淡出增益 0~1
+	fadeSpeed float64 // 每秒增益变化量，正数淡入，负数淡出
+}
+
+// sfxPool 同一音效的声音池，避免每次播放都创建新的播放器
+type sfxPool struct {
+	voices []Voice
+	owners []*Source // 每路声音当前所属的一次性世界声源，普通音效为空
+	next   int       // 声音池满时下一个被抢占的声音
+}
+
+// release 声源不再使用池中的声音
+func (p *sfxPool) release(s *Source) {
+	for i, owner := range p.owners {
+		if owner == s {
+			p.owners[i] = nil
+		}
+	}
+}
+
+// Engine 音频引擎：背景音乐交叉淡入淡出、音效池、音量总线和静音
+type Engine struct {
+	backend   Backend
+	clips     map[string]*Clip
+	master    float64
+	buses     [busCount]float64
+	muted     bool
+	music     *track   // 当前背景音乐
+	fading    []*track // 正在淡出的旧背景音乐
+	pools     map[string]*sfxPool
+	maxVoices int
+
+	listenerX, listenerY float64   // 听者位置
+	sources              []*Source // 世界声源
+	sourcesPaused        bool      // 世界声源是否暂停
+}
+
+// NewEngine 创建音频引擎
+func NewEngine(backend Backend) *Engine {
+	e := &Engine{
+		backend:   backend,
+		clips:     map[string]*Clip{},
+		master:    1,
+		pools:     map[string]*sfxPool{},
+		maxVoices: DefaultMaxVoices,
+	}
+	for i := range e.buses {
+		e.buses[i] = 1
+	}
+	return e
+}
+
+// AddClip 注册音频，同名音频会被替换
+func (e *Engine) AddClip(clip *Clip) {
+	e.clips[clip.Name] = clip
+}
+
+// LoadFile 从文件加载音频并注册
+func (e *Engine) LoadFile(name, path string) error {
+	clip, err := LoadClip(name, path)
+	if err != nil {
+		return err
+	}
+	e.AddClip(clip)
+	return nil
+}
+
+// clip 按名称查找已注册的音频
+func (e *Engine) clip(name string) (*Clip, error) {
+	clip, ok := e.clips[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownClip, name)
+	}
+	return clip, nil
+}
+
+// SetMasterVolume 设置总音量（0~1）
+func (e *Engine) SetMasterVolume(volume float64) {
+	e.master = clamp01(volume)
+	e.applyVolumes()
+}
+
+// SetBusVolume 设置总线音量（0~1）
+func (e *Engine) SetBusVolume(bus Bus, volume float64) {
+	e.buses[bus] = clamp01(volume)
+	e.applyVolumes()
+}
+
+// SetMuted 静音或取消静音
+func (e *Engine) SetMuted(muted bool) {
+	e.muted = muted
+	e.applyVolumes()
+}
+
+// Muted 是否处于静音状态
+func (e *Engine) Muted() bool {
+	return e.muted
+}
+
+// BusGain 返回总线的最终增益（总音量 × 总线音量，静音时为 0）
+func (e *Engine) BusGain(bus Bus) float64 {
+	if e.muted {
+		return 0
+	}
+	return e.master * e.buses[bus]
+}
+
+// PlayMusic 切换背景音乐，旧音乐在 fade 时间内淡出，新音乐同时淡入
+// 正在播放同一首音乐时不做任何事
+func (e *Engine) PlayMusic(name string, fade time.Duration) error {
+	if e.music != nil && e.music.name == name {
+		return nil
+	}
+	clip, err := e.clip(name)
+	if err != nil {
+		return err
+	}
+	voice, err := e.backend.NewStream(clip, true)
+	if err != nil {
+		return err
+	}
+
+	e.StopMusic(fade)
+	t := &track{name: name, voice: voice, gain: 1}
+	if fade > 0 {
+		t.gain = 0
+		t.fadeSpeed = 1 / fade.Seconds()
+	}
+	e.music = t
+	e.applyTrackVolume(t)
+	voice.Play()
+	return nil
+}
+
+// StopMusic 在 fade 时间内淡出并停止当前背景音乐
+func (e *Engine) StopMusic(fade time.Duration) {
+	if e.music == nil {
+		return
+	}
+	t := e.music
+	e.music = nil
+	if fade <= 0 {
+		_ = t.voice.Close()
+		return
+	}
+	t.fadeSpeed = -1 / fade.Seconds()
+	e.fading = append(e.fading, t)
+}
+
+// CurrentMusic 返回当前背景音乐名称，没有时返回空字符串
+func (e *Engine) CurrentMusic() string {
+	if e.music == nil {
+		return ""
+	}
+	return e.music.name
+}
+
+// PlaySFX 播放一次音效，优先复用声音池中已播放完的声音
+func (e *Engine) PlaySFX(name string) error {
+	voice, err := e.pooledVoice(name, nil)
+	if err != nil {
+		return err
+	}
+	voice.SetVolume(e.BusGain(BusSFX))
+	voice.SetPan(0)
+	voice.Play()
+	return nil
+}
+
+// pooledVoice 从音效的声音池取出一路倒回开头的声音，owner 为使用这路声音的一次性世界声源（普通音效为空）
+func (e *Engine) pooledVoice(name string, owner *Source) (Voice, error) {
+	clip, err := e.clip(name)
+	if err != nil {
+		return nil, err
+	}
+	pool, ok := e.pools[name]
+	if !ok {
+		pool = &sfxPool{}
+		e.pools[name] = pool
+	}
+
+	i, err := e.acquireVoice(pool, clip)
+	if err != nil {
+		return nil, err
+	}
+	// 被抢占的世界声源就此结束，不再控制这路声音
+	if old := pool.owners[i]; old != nil {
+		old.voice, old.pool, old.finished = nil, nil, true
+	}
+	pool.owners[i] = owner
+	if owner != nil {
+		owner.pool = pool
+	}
+	voice := pool.voices[i]
+	if err := voice.Rewind(); err != nil {
+		return nil, err
+	}
+	return voice, nil
+}
+
+// acquireVoice 从声音池取出一路空闲声音，返回它的下标；池满且都在播放时抢占最早的那一路
+func (e *Engine) acquireVoice(pool *sfxPool, clip *Clip) (int, error) {
+	for i, v := range pool.voices {
+		if !v.IsPlaying() {
+			return i, nil
+		}
+	}
+	if len(pool.voices) < e.maxVoices {
+		v, err := e.backend.NewSound(clip)
+		if err != nil {
+			return 0, err
+		}
+		pool.voices = append(pool.voices, v)
+		pool.owners = append(pool.owners, nil)
+		return len(pool.voices) - 1, nil
+	}
+	i := pool.next
+	pool.next = (pool.next + 1) % len(pool.voices)
+	pool.voices[i].Pause()
+	return i, nil
+}
+
+// Update 推进淡入淡出并更新世界声源，每帧调用一次
+func (e *Engine) Update(dt time.Duration) {
+	e.updateSources()
+
+	step := dt.Seconds()
+	if t := e.music; t != nil && t.fadeSpeed > 0 {
+		t.gain += t.fadeSpeed * step
+		if t.gain >= 1 {
+			t.gain = 1
+			t.fadeSpeed = 0
+		}
+		e.applyTrackVolume(t)
+	}
+
+	remaining := e.fading[:0]
+	for _, t := range e.fading {
+		t.gain += t.fadeSpeed * step
+		if t.gain <= 0 {
+			_ = t.voice.Close()
+			continue
+		}
+		e.applyTrackVolume(t)
+		remaining = append(remaining, t)
+	}
+	for i := len(remaining); i < len(e.fading); i++ {
+		e.fading[i] = nil
+	}
+	e.fading = remaining
+}
+
+// applyTrackVolume 根据总线音量和淡入淡出增益设置音乐音量
+func (e *Engine) applyTrackVolume(t *track) {
+	t.voice.SetVolume(e.BusGain(BusMusic) * t.gain)
+}
+
+// applyVolumes 音量或静音变化后重新设置所有声音的音量
+func (e *Engine) applyVolumes() {
+	if e.music != nil {
+		e.applyTrackVolume(e.music)
+	}
+	for _, t := range e.fading {
+		e.applyTrackVolume(t)
+	}
+	gain := e.BusGain(BusSFX)
+	for _, pool := range e.pools {
+		for i, v := range pool.voices {
+			// 属于世界声源的声音在下一次 Update 时按距离重新计算
+			if v.IsPlaying() && pool.owners[i] == nil {
+				v.SetVolume(gain)
+			}
+		}
+	}
+}
+
+// Close 停止并释放所有声音
+func (e *Engine) Close() {
+	e.StopMusic(0)
+	for _, s := range e.sources {
+		e.cullSource(s)
+	}
+	e.sources = nil
+	for _, t := range e.fading {
+		_ = t.voice.Close()
+	}
+	e.fading = nil
+	for _, pool := range e.pools {
+		for _, v := range pool.voices {
+			_ = v.Close()
+		}
+	}
+	e.pools = map[string]*sfxPool{}
+}
+
+// clamp01 将数值限制在 0~1 之间
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
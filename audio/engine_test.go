@@ -0,0 +1,139 @@
+package audio
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func newTestEngine() (*Engine, *NullBackend) {
+	backend := &NullBackend{}
+	e := NewEngine(backend)
+	for _, name := range []string{"menu", "play", "click"} {
+		e.AddClip(&Clip{Name: name, Format: FormatWAV})
+	}
+	return e, backend
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestFormatFromPath(t *testing.T) {
+	cases := map[string]Format{
+		"sounds/a.wav": FormatWAV,
+		"b.OGG":        FormatOGG,
+		"c.mp3":        FormatMP3,
+	}
+	for path, want := range cases {
+		got, err := FormatFromPath(path)
+		if err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %v, %v; want %v", path, got, err, want)
+		}
+	}
+	if _, err := FormatFromPath("d.flac"); err == nil {
+		t.Error("FormatFromPath(.flac) 应返回错误")
+	}
+}
+
+func TestMusicCrossfade(t *testing.T) {
+	e, backend := newTestEngine()
+	if err := e.PlayMusic("menu", 0); err != nil {
+		t.Fatal(err)
+	}
+	menu := backend.Voices[0]
+	if !menu.Playing || !menu.Loop || menu.Volume != 1 {
+		t.Fatalf("menu voice = %+v; want playing looped at full volume", menu)
+	}
+
+	if err := e.PlayMusic("play", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	play := backend.Voices[1]
+	if play.Volume != 0 {
+		t.Errorf("新音乐初始音量 = %v; want 0", play.Volume)
+	}
+
+	e.Update(500 * time.Millisecond)
+	if !almostEqual(menu.Volume, 0.5) || !almostEqual(play.Volume, 0.5) {
+		t.Errorf("淡入淡出一半时音量 = %v, %v; want 0.5, 0.5", menu.Volume, play.Volume)
+	}
+
+	e.Update(500 * time.Millisecond)
+	if !menu.Closed {
+		t.Error("旧音乐淡出完成后应被关闭")
+	}
+	if !almostEqual(play.Volume, 1) {
+		t.Errorf("新音乐最终音量 = %v; want 1", play.Volume)
+	}
+	if e.CurrentMusic() != "play" {
+		t.Errorf("CurrentMusic() = %q; want play", e.CurrentMusic())
+	}
+}
+
+func TestPlaySameMusicIsNoop(t *testing.T) {
+	e, backend := newTestEngine()
+	_ = e.PlayMusic("menu", 0)
+	_ = e.PlayMusic("menu", time.Second)
+	if len(backend.Voices) != 1 {
+		t.Errorf("重复播放同一首音乐创建了 %d 路声音; want 1", len(backend.Voices))
+	}
+}
+
+func TestSFXPoolReusesFinishedVoices(t *testing.T) {
+	e, backend := newTestEngine()
+	_ = e.PlaySFX("click")
+	backend.Voices[0].Finish()
+	_ = e.PlaySFX("click")
+	if len(backend.Voices) != 1 {
+		t.Fatalf("播放完的音效应被复用，实际创建了 %d 路声音", len(backend.Voices))
+	}
+	if backend.Voices[0].Rewinds != 2 || !backend.Voices[0].Playing {
+		t.Errorf("复用的声音 = %+v; want rewound twice and playing", backend.Voices[0])
+	}
+}
+
+func TestSFXPoolIsCapped(t *testing.T) {
+	e, backend := newTestEngine()
+	for i := 0; i < DefaultMaxVoices*2; i++ {
+		if err := e.PlaySFX("click"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(backend.Voices) != DefaultMaxVoices {
+		t.Errorf("声音池大小 = %d; want %d", len(backend.Voices), DefaultMaxVoices)
+	}
+}
+
+func TestBusVolumeAndMute(t *testing.T) {
+	e, backend := newTestEngine()
+	_ = e.PlayMusic("menu", 0)
+	_ = e.PlaySFX("click")
+	music, click := backend.Voices[0], backend.Voices[1]
+
+	e.SetMasterVolume(0.5)
+	e.SetBusVolume(BusMusic, 0.5)
+	if !almostEqual(music.Volume, 0.25) || !almostEqual(click.Volume, 0.5) {
+		t.Errorf("音量 = %v, %v; want 0.25, 0.5", music.Volume, click.Volume)
+	}
+
+	e.SetMuted(true)
+	if music.Volume != 0 || click.Volume != 0 {
+		t.Errorf("静音后音量 = %v, %v; want 0", music.Volume, click.Volume)
+	}
+	e.SetMuted(false)
+	if !almostEqual(music.Volume, 0.25) {
+		t.Errorf("取消静音后音乐音量 = %v; want 0.25", music.Volume)
+	}
+}
+
+func TestUnknownClip(t *testing.T) {
+	e, _ := newTestEngine()
+	if err := e.PlaySFX("missing"); !errors.Is(err, ErrUnknownClip) {
+		t.Errorf("PlaySFX(missing) = %v; want ErrUnknownClip", err)
+	}
+	if err := e.PlayMusic("missing", 0); !errors.Is(err, ErrUnknownClip) {
+		t.Errorf("PlayMusic(missing) = %v; want ErrUnknownClip", err)
+	}
+}
@@ -0,0 +1,207 @@
+package audio
+
+import (
+	"math"
+)
+
+// Falloff 距离衰减曲线：输入 0~1 的归一化距离（0 为 MinDistance，1 为 MaxDistance），返回 0~1 的增益
+type Falloff func(t float64) float64
+
+var (
+	// FalloffLinear 线性衰减
+	FalloffLinear Falloff = func(t float64) float64 { return 1 - t }
+	// FalloffQuadratic 平方衰减，近处衰减慢、远处衰减快
+	FalloffQuadratic Falloff = func(t float64) float64 { return (1 - t) * (1 - t) }
+	// FalloffInverse 近似真实的反比衰减，在 MaxDistance 处归零
+	FalloffInverse Falloff = func(t float64) float64 { return (1 - t) / (1 + 4*t) }
+)
+
+// Source 世界坐标中的声源（如河流、商店），超出范围时自动剔除
+type Source struct {
+	Clip        string  // 音频名称
+	X, Y        float64 // 世界坐标
+	Volume      float64 // 声源自身音量 0~1
+	MinDistance float64 // 该距离内不衰减
+	MaxDistance float64 // 超过该距离听不到，声音被剔除
+	Falloff     Falloff // 衰减曲线，为空时使用线性衰减
+	Loop        bool    // 是否循环播放（环境音）
+
+	voice    Voice    // 正在播放的声音，被剔除时为空
+	pool     *sfxPool // 一次性声源的声音所在的音效池
+	started  bool     // 一次性声源是否已经开始（只在开始时刻播放一次）
+	finished bool     // 一次性声源播放结束
+}
+
+// Audible 声源当前是否有正在播放的声音（未被剔除）
+func (s *Source) Audible() bool {
+	return s.voice != nil
+}
+
+// attenuate 计算声源相对听者的增益和声像（-1 左 ~ 1 右），超出范围时 ok 为 false
+func (s *Source) attenuate(listenerX, listenerY float64) (gain, pan float64, ok bool) {
+	dx := s.X - listenerX
+	dy := s.Y - listenerY
+	dist := math.Hypot(dx, dy)
+	if dist > s.MaxDistance {
+		return 0, 0, false
+	}
+
+	t := 0.0
+	if span := s.MaxDistance - s.MinDistance; span > 0 && dist > s.MinDistance {
+		t = (dist - s.MinDistance) / span
+	}
+	falloff := s.Falloff
+	if falloff == nil {
+		falloff = FalloffLinear
+	}
+	gain = s.Volume * clamp01(falloff(t))
+
+	// 声像只取决于水平方向的偏移，距离越远越偏向一侧
+	if s.MaxDistance > 0 {
+		pan = math.Max(-1, math.Min(1, dx/s.MaxDistance*2))
+	}
+	return gain, pan, true
+}
+
+// PanGains 把声像换算成左右声道增益，居中时两边都是 1
+func PanGains(pan float64) (left, right float64) {
+	return math.Min(1, 1-pan), math.Min(1, 1+pan)
+}
+
+// SetListener 设置听者（玩家）位置
+func (e *Engine) SetListener(x, y float64) {
+	e.listenerX, e.listenerY = x, y
+}
+
+// AddSource 添加世界声源，下一次 Update 时开始计算衰减
+func (e *Engine) AddSource(s *Source) {
+	e.sources = append(e.sources, s)
+}
+
+// PlayAt 在世界坐标播放一次性音效，与 PlaySFX 共用音效池；
+// 下一次 Update 时不在范围内的不会再播放，播放结束或离开范围后自动移除
+func (e *Engine) PlayAt(clip string, x, y, maxDistance float64) *Source {
+	s := &Source{Clip: clip, X: x, Y: y, Volume: 1, MaxDistance: maxDistance}
+	e.AddSource(s)
+	return s
+}
+
+// RemoveSource 移除世界声源并停止其声音
+func (e *Engine) RemoveSource(s *Source) {
+	for i, src := range e.sources {
+		if src == s {
+			e.cullSource(s)
+			e.sources = append(e.sources[:i], e.sources[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetSourcesPaused 暂停时剔除所有世界声源（如回到主菜单），恢复后按距离重新播放
+func (e *Engine) SetSourcesPaused(paused bool) {
+	e.sourcesPaused = paused
+	if paused {
+		for _, s := range e.sources {
+			e.cullSource(s)
+		}
+	}
+}
+
+// updateSources 按听者位置更新每个声源的音量和声像，并剔除超出范围的声源；
+// 暂停期间的一次性声源错过了开始时刻，直接移除
+func (e *Engine) updateSources() {
+	remaining := e.sources[:0]
+	for _, s := range e.sources {
+		if e.sourcesPaused {
+			s.finished = !s.Loop
+		} else {
+			e.updateSource(s)
+		}
+		if s.finished {
+			e.cullSource(s)
+			continue
+		}
+		remaining = append(remaining, s)
+	}
+	for i := len(remaining); i < len(e.sources); i++ {
+		e.sources[i] = nil
+	}
+	e.sources = remaining
+}
+
+// updateSource 更新单个声源
+func (e *Engine) updateSource(s *Source) {
+	gain, pan, ok := s.attenuate(e.listenerX, e.listenerY)
+	if !s.Loop {
+		e.updateOneShot(s, gain, pan, ok)
+		return
+	}
+	if !ok {
+		e.cullSource(s)
+		return
+	}
+
+	if s.voice == nil {
+		clip, err := e.clip(s.Clip)
+		if err != nil {
+			s.finished = true
+			return
+		}
+		voice, err := e.backend.NewStream(clip, true)
+		if err != nil {
+			s.finished = true
+			return
+		}
+		s.voice = voice
+		e.applySource(s, gain, pan)
+		voice.Play()
+		return
+	}
+	e.applySource(s, gain, pan)
+}
+
+// updateOneShot 更新一次性声源：开始时不在范围内就不再播放，开始后离开范围、被打断或播放结束都会结束
+func (e *Engine) updateOneShot(s *Source, gain, pan float64, ok bool) {
+	if s.started && (s.voice == nil || !s.voice.IsPlaying()) {
+		s.finished = true
+		return
+	}
+	s.started = true
+	if !ok {
+		s.finished = true
+		return
+	}
+	if s.voice == nil {
+		voice, err := e.pooledVoice(s.Clip, s)
+		if err != nil {
+			s.finished = true
+			return
+		}
+		s.voice = voice
+		e.applySource(s, gain, pan)
+		voice.Play()
+		return
+	}
+	e.applySource(s, gain, pan)
+}
+
+// applySource 设置声源的最终音量和声像
+func (e *Engine) applySource(s *Source, gain, pan float64) {
+	s.voice.SetVolume(e.BusGain(BusSFX) * gain)
+	s.voice.SetPan(pan)
+}
+
+// cullSource 停止并释放声源的声音；音效池中的声音只暂停并还给声音池
+func (e *Engine) cullSource(s *Source) {
+	if s.voice == nil {
+		return
+	}
+	if s.pool != nil {
+		s.voice.Pause()
+		s.pool.release(s)
+		s.pool = nil
+	} else {
+		_ = s.voice.Close()
+	}
+	s.voice = nil
+}
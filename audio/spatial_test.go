@@ -0,0 +1,166 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFalloffCurves(t *testing.T) {
+	for name, f := range map[string]Falloff{
+		"linear":    FalloffLinear,
+		"quadratic": FalloffQuadratic,
+		"inverse":   FalloffInverse,
+	} {
+		if !almostEqual(f(0), 1) || !almostEqual(f(1), 0) {
+			t.Errorf("%s: f(0)=%v f(1)=%v; want 1 and 0", name, f(0), f(1))
+		}
+		if f(0.25) < f(0.75) {
+			t.Errorf("%s: 衰减曲线应随距离递减", name)
+		}
+	}
+}
+
+func TestSourceAttenuationAndPan(t *testing.T) {
+	e, backend := newTestEngine()
+	src := &Source{Clip: "click", X: 100, Y: 0, Volume: 1, MinDistance: 0, MaxDistance: 200, Loop: true}
+	e.AddSource(src)
+
+	e.SetListener(0, 0)
+	e.Update(time.Millisecond)
+	voice := backend.Voices[0]
+	if !voice.Playing || !voice.Loop {
+		t.Fatalf("范围内的环境声源应循环播放: %+v", voice)
+	}
+	if !almostEqual(voice.Volume, 0.5) {
+		t.Errorf("距离一半时音量 = %v; want 0.5", voice.Volume)
+	}
+	if !almostEqual(voice.Pan, 1) {
+		t.Errorf("声源在右侧时声像 = %v; want 1", voice.Pan)
+	}
+
+	e.SetListener(100, 50)
+	e.Update(time.Millisecond)
+	if voice.Pan != 0 {
+		t.Errorf("声源在正下方时声像 = %v; want 0", voice.Pan)
+	}
+}
+
+func TestSourceCulledOutOfRange(t *testing.T) {
+	e, backend := newTestEngine()
+	src := &Source{Clip: "click", X: 0, Y: 0, Volume: 1, MaxDistance: 100, Loop: true}
+	e.AddSource(src)
+
+	e.SetListener(500, 0)
+	e.Update(time.Millisecond)
+	if len(backend.Voices) != 0 || src.Audible() {
+		t.Fatal("范围外的声源不应创建声音")
+	}
+
+	e.SetListener(50, 0)
+	e.Update(time.Millisecond)
+	if !src.Audible() {
+		t.Fatal("进入范围后声源应开始播放")
+	}
+
+	e.SetListener(500, 0)
+	e.Update(time.Millisecond)
+	if src.Audible() || !backend.Voices[0].Closed {
+		t.Error("离开范围后声源的声音应被关闭")
+	}
+
+	e.SetListener(0, 0)
+	e.Update(time.Millisecond)
+	if !src.Audible() || len(backend.Voices) != 2 {
+		t.Error("环境声源再次进入范围后应重新播放")
+	}
+}
+
+func TestPlayAtRemovedWhenFinished(t *testing.T) {
+	e, backend := newTestEngine()
+	e.PlayAt("click", 10, 0, 100)
+	e.Update(time.Millisecond)
+	if len(backend.Voices) != 1 || backend.Voices[0].Loop || !backend.Voices[0].Playing {
+		t.Fatalf("PlayAt 应播放一路不循环的声音: %+v", backend.Voices)
+	}
+
+	backend.Voices[0].Finish()
+	e.Update(time.Millisecond)
+	if len(e.sources) != 0 {
+		t.Error("一次性声源播放结束后应被移除")
+	}
+
+	// 声音还给音效池，下一次播放复用同一路声音
+	e.PlayAt("click", 10, 0, 100)
+	e.Update(time.Millisecond)
+	if len(backend.Voices) != 1 || !backend.Voices[0].Playing || backend.Voices[0].Closed {
+		t.Errorf("PlayAt 应复用音效池中的声音: %+v", backend.Voices)
+	}
+}
+
+// TestPlayAtOutOfRangeExpires 开始时不在范围内的一次性音效直接结束，听者之后走近也不会播放
+func TestPlayAtOutOfRangeExpires(t *testing.T) {
+	e, backend := newTestEngine()
+	e.SetListener(500, 0)
+	e.PlayAt("click", 0, 0, 100)
+	e.Update(time.Millisecond)
+	if len(e.sources) != 0 || len(backend.Voices) != 0 {
+		t.Fatal("范围外的一次性声源应被移除")
+	}
+	e.SetListener(0, 0)
+	e.Update(time.Millisecond)
+	if len(backend.Voices) != 0 {
+		t.Error("过期的一次性声源不应再播放")
+	}
+
+	// 暂停期间开始的一次性音效恢复后也不再播放
+	e.SetSourcesPaused(true)
+	e.PlayAt("click", 0, 0, 100)
+	e.Update(time.Millisecond)
+	e.SetSourcesPaused(false)
+	e.Update(time.Millisecond)
+	if len(backend.Voices) != 0 || len(e.sources) != 0 {
+		t.Error("暂停期间的一次性声源不应在恢复后播放")
+	}
+}
+
+// TestPlayAtPreempted 音效池满时一次性声源的声音被抢占，声源随之结束且不再控制那路声音
+func TestPlayAtPreempted(t *testing.T) {
+	e, backend := newTestEngine()
+	e.maxVoices = 1
+	src := e.PlayAt("click", 100, 0, 200)
+	e.Update(time.Millisecond)
+	voice := backend.Voices[0]
+	if !almostEqual(voice.Volume, 0.5) {
+		t.Fatalf("距离一半时音量 = %v; want 0.5", voice.Volume)
+	}
+
+	if err := e.PlaySFX("click"); err != nil {
+		t.Fatal(err)
+	}
+	e.Update(time.Millisecond)
+	if src.Audible() || len(e.sources) != 0 || len(backend.Voices) != 1 {
+		t.Fatal("被抢占的声源应结束")
+	}
+	if voice.Volume != 1 || voice.Pan != 0 {
+		t.Errorf("被抢占后的声音应属于普通音效: 音量 %v 声像 %v", voice.Volume, voice.Pan)
+	}
+}
+
+func TestSourcesPaused(t *testing.T) {
+	e, backend := newTestEngine()
+	src := &Source{Clip: "click", Volume: 1, MaxDistance: 100, Loop: true}
+	e.AddSource(src)
+	e.Update(time.Millisecond)
+
+	e.SetSourcesPaused(true)
+	e.Update(time.Millisecond)
+	if src.Audible() || !backend.Voices[0].Closed {
+		t.Error("暂停后世界声源应被剔除")
+	}
+
+	e.SetSourcesPaused(false)
+	e.Update(time.Millisecond)
+	if !src.Audible() {
+		t.Error("恢复后世界声源应重新播放")
+	}
+}
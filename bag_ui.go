@@ -0,0 +1,358 @@
+package main
+
+import (
+	"Game/inventory"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+	"slices"
+	"unicode/utf8"
+)
+
+// 背包界面布局
+const (
+	inventoryW       = 300
+	inventoryH       = 400
+	inventoryRows    = 4  // 每页的行数
+	inventorySlot    = 48 // 物品格子尺寸
+	inventorySpacing = 15 // 物品格子间距
+	bagControlsH     = 96 // 标题、分类标签、搜索框和排序按钮占用的高度
+	bagSearchMax     = 20 // 搜索文字的最大长度
+)
+
+// bagTabs 背包的分类标签；货币放在钱包里，不需要单独的标签
+var bagTabs = slices.DeleteFunc(slices.Clone(inventory.Categories), func(c inventory.Category) bool {
+	return c == inventory.CategoryCurrency
+})
+
+// bagPanel 背包界面的状态
+type bagPanel struct {
+	loaded     bool               // 背包是否需要加载
+	page       int                // 当前背包页码
+	selected   int                // 当前选中的背包物品索引
+	hovered    *item              // 鼠标悬停的背包或装备栏物品，绘制时更新，用于显示提示框
+	inspecting *item              // 物品详情面板中的物品，为空表示没有打开
+	sort       inventory.SortMode // 背包排序方式
+	filter     inventory.Filter   // 背包的分类和搜索筛选
+	searching  bool               // 是否正在输入背包搜索文字
+	drag       *item              // 正在从背包拖向快捷栏的物品
+}
+
+// inventoryRect 背包面板的位置（屏幕居中）
+func inventoryRect() [4]int {
+	return [4]int{(screenWidth - inventoryW) / 2, (screenHeight - inventoryH) / 2, inventoryW, inventoryH}
+}
+
+// bagTabRect 第 i 个分类标签
+func bagTabRect(i int) [4]int {
+	r := inventoryRect()
+	w := (inventoryW - 20) / len(bagTabs)
+	return [4]int{r[0] + 10 + i*w, r[1] + 42, w - 2, 18}
+}
+
+// bagSearchRect 搜索框
+func bagSearchRect() [4]int {
+	r := inventoryRect()
+	return [4]int{r[0] + 10, r[1] + 66, 150, 18}
+}
+
+// bagSortRect 切换排序方式的按钮
+func bagSortRect() [4]int {
+	r := inventoryRect()
+	return [4]int{r[0] + 166, r[1] + 66, 70, 18}
+}
+
+// bagStackRect 合并同种物品的按钮
+func bagStackRect() [4]int {
+	r := inventoryRect()
+	return [4]int{r[0] + 240, r[1] + 66, 50, 18}
+}
+
+// itemGrid 物品格子的布局：左上角位置和每页的列数、行数（背包和容器界面共用）
+type itemGrid struct {
+	x, y       int
+	cols, rows int
+}
+
+// perPage 每页的格子数
+func (g itemGrid) perPage() int {
+	return g.cols * g.rows
+}
+
+// slotRect 当前页第 local 个格子
+func (g itemGrid) slotRect(local int) [4]int {
+	row, col := local/g.cols, local%g.cols
+	return [4]int{g.x + col*(inventorySlot+inventorySpacing), g.y + row*(inventorySlot+inventorySpacing), inventorySlot, inventorySlot}
+}
+
+// at 返回 (x, y) 所在格子在当前页中的序号，不在任何格子上时返回 -1
+func (g itemGrid) at(x, y int) int {
+	for local := range g.perPage() {
+		if inRect(x, y, g.slotRect(local)) {
+			return local
+		}
+	}
+	return -1
+}
+
+// bagGrid 背包的物品格子（标题、分隔线、分类标签和搜索框下方）
+func bagGrid() itemGrid {
+	r := inventoryRect()
+	return itemGrid{x: r[0] + 20, y: r[1] + bagControlsH, cols: bagItemsPerRow(), rows: inventoryRows}
+}
+
+// bagItemAt 返回 (x, y) 处的背包物品在背包物品中的下标，没有时返回 -1
+func (p *PlayScreen) bagItemAt(x, y int) int {
+	local := bagGrid().at(x, y)
+	view := p.bagView()
+	if index := p.bagUI.page*bagPerPage() + local; local >= 0 && index < len(view) {
+		return view[index]
+	}
+	return -1
+}
+
+// drawItemGrid 绘制 items 中由 view 给出下标的物品的第 page 页，selected 为选中物品的下标；
+// 返回鼠标下的物品下标，没有时返回 -1
+func (p *PlayScreen) drawItemGrid(screen *ebiten.Image, g itemGrid, items []*item, view []int, page, selected int) int {
+	cursorX, cursorY := ebiten.CursorPosition()
+	hovered := -1
+	start := page * g.perPage()
+	for local := 0; local < g.perPage() && start+local < len(view); local++ {
+		i := view[start+local]
+		it := items[i]
+		r := g.slotRect(local)
+		x, y, itemWidth, itemHeight := r[0], r[1], r[2], r[3]
+
+		// 绘制物品背景阴影
+		vector.DrawFilledRect(screen, float32(x+2), float32(y+2), float32(itemWidth), float32(itemHeight), color.RGBA{A: 100}, false)
+
+		// 如果是选中的物品，绘制高亮背景
+		if i == selected {
+			// 绘制选中效果的发光边框
+			for j := 0; j < 3; j++ {
+				vector.StrokeRect(screen, float32(x-j), float32(y-j), float32(itemWidth+2*j), float32(itemHeight+2*j), 1, color.RGBA{R: 100, G: 150, B: 255, A: uint8(150 - j*50)}, false)
+			}
+		}
+
+		// 绘制物品背景框，边框和光晕的颜色表示稀有度
+		drawRarityFrame(screen, x, y, itemWidth, itemHeight, it.ItemData.Rarity)
+		// 绘制物品图片
+		if it.ItemData.Image != nil {
+			op := &ebiten.DrawImageOptions{}
+			// 缩放图片以适应物品框，保持一定边距
+			imgBounds := it.ItemData.Image.Bounds()
+			scaleX := float64(itemWidth-8) / float64(imgBounds.Dx())
+			scaleY := float64(itemHeight-8) / float64(imgBounds.Dy())
+			finalScale := math.Min(scaleX, scaleY) // 保持图片比例
+			op.GeoM.Scale(finalScale, finalScale)
+			op.GeoM.Translate(float64(x)+(float64(itemWidth)-float64(imgBounds.Dx())*finalScale)/2,
+				float64(y)+(float64(itemHeight)-float64(imgBounds.Dy())*finalScale)/2)
+			screen.DrawImage(it.ItemData.Image, op)
+		}
+
+		// 绘制物品数量（右下角）
+		if it.count > 1 {
+			countText := fmt.Sprintf("%d", it.count)
+			textWidth := len(countText) * 6
+			// 绘制数量背景
+			vector.DrawFilledRect(screen, float32(x+itemWidth-textWidth-4), float32(y+itemHeight-16), float32(textWidth+4), 16, color.RGBA{A: 200}, false)
+			ebitenutil.DebugPrintAt(screen, countText, x+itemWidth-textWidth-2, y+itemHeight-14)
+		}
+
+		if inRect(cursorX, cursorY, r) {
+			hovered = i
+		}
+	}
+	return hovered
+}
+
+// bagItemsPerRow 每行的物品格子数
+func bagItemsPerRow() int {
+	return (inventoryW - 40) / (inventorySlot + inventorySpacing)
+}
+
+// bagPerPage 每页的物品格子数
+func bagPerPage() int {
+	return bagItemsPerRow() * inventoryRows
+}
+
+// bagView 返回按当前分类、搜索文字筛选并排序后要显示的物品在背包物品中的下标
+func (p *PlayScreen) bagView() []int {
+	entries := make([]inventory.Entry, len(p.bag().Items))
+	for i, it := range p.bag().Items {
+		entries[i] = inventory.Entry{
+			Item:     it.ItemData.id,
+			Name:     it.ItemData.Name,
+			Category: it.ItemData.Category,
+			Rarity:   it.ItemData.Rarity.Index(),
+			Count:    it.count,
+			Acquired: it.acquired,
+		}
+	}
+	return inventory.View(entries, p.bagUI.filter, p.bagUI.sort)
+}
+
+// nextAcquired 返回下一个获得顺序
+func (p *PlayScreen) nextAcquired() int {
+	p.bag().Acquired++
+	return p.bag().Acquired
+}
+
+// moveBagSelection 在当前显示的物品中向前或向后移动选中位置（首尾循环），并翻到选中物品所在的页
+func (p *PlayScreen) moveBagSelection(step int) {
+	view := p.bagView()
+	if len(view) == 0 {
+		return
+	}
+	pos := slices.Index(view, p.bagUI.selected)
+	if pos < 0 {
+		pos = 0
+	} else {
+		pos = (pos + step + len(view)) % len(view)
+	}
+	p.bagUI.selected = view[pos]
+	p.bagUI.page = pos / bagPerPage()
+}
+
+// bagFilterChanged 筛选条件或排序方式变化后回到第一页，选中的物品不再显示时改为选中第一个
+func (p *PlayScreen) bagFilterChanged() {
+	p.bagUI.page = 0
+	view := p.bagView()
+	if len(view) > 0 && !slices.Contains(view, p.bagUI.selected) {
+		p.bagUI.selected = view[0]
+	}
+}
+
+// consolidateItems 把同种物品的多组合并成一组，选中的物品保持选中
+func (p *PlayScreen) consolidateItems() {
+	stacks := make([]inventory.Stack, len(p.bag().Items))
+	for i, it := range p.bag().Items {
+		stacks[i] = inventory.Stack{Item: it.ItemData.id, Count: it.count}
+	}
+	kept, counts := inventory.Consolidate(stacks)
+	if len(kept) == len(p.bag().Items) {
+		return
+	}
+	var selected int64 = -1
+	if p.bagUI.selected >= 0 && p.bagUI.selected < len(p.bag().Items) {
+		selected = p.bag().Items[p.bagUI.selected].ItemData.id
+	}
+	items := make([]*item, len(kept))
+	for i, index := range kept {
+		items[i] = p.bag().Items[index]
+		items[i].count = counts[i]
+		if items[i].ItemData.id == selected {
+			p.bagUI.selected = i
+		}
+	}
+	p.bag().Items = items
+	p.inventoryChanged()
+}
+
+// updateInventoryControls 处理分类标签、搜索框、排序和合并按钮的点击；按 / 键开始搜索
+func (p *PlayScreen) updateInventoryControls() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySlash) {
+		p.bagUI.searching = true
+		return
+	}
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	p.bagUI.searching = inRect(x, y, bagSearchRect())
+	for i, c := range bagTabs {
+		if inRect(x, y, bagTabRect(i)) && p.bagUI.filter.Category != c {
+			p.sound.PlaySFX(sfxClick)
+			p.bagUI.filter.Category = c
+			p.bagFilterChanged()
+		}
+	}
+	switch {
+	case inRect(x, y, bagSortRect()):
+		p.sound.PlaySFX(sfxClick)
+		i := slices.Index(inventory.SortModes, p.bagUI.sort)
+		p.bagUI.sort = inventory.SortModes[(i+1)%len(inventory.SortModes)]
+		p.bagFilterChanged()
+	case inRect(x, y, bagStackRect()):
+		p.sound.PlaySFX(sfxClick)
+		p.consolidateItems()
+	}
+}
+
+// updateBagSearch 输入搜索文字；输入期间按键不触发游戏动作，回车、Esc 或点击其他地方结束输入
+func (p *PlayScreen) updateBagSearch() {
+	search := p.bagUI.filter.Search
+	for _, r := range ebiten.AppendInputChars(nil) {
+		// 调试字体只能显示 ASCII 字符
+		if r >= ' ' && r <= '~' && len(search) < bagSearchMax {
+			search += string(r)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && search != "" {
+		_, size := utf8.DecodeLastRuneInString(search)
+		search = search[:len(search)-size]
+	}
+	if search != p.bagUI.filter.Search {
+		p.bagUI.filter.Search = search
+		p.bagFilterChanged()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		p.bagUI.searching = false
+		return
+	}
+	p.updateInventoryControls()
+}
+
+// drawInventoryControls 绘制分类标签、搜索框、排序和合并按钮
+func (p *PlayScreen) drawInventoryControls(screen *ebiten.Image) {
+	buttonColor := color.RGBA{R: 100, G: 100, B: 150, A: 255}
+	activeColor := color.RGBA{R: 100, G: 150, B: 255, A: 255}
+	for i, c := range bagTabs {
+		r := bagTabRect(i)
+		clr := buttonColor
+		if c == p.bagUI.filter.Category {
+			clr = activeColor
+		}
+		vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), clr, false)
+		drawCenteredText(screen, p.settings.T("bag.tab."+bagTabKey(c)), r[0], r[1]+1, r[2])
+	}
+
+	r := bagSearchRect()
+	vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), color.RGBA{R: 20, G: 20, B: 30, A: 255}, false)
+	border := buttonColor
+	if p.bagUI.searching {
+		border = activeColor
+	}
+	vector.StrokeRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), 1, border, false)
+	switch {
+	case p.bagUI.searching:
+		ebitenutil.DebugPrintAt(screen, p.bagUI.filter.Search+"_", r[0]+4, r[1]+1)
+	case p.bagUI.filter.Search != "":
+		ebitenutil.DebugPrintAt(screen, p.bagUI.filter.Search, r[0]+4, r[1]+1)
+	default:
+		drawColoredText(screen, p.settings.T("bag.search"), r[0]+4, r[1]+1, tooltipGray)
+	}
+
+	for _, b := range []struct {
+		rect  [4]int
+		label string
+	}{
+		{bagSortRect(), p.settings.T("bag.sort." + string(p.bagUI.sort))},
+		{bagStackRect(), p.settings.T("bag.stack")},
+	} {
+		vector.DrawFilledRect(screen, float32(b.rect[0]), float32(b.rect[1]), float32(b.rect[2]), float32(b.rect[3]), buttonColor, false)
+		drawCenteredText(screen, b.label, b.rect[0], b.rect[1]+1, b.rect[2])
+	}
+}
+
+// bagTabKey 返回分类标签的翻译键名
+func bagTabKey(c inventory.Category) string {
+	if c == inventory.CategoryAll {
+		return "all"
+	}
+	return string(c)
+}
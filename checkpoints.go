@@ -0,0 +1,143 @@
+package main
+
+import (
+	"Game/ecs"
+	"fmt"
+)
+
+// 复活点图片
+const (
+	checkpointImage       = "photos/checkpoint.png"
+	checkpointActiveImage = "photos/checkpoint_active.png"
+)
+
+// Checkpoint 复活点组件，玩家走上去后成为死亡后复活的位置
+type Checkpoint struct {
+	ID string
+}
+
+// respawnPoint 玩家死亡后复活的位置；ID 为空表示还没有激活过复活点，在新游戏的起点复活
+type respawnPoint struct {
+	ID  string `json:"id,omitempty"`
+	Map string `json:"map"`
+	Col int    `json:"col"`
+	Row int    `json:"row"`
+}
+
+// Respawner 玩家的复活组件：复活的位置和死亡状态
+type Respawner struct {
+	Point respawnPoint // 死亡后复活的位置
+	Dead  bool         // 是否已死亡（显示游戏结束界面）
+	Loss  int64        // 这次死亡损失的货币数量
+}
+
+// respawner 返回玩家的复活组件
+func (p *PlayScreen) respawner() *Respawner {
+	return ecs.Get[Respawner](p.world, p.player)
+}
+
+// initCheckpoints 加载复活点图片，复活位置默认为新游戏的起点（需要在加载地图之后调用）
+func (p *PlayScreen) initCheckpoints() {
+	loadImage(checkpointImage)
+	loadImage(checkpointActiveImage)
+	ecs.Add(p.world, p.player, Respawner{Point: p.defaultRespawn()})
+}
+
+// defaultRespawn 新游戏起点的复活位置
+func (p *PlayScreen) defaultRespawn() respawnPoint {
+	spawn := p.atlas().Maps[startMap].Spawn(startSpawn)
+	return respawnPoint{Map: startMap, Col: spawn.Col, Row: spawn.Row}
+}
+
+// spawnCheckpoint 在网格坐标 (col, row) 放置复活点
+func (p *PlayScreen) spawnCheckpoint(id string, col, row int) ecs.Entity {
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: float64(col * p.gridSize), Y: float64(row * p.gridSize)})
+	ecs.Add(p.world, e, ecs.Sprite{Image: loadImage(checkpointImage), Width: float64(p.gridSize), Height: float64(p.gridSize), Layer: layerGround})
+	ecs.Add(p.world, e, ecs.Collider{W: float64(p.gridSize), H: float64(p.gridSize)})
+	ecs.Add(p.world, e, Checkpoint{ID: id})
+	return e
+}
+
+// updateCheckpoints 玩家走上还没激活的复活点时把它设为复活位置
+func (p *PlayScreen) updateCheckpoints() {
+	for _, e := range ecs.Touching(p.world, p.player) {
+		cp := ecs.Get[Checkpoint](p.world, e)
+		if cp == nil || (cp.ID == p.respawner().Point.ID && p.atlas().Current == p.respawner().Point.Map) {
+			continue
+		}
+		pos := ecs.Get[ecs.Position](p.world, e)
+		p.setCheckpoint(respawnPoint{ID: cp.ID, Map: p.atlas().Current, Col: int(pos.X) / p.gridSize, Row: int(pos.Y) / p.gridSize})
+		p.notices.Show(p.settings.T("notice.checkpoint"))
+		p.sound.PlaySFX(sfxPickup)
+	}
+}
+
+// setCheckpoint 设置复活位置，并让当前地图上对应的复活点显示为激活
+func (p *PlayScreen) setCheckpoint(point respawnPoint) {
+	p.respawner().Point = point
+	ecs.Each(p.world, func(e ecs.Entity, cp *Checkpoint) {
+		image := checkpointImage
+		if cp.ID == point.ID && p.atlas().Current == point.Map {
+			image = checkpointActiveImage
+		}
+		ecs.Get[ecs.Sprite](p.world, e).Image = loadImage(image)
+	})
+}
+
+// die 玩家死亡：扣除死亡惩罚，关闭所有界面，显示游戏结束界面
+func (p *PlayScreen) die() {
+	p.respawner().Dead = true
+	p.respawner().Loss = p.applyDeathPenalty()
+	p.setInventoryOpen(false)
+	p.dialog = nil
+	p.trade = nil
+	p.craftOpen = false
+	p.skillsOpen = false
+	p.questLogOpen = false
+	p.paused = false
+	p.playerVitals().Sprinting = false
+	p.sound.PlaySFX(sfxInventoryClose)
+}
+
+// applyDeathPenalty 按配置从钱包中扣除一部分货币，返回损失的数量
+func (p *PlayScreen) applyDeathPenalty() int64 {
+	penalty := playerConfig.DeathPenalty
+	lost := int64(float64(p.purse().Wallet.Balance(penalty.Currency)) * penalty.Percent)
+	if penalty.Max > 0 {
+		lost = min(lost, penalty.Max)
+	}
+	if lost <= 0 {
+		return 0
+	}
+	defer p.walletTx("death")()
+	p.takeItem(penalty.Currency, lost)
+	return lost
+}
+
+// deathSummary 游戏结束界面显示的损失说明
+func (p *PlayScreen) deathSummary() string {
+	if p.respawner().Loss == 0 {
+		return p.settings.T("gameOver.noLoss")
+	}
+	return fmt.Sprintf(p.settings.T("gameOver.lost"), p.respawner().Loss, ItemImages[playerConfig.DeathPenalty.Currency].Name)
+}
+
+// respawn 在复活点复活（复活点在其他地图时直接切换过去）：恢复所有数值，清除所有状态，短暂无敌
+func (p *PlayScreen) respawn() {
+	// 死亡的同一帧走进传送点时取消那次切换
+	p.atlas().Transition = nil
+	p.switchMap(p.respawner().Point.Map, p.respawner().Point.Col, p.respawner().Point.Row)
+	health := ecs.Get[ecs.Health](p.world, p.player)
+	health.Current = health.Max
+	v := p.playerVitals()
+	v.Mana.Fill()
+	v.Stamina.Fill()
+	c := ecs.Get[Combatant](p.world, p.player)
+	c.knockRemaining = 0
+	c.Trigger()
+	p.statusSet(p.player).Clear()
+	p.vitalFlashes = map[VitalStat]vitalFlash{}
+	p.respawner().Dead = false
+	p.respawner().Loss = 0
+}
@@ -0,0 +1,101 @@
+// Command dungeon 生成地下城楼层并打印为字符画或保存为 PNG，用于检查生成器和调整地下城数据
+//
+//	go run ./cmd/dungeon -gen cave -seed 7 -width 60 -height 40
+//	go run ./cmd/dungeon -floor mine_2 -png mine_2.png
+package main
+
+import (
+	"Game/dungeon"
+	"Game/nav"
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"maps"
+	"os"
+	"slices"
+)
+
+func main() {
+	dataDir := flag.String("data", "data", "数据目录")
+	floor := flag.String("floor", "", "使用 dungeons.json 中这一层的参数（生成器、种子和预算），为空时使用下面的参数")
+	gen := flag.String("gen", string(dungeon.Rooms), "生成器：rooms、bsp 或 cave")
+	seed := flag.Uint64("seed", 1, "随机数种子")
+	width := flag.Int("width", 25, "宽度（格子）")
+	height := flag.Int("height", 18, "高度（格子）")
+	budget := flag.Int("budget", 6, "敌人的难度预算（每个敌人花费 1）")
+	lootBudget := flag.Int("loot", 3, "宝箱的预算（每个宝箱花费 1）")
+	out := flag.String("png", "", "保存为 PNG 的路径，为空时打印字符画")
+	scale := flag.Int("scale", 8, "PNG 中每格的像素数")
+	flag.Parse()
+
+	cfg := dungeon.Config{
+		Width: *width, Height: *height,
+		Generator: dungeon.Generator(*gen), Seed: *seed,
+		Budget: *budget, LootBudget: *lootBudget,
+		Enemies: []dungeon.Spawn{{Kind: "enemy", Cost: 1}},
+		Chests:  []dungeon.Spawn{{Kind: "chest", Cost: 1}},
+	}
+	if *floor != "" {
+		cfg = floorConfig(*dataDir+"/dungeons.json", *floor, *width, *height)
+	}
+	f, err := dungeon.Generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		fmt.Print(f.ASCII())
+	} else if err := writePNG(*out, f, *scale); err != nil {
+		log.Fatal(err)
+	}
+	path, _ := nav.FindPath(f, f.Start, f.Exit, 0)
+	fmt.Printf("%s %d×%d 种子 %d: 入口 %v 出口 %v，最短路径 %d 步\n", cfg.Generator, cfg.Width, cfg.Height, cfg.Seed, f.Entrance, f.Exit, len(path)+1)
+	fmt.Printf("敌人花费 %d/%d，宝箱花费 %d/%d\n", f.Spent(dungeon.Enemy), cfg.Budget, f.Spent(dungeon.Chest), cfg.LootBudget)
+	for _, o := range f.Objects {
+		fmt.Printf("  %-5s %-12s %v\n", o.Type, o.Kind, o.At)
+	}
+	if errs := f.Validate(); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		os.Exit(1)
+	}
+}
+
+// floorConfig 从地下城数据文件中找到楼层 id 并返回它的生成参数
+func floorConfig(path, id string, width, height int) dungeon.Config {
+	dungeons, err := dungeon.Load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if errs := dungeon.Validate(dungeons); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		os.Exit(1)
+	}
+	for _, name := range slices.Sorted(maps.Keys(dungeons)) {
+		d := dungeons[name]
+		for i, l := range d.Floors {
+			if l.ID == id {
+				return d.Config(i, width, height)
+			}
+		}
+	}
+	log.Fatalf("楼层 %q 不存在", id)
+	return dungeon.Config{}
+}
+
+// writePNG 把楼层画成 PNG 保存到 path
+func writePNG(path string, f *dungeon.Floor, scale int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, f.Image(scale)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,77 @@
+// Command loot 模拟掉落表并打印掉落率分布，用于调整掉落表数据
+//
+//	go run ./cmd/loot -table slime -n 10000
+package main
+
+import (
+	"Game/loot"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+)
+
+func main() {
+	dataDir := flag.String("data", "data", "数据目录")
+	table := flag.String("table", "", "要模拟的掉落表 id，为空时列出所有掉落表")
+	n := flag.Int("n", 10000, "模拟次数")
+	seed := flag.Uint64("seed", 1, "随机数种子")
+	level := flag.Int("level", 1, "玩家等级")
+	first := flag.Bool("first", false, "是否是第一次击败")
+	flag.Parse()
+	if *n <= 0 {
+		log.Fatalf("模拟次数 -n 必须大于 0: %d", *n)
+	}
+
+	tables, err := loot.Load(*dataDir + "/loot.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if errs := loot.Validate(tables, loot.Catalog{}); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		os.Exit(1)
+	}
+	if *table == "" {
+		for _, id := range slices.Sorted(maps.Keys(tables)) {
+			fmt.Println(id)
+		}
+		return
+	}
+	if tables[*table] == nil {
+		log.Fatalf("掉落表 %q 不存在", *table)
+	}
+	names := itemNames(*dataDir + "/items.json")
+	report := loot.Simulate(tables, *table, loot.Context{Level: *level, FirstKill: *first}, *n, loot.NewRNG(*seed))
+	report.Print(os.Stdout, func(id int64) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+		return strconv.FormatInt(id, 10)
+	})
+}
+
+// itemNames 读取物品名称，读取失败时返回空表（只显示物品 id）
+func itemNames(path string) map[int64]string {
+	names := map[int64]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return names
+	}
+	var items []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &items) != nil {
+		return names
+	}
+	for _, it := range items {
+		names[it.ID] = it.Name
+	}
+	return names
+}
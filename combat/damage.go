@@ -0,0 +1,88 @@
+// Package combat 战斗数值计算：伤害、暴击、抗性、攻击判定框、击退和无敌时间
+// 伤害公式是纯函数，暴击用的随机数由调用方传入；判定框和击退只做几何计算，实体的移动和受击表现由游戏处理
+package combat
+
+import (
+	"math"
+)
+
+// DamageType 伤害类型，用于查找防御方的抗性
+type DamageType string
+
+const (
+	Physical DamageType = "physical" // 物理
+	Fire     DamageType = "fire"     // 火焰
+	Ice      DamageType = "ice"      // 冰霜
+	Poison   DamageType = "poison"   // 毒素
+)
+
+const (
+	// DefenseScale 防御的减伤系数：防御等于该值时伤害减半
+	DefenseScale = 20.0
+	// DefaultCritMultiplier 未指定暴击倍率时使用的倍率
+	DefaultCritMultiplier = 1.5
+)
+
+// Attacker 攻击方的战斗属性
+type Attacker struct {
+	Attack         float64    // 攻击力
+	CritChance     float64    // 暴击率 0~1
+	CritMultiplier float64    // 暴击倍率，不大于 1 时使用 DefaultCritMultiplier
+	Type           DamageType // 伤害类型，为空时按物理伤害计算
+}
+
+// Defender 防御方的战斗属性
+type Defender struct {
+	Defense     float64                // 防御力
+	Resistances map[DamageType]float64 // 抗性：0.25 表示减免 25%，1 及以上表示免疫，负数表示弱点
+}
+
+// Hit 一次攻击的结算结果
+type Hit struct {
+	Damage int  // 最终伤害
+	Crit   bool // 是否暴击
+	Immune bool // 防御方是否免疫该类型伤害
+}
+
+// Resolve 结算一次攻击，roll 为 [0, 1) 的随机数，用于判定暴击
+// 伤害 = 攻击 × DefenseScale / (DefenseScale + 防御) × 暴击倍率 × (1 - 抗性)，非免疫时至少为 1
+func Resolve(a Attacker, d Defender, roll float64) Hit {
+	if a.Attack <= 0 {
+		return Hit{}
+	}
+	damageType := a.Type
+	if damageType == "" {
+		damageType = Physical
+	}
+	resist := min(d.Resistances[damageType], 1)
+	if resist >= 1 {
+		return Hit{Immune: true}
+	}
+
+	damage := a.Attack * DefenseScale / (DefenseScale + max(d.Defense, 0))
+	crit := roll < a.CritChance
+	if crit {
+		multiplier := a.CritMultiplier
+		if multiplier <= 1 {
+			multiplier = DefaultCritMultiplier
+		}
+		damage *= multiplier
+	}
+	damage *= 1 - resist
+	return Hit{Damage: max(int(math.Round(damage)), 1), Crit: crit}
+}
+
+// Periodic 结算一次持续伤害（中毒、燃烧等）：不计防御和暴击，只按抗性减免，非免疫时至少为 1
+func Periodic(amount float64, damageType DamageType, d Defender) Hit {
+	if amount <= 0 {
+		return Hit{}
+	}
+	if damageType == "" {
+		damageType = Physical
+	}
+	resist := min(d.Resistances[damageType], 1)
+	if resist >= 1 {
+		return Hit{Immune: true}
+	}
+	return Hit{Damage: max(int(math.Round(amount*(1-resist))), 1)}
+}
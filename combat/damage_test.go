@@ -0,0 +1,83 @@
+package combat
+
+import (
+	"testing"
+)
+
+func TestResolveDefense(t *testing.T) {
+	tests := []struct {
+		attack, defense float64
+		want            int
+	}{
+		{attack: 10, defense: 0, want: 10},
+		{attack: 10, defense: DefenseScale, want: 5}, // 防御等于系数时伤害减半
+		{attack: 12, defense: 4, want: 10},
+		{attack: 1, defense: 1000, want: 1}, // 至少造成 1 点伤害
+		{attack: 0, defense: 0, want: 0},    // 没有攻击力时不造成伤害
+		{attack: 10, defense: -5, want: 10}, // 负防御按 0 计算
+	}
+	for _, tt := range tests {
+		hit := Resolve(Attacker{Attack: tt.attack}, Defender{Defense: tt.defense}, 0.99)
+		if hit.Damage != tt.want {
+			t.Errorf("攻击 %v 防御 %v: 伤害 = %d; want %d", tt.attack, tt.defense, hit.Damage, tt.want)
+		}
+	}
+}
+
+func TestResolveCrit(t *testing.T) {
+	a := Attacker{Attack: 10, CritChance: 0.25}
+	if hit := Resolve(a, Defender{}, 0.1); !hit.Crit || hit.Damage != 15 {
+		t.Errorf("roll 低于暴击率应暴击并使用默认倍率: %+v", hit)
+	}
+	if hit := Resolve(a, Defender{}, 0.25); hit.Crit || hit.Damage != 10 {
+		t.Errorf("roll 不低于暴击率不应暴击: %+v", hit)
+	}
+	a.CritMultiplier = 3
+	if hit := Resolve(a, Defender{}, 0); hit.Damage != 30 {
+		t.Errorf("指定暴击倍率后伤害 = %d; want 30", hit.Damage)
+	}
+}
+
+func TestResolveResistances(t *testing.T) {
+	d := Defender{Resistances: map[DamageType]float64{
+		Physical: 0.5,
+		Fire:     1,
+		Ice:      -0.5,
+		Poison:   2,
+	}}
+	tests := []struct {
+		damageType DamageType
+		want       Hit
+	}{
+		{damageType: "", want: Hit{Damage: 10}}, // 未指定类型按物理计算
+		{damageType: Physical, want: Hit{Damage: 10}},
+		{damageType: Fire, want: Hit{Immune: true}},
+		{damageType: Poison, want: Hit{Immune: true}},
+		{damageType: Ice, want: Hit{Damage: 30}}, // 弱点增加伤害
+	}
+	for _, tt := range tests {
+		hit := Resolve(Attacker{Attack: 20, Type: tt.damageType}, d, 0.5)
+		if hit != tt.want {
+			t.Errorf("%q: %+v; want %+v", tt.damageType, hit, tt.want)
+		}
+	}
+}
+
+func TestPeriodic(t *testing.T) {
+	d := Defender{Defense: 100, Resistances: map[DamageType]float64{Poison: 1, Fire: -0.5, Physical: 0.9}}
+	tests := []struct {
+		amount     float64
+		damageType DamageType
+		want       Hit
+	}{
+		{amount: 4, damageType: Fire, want: Hit{Damage: 6}}, // 不计防御，弱点增加伤害
+		{amount: 4, damageType: Poison, want: Hit{Immune: true}},
+		{amount: 4, damageType: "", want: Hit{Damage: 1}}, // 至少 1 点
+		{amount: 0, damageType: Fire, want: Hit{}},
+	}
+	for _, tt := range tests {
+		if hit := Periodic(tt.amount, tt.damageType, d); hit != tt.want {
+			t.Errorf("Periodic(%v, %q) = %+v; want %+v", tt.amount, tt.damageType, hit, tt.want)
+		}
+	}
+}
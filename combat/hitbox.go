@@ -0,0 +1,69 @@
+package combat
+
+import (
+	"math"
+	"time"
+)
+
+// Box 轴对齐矩形
+type Box struct {
+	X, Y, W, H float64
+}
+
+// Overlaps 两个矩形是否相交（边缘相接不算）
+func (b Box) Overlaps(o Box) bool {
+	return b.X < o.X+o.W && o.X < b.X+b.W && b.Y < o.Y+o.H && o.Y < b.Y+b.H
+}
+
+// Center 返回矩形中心
+func (b Box) Center() (float64, float64) {
+	return b.X + b.W/2, b.Y + b.H/2
+}
+
+// Hitbox 返回攻击判定框：紧贴身体 body 朝 (dirX, dirY) 方向伸出 reach，横向宽度为 width
+// 方向只看符号，同时有水平和垂直分量时以水平方向为准（与角色朝向一致）
+func Hitbox(body Box, dirX, dirY float64, reach, width float64) Box {
+	cx, cy := body.Center()
+	switch {
+	case dirX < 0:
+		return Box{X: body.X - reach, Y: cy - width/2, W: reach, H: width}
+	case dirX > 0:
+		return Box{X: body.X + body.W, Y: cy - width/2, W: reach, H: width}
+	case dirY < 0:
+		return Box{X: cx - width/2, Y: body.Y - reach, W: width, H: reach}
+	default:
+		return Box{X: cx - width/2, Y: body.Y + body.H, W: width, H: reach}
+	}
+}
+
+// Knockback 返回把目标从 (fromX, fromY) 推向远离方向的速度，大小为 force
+// 两点重合时没有方向，返回 0
+func Knockback(fromX, fromY, toX, toY, force float64) (vx, vy float64) {
+	dx, dy := toX-fromX, toY-fromY
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return 0, 0
+	}
+	return dx / length * force, dy / length * force
+}
+
+// Invulnerability 受击后的无敌时间，期间不会再次受到伤害
+type Invulnerability struct {
+	Duration  time.Duration // 每次受击后的无敌时长
+	Remaining time.Duration // 剩余无敌时间
+}
+
+// Active 是否处于无敌状态
+func (i *Invulnerability) Active() bool {
+	return i.Remaining > 0
+}
+
+// Trigger 受击后开始无敌
+func (i *Invulnerability) Trigger() {
+	i.Remaining = i.Duration
+}
+
+// Update 推进无敌时间
+func (i *Invulnerability) Update(dt time.Duration) {
+	i.Remaining = max(i.Remaining-dt, 0)
+}
@@ -0,0 +1,67 @@
+package combat
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHitboxDirections(t *testing.T) {
+	body := Box{X: 100, Y: 100, W: 32, H: 32}
+	tests := []struct {
+		name       string
+		dirX, dirY float64
+		want       Box
+	}{
+		{name: "right", dirX: 1, want: Box{X: 132, Y: 106, W: 24, H: 20}},
+		{name: "left", dirX: -1, want: Box{X: 76, Y: 106, W: 24, H: 20}},
+		{name: "up", dirY: -1, want: Box{X: 106, Y: 76, W: 20, H: 24}},
+		{name: "down", dirY: 1, want: Box{X: 106, Y: 132, W: 20, H: 24}},
+		{name: "diagonal", dirX: -1, dirY: 1, want: Box{X: 76, Y: 106, W: 24, H: 20}},
+	}
+	for _, tt := range tests {
+		got := Hitbox(body, tt.dirX, tt.dirY, 24, 20)
+		if got != tt.want {
+			t.Errorf("%s: %+v; want %+v", tt.name, got, tt.want)
+		}
+		if got.Overlaps(body) {
+			t.Errorf("%s: 判定框不应与身体重叠", tt.name)
+		}
+	}
+}
+
+func TestBoxOverlaps(t *testing.T) {
+	a := Box{X: 0, Y: 0, W: 10, H: 10}
+	if !a.Overlaps(Box{X: 5, Y: 5, W: 10, H: 10}) {
+		t.Error("相交的矩形应判定为重叠")
+	}
+	if a.Overlaps(Box{X: 10, Y: 0, W: 10, H: 10}) {
+		t.Error("边缘相接不应判定为重叠")
+	}
+}
+
+func TestKnockback(t *testing.T) {
+	vx, vy := Knockback(0, 0, 3, 4, 10)
+	if math.Abs(vx-6) > 1e-9 || math.Abs(vy-8) > 1e-9 {
+		t.Errorf("击退速度 = (%v, %v); want (6, 8)", vx, vy)
+	}
+	if vx, vy := Knockback(1, 1, 1, 1, 10); vx != 0 || vy != 0 {
+		t.Errorf("重合时击退速度 = (%v, %v); want 0", vx, vy)
+	}
+}
+
+func TestInvulnerability(t *testing.T) {
+	i := Invulnerability{Duration: 500 * time.Millisecond}
+	if i.Active() {
+		t.Fatal("初始不应处于无敌状态")
+	}
+	i.Trigger()
+	i.Update(300 * time.Millisecond)
+	if !i.Active() {
+		t.Fatal("无敌时间未结束")
+	}
+	i.Update(300 * time.Millisecond)
+	if i.Active() || i.Remaining != 0 {
+		t.Fatalf("无敌时间应已结束: %+v", i)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"Game/inventory"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"math"
+	"slices"
+)
+
+// 容器面板布局（背包右侧）
+const (
+	containerX     = 560
+	containerY     = 100
+	containerW     = 230
+	containerH     = 400
+	containerReach = 2 // 玩家离开打开容器的位置超过几格时自动关闭
+)
+
+// containerView 打开的容器界面
+type containerView struct {
+	c        *Container
+	page     int
+	selected int     // 选中物品在 c.Items 中的下标
+	originX  float64 // 打开时玩家的位置
+	originY  float64
+}
+
+// containerGrid 容器的物品格子
+func containerGrid() itemGrid {
+	return itemGrid{x: containerX + 20, y: containerY + bagControlsH, cols: (containerW - 40) / (inventorySlot + inventorySpacing), rows: inventoryRows}
+}
+
+// containerTakeAllRect 全部取出按钮
+func containerTakeAllRect() [4]int {
+	return [4]int{containerX + 10, containerY + 50, 100, 20}
+}
+
+// containerDepositAllRect 全部存入按钮
+func containerDepositAllRect() [4]int {
+	return [4]int{containerX + 120, containerY + 50, 100, 20}
+}
+
+// containerPrevRect 上一页按钮
+func containerPrevRect() [4]int {
+	return [4]int{containerX + 20, containerY + containerH - 30, 60, 20}
+}
+
+// containerNextRect 下一页按钮
+func containerNextRect() [4]int {
+	return [4]int{containerX + containerW - 80, containerY + containerH - 30, 60, 20}
+}
+
+// openContainer 打开容器，同时打开背包，两边的物品可以互相转移
+func (p *PlayScreen) openContainer(id string) {
+	c, ok := p.containers[id]
+	if !ok {
+		log.Printf("容器 %q 不存在", id)
+		return
+	}
+	x, y := p.playerCenter()
+	p.container = &containerView{c: c, originX: x, originY: y}
+	p.setInventoryOpen(true)
+}
+
+// containerItemAt 返回 (x, y) 处的容器物品下标，没有时返回 -1
+func (p *PlayScreen) containerItemAt(x, y int) int {
+	g := containerGrid()
+	local := g.at(x, y)
+	if index := p.container.page*g.perPage() + local; local >= 0 && index < len(p.container.c.Items) {
+		return index
+	}
+	return -1
+}
+
+// deposit 把背包中的一组物品整组放进容器
+func (p *PlayScreen) deposit(it *item) bool {
+	if !p.container.c.add(&item{id: it.ItemData.id, count: it.count, charges: it.charges, ItemData: it.ItemData}) {
+		return false
+	}
+	p.removeItem(it)
+	return true
+}
+
+// withdraw 把容器中第 index 组物品整组放进背包
+func (p *PlayScreen) withdraw(index int) bool {
+	c := p.container.c
+	it := c.Items[index]
+	if !p.putItem(it) {
+		return false
+	}
+	c.Items = slices.Delete(c.Items, index, index+1)
+	if p.container.selected >= len(c.Items) {
+		p.container.selected = max(len(c.Items)-1, 0)
+	}
+	return true
+}
+
+// takeAll 把容器中的物品尽量全部放进背包，放不下的留在容器里
+func (p *PlayScreen) takeAll() {
+	full := false
+	for i := 0; i < len(p.container.c.Items); {
+		if !p.withdraw(i) {
+			full = true
+			i++
+		}
+	}
+	if full {
+		p.notices.Show(p.settings.T("notice.inventoryFull"))
+	}
+}
+
+// depositAll 把背包中的物品尽量全部放进容器，放不下的留在背包里
+func (p *PlayScreen) depositAll() {
+	full := false
+	for _, it := range slices.Clone(p.bag().Items) {
+		if !p.deposit(it) {
+			full = true
+		}
+	}
+	if full {
+		p.notices.Show(p.settings.T("notice.containerFull"))
+	}
+}
+
+// updateContainer 处理容器界面的输入：按住 Shift 点击背包或容器中的物品把整组转移到另一边，
+// 点击按钮全部取出或全部存入；玩家走开后关闭容器
+func (p *PlayScreen) updateContainer() {
+	v := p.container
+	x, y := p.playerCenter()
+	if math.Hypot(x-v.originX, y-v.originY) > float64(containerReach*p.gridSize) {
+		p.container = nil
+		return
+	}
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	cursorX, cursorY := ebiten.CursorPosition()
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	perPage := containerGrid().perPage()
+	switch {
+	case inRect(cursorX, cursorY, containerTakeAllRect()):
+		p.sound.PlaySFX(sfxClick)
+		p.takeAll()
+	case inRect(cursorX, cursorY, containerDepositAllRect()):
+		p.sound.PlaySFX(sfxClick)
+		p.depositAll()
+	case inRect(cursorX, cursorY, containerPrevRect()):
+		v.page = max(v.page-1, 0)
+	case inRect(cursorX, cursorY, containerNextRect()):
+		if (v.page+1)*perPage < len(v.c.Items) {
+			v.page++
+		}
+	}
+	if i := p.containerItemAt(cursorX, cursorY); i >= 0 {
+		v.selected = i
+		if shift {
+			if p.withdraw(i) {
+				p.sound.PlaySFX(sfxPickup)
+			} else {
+				p.notices.Show(p.settings.T("notice.inventoryFull"))
+			}
+		}
+	}
+	if i := p.bagItemAt(cursorX, cursorY); i >= 0 && shift {
+		if p.deposit(p.bag().Items[i]) {
+			p.sound.PlaySFX(sfxPickup)
+		} else {
+			p.notices.Show(p.settings.T("notice.containerFull"))
+		}
+	}
+	// 取出物品后当前页可能已经没有物品
+	v.page = min(v.page, inventory.PageCount(len(v.c.Items), perPage)-1)
+}
+
+// drawContainer 在背包右侧绘制容器面板
+func (p *PlayScreen) drawContainer(screen *ebiten.Image) {
+	v := p.container
+	p.drawInventoryBackground(screen, containerX, containerY, containerW, containerH)
+	drawCenteredText(screen, v.c.Def.Name, containerX, containerY+15, containerW)
+	vector.DrawFilledRect(screen, containerX+10, containerY+35, containerW-20, 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+
+	buttonColor := color.RGBA{R: 100, G: 100, B: 150, A: 255}
+	for _, b := range []struct {
+		rect  [4]int
+		label string
+	}{
+		{containerTakeAllRect(), p.settings.T("container.takeAll")},
+		{containerDepositAllRect(), p.settings.T("container.depositAll")},
+		{containerPrevRect(), "Prev"},
+		{containerNextRect(), "Next"},
+	} {
+		vector.DrawFilledRect(screen, float32(b.rect[0]), float32(b.rect[1]), float32(b.rect[2]), float32(b.rect[3]), buttonColor, false)
+		drawCenteredText(screen, b.label, b.rect[0], b.rect[1]+3, b.rect[2])
+	}
+	ebitenutil.DebugPrintAt(screen, p.settings.T("container.hint"), containerX+10, containerY+74)
+
+	view := make([]int, len(v.c.Items))
+	for i := range view {
+		view[i] = i
+	}
+	if i := p.drawItemGrid(screen, containerGrid(), v.c.Items, view, v.page, v.selected); i >= 0 {
+		p.bagUI.hovered = v.c.Items[i]
+	}
+
+	pages := inventory.PageCount(len(v.c.Items), containerGrid().perPage())
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Page %d/%d", v.page+1, pages), containerX+20, containerY+containerH-50)
+	capacity := fmt.Sprintf("Items: %d/%d", len(v.c.Items), v.c.Def.Size)
+	ebitenutil.DebugPrintAt(screen, capacity, containerX+containerW-len(capacity)*6-20, containerY+containerH-50)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/inventory"
+	"Game/worldmap"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+)
+
+// containersPath 容器数据文件
+const containersPath = "data/containers.json"
+
+// storageImage 储物箱图片
+const storageImage = "photos/storage.png"
+
+// ContainerDef 一个容器的定义：放在地图上的储物箱，或者不在地图上、通过 NPC 打开的共享容器（如银行）
+type ContainerDef struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Size  int               `json:"size"` // 格子数
+	Map   string            `json:"map"`  // 所在地图，为空表示共享容器，不放在地图上
+	Col   int               `json:"col"`  // 储物箱的位置（格子）
+	Row   int               `json:"row"`
+	Items []inventory.Stack `json:"items"` // 新游戏时的物品
+}
+
+// Container 容器中的物品；和背包一样，同种物品叠加在一个格子里
+type Container struct {
+	Def   *ContainerDef
+	Items []*item
+}
+
+// Storage 地图上的储物箱组件，按交互键打开对应的容器
+type Storage struct {
+	ID string // 容器 id
+}
+
+// loadContainerDefs 加载容器数据文件
+func loadContainerDefs(path string) ([]*ContainerDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []*ContainerDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// validate 检查容器定义：格子数、初始物品（货币放在钱包里，不能放进容器）和储物箱所在的地图和位置
+func (d *ContainerDef) validate(maps map[string]*worldmap.Map) error {
+	if d.ID == "" {
+		return fmt.Errorf("缺少 id")
+	}
+	if d.Size <= 0 {
+		return fmt.Errorf("%s: size 必须大于 0", d.ID)
+	}
+	stacks := map[int64]bool{}
+	for _, s := range d.Items {
+		switch {
+		case ItemImages[s.Item] == nil:
+			return fmt.Errorf("%s: 物品 %d 不存在", d.ID, s.Item)
+		case isCurrency(s.Item):
+			return fmt.Errorf("%s: 货币 %d 不能放进容器", d.ID, s.Item)
+		case s.Count <= 0:
+			return fmt.Errorf("%s: 物品 %d 的数量必须大于 0", d.ID, s.Item)
+		}
+		stacks[s.Item] = true
+	}
+	if len(stacks) > d.Size {
+		return fmt.Errorf("%s: 初始物品超过 %d 格", d.ID, d.Size)
+	}
+	if d.Map != "" {
+		if maps[d.Map] == nil {
+			return fmt.Errorf("%s: 地图 %q 不存在", d.ID, d.Map)
+		}
+		if maps[d.Map].Solid(d.Col, d.Row) {
+			return fmt.Errorf("%s: 位置 (%d, %d) 不在地图上或被挡住", d.ID, d.Col, d.Row)
+		}
+	}
+	return nil
+}
+
+// initContainers 加载并校验容器数据和储物箱图片，放入初始物品；数据有误时直接退出（需要在加载地图之后调用，储物箱在进入地图时放置）
+func (p *PlayScreen) initContainers() {
+	defs, err := loadContainerDefs(containersPath)
+	if err != nil {
+		log.Fatalf("加载容器数据失败: %v", err)
+	}
+	p.containers = map[string]*Container{}
+	for _, def := range defs {
+		if err := def.validate(p.atlas().Maps); err != nil {
+			log.Fatalf("容器数据有误 %s: %v", containersPath, err)
+		}
+		if p.containers[def.ID] != nil {
+			log.Fatalf("容器数据有误 %s: id %q 重复", containersPath, def.ID)
+		}
+		c := &Container{Def: def}
+		for _, s := range def.Items {
+			c.add(&item{id: s.Item, count: s.Count, ItemData: ItemImages[s.Item]})
+		}
+		p.containers[def.ID] = c
+	}
+	loadImage(storageImage)
+}
+
+// spawnStorage 在地图上放置储物箱
+func (p *PlayScreen) spawnStorage(def *ContainerDef) ecs.Entity {
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: float64(def.Col * p.gridSize), Y: float64(def.Row * p.gridSize)})
+	ecs.Add(p.world, e, ecs.Sprite{Image: loadImage(storageImage), Width: float64(p.gridSize), Height: float64(p.gridSize), Layer: layerItems})
+	ecs.Add(p.world, e, ecs.Collider{W: float64(p.gridSize), H: float64(p.gridSize), Solid: true})
+	ecs.Add(p.world, e, Storage{ID: def.ID})
+	return e
+}
+
+// storageInFront 查找玩家面前的储物箱
+func (p *PlayScreen) storageInFront() (string, bool) {
+	col, row := p.frontTile()
+	for _, e := range p.world.Query(ecs.MaskOf[Storage](p.world) | ecs.MaskOf[ecs.Position](p.world)) {
+		pos := ecs.Get[ecs.Position](p.world, e)
+		if int(pos.X)/p.gridSize == col && int(pos.Y)/p.gridSize == row {
+			return ecs.Get[Storage](p.world, e).ID, true
+		}
+	}
+	return "", false
+}
+
+// stackOf 返回容器中某种物品的那一组，没有时返回 nil
+func (c *Container) stackOf(itemID int64) *item {
+	for _, it := range c.Items {
+		if it.ItemData.id == itemID {
+			return it
+		}
+	}
+	return nil
+}
+
+// add 把一组物品放进容器：叠加到同种物品上，否则占用新格子；容器已满或叠加后数量溢出时返回 false
+func (c *Container) add(it *item) bool {
+	if existing := c.stackOf(it.ItemData.id); existing != nil {
+		if existing.count > math.MaxInt64-it.count {
+			return false
+		}
+		existing.count += it.count
+		return true
+	}
+	if len(c.Items) >= c.Def.Size {
+		return false
+	}
+	c.Items = append(c.Items, it)
+	return true
+}
+
+// saveContainers 按地图收集所有容器的物品（共享容器的地图为空字符串）；空容器也要保存，读档时才不会恢复初始物品
+func (p *PlayScreen) saveContainers() map[string]map[string][]savedItem {
+	saved := map[string]map[string][]savedItem{}
+	for id, c := range p.containers {
+		if saved[c.Def.Map] == nil {
+			saved[c.Def.Map] = map[string][]savedItem{}
+		}
+		items := []savedItem{}
+		for _, it := range c.Items {
+			items = append(items, savedItem{ID: it.ItemData.id, Count: it.count, Charges: it.charges})
+		}
+		saved[c.Def.Map][id] = items
+	}
+	return saved
+}
+
+// loadContainers 用存档替换容器中的物品；存档中没有的容器保留初始物品，已经不存在的容器被忽略
+func (p *PlayScreen) loadContainers(saved map[string]map[string][]savedItem) {
+	for mapID, containers := range saved {
+		for id, items := range containers {
+			c := p.containers[id]
+			if c == nil || c.Def.Map != mapID {
+				log.Printf("存档中的容器 %s/%s 已不存在，忽略", mapID, id)
+				continue
+			}
+			c.Items = nil
+			for _, s := range items {
+				if it := restoreItem(s); it != nil && !c.add(it) {
+					log.Printf("容器 %s 放不下存档中的物品 %d，忽略", id, s.ID)
+				}
+			}
+		}
+	}
+}
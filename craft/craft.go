@@ -0,0 +1,177 @@
+// Package craft 合成配方：输入物品和金币、输出物品、成功率和合成台要求
+// Book 检查材料、金币和合成台后通过 inventory.Inventory 原子地交换物品，成功率用的随机数由调用方传入
+package craft
+
+import (
+	"Game/inventory"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// 无法合成的原因
+var (
+	ErrUnknown       = errors.New("craft: 尚未学会配方")
+	ErrStation       = errors.New("craft: 需要在合成台旁边")
+	ErrMissing       = errors.New("craft: 材料不足")
+	ErrNotEnoughGold = errors.New("craft: 金币不足")
+)
+
+// Recipe 合成配方
+type Recipe struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Inputs       []inventory.Stack `json:"inputs"`
+	Gold         int64             `json:"gold"` // 每次合成花费的金币
+	Outputs      []inventory.Stack `json:"outputs"`
+	Chance       float64           `json:"chance"`       // 成功率（0~1），为 0 时按 1 计算
+	Station      string            `json:"station"`      // 需要靠近的合成台种类，为空表示随时可以合成
+	Unlock       bool              `json:"unlock"`       // 需要先学会（如使用配方卷轴）
+	FailConsumes bool              `json:"failConsumes"` // 失败时是否消耗材料（金币总是会消耗）
+}
+
+// SuccessChance 返回成功率
+func (r *Recipe) SuccessChance() float64 {
+	if r.Chance <= 0 {
+		return 1
+	}
+	return min(r.Chance, 1)
+}
+
+// Book 所有配方和合成使用的货币
+type Book struct {
+	Currency int64     `json:"currency"` // 用作金币的物品 id
+	Recipes  []*Recipe `json:"recipes"`
+}
+
+// Load 读取配方数据文件
+func Load(path string) (*Book, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var book Book
+	if err := json.Unmarshal(data, &book); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &book, nil
+}
+
+// Recipe 返回配方，不存在时返回 nil
+func (b *Book) Recipe(id string) *Recipe {
+	for _, r := range b.Recipes {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// Context 合成时玩家的状态
+type Context struct {
+	Inventory inventory.Inventory
+	Stations  map[string]bool          // 玩家附近的合成台种类
+	Known     func(recipe string) bool // 是否已学会配方，为空表示都没学会
+}
+
+// Check 检查配方当前能否合成，可以时返回 nil
+func (b *Book) Check(r *Recipe, ctx Context) error {
+	if r.Unlock && (ctx.Known == nil || !ctx.Known(r.ID)) {
+		return ErrUnknown
+	}
+	if r.Station != "" && !ctx.Stations[r.Station] {
+		return ErrStation
+	}
+	for _, in := range r.Inputs {
+		if ctx.Inventory.CountItem(in.Item) < in.Count {
+			return ErrMissing
+		}
+	}
+	if ctx.Inventory.CountItem(b.Currency) < r.Gold {
+		return ErrNotEnoughGold
+	}
+	return nil
+}
+
+// Craft 合成一次：roll 为 [0, 1) 的随机数，小于成功率时成功
+// 成功时一次性取走材料和金币并放入产物；失败时只取走金币（FailConsumes 时连同材料）
+// 背包放不下产物时返回错误，背包不做任何修改
+func (b *Book) Craft(r *Recipe, ctx Context, roll float64) (success bool, err error) {
+	if err := b.Check(r, ctx); err != nil {
+		return false, err
+	}
+	gold := inventory.Stack{Item: b.Currency, Count: r.Gold}
+	if roll < r.SuccessChance() {
+		return true, ctx.Inventory.Exchange(append(slices.Clone(r.Inputs), gold), r.Outputs)
+	}
+	if r.FailConsumes {
+		return false, ctx.Inventory.Exchange(append(slices.Clone(r.Inputs), gold), nil)
+	}
+	return false, ctx.Inventory.Exchange([]inventory.Stack{gold}, nil)
+}
+
+// Catalog 校验时使用的物品和合成台目录，字段为空时不检查对应的引用
+type Catalog struct {
+	Item    func(id int64) bool
+	Station func(kind string) bool
+}
+
+// Validate 检查配方数据，返回所有发现的问题
+func Validate(b *Book, catalog Catalog) []error {
+	var errs []error
+	if catalog.Item != nil && !catalog.Item(b.Currency) {
+		errs = append(errs, fmt.Errorf("货币物品 %d 不存在", b.Currency))
+	}
+	seen := map[string]bool{}
+	for _, r := range b.Recipes {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("配方 %s: "+format, append([]any{r.ID}, args...)...))
+		}
+		if r.ID == "" {
+			fail("缺少 id")
+		}
+		if seen[r.ID] {
+			fail("id 重复")
+		}
+		seen[r.ID] = true
+		if r.Name == "" {
+			fail("缺少 name")
+		}
+		if len(r.Inputs) == 0 && r.Gold == 0 {
+			fail("没有材料")
+		}
+		if len(r.Outputs) == 0 {
+			fail("没有产物")
+		}
+		if r.Gold < 0 {
+			fail("金币不能为负数")
+		}
+		if r.Chance < 0 || r.Chance > 1 {
+			fail("成功率 %v 超出范围 0~1", r.Chance)
+		}
+		for _, list := range [][]inventory.Stack{r.Inputs, r.Outputs} {
+			items := map[int64]bool{}
+			for _, s := range list {
+				if items[s.Item] {
+					fail("物品 %d 重复", s.Item)
+				}
+				items[s.Item] = true
+				if s.Count <= 0 {
+					fail("物品 %d 的数量必须大于 0", s.Item)
+				}
+				if catalog.Item != nil && !catalog.Item(s.Item) {
+					fail("物品 %d 不存在", s.Item)
+				}
+				if s.Item == b.Currency {
+					fail("金币请使用 gold 字段")
+				}
+			}
+		}
+		if r.Station != "" && catalog.Station != nil && !catalog.Station(r.Station) {
+			fail("合成台 %q 不存在", r.Station)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,133 @@
+package craft
+
+import (
+	"Game/inventory"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const gold = 1001
+
+func newBook() *Book {
+	return &Book{Currency: gold, Recipes: []*Recipe{
+		{ID: "upgrade", Name: "Upgrade", Inputs: []inventory.Stack{{Item: 1, Count: 1}}, Gold: 100,
+			Outputs: []inventory.Stack{{Item: 2, Count: 1}}, Chance: 0.5, Station: "anvil", Unlock: true},
+		{ID: "brew", Name: "Brew", Inputs: []inventory.Stack{{Item: 3, Count: 2}},
+			Outputs: []inventory.Stack{{Item: 4, Count: 1}}, Chance: 0.5, FailConsumes: true},
+	}}
+}
+
+func TestCheck(t *testing.T) {
+	b := newBook()
+	r := b.Recipe("upgrade")
+	inv := &inventory.Counts{Items: map[int64]int64{1: 1, gold: 50}, Capacity: 5}
+	ctx := Context{Inventory: inv}
+	if err := b.Check(r, ctx); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("未学会 err = %v", err)
+	}
+	ctx.Known = func(id string) bool { return id == "upgrade" }
+	if err := b.Check(r, ctx); !errors.Is(err, ErrStation) {
+		t.Fatalf("不在合成台旁 err = %v", err)
+	}
+	ctx.Stations = map[string]bool{"anvil": true}
+	if err := b.Check(r, ctx); !errors.Is(err, ErrNotEnoughGold) {
+		t.Fatalf("金币不足 err = %v", err)
+	}
+	inv.Items[gold] = 100
+	if err := b.Check(r, ctx); err != nil {
+		t.Fatalf("可以合成时 err = %v", err)
+	}
+	inv.Items[1] = 0
+	if err := b.Check(r, ctx); !errors.Is(err, ErrMissing) {
+		t.Fatalf("材料不足 err = %v", err)
+	}
+}
+
+func TestCraft(t *testing.T) {
+	b := newBook()
+	r := b.Recipe("upgrade")
+	inv := &inventory.Counts{Items: map[int64]int64{1: 2, gold: 300}, Capacity: 5}
+	ctx := Context{Inventory: inv, Stations: map[string]bool{"anvil": true}, Known: func(string) bool { return true }}
+
+	// 失败：只扣金币，材料保留
+	if ok, err := b.Craft(r, ctx, 0.9); ok || err != nil {
+		t.Fatalf("Craft = %v, %v", ok, err)
+	}
+	if inv.Items[gold] != 200 || inv.Items[1] != 2 || inv.Items[2] != 0 {
+		t.Fatalf("失败后背包 = %v", inv.Items)
+	}
+	// 成功：扣材料和金币，得到产物
+	if ok, err := b.Craft(r, ctx, 0.1); !ok || err != nil {
+		t.Fatalf("Craft = %v, %v", ok, err)
+	}
+	if inv.Items[gold] != 100 || inv.Items[1] != 1 || inv.Items[2] != 1 {
+		t.Fatalf("成功后背包 = %v", inv.Items)
+	}
+}
+
+func TestCraftFailConsumes(t *testing.T) {
+	b := newBook()
+	inv := &inventory.Counts{Items: map[int64]int64{3: 2}, Capacity: 5}
+	if ok, err := b.Craft(b.Recipe("brew"), Context{Inventory: inv}, 0.7); ok || err != nil {
+		t.Fatalf("Craft = %v, %v", ok, err)
+	}
+	if inv.Items[3] != 0 || inv.Items[4] != 0 {
+		t.Fatalf("失败后材料没有被消耗: %v", inv.Items)
+	}
+}
+
+// TestCraftBagFull 背包放不下产物时不做任何修改
+func TestCraftBagFull(t *testing.T) {
+	b := newBook()
+	r := b.Recipe("upgrade")
+	inv := &inventory.Counts{Items: map[int64]int64{1: 2, gold: 500}, Capacity: 2}
+	ctx := Context{Inventory: inv, Stations: map[string]bool{"anvil": true}, Known: func(string) bool { return true }}
+	if _, err := b.Craft(r, ctx, 0); !errors.Is(err, inventory.ErrFull) {
+		t.Fatalf("err = %v, 期望背包已满", err)
+	}
+	if inv.Items[1] != 2 || inv.Items[gold] != 500 {
+		t.Fatalf("失败的合成修改了背包: %v", inv.Items)
+	}
+	// 用掉最后一个材料会空出格子
+	inv.Items[1] = 1
+	if ok, err := b.Craft(r, ctx, 0); !ok || err != nil {
+		t.Fatalf("空出格子后 Craft = %v, %v", ok, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	b := &Book{Currency: gold, Recipes: []*Recipe{
+		{ID: "a", Name: "A", Inputs: []inventory.Stack{{Item: 9, Count: 1}, {Item: 1, Count: 0}, {Item: gold, Count: 1}},
+			Outputs: []inventory.Stack{{Item: 1, Count: 1}, {Item: 1, Count: 1}}, Chance: 2, Station: "forge"},
+		{ID: "a"},
+	}}
+	catalog := Catalog{
+		Item:    func(id int64) bool { return id != 9 },
+		Station: func(kind string) bool { return kind == "anvil" },
+	}
+	var msgs []string
+	for _, err := range Validate(b, catalog) {
+		msgs = append(msgs, err.Error())
+	}
+	all := strings.Join(msgs, "\n")
+	for _, want := range []string{"物品 9 不存在", "数量必须大于 0", "gold 字段", "物品 1 重复", "成功率", "forge", "id 重复", "缺少 name", "没有材料", "没有产物"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("缺少错误 %q:\n%s", want, all)
+		}
+	}
+}
+
+// TestShippedRecipes 校验游戏自带的配方数据
+func TestShippedRecipes(t *testing.T) {
+	b, err := Load("../data/recipes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range Validate(b, Catalog{}) {
+		t.Error(err)
+	}
+	if b.Recipe("sword1") == nil {
+		t.Error("缺少新手剑升级配方 sword1")
+	}
+}
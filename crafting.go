@@ -0,0 +1,308 @@
+package main
+
+import (
+	"Game/craft"
+	"Game/ecs"
+	"Game/inventory"
+	"Game/wallet"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"math"
+)
+
+// recipesPath 配方数据文件
+const recipesPath = "data/recipes.json"
+
+// stationImages 合成台种类及其图片
+var stationImages = map[string]string{
+	"anvil": "photos/anvil.png",
+}
+
+// 合成界面布局
+const (
+	craftX     = 120
+	craftY     = 80
+	craftW     = 560
+	craftH     = 440
+	craftListW = 200 // 左侧配方列表宽度
+	craftRowH  = 20
+)
+
+// craftButtonRect 合成按钮
+var craftButtonRect = [4]int{craftX + craftW - 140, craftY + craftH - 44, 120, 28}
+
+// Station 合成台组件，玩家站在旁边时可以合成需要该合成台的配方
+type Station struct {
+	Kind string
+}
+
+// spawnStation 在网格坐标 (col, row) 放置合成台
+func (p *PlayScreen) spawnStation(kind string, col, row int) ecs.Entity {
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: float64(col * p.gridSize), Y: float64(row * p.gridSize)})
+	ecs.Add(p.world, e, ecs.Sprite{Image: loadImage(stationImages[kind]), Width: float64(p.gridSize), Height: float64(p.gridSize), Layer: layerCharacters})
+	ecs.Add(p.world, e, ecs.Collider{W: float64(p.gridSize), H: float64(p.gridSize), Solid: true})
+	ecs.Add(p.world, e, Station{Kind: kind})
+	return e
+}
+
+// initCrafting 加载并校验配方数据和合成台图片，数据有误时直接退出
+func (p *PlayScreen) initCrafting() {
+	book, err := craft.Load(recipesPath)
+	if err != nil {
+		log.Fatalf("加载配方数据失败: %v", err)
+	}
+	catalog := craft.Catalog{
+		Item:    func(id int64) bool { return ItemImages[id] != nil },
+		Station: func(kind string) bool { return stationImages[kind] != "" },
+	}
+	errs := craft.Validate(book, catalog)
+	// 配方卷轴学会的配方必须存在
+	for _, data := range ItemImages {
+		for _, effect := range data.Effects {
+			if e, ok := effect.(*unlockRecipeEffect); ok && book.Recipe(e.Recipe) == nil {
+				errs = append(errs, fmt.Errorf("物品 %d (%s): 配方 %q 不存在", data.id, data.Name, e.Recipe))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("配方数据有误: %s", recipesPath)
+	}
+	for _, path := range stationImages {
+		loadImage(path)
+	}
+	p.recipes = book
+}
+
+// craftContext 返回玩家当前的合成条件：背包、附近的合成台和已学会的配方
+func (p *PlayScreen) craftContext() craft.Context {
+	stations := map[string]bool{}
+	cx, cy := p.playerCenter()
+	col, row := int(cx)/p.gridSize, int(cy)/p.gridSize
+	ecs.Each2(p.world, func(e ecs.Entity, station *Station, pos *ecs.Position) {
+		sc, sr := int(pos.X)/p.gridSize, int(pos.Y)/p.gridSize
+		if max(abs(sc-col), abs(sr-row)) <= 1 {
+			stations[station.Kind] = true
+		}
+	})
+	return craft.Context{
+		Inventory: p,
+		Stations:  stations,
+		Known:     func(id string) bool { return p.story().Recipes[id] },
+	}
+}
+
+// knownRecipes 按数据文件中的顺序返回玩家已经会的配方
+func (p *PlayScreen) knownRecipes() []*craft.Recipe {
+	var known []*craft.Recipe
+	for _, r := range p.recipes.Recipes {
+		if !r.Unlock || p.story().Recipes[r.ID] {
+			known = append(known, r)
+		}
+	}
+	return known
+}
+
+// setCraftingOpen 打开或关闭合成界面
+func (p *PlayScreen) setCraftingOpen(open bool) {
+	if open == p.craftOpen {
+		return
+	}
+	p.craftOpen = open
+	p.craftIndex = 0
+	if open {
+		p.setQuestLogOpen(false)
+		p.sound.PlaySFX(sfxInventoryOpen)
+	} else {
+		p.sound.PlaySFX(sfxInventoryClose)
+	}
+}
+
+// stationInFront 面前是否有合成台
+func (p *PlayScreen) stationInFront() bool {
+	col, row := p.frontTile()
+	found := false
+	ecs.Each2(p.world, func(e ecs.Entity, station *Station, pos *ecs.Position) {
+		if int(pos.X)/p.gridSize == col && int(pos.Y)/p.gridSize == row {
+			found = true
+		}
+	})
+	return found
+}
+
+// updateCrafting 处理合成界面的输入；合成界面打开期间游戏世界暂停
+func (p *PlayScreen) updateCrafting() {
+	if p.settings.isActionJustPressed(ActionPause) || p.settings.isActionJustPressed(ActionCraft) {
+		p.setCraftingOpen(false)
+		return
+	}
+	recipes := p.knownRecipes()
+	if len(recipes) == 0 {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || p.settings.isActionJustPressed(ActionMoveUp) {
+		p.craftIndex = (p.craftIndex - 1 + len(recipes)) % len(recipes)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || p.settings.isActionJustPressed(ActionMoveDown) {
+		p.craftIndex = (p.craftIndex + 1) % len(recipes)
+	}
+	x, y := ebiten.CursorPosition()
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	for i := range recipes {
+		if clicked && inRect(x, y, craftRowRect(i)) {
+			p.craftIndex = i
+		}
+	}
+	p.craftIndex = min(p.craftIndex, len(recipes)-1)
+
+	if p.settings.isActionJustPressed(ActionInteract) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		(clicked && inRect(x, y, craftButtonRect)) {
+		p.craftRecipe(recipes[p.craftIndex])
+	}
+}
+
+// craftRecipe 合成一次并提示结果
+func (p *PlayScreen) craftRecipe(r *craft.Recipe) {
+	defer p.walletTx("craft")()
+	success, err := p.recipes.Craft(r, p.craftContext(), p.rng.Float64())
+	switch {
+	case err != nil:
+		p.notices.Show(p.craftErrorText(r, err))
+	case success:
+		p.notices.Show(fmt.Sprintf(p.settings.T("craft.success"), ItemImages[r.Outputs[0].Item].Name))
+		p.sound.PlaySFX(sfxPickup)
+	default:
+		p.notices.Show(p.settings.T("craft.failed"))
+		p.sound.PlaySFX(sfxHit)
+	}
+}
+
+// craftErrorText 返回无法合成的原因
+func (p *PlayScreen) craftErrorText(r *craft.Recipe, err error) string {
+	switch {
+	case errors.Is(err, craft.ErrStation):
+		return fmt.Sprintf(p.settings.T("craft.needStation"), p.settings.T("station."+r.Station))
+	case errors.Is(err, craft.ErrMissing):
+		return p.settings.T("craft.missing")
+	case errors.Is(err, craft.ErrNotEnoughGold):
+		return p.settings.T("shop.notEnoughGold")
+	case errors.Is(err, inventory.ErrFull), errors.Is(err, inventory.ErrOverflow):
+		return p.settings.T("notice.inventoryFull")
+	case errors.Is(err, wallet.ErrOverflow):
+		return p.settings.T("notice.walletFull")
+	}
+	return p.settings.T("craft.unknown")
+}
+
+// craftRowRect 左侧配方列表第 index 行的点击区域
+func craftRowRect(index int) [4]int {
+	return [4]int{craftX + 10, craftY + 40 + index*craftRowH, craftListW - 20, craftRowH - 2}
+}
+
+// drawCrafting 绘制合成界面：左侧配方列表（能合成的标为绿色），右侧材料、产物、成功率和合成台要求
+func (p *PlayScreen) drawCrafting(screen *ebiten.Image) {
+	drawPanel(screen, craftX, craftY, craftW, craftH)
+	drawCenteredText(screen, p.settings.T("craft.title"), craftX, craftY+12, craftW)
+	vector.DrawFilledRect(screen, craftX+craftListW, craftY+36, 1, craftH-50, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+
+	recipes := p.knownRecipes()
+	if len(recipes) == 0 {
+		ebitenutil.DebugPrintAt(screen, p.settings.T("craft.none"), craftX+16, craftY+44)
+		return
+	}
+	ctx := p.craftContext()
+	green := color.RGBA{R: 100, G: 220, B: 100, A: 255}
+	red := color.RGBA{R: 220, G: 80, B: 80, A: 255}
+	for i, r := range recipes {
+		rect := craftRowRect(i)
+		if i == p.craftIndex {
+			vector.DrawFilledRect(screen, float32(rect[0]), float32(rect[1]), float32(rect[2]), float32(rect[3]), color.RGBA{R: 100, G: 150, B: 255, A: 80}, false)
+		}
+		clr := red
+		if p.recipes.Check(r, ctx) == nil {
+			clr = green
+		}
+		vector.DrawFilledRect(screen, float32(rect[0]+4), float32(rect[1]+5), 8, 8, clr, false)
+		ebitenutil.DebugPrintAt(screen, r.Name, rect[0]+18, rect[1]+1)
+	}
+
+	r := recipes[p.craftIndex]
+	x := craftX + craftListW + 20
+	y := craftY + 40
+	ebitenutil.DebugPrintAt(screen, r.Name, x, y)
+	y += 28
+
+	// 材料：拥有数量/需要数量
+	ebitenutil.DebugPrintAt(screen, p.settings.T("craft.inputs"), x, y)
+	y += 20
+	for _, in := range r.Inputs {
+		have := p.CountItem(in.Item)
+		p.drawCraftLine(screen, x+8, y, ItemImages[in.Item], fmt.Sprintf("%s %d/%d", ItemImages[in.Item].Name, have, in.Count), have >= in.Count)
+		y += 22
+	}
+	if r.Gold > 0 {
+		have := p.CountItem(p.recipes.Currency)
+		gold := ItemImages[p.recipes.Currency]
+		p.drawCraftLine(screen, x+8, y, gold, fmt.Sprintf("%s %d/%d", gold.Name, have, r.Gold), have >= r.Gold)
+		y += 22
+	}
+
+	y += 10
+	ebitenutil.DebugPrintAt(screen, p.settings.T("craft.outputs"), x, y)
+	y += 20
+	for _, out := range r.Outputs {
+		drawItemIcon(screen, ItemImages[out.Item], x+8, y, 18)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s x%d", ItemImages[out.Item].Name, out.Count), x+32, y+2)
+		y += 22
+	}
+
+	y += 10
+	chance := fmt.Sprintf(p.settings.T("craft.chance"), int(math.Round(r.SuccessChance()*100)))
+	if r.SuccessChance() < 1 && r.FailConsumes {
+		chance += " " + p.settings.T("craft.failConsumes")
+	}
+	ebitenutil.DebugPrintAt(screen, chance, x, y)
+	y += 20
+	if r.Station != "" {
+		station := fmt.Sprintf(p.settings.T("craft.station"), p.settings.T("station."+r.Station))
+		p.drawCraftLine(screen, x, y, nil, station, ctx.Stations[r.Station])
+	}
+
+	if err := p.recipes.Check(r, ctx); err != nil {
+		ebitenutil.DebugPrintAt(screen, p.craftErrorText(r, err), x, craftY+craftH-80)
+	}
+	ebitenutil.DebugPrintAt(screen, p.settings.T("craft.hint"), craftX+16, craftY+craftH-38)
+	drawButton(screen, craftButtonRect, p.settings.T("craft.button"))
+}
+
+// drawCraftLine 绘制一行条件：可选的图标、文字和表示是否满足的色块
+func (p *PlayScreen) drawCraftLine(screen *ebiten.Image, x, y int, data *ItemData, text string, ok bool) {
+	clr := color.RGBA{R: 220, G: 80, B: 80, A: 255}
+	if ok {
+		clr = color.RGBA{R: 100, G: 220, B: 100, A: 255}
+	}
+	vector.DrawFilledRect(screen, float32(x), float32(y+5), 6, 6, clr, false)
+	textX := x + 12
+	if data != nil {
+		drawItemIcon(screen, data, textX, y, 18)
+		textX += 24
+	}
+	ebitenutil.DebugPrintAt(screen, text, textX, y+2)
+}
+
+// abs 返回整数的绝对值
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/inventory"
+	"Game/wallet"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"slices"
+)
+
+// walletLogSize 交易日志保留的条数
+const walletLogSize = 100
+
+// 屏幕左下角的钱包显示
+const (
+	walletHUDX   = 10
+	walletHUDY   = screenHeight - 34
+	walletHUDW   = 150
+	walletHUDH   = 24
+	walletHUDLog = 8 // 鼠标悬停时显示的最近交易条数
+)
+
+// isCurrency 物品是否是货币；货币放在钱包里，不占背包格子
+func isCurrency(itemID int64) bool {
+	data := ItemImages[itemID]
+	return data != nil && data.Category == inventory.CategoryCurrency
+}
+
+// currencies 返回所有货币的物品 id（按 id 排序）
+func currencies() []int64 {
+	var ids []int64
+	for id := range ItemImages {
+		if isCurrency(id) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Purse 玩家的钱包组件，货币不占背包格子
+type Purse struct {
+	Wallet *wallet.Wallet
+	Reason string // 接下来钱包交易记录的原因，见 walletTx
+}
+
+// purse 返回玩家的钱包组件
+func (p *PlayScreen) purse() *Purse {
+	return ecs.Get[Purse](p.world, p.player)
+}
+
+// walletTx 设置接下来钱包交易记录的原因，返回恢复原来原因的函数，用法：defer p.walletTx("shop")()
+func (p *PlayScreen) walletTx(reason string) func() {
+	old := p.purse().Reason
+	p.purse().Reason = reason
+	return func() { p.purse().Reason = old }
+}
+
+// walletTxReason 返回当前的交易原因，没有设置时为 "other"
+func (p *PlayScreen) walletTxReason() string {
+	if p.purse().Reason == "" {
+		return "other"
+	}
+	return p.purse().Reason
+}
+
+// addCurrency 把货币放进钱包，余额超出上限时返回 false
+func (p *PlayScreen) addCurrency(itemID, count int64) bool {
+	if err := p.purse().Wallet.Add(itemID, count, p.walletTxReason()); err != nil {
+		return false
+	}
+	p.inventoryChanged()
+	return true
+}
+
+// spendCurrency 从钱包中扣除货币，余额不足时返回 false
+func (p *PlayScreen) spendCurrency(itemID, count int64) bool {
+	if err := p.purse().Wallet.Spend(itemID, count, p.walletTxReason()); err != nil {
+		return false
+	}
+	p.inventoryChanged()
+	return true
+}
+
+// splitCurrency 把物品组分成钱包的变化量和背包中的物品组；sign 为 -1 时表示取走
+func splitCurrency(stacks []inventory.Stack, sign int64, coins map[int64]int64) []inventory.Stack {
+	var items []inventory.Stack
+	for _, s := range stacks {
+		if isCurrency(s.Item) {
+			coins[s.Item] += sign * s.Count
+		} else {
+			items = append(items, s)
+		}
+	}
+	return items
+}
+
+// drawWallet 在屏幕左下角显示金币余额；鼠标悬停时显示最近的交易记录（用于排查金币的来源和去向）
+func (p *PlayScreen) drawWallet(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, walletHUDX, walletHUDY, walletHUDW, walletHUDH, color.RGBA{A: 160}, false)
+	x := walletHUDX + 4
+	for _, id := range currencies() {
+		data := ItemImages[id]
+		drawItemIcon(screen, data, x, walletHUDY+2, 20)
+		text := fmt.Sprintf("%d", p.purse().Wallet.Balance(id))
+		drawColoredText(screen, text, x+24, walletHUDY+4, tooltipGold)
+		x += 24 + len(text)*6 + 12
+	}
+
+	// 有界面打开时不显示交易记录，避免和界面重叠
+	if p.bagUI.loaded || p.paused || p.dialog != nil || p.trade != nil || p.craftOpen || p.skillsOpen || p.questLogOpen {
+		return
+	}
+	cursorX, cursorY := ebiten.CursorPosition()
+	if !inRect(cursorX, cursorY, [4]int{walletHUDX, walletHUDY, walletHUDW, walletHUDH}) {
+		return
+	}
+	lines := []tooltipLine{{text: p.settings.T("wallet.log"), clr: tooltipGray}}
+	log := p.purse().Wallet.Log()
+	if len(log) == 0 {
+		lines = append(lines, tooltipLine{text: p.settings.T("wallet.noLog")})
+	}
+	for _, t := range log[max(len(log)-walletHUDLog, 0):] {
+		line := tooltipLine{
+			text:    fmt.Sprintf("#%d %+d %s", t.Seq, t.Amount, ItemImages[t.Currency].Name),
+			clr:     tooltipGreen,
+			note:    fmt.Sprintf("%s -> %d", t.Reason, t.Balance),
+			noteClr: tooltipGray,
+		}
+		if t.Amount < 0 {
+			line.clr = tooltipRed
+		}
+		lines = append(lines, line)
+	}
+	w := 0
+	for _, line := range lines {
+		w = max(w, len(line.text)*6+len(line.note)*6+6)
+	}
+	w += 16
+	h := len(lines)*16 + 12
+	tx, ty := tooltipPosition(cursorX, cursorY, w, h, screen.Bounds().Dx(), screen.Bounds().Dy())
+	vector.DrawFilledRect(screen, float32(tx), float32(ty), float32(w), float32(h), color.RGBA{R: 10, G: 10, B: 20, A: 235}, false)
+	drawTooltipLines(screen, lines, tx+8, ty+6)
+}
+
+// fullNoticeKey 物品放不下时的提示文字键：货币是钱包已满，其他物品是背包已满
+func fullNoticeKey(itemID int64) string {
+	if isCurrency(itemID) {
+		return "notice.walletFull"
+	}
+	return "notice.inventoryFull"
+}
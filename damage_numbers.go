@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"image/color"
+	"time"
+)
+
+const (
+	damageNumberLife  = 800 * time.Millisecond // 伤害数字的显示时长
+	damageNumberRise  = 40.0                   // 伤害数字上升速度（像素/秒）
+	damageNumberFades = 300 * time.Millisecond // 最后一段时间逐渐淡出
+)
+
+// damageNumber 一个飘动的伤害数字
+type damageNumber struct {
+	image     *ebiten.Image // 预先绘制好的文字
+	x, y      float64       // 中心位置
+	clr       color.Color
+	scale     float64
+	remaining time.Duration
+}
+
+// DamageNumbers 受击时在实体上方飘出的数字
+type DamageNumbers struct {
+	list []damageNumber
+}
+
+// Add 在 (x, y) 处添加一个数字（或 "Immune" 等文字）
+func (d *DamageNumbers) Add(text string, x, y float64, clr color.Color, scale float64) {
+	// DebugPrint 只能画白色文字，先画到小图片上，绘制时再着色
+	image := ebiten.NewImage(len(text)*6+2, 16)
+	ebitenutil.DebugPrint(image, text)
+	d.list = append(d.list, damageNumber{image: image, x: x, y: y, clr: clr, scale: scale, remaining: damageNumberLife})
+}
+
+// Update 让数字上升，移除过期的数字
+func (d *DamageNumbers) Update(dt time.Duration) {
+	remaining := d.list[:0]
+	for _, n := range d.list {
+		n.remaining -= dt
+		n.y -= damageNumberRise * dt.Seconds()
+		if n.remaining > 0 {
+			remaining = append(remaining, n)
+		} else {
+			n.image.Deallocate()
+		}
+	}
+	d.list = remaining
+}
+
+// Draw 绘制所有数字
+func (d *DamageNumbers) Draw(screen *ebiten.Image) {
+	for _, n := range d.list {
+		alpha := 1.0
+		if n.remaining < damageNumberFades {
+			alpha = n.remaining.Seconds() / damageNumberFades.Seconds()
+		}
+		w, h := n.image.Bounds().Dx(), n.image.Bounds().Dy()
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-float64(w)/2, -float64(h)/2)
+		op.GeoM.Scale(n.scale, n.scale)
+		op.GeoM.Translate(n.x, n.y)
+		op.ColorScale.ScaleWithColor(n.clr)
+		op.ColorScale.ScaleAlpha(float32(alpha))
+		screen.DrawImage(n.image, op)
+	}
+}
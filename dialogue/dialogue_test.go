@@ -0,0 +1,192 @@
+package dialogue
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeState 测试用的游戏状态
+type fakeState struct {
+	items  map[int64]int64
+	flags  map[string]bool
+	quests map[string]string
+}
+
+func (s *fakeState) HasItem(id, count int64) bool { return s.items[id] >= count }
+func (s *fakeState) Flag(name string) bool        { return s.flags[name] }
+func (s *fakeState) QuestState(id string) string  { return s.quests[id] }
+
+func newFakeState() *fakeState {
+	return &fakeState{items: map[int64]int64{}, flags: map[string]bool{}, quests: map[string]string{}}
+}
+
+const testTree = `{
+  "id": "test",
+  "start": "a",
+  "nodes": {
+    "a": {"speaker": "NPC", "text": "Hello", "actions": [{"type": "set_flag", "flag": "met"}], "next": "b"},
+    "b": {"text": "What now?", "choices": [
+      {"text": "Quest", "next": "c", "if": [{"type": "quest", "quest": "q", "state": ""}],
+       "actions": [{"type": "start_quest", "quest": "q"}]},
+      {"text": "Gold", "next": "c", "if": [{"type": "has_item", "item": 1001, "count": 10}]},
+      {"text": "Secret", "if": [{"type": "flag", "flag": "secret", "not": true}]},
+      {"text": "Bye"}
+    ]},
+    "c": {"text": "Done"}
+  }
+}`
+
+func mustParse(t *testing.T, data string) *Tree {
+	t.Helper()
+	tree, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func choiceTexts(choices []Choice) string {
+	var texts []string
+	for _, c := range choices {
+		texts = append(texts, c.Text)
+	}
+	return strings.Join(texts, ",")
+}
+
+func TestRunnerFlow(t *testing.T) {
+	state := newFakeState()
+	r := NewRunner(mustParse(t, testTree), state)
+	if actions := r.TakeActions(); len(actions) != 1 || actions[0].Type != ActSetFlag {
+		t.Fatalf("进入起点应产生 set_flag 动作: %+v", actions)
+	}
+
+	// 文字没显示完时，第一次继续只显示全部文字
+	r.Update(50 * time.Millisecond)
+	if r.Typewriter.Done() {
+		t.Fatal("50ms 内不应显示完全部文字")
+	}
+	r.Advance()
+	if r.NodeID() != "a" || !r.Typewriter.Done() {
+		t.Fatalf("应停留在节点 a 并显示全部文字, node = %s", r.NodeID())
+	}
+	r.Advance()
+	if r.NodeID() != "b" {
+		t.Fatalf("node = %s; want b", r.NodeID())
+	}
+	if choices := r.Choices(); choices != nil {
+		t.Fatalf("文字显示完之前不应显示选项: %v", choiceTexts(choices))
+	}
+	r.Typewriter.Finish()
+	if got := choiceTexts(r.Choices()); got != "Quest,Secret,Bye" {
+		t.Fatalf("选项 = %s; want Quest,Secret,Bye", got)
+	}
+
+	// 有选项时继续不会离开节点
+	r.Advance()
+	if r.NodeID() != "b" {
+		t.Fatalf("有选项时继续不应离开节点, node = %s", r.NodeID())
+	}
+	r.Choose(0)
+	if r.NodeID() != "c" {
+		t.Fatalf("node = %s; want c", r.NodeID())
+	}
+	if actions := r.TakeActions(); len(actions) != 1 || actions[0].Quest != "q" {
+		t.Fatalf("选择选项应产生 start_quest 动作: %+v", actions)
+	}
+	r.Typewriter.Finish()
+	r.Advance()
+	if !r.Done() {
+		t.Fatal("没有 next 的节点继续后对话应结束")
+	}
+}
+
+func TestConditions(t *testing.T) {
+	state := newFakeState()
+	r := NewRunner(mustParse(t, testTree), state)
+	r.Typewriter.Finish()
+	r.Advance()
+	r.Typewriter.Finish()
+
+	state.items[1001] = 10
+	state.quests["q"] = "active"
+	state.flags["secret"] = true
+	if got := choiceTexts(r.Choices()); got != "Gold,Bye" {
+		t.Fatalf("选项 = %s; want Gold,Bye", got)
+	}
+	// 选项下标对应过滤后的列表
+	r.Choose(1)
+	if !r.Done() {
+		t.Fatal("选择没有 next 的选项后对话应结束")
+	}
+}
+
+func TestTypewriter(t *testing.T) {
+	var tw Typewriter
+	tw.Speed = 10
+	tw.Reset("你好，世界")
+	tw.Update(200 * time.Millisecond)
+	if got := tw.Text(); got != "你好" {
+		t.Errorf("Text() = %q; want 你好（按字符而不是字节显示）", got)
+	}
+	tw.Update(time.Second)
+	if !tw.Done() || tw.Text() != "你好，世界" {
+		t.Errorf("Text() = %q, Done() = %v", tw.Text(), tw.Done())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tree := mustParse(t, `{
+	  "id": "broken",
+	  "start": "a",
+	  "nodes": {
+	    "a": {"text": "Hi", "next": "missing"},
+	    "b": {"text": "Orphan", "choices": [{"text": "Go", "next": "nowhere"}]},
+	    "c": {"text": "Bad", "actions": [{"type": "explode"}, {"type": "give_item", "item": 9999},
+	      {"type": "open_container"}, {"type": "open_container", "container": "vault"}]}
+	  }
+	}`)
+	errs := Validate(tree, Catalog{
+		Item:      func(id int64) bool { return id == 1001 },
+		Container: func(id string) bool { return id == "bank" },
+	})
+	var all []string
+	for _, err := range errs {
+		all = append(all, err.Error())
+	}
+	joined := strings.Join(all, "\n")
+	for _, want := range []string{
+		`节点 a 的 next 指向不存在的节点 "missing"`,
+		`节点 b 的第 1 个选项指向不存在的节点 "nowhere"`,
+		`未知的动作类型 "explode"`,
+		`物品 9999 不存在`,
+		`缺少 container`,
+		`容器 "vault" 不存在`,
+		`节点 b 无法从起点到达`,
+		`节点 c 无法从起点到达`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("缺少错误 %q，实际:\n%s", want, joined)
+		}
+	}
+
+	if errs := Validate(mustParse(t, testTree), Catalog{}); len(errs) != 0 {
+		t.Errorf("有效的对话树不应报错: %v", errs)
+	}
+}
+
+// TestShippedDialogues 校验游戏自带的对话数据
+func TestShippedDialogues(t *testing.T) {
+	trees, err := LoadDir("../data/dialogues")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trees) == 0 {
+		t.Fatal("没有找到对话数据")
+	}
+	for id, tree := range trees {
+		for _, err := range Validate(tree, Catalog{}) {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+}
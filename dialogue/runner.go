@@ -0,0 +1,151 @@
+package dialogue
+
+import (
+	"time"
+)
+
+// DefaultSpeed 打字机效果默认每秒显示的字符数
+const DefaultSpeed = 40.0
+
+// Typewriter 逐字显示文字的打字机效果
+type Typewriter struct {
+	Speed float64 // 每秒显示的字符数，不大于 0 时使用 DefaultSpeed
+
+	text  []rune
+	shown float64
+}
+
+// Reset 开始显示新的文字
+func (tw *Typewriter) Reset(text string) {
+	tw.text = []rune(text)
+	tw.shown = 0
+}
+
+// Update 推进显示的字符数
+func (tw *Typewriter) Update(dt time.Duration) {
+	speed := tw.Speed
+	if speed <= 0 {
+		speed = DefaultSpeed
+	}
+	tw.shown = min(tw.shown+speed*dt.Seconds(), float64(len(tw.text)))
+}
+
+// Finish 立即显示全部文字
+func (tw *Typewriter) Finish() {
+	tw.shown = float64(len(tw.text))
+}
+
+// Done 文字是否已全部显示
+func (tw *Typewriter) Done() bool {
+	return int(tw.shown) >= len(tw.text)
+}
+
+// Text 返回当前显示的文字
+func (tw *Typewriter) Text() string {
+	return string(tw.text[:int(tw.shown)])
+}
+
+// Runner 进行中的一段对话
+// 进入节点和选择选项时产生的动作会排队，由游戏通过 TakeActions 取出执行
+type Runner struct {
+	Tree       *Tree
+	State      State
+	Typewriter Typewriter
+
+	nodeID  string
+	node    *Node
+	actions []Action
+}
+
+// NewRunner 从对话树的起点开始对话
+func NewRunner(tree *Tree, state State) *Runner {
+	r := &Runner{Tree: tree, State: state}
+	r.enter(tree.Start)
+	return r
+}
+
+// enter 进入节点，节点不存在或 id 为空时对话结束
+func (r *Runner) enter(id string) {
+	r.nodeID = id
+	r.node = r.Tree.Nodes[id]
+	if r.node == nil {
+		return
+	}
+	r.Typewriter.Reset(r.node.Text)
+	r.actions = append(r.actions, r.node.Actions...)
+}
+
+// Done 对话是否已经结束
+func (r *Runner) Done() bool {
+	return r.node == nil
+}
+
+// Node 返回当前节点，对话结束时返回 nil
+func (r *Runner) Node() *Node {
+	return r.node
+}
+
+// NodeID 返回当前节点 id
+func (r *Runner) NodeID() string {
+	return r.nodeID
+}
+
+// Update 推进打字机效果
+func (r *Runner) Update(dt time.Duration) {
+	if r.node != nil {
+		r.Typewriter.Update(dt)
+	}
+}
+
+// Choices 返回当前可选的选项（按条件过滤），文字还没显示完时不显示选项
+func (r *Runner) Choices() []Choice {
+	if r.node == nil || !r.Typewriter.Done() {
+		return nil
+	}
+	var choices []Choice
+	for _, c := range r.node.Choices {
+		if c.Available(r.State) {
+			choices = append(choices, c)
+		}
+	}
+	return choices
+}
+
+// Advance 继续对话：文字没显示完时立即显示全部；没有选项时进入下一个节点
+// 有选项时什么也不做，需要调用 Choose
+func (r *Runner) Advance() {
+	if r.node == nil {
+		return
+	}
+	if !r.Typewriter.Done() {
+		r.Typewriter.Finish()
+		return
+	}
+	if len(r.node.Choices) > 0 && len(r.Choices()) > 0 {
+		return
+	}
+	r.enter(r.node.Next)
+}
+
+// Choose 选择 Choices() 返回的第 index 个选项
+func (r *Runner) Choose(index int) {
+	choices := r.Choices()
+	if index < 0 || index >= len(choices) {
+		return
+	}
+	choice := choices[index]
+	r.actions = append(r.actions, choice.Actions...)
+	r.enter(choice.Next)
+}
+
+// End 立即结束对话
+func (r *Runner) End() {
+	r.enter("")
+}
+
+// TakeActions 取出排队的动作
+func (r *Runner) TakeActions() []Action {
+	actions := r.actions
+	r.actions = nil
+	return actions
+}
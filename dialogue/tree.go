@@ -0,0 +1,146 @@
+// Package dialogue NPC 对话：对话树数据、条件、动作、打字机效果和对话树校验
+// Runner 按玩家的选择推进对话树，选项的条件通过 State 向游戏查询物品、剧情标记和任务状态，节点上的动作交给游戏执行
+package dialogue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Tree 一棵对话树，从 Start 节点开始
+type Tree struct {
+	ID    string           `json:"id"`
+	Start string           `json:"start"`
+	Nodes map[string]*Node `json:"nodes"`
+}
+
+// Node 对话中的一句话
+type Node struct {
+	Speaker  string   `json:"speaker"`  // 说话人
+	Portrait string   `json:"portrait"` // 头像名，为空时不显示头像
+	Text     string   `json:"text"`
+	Next     string   `json:"next"`    // 没有选项时继续后进入的节点，为空表示对话结束
+	Choices  []Choice `json:"choices"` // 分支选项
+	Actions  []Action `json:"actions"` // 进入节点时执行的动作
+}
+
+// Choice 对话选项
+type Choice struct {
+	Text    string      `json:"text"`
+	Next    string      `json:"next"`    // 选择后进入的节点，为空表示对话结束
+	If      []Condition `json:"if"`      // 全部满足时才显示该选项
+	Actions []Action    `json:"actions"` // 选择时执行的动作
+}
+
+// 条件类型
+const (
+	CondHasItem = "has_item" // 背包中至少有 Count 个 Item
+	CondFlag    = "flag"     // 标记 Flag 已设置
+	CondQuest   = "quest"    // 任务 Quest 处于 State 状态（"" 表示未接取）
+)
+
+// Condition 选项的显示条件，Not 为 true 时取反
+type Condition struct {
+	Type  string `json:"type"`
+	Item  int64  `json:"item,omitempty"`
+	Count int64  `json:"count,omitempty"` // 为 0 时按 1 计算
+	Flag  string `json:"flag,omitempty"`
+	Quest string `json:"quest,omitempty"`
+	State string `json:"state,omitempty"`
+	Not   bool   `json:"not,omitempty"`
+}
+
+// 动作类型
+const (
+	ActGiveItem      = "give_item"      // 给玩家 Count 个 Item
+	ActTakeItem      = "take_item"      // 从玩家背包中取走 Count 个 Item
+	ActSetFlag       = "set_flag"       // 设置标记 Flag
+	ActClearFlag     = "clear_flag"     // 清除标记 Flag
+	ActStartQuest    = "start_quest"    // 接取任务 Quest
+	ActOpenShop      = "open_shop"      // 打开商店 Shop（对话随之结束）
+	ActOpenContainer = "open_container" // 打开容器 Container，如银行（对话随之结束）
+)
+
+// Action 对话触发的动作，由游戏执行
+type Action struct {
+	Type      string `json:"type"`
+	Item      int64  `json:"item,omitempty"`
+	Count     int64  `json:"count,omitempty"` // 为 0 时按 1 计算
+	Flag      string `json:"flag,omitempty"`
+	Quest     string `json:"quest,omitempty"`
+	Shop      string `json:"shop,omitempty"`
+	Container string `json:"container,omitempty"`
+}
+
+// Amount 返回物品数量，未填写时为 1
+func (a Action) Amount() int64 {
+	return max(a.Count, 1)
+}
+
+// State 条件判断需要的游戏状态
+type State interface {
+	HasItem(id, count int64) bool
+	Flag(name string) bool
+	QuestState(id string) string
+}
+
+// Met 条件是否满足
+func (c Condition) Met(s State) bool {
+	var ok bool
+	switch c.Type {
+	case CondHasItem:
+		ok = s.HasItem(c.Item, max(c.Count, 1))
+	case CondFlag:
+		ok = s.Flag(c.Flag)
+	case CondQuest:
+		ok = s.QuestState(c.Quest) == c.State
+	}
+	return ok != c.Not
+}
+
+// Available 选项的所有条件是否都满足
+func (c Choice) Available(s State) bool {
+	for _, cond := range c.If {
+		if !cond.Met(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse 解析 JSON 格式的对话树
+func Parse(data []byte) (*Tree, error) {
+	t := &Tree{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LoadDir 加载目录下所有 .json 对话树，按 ID 索引
+func LoadDir(dir string) (map[string]*Tree, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	trees := make(map[string]*Tree, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		t, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, ok := trees[t.ID]; ok {
+			return nil, fmt.Errorf("%s: 对话 id %q 重复", path, t.ID)
+		}
+		trees[t.ID] = t
+	}
+	return trees, nil
+}
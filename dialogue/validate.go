@@ -0,0 +1,170 @@
+package dialogue
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Catalog 校验时用于检查引用是否存在，字段为空时不检查对应的引用
+type Catalog struct {
+	Item      func(id int64) bool
+	Quest     func(id string) bool
+	Shop      func(id string) bool
+	Container func(id string) bool
+}
+
+// Validate 检查对话树：起点和跳转指向的节点必须存在，所有节点必须可以到达，
+// 条件和动作的类型和参数必须有效；返回所有发现的问题
+func Validate(t *Tree, catalog Catalog) []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("对话 %s: "+format, append([]any{t.ID}, args...)...))
+	}
+	if t.ID == "" {
+		fail("缺少 id")
+	}
+	if _, ok := t.Nodes[t.Start]; !ok {
+		fail("起点节点 %q 不存在", t.Start)
+	}
+
+	ids := make([]string, 0, len(t.Nodes))
+	for id := range t.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		node := t.Nodes[id]
+		if node == nil {
+			fail("节点 %s 为空", id)
+			continue
+		}
+		if node.Text == "" {
+			fail("节点 %s 没有文字", id)
+		}
+		if node.Next != "" && len(node.Choices) > 0 {
+			fail("节点 %s 同时有 next 和 choices", id)
+		}
+		if _, ok := t.Nodes[node.Next]; node.Next != "" && !ok {
+			fail("节点 %s 的 next 指向不存在的节点 %q", id, node.Next)
+		}
+		for i, a := range node.Actions {
+			if err := validateAction(a, catalog); err != nil {
+				fail("节点 %s 的第 %d 个动作: %v", id, i+1, err)
+			}
+		}
+		for i, c := range node.Choices {
+			if c.Text == "" {
+				fail("节点 %s 的第 %d 个选项没有文字", id, i+1)
+			}
+			if _, ok := t.Nodes[c.Next]; c.Next != "" && !ok {
+				fail("节点 %s 的第 %d 个选项指向不存在的节点 %q", id, i+1, c.Next)
+			}
+			for _, cond := range c.If {
+				if err := validateCondition(cond, catalog); err != nil {
+					fail("节点 %s 的第 %d 个选项的条件: %v", id, i+1, err)
+				}
+			}
+			for j, a := range c.Actions {
+				if err := validateAction(a, catalog); err != nil {
+					fail("节点 %s 的第 %d 个选项的第 %d 个动作: %v", id, i+1, j+1, err)
+				}
+			}
+		}
+	}
+
+	// 从起点出发到达不了的节点
+	reached := reachable(t)
+	for _, id := range ids {
+		if !reached[id] {
+			fail("节点 %s 无法从起点到达", id)
+		}
+	}
+	return errs
+}
+
+// reachable 返回从起点出发可以到达的节点
+func reachable(t *Tree) map[string]bool {
+	reached := map[string]bool{}
+	stack := []string{t.Start}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node, ok := t.Nodes[id]
+		if !ok || node == nil || reached[id] {
+			continue
+		}
+		reached[id] = true
+		stack = append(stack, node.Next)
+		for _, c := range node.Choices {
+			stack = append(stack, c.Next)
+		}
+	}
+	return reached
+}
+
+func validateCondition(c Condition, catalog Catalog) error {
+	switch c.Type {
+	case CondHasItem:
+		return checkItem(c.Item, catalog)
+	case CondFlag:
+		if c.Flag == "" {
+			return fmt.Errorf("缺少 flag")
+		}
+	case CondQuest:
+		return checkQuest(c.Quest, catalog)
+	default:
+		return fmt.Errorf("未知的条件类型 %q", c.Type)
+	}
+	return nil
+}
+
+func validateAction(a Action, catalog Catalog) error {
+	switch a.Type {
+	case ActGiveItem, ActTakeItem:
+		return checkItem(a.Item, catalog)
+	case ActSetFlag, ActClearFlag:
+		if a.Flag == "" {
+			return fmt.Errorf("缺少 flag")
+		}
+	case ActStartQuest:
+		return checkQuest(a.Quest, catalog)
+	case ActOpenShop:
+		if a.Shop == "" {
+			return fmt.Errorf("缺少 shop")
+		}
+		if catalog.Shop != nil && !catalog.Shop(a.Shop) {
+			return fmt.Errorf("商店 %q 不存在", a.Shop)
+		}
+	case ActOpenContainer:
+		if a.Container == "" {
+			return fmt.Errorf("缺少 container")
+		}
+		if catalog.Container != nil && !catalog.Container(a.Container) {
+			return fmt.Errorf("容器 %q 不存在", a.Container)
+		}
+	default:
+		return fmt.Errorf("未知的动作类型 %q", a.Type)
+	}
+	return nil
+}
+
+func checkItem(id int64, catalog Catalog) error {
+	if id == 0 {
+		return fmt.Errorf("缺少 item")
+	}
+	if catalog.Item != nil && !catalog.Item(id) {
+		return fmt.Errorf("物品 %d 不存在", id)
+	}
+	return nil
+}
+
+func checkQuest(id string, catalog Catalog) error {
+	if id == "" {
+		return fmt.Errorf("缺少 quest")
+	}
+	if catalog.Quest != nil && !catalog.Quest(id) {
+		return fmt.Errorf("任务 %q 不存在", id)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"Game/dialogue"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"slices"
+)
+
+// dialoguesDir 对话数据目录
+const dialoguesDir = "data/dialogues"
+
+// 对话框布局
+const (
+	dialogueBoxX      = 20
+	dialogueBoxY      = 400
+	dialogueBoxW      = 760
+	dialogueBoxH      = 180
+	dialoguePortrait  = 96 // 头像尺寸
+	dialogueTextChars = 100
+)
+
+// initDialogues 加载并校验对话数据，数据有误时直接退出（需要在加载任务、商店和容器之后调用）
+func (p *PlayScreen) initDialogues() {
+	trees, err := dialogue.LoadDir(dialoguesDir)
+	if err != nil {
+		log.Fatalf("加载对话数据失败: %v", err)
+	}
+	catalog := dialogue.Catalog{
+		Item:      func(id int64) bool { return ItemImages[id] != nil },
+		Quest:     func(id string) bool { return p.quests.Def(id) != nil },
+		Shop:      func(id string) bool { return p.shops[id] != nil },
+		Container: func(id string) bool { return p.containers[id] != nil },
+	}
+	for _, tree := range trees {
+		if errs := dialogue.Validate(tree, catalog); len(errs) > 0 {
+			for _, err := range errs {
+				log.Print(err)
+			}
+			log.Fatalf("对话数据有误: %s", tree.ID)
+		}
+	}
+	p.dialogues = trees
+	// 所有地图上的 NPC 引用的对话必须存在
+	for _, o := range p.mapObjects(objectNPC) {
+		if _, ok := trees[o.String("dialogue")]; !ok {
+			log.Fatalf("NPC %s 的对话 %q 不存在", o.Name, o.String("dialogue"))
+		}
+	}
+}
+
+// startDialogue 开始一段对话
+func (p *PlayScreen) startDialogue(tree *dialogue.Tree) {
+	p.dialog = dialogue.NewRunner(tree, p)
+	p.dialogChoice = 0
+	p.runDialogueActions()
+}
+
+// updateDialogue 处理对话中的输入；对话期间游戏世界暂停
+func (p *PlayScreen) updateDialogue() {
+	r := p.dialog
+	r.Update(tickDuration())
+
+	if p.settings.isActionJustPressed(ActionPause) {
+		r.End()
+	}
+	choices := r.Choices()
+	if len(choices) > 0 {
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || p.settings.isActionJustPressed(ActionMoveUp) {
+			p.dialogChoice = (p.dialogChoice - 1 + len(choices)) % len(choices)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || p.settings.isActionJustPressed(ActionMoveDown) {
+			p.dialogChoice = (p.dialogChoice + 1) % len(choices)
+		}
+		p.dialogChoice = min(p.dialogChoice, len(choices)-1)
+	}
+
+	// 鼠标点击选项
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		for i := range choices {
+			if inRect(x, y, dialogueChoiceRect(i)) {
+				p.chooseDialogue(i)
+				return
+			}
+		}
+		if len(choices) == 0 {
+			r.Advance()
+		}
+	}
+
+	if p.settings.isActionJustPressed(ActionInteract) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if len(choices) > 0 {
+			p.chooseDialogue(p.dialogChoice)
+		} else {
+			r.Advance()
+		}
+	}
+
+	p.runDialogueActions()
+	if r.Done() {
+		p.dialog = nil
+	}
+}
+
+// chooseDialogue 选择第 index 个选项
+func (p *PlayScreen) chooseDialogue(index int) {
+	p.sound.PlaySFX(sfxClick)
+	p.dialog.Choose(index)
+	p.dialogChoice = 0
+}
+
+// runDialogueActions 执行对话产生的动作
+func (p *PlayScreen) runDialogueActions() {
+	defer p.walletTx("dialogue")()
+	for _, a := range p.dialog.TakeActions() {
+		switch a.Type {
+		case dialogue.ActGiveItem:
+			p.giveItem(a.Item, a.Amount())
+		case dialogue.ActTakeItem:
+			p.takeItem(a.Item, a.Amount())
+		case dialogue.ActSetFlag:
+			p.story().Flags[a.Flag] = true
+		case dialogue.ActClearFlag:
+			delete(p.story().Flags, a.Flag)
+		case dialogue.ActStartQuest:
+			p.startQuest(a.Quest)
+		case dialogue.ActOpenShop:
+			p.dialog.End()
+			p.openShop(a.Shop)
+		case dialogue.ActOpenContainer:
+			p.dialog.End()
+			p.openContainer(a.Container)
+		}
+	}
+}
+
+// giveItem 把物品放进背包，背包已满时放在玩家脚下
+func (p *PlayScreen) giveItem(itemID, count int64) {
+	if !p.addItem(itemID, count) {
+		cx, cy := p.playerCenter()
+		p.spawnPickup(itemID, count, int(cx)/p.gridSize, int(cy)/p.gridSize)
+		p.notices.Show(p.settings.T(fullNoticeKey(itemID)))
+		return
+	}
+	p.notices.Show(fmt.Sprintf(p.settings.T("notice.received"), ItemImages[itemID].Name, count))
+	p.sound.PlaySFX(sfxPickup)
+}
+
+// takeItem 从背包（货币从钱包）中取走物品，数量不足时不取走并返回 false
+func (p *PlayScreen) takeItem(itemID, count int64) bool {
+	if isCurrency(itemID) {
+		return p.spendCurrency(itemID, count)
+	}
+	if !p.HasItem(itemID, count) {
+		return false
+	}
+	for _, it := range slices.Clone(p.bag().Items) {
+		if it.ItemData.id != itemID || count == 0 {
+			continue
+		}
+		n := min(it.count, count)
+		it.count -= n
+		count -= n
+		if it.count == 0 {
+			p.removeItem(it)
+		}
+	}
+	p.inventoryChanged()
+	return true
+}
+
+// HasItem 背包中是否至少有 count 个物品（实现 dialogue.State）
+func (p *PlayScreen) HasItem(itemID, count int64) bool {
+	return p.CountItem(itemID) >= count
+}
+
+// Flag 标记是否已设置（实现 dialogue.State）
+func (p *PlayScreen) Flag(name string) bool {
+	return p.story().Flags[name]
+}
+
+// QuestState 返回任务状态，未接取时为空（实现 dialogue.State）
+func (p *PlayScreen) QuestState(id string) string {
+	return string(p.quests.State(id))
+}
+
+// dialogueChoiceRect 第 index 个选项的点击区域
+func dialogueChoiceRect(index int) [4]int {
+	x := dialogueBoxX + 20 + dialoguePortrait + 20
+	return [4]int{x, dialogueBoxY + 90 + index*18, dialogueBoxW - (x - dialogueBoxX) - 20, 16}
+}
+
+// drawDialogue 绘制对话框：头像、说话人、逐字显示的文字和选项
+func (p *PlayScreen) drawDialogue(screen *ebiten.Image) {
+	r := p.dialog
+	node := r.Node()
+	if node == nil {
+		return
+	}
+	drawPanel(screen, dialogueBoxX, dialogueBoxY, dialogueBoxW, dialogueBoxH)
+
+	textX := dialogueBoxX + 20
+	if node.Portrait != "" {
+		img := p.portrait(node.Portrait)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(dialoguePortrait/float64(img.Bounds().Dx()), dialoguePortrait/float64(img.Bounds().Dy()))
+		op.GeoM.Translate(float64(dialogueBoxX+20), float64(dialogueBoxY+20))
+		screen.DrawImage(img, op)
+		vector.StrokeRect(screen, dialogueBoxX+20, dialogueBoxY+20, dialoguePortrait, dialoguePortrait, 1, color.RGBA{R: 200, G: 200, B: 255, A: 255}, false)
+		textX += dialoguePortrait + 20
+	}
+	if node.Speaker != "" {
+		ebitenutil.DebugPrintAt(screen, node.Speaker, textX, dialogueBoxY+14)
+		vector.DrawFilledRect(screen, float32(textX), float32(dialogueBoxY+32), float32(len(node.Speaker)*6), 1, color.RGBA{R: 200, G: 200, B: 255, A: 255}, false)
+	}
+
+	// 先按完整文字折行，再逐字显示，避免显示过程中单词在行间跳动
+	shown := len([]rune(r.Typewriter.Text()))
+	maxChars := (dialogueBoxX + dialogueBoxW - 20 - textX) / 6
+	for i, line := range wrapText(node.Text, min(maxChars, dialogueTextChars)) {
+		runes := []rune(line)
+		if shown <= 0 {
+			break
+		}
+		n := min(len(runes), shown)
+		ebitenutil.DebugPrintAt(screen, string(runes[:n]), textX, dialogueBoxY+40+i*16)
+		shown -= n + 1 // 折行处的空格
+	}
+
+	choices := r.Choices()
+	for i, choice := range choices {
+		rect := dialogueChoiceRect(i)
+		if i == p.dialogChoice {
+			vector.DrawFilledRect(screen, float32(rect[0]-4), float32(rect[1]), float32(rect[2]), float32(rect[3]), color.RGBA{R: 100, G: 150, B: 255, A: 80}, false)
+			ebitenutil.DebugPrintAt(screen, ">", rect[0]-2, rect[1])
+		}
+		ebitenutil.DebugPrintAt(screen, choice.Text, rect[0]+10, rect[1])
+	}
+	if len(choices) == 0 && r.Typewriter.Done() {
+		hint := fmt.Sprintf(p.settings.T("dialogue.continue"), p.settings.Key(ActionInteract))
+		ebitenutil.DebugPrintAt(screen, hint, dialogueBoxX+dialogueBoxW-len(hint)*6-16, dialogueBoxY+dialogueBoxH-22)
+	}
+}
@@ -0,0 +1,175 @@
+package dungeon
+
+import (
+	"Game/nav"
+	"Game/worldmap"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+)
+
+// 生成的地图中入口和出口旁边的出生点名字，楼梯通过它们连接上下两层
+const (
+	SpawnEntrance = "entrance"
+	SpawnExit     = "exit"
+)
+
+// Link 楼梯通往的地图和出生点
+type Link struct {
+	Map   string `json:"map"`
+	Spawn string `json:"spawn"`
+}
+
+// Level 地下城的一层
+type Level struct {
+	ID         string    `json:"id"`   // 生成的地图 id
+	Name       string    `json:"name"` // 地图的显示名称
+	Generator  Generator `json:"generator"`
+	Seed       uint64    `json:"seed"`
+	Budget     int       `json:"budget"`     // 敌人的难度预算
+	LootBudget int       `json:"lootBudget"` // 宝箱的预算
+}
+
+// Dungeon 一座多层的地下城：每层的入口通往上一层的出口，出口通往下一层的入口，
+// 第一层的入口和最后一层的出口通往 Entry
+type Dungeon struct {
+	Entry   Link    `json:"entry"`
+	Enemies []Spawn `json:"enemies"` // 所有楼层可以放置的敌人
+	Chests  []Spawn `json:"chests"`  // 所有楼层可以放置的宝箱
+	Floors  []Level `json:"floors"`
+}
+
+// Load 读取地下城数据文件：地下城 id -> 地下城
+func Load(path string) (map[string]*Dungeon, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var dungeons map[string]*Dungeon
+	if err := json.Unmarshal(data, &dungeons); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dungeons, nil
+}
+
+// Validate 检查地下城数据：入口指向地图和出生点、至少有一层、楼层 id 不重复、生成器存在、预算和花费有效；
+// 返回所有发现的问题（敌人种类和掉落表是否存在由游戏检查）
+func Validate(dungeons map[string]*Dungeon) []error {
+	var errs []error
+	ids := map[string]bool{}
+	for _, id := range slices.Sorted(maps.Keys(dungeons)) {
+		d := dungeons[id]
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("地下城 %s: "+format, append([]any{id}, args...)...))
+		}
+		if d.Entry.Map == "" || d.Entry.Spawn == "" {
+			fail("entry 缺少 map 或 spawn")
+		}
+		if len(d.Floors) == 0 {
+			fail("没有楼层")
+		}
+		for _, err := range append(checkSpawns(d.Enemies), checkSpawns(d.Chests)...) {
+			fail("%v", err)
+		}
+		for _, l := range d.Floors {
+			switch {
+			case l.ID == "":
+				fail("楼层缺少 id")
+			case ids[l.ID]:
+				fail("楼层 id %q 重复", l.ID)
+			}
+			ids[l.ID] = true
+			if generators[l.Generator] == nil {
+				fail("楼层 %s: 未知的生成器 %q", l.ID, l.Generator)
+			}
+			if l.Budget < 0 || l.LootBudget < 0 {
+				fail("楼层 %s: 预算不能为负数", l.ID)
+			}
+		}
+	}
+	return errs
+}
+
+// Config 第 i 层 width×height 格的生成参数
+func (d *Dungeon) Config(i, width, height int) Config {
+	l := d.Floors[i]
+	return Config{
+		Width: width, Height: height,
+		Generator: l.Generator, Seed: l.Seed,
+		Budget: l.Budget, LootBudget: l.LootBudget,
+		Enemies: d.Enemies, Chests: d.Chests,
+	}
+}
+
+// Links 第 i 层的入口和出口通往的地图和出生点
+func (d *Dungeon) Links(i int) (up, down Link) {
+	up, down = d.Entry, d.Entry
+	if i > 0 {
+		up = Link{Map: d.Floors[i-1].ID, Spawn: SpawnExit}
+	}
+	if i < len(d.Floors)-1 {
+		down = Link{Map: d.Floors[i+1].ID, Spawn: SpawnEntrance}
+	}
+	return up, down
+}
+
+// Maps 生成所有地下城的所有楼层并转换为地图：地图 id -> 地图；每层 width×height 格，每格 tileSize 像素
+func Maps(dungeons map[string]*Dungeon, width, height, tileSize int) (map[string]*worldmap.Map, error) {
+	floors := map[string]*worldmap.Map{}
+	for _, id := range slices.Sorted(maps.Keys(dungeons)) {
+		d := dungeons[id]
+		for i, l := range d.Floors {
+			f, err := Generate(d.Config(i, width, height))
+			if err != nil {
+				return nil, fmt.Errorf("地下城 %s 楼层 %s: %w", id, l.ID, err)
+			}
+			up, down := d.Links(i)
+			m, err := f.Map(l.ID, l.Name, tileSize, up, down)
+			if err != nil {
+				return nil, fmt.Errorf("地下城 %s 楼层 %s: %w", id, l.ID, err)
+			}
+			floors[l.ID] = m
+		}
+	}
+	return floors, nil
+}
+
+// Map 把楼层转换为地图：入口和出口是通往 up 和 down 的传送点，旁边是出生点 SpawnEntrance 和 SpawnExit，
+// 敌人的属性 kind 为种类，宝箱按顺序命名为 chest_1、chest_2……，属性 table 为掉落表
+func (f *Floor) Map(id, name string, tileSize int, up, down Link) (*worldmap.Map, error) {
+	walls := make([]int, len(f.wall))
+	for i, w := range f.wall {
+		if w {
+			walls[i] = 1
+		}
+	}
+	var objects []*worldmap.Object
+	add := func(typ, name string, p nav.Point, props ...worldmap.Property) {
+		objects = append(objects, &worldmap.Object{
+			ID: len(objects) + 1, Name: name, Type: typ,
+			X: float64(p.X * tileSize), Y: float64(p.Y * tileSize), Width: float64(tileSize), Height: float64(tileSize),
+			Properties: props,
+		})
+	}
+	add(worldmap.TypeSpawn, SpawnEntrance, f.Start)
+	add(worldmap.TypeSpawn, SpawnExit, f.End)
+	add(worldmap.TypeWarp, "", f.Entrance, stringProp("map", up.Map), stringProp("spawn", up.Spawn))
+	add(worldmap.TypeWarp, "", f.Exit, stringProp("map", down.Map), stringProp("spawn", down.Spawn))
+	chests := 0
+	for _, o := range f.Objects {
+		switch o.Type {
+		case Enemy:
+			add(Enemy, "", o.At, stringProp("kind", o.Kind))
+		case Chest:
+			chests++
+			add(Chest, fmt.Sprintf("chest_%d", chests), o.At, stringProp("table", o.Kind))
+		}
+	}
+	return worldmap.New(id, f.Width, f.Height, tileSize, walls, objects, []worldmap.Property{stringProp("name", name)})
+}
+
+func stringProp(name, value string) worldmap.Property {
+	return worldmap.Property{Name: name, Type: "string", Value: value}
+}
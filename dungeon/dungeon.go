@@ -0,0 +1,411 @@
+// Package dungeon 程序生成的地下城楼层：房间与走廊、BSP 分割和元胞自动机洞穴三种生成器，
+// 放置入口和出口楼梯，再按难度预算放置敌人和宝箱；相同的参数和种子总是生成相同的楼层
+// Maps 把 data/dungeons.json 中的每座地下城展开为一串 worldmap 地图，相邻楼层用楼梯传送点相连
+package dungeon
+
+import (
+	"Game/nav"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// Generator 生成器种类
+type Generator string
+
+const (
+	Rooms Generator = "rooms" // 随机放置的矩形房间，按位置顺序用 L 形走廊连接
+	BSP   Generator = "bsp"   // 递归二分空间，每块放一个房间，兄弟分块之间用走廊连接
+	Cave  Generator = "cave"  // 元胞自动机平滑随机噪声形成的洞穴
+)
+
+// Generators 所有生成器，按文档中的顺序
+var Generators = []Generator{Rooms, BSP, Cave}
+
+// generators 生成器种类 -> 在全是墙的楼层上挖出地面的函数
+var generators = map[Generator]func(*Floor, *rand.Rand){
+	Rooms: (*Floor).rooms,
+	BSP:   (*Floor).bsp,
+	Cave:  (*Floor).cave,
+}
+
+// 楼层上的对象类型，和游戏地图中的对象类型相同
+const (
+	Enemy = "enemy"
+	Chest = "chest"
+)
+
+// MinSize 楼层的最小宽度和高度（格子）
+const MinSize = 16
+
+const (
+	safeRadius  = 5  // 敌人离入口的最小步数，避免一下楼就被围住
+	maxAttempts = 50 // 生成的楼层不合格（地面太少、入口和出口太近）时重新生成的次数
+)
+
+// dirs 上下左右四个方向；楼层只按四方向判断连通，和游戏中玩家不能斜穿墙角一致
+var dirs = [4]nav.Point{{X: 0, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: -1, Y: 0}}
+
+// Spawn 可以按预算放置的一种敌人或宝箱：Kind 为敌人种类或宝箱的掉落表，Cost 为占用的预算
+type Spawn struct {
+	Kind string `json:"kind"`
+	Cost int    `json:"cost"`
+}
+
+// Config 生成一层的参数
+type Config struct {
+	Width, Height int
+	Generator     Generator
+	Seed          uint64
+	Budget        int // 敌人的难度预算，放置的敌人花费之和不超过它
+	LootBudget    int // 宝箱的预算
+	Enemies       []Spawn
+	Chests        []Spawn
+}
+
+// Object 楼层上的敌人或宝箱
+type Object struct {
+	Type string // Enemy 或 Chest
+	Kind string
+	Cost int
+	At   nav.Point
+}
+
+// Floor 生成的一层；墙和宝箱不可通行，楼梯可以走上去（走上去就离开这一层）
+type Floor struct {
+	Width, Height int
+	Entrance      nav.Point // 入口楼梯，通往上一层
+	Exit          nav.Point // 出口楼梯，通往下一层
+	Start         nav.Point // 从上一层下来时站的格子，在入口旁边
+	End           nav.Point // 从下一层上来时站的格子，在出口旁边
+	Objects       []Object  // 按放置顺序，先敌人后宝箱
+
+	wall []bool
+}
+
+// Generate 按参数生成一层；参数有误或多次尝试都生成不出合格的楼层时返回错误
+func Generate(cfg Config) (*Floor, error) {
+	if err := cfg.check(); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15))
+	for range maxAttempts {
+		f := &Floor{Width: cfg.Width, Height: cfg.Height, wall: make([]bool, cfg.Width*cfg.Height)}
+		for i := range f.wall {
+			f.wall[i] = true
+		}
+		generators[cfg.Generator](f, rng)
+		f.keepLargestRegion()
+		if f.floorCount() < cfg.Width*cfg.Height/6 || !f.placeStairs(rng) {
+			continue
+		}
+		f.placeEnemies(rng, cfg.Enemies, cfg.Budget)
+		f.placeChests(rng, cfg.Chests, cfg.LootBudget)
+		return f, nil
+	}
+	return nil, fmt.Errorf("%s 生成器尝试 %d 次都没有生成合格的 %d×%d 楼层（种子 %d）", cfg.Generator, maxAttempts, cfg.Width, cfg.Height, cfg.Seed)
+}
+
+// check 检查生成参数
+func (c Config) check() error {
+	var errs []error
+	if generators[c.Generator] == nil {
+		errs = append(errs, fmt.Errorf("未知的生成器 %q", c.Generator))
+	}
+	if c.Width < MinSize || c.Height < MinSize {
+		errs = append(errs, fmt.Errorf("楼层至少 %d×%d 格，实际 %d×%d", MinSize, MinSize, c.Width, c.Height))
+	}
+	if c.Budget < 0 || c.LootBudget < 0 {
+		errs = append(errs, fmt.Errorf("预算不能为负数"))
+	}
+	errs = append(errs, checkSpawns(c.Enemies)...)
+	errs = append(errs, checkSpawns(c.Chests)...)
+	return errors.Join(errs...)
+}
+
+// checkSpawns 检查可放置的敌人或宝箱：种类不为空，花费大于 0
+func checkSpawns(spawns []Spawn) []error {
+	var errs []error
+	for _, s := range spawns {
+		if s.Kind == "" || s.Cost <= 0 {
+			errs = append(errs, fmt.Errorf("%+v: 缺少种类或花费不大于 0", s))
+		}
+	}
+	return errs
+}
+
+// InBounds 格子是否在楼层内
+func (f *Floor) InBounds(p nav.Point) bool {
+	return p.X >= 0 && p.X < f.Width && p.Y >= 0 && p.Y < f.Height
+}
+
+// Wall 格子是否是墙，越界视为墙
+func (f *Floor) Wall(p nav.Point) bool {
+	return !f.InBounds(p) || f.wall[f.index(p)]
+}
+
+// Size 实现 nav.Grid
+func (f *Floor) Size() (int, int) {
+	return f.Width, f.Height
+}
+
+// Blocked 实现 nav.Grid：墙和宝箱不可通行
+func (f *Floor) Blocked(p nav.Point) bool {
+	if f.Wall(p) {
+		return true
+	}
+	o := f.ObjectAt(p)
+	return o != nil && o.Type == Chest
+}
+
+// ObjectAt 返回格子上的敌人或宝箱，没有时返回 nil
+func (f *Floor) ObjectAt(p nav.Point) *Object {
+	for i := range f.Objects {
+		if f.Objects[i].At == p {
+			return &f.Objects[i]
+		}
+	}
+	return nil
+}
+
+// Spent 某种对象花掉的预算
+func (f *Floor) Spent(typ string) int {
+	total := 0
+	for _, o := range f.Objects {
+		if o.Type == typ {
+			total += o.Cost
+		}
+	}
+	return total
+}
+
+// Validate 检查楼层：楼梯和对象都在地面上且互不重叠，从入口旁边能走到出口旁边、所有敌人和每个宝箱旁边；
+// 返回所有发现的问题
+func (f *Floor) Validate() []error {
+	var errs []error
+	marks := []struct {
+		name string
+		p    nav.Point
+	}{{"入口", f.Entrance}, {"出口", f.Exit}, {"入口旁的出生格", f.Start}, {"出口旁的出生格", f.End}}
+	occupied := map[nav.Point]bool{}
+	for _, m := range marks {
+		if f.Wall(m.p) {
+			errs = append(errs, fmt.Errorf("%s %v 在墙上", m.name, m.p))
+		}
+		if occupied[m.p] {
+			errs = append(errs, fmt.Errorf("%s %v 和其他楼梯或出生格重叠", m.name, m.p))
+		}
+		occupied[m.p] = true
+	}
+	if manhattan(f.Start, f.Entrance) != 1 || manhattan(f.End, f.Exit) != 1 {
+		errs = append(errs, fmt.Errorf("出生格必须紧挨着楼梯"))
+	}
+	for _, o := range f.Objects {
+		if f.Wall(o.At) || occupied[o.At] {
+			errs = append(errs, fmt.Errorf("%s %s %v 在墙上或和其他东西重叠", o.Type, o.Kind, o.At))
+		}
+		occupied[o.At] = true
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	dist := f.distances(f.Start, f.solid)
+	if dist[f.index(f.End)] < 0 {
+		errs = append(errs, fmt.Errorf("从入口 %v 无法到达出口 %v", f.Entrance, f.Exit))
+	}
+	for _, o := range f.Objects {
+		if o.Type == Enemy && dist[f.index(o.At)] < 0 {
+			errs = append(errs, fmt.Errorf("敌人 %s %v 无法到达", o.Kind, o.At))
+		}
+		if o.Type == Chest && !slices.ContainsFunc(dirs[:], func(d nav.Point) bool {
+			q := add(o.At, d)
+			return f.InBounds(q) && dist[f.index(q)] >= 0
+		}) {
+			errs = append(errs, fmt.Errorf("宝箱 %s %v 无法到达", o.Kind, o.At))
+		}
+	}
+	return errs
+}
+
+// placeStairs 放置入口和出口：都在周围八格都是地面的格子上（占用它们不会把楼层隔断），
+// 出口是离入口步数最远的这种格子；入口和出口太近时返回 false
+func (f *Floor) placeStairs(rng *rand.Rand) bool {
+	var open []nav.Point
+	for i := range f.wall {
+		if p := f.point(i); f.open(p) {
+			open = append(open, p)
+		}
+	}
+	if len(open) < 2 {
+		return false
+	}
+	f.Entrance = open[rng.IntN(len(open))]
+	dist := f.distances(f.Entrance, f.Wall)
+	f.Exit = f.Entrance
+	for _, p := range open {
+		if dist[f.index(p)] > dist[f.index(f.Exit)] {
+			f.Exit = p
+		}
+	}
+	if dist[f.index(f.Exit)] < (f.Width+f.Height)/4 {
+		return false
+	}
+	// 楼梯周围都是地面，第一个方向的格子就可以站立
+	f.Start, f.End = add(f.Entrance, dirs[0]), add(f.Exit, dirs[0])
+	return true
+}
+
+// placeEnemies 在离入口至少 safeRadius 步的格子上随机放置敌人，直到剩余的预算不够放任何一种
+func (f *Floor) placeEnemies(rng *rand.Rand, spawns []Spawn, budget int) {
+	dist := f.distances(f.Start, f.solid)
+	var candidates []nav.Point
+	for i, d := range dist {
+		if d >= safeRadius {
+			candidates = append(candidates, f.point(i))
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	f.spend(rng, Enemy, spawns, budget, candidates, func(nav.Point) bool { return true })
+}
+
+// placeChests 随机放置宝箱，优先放在角落和死路（周围墙多的格子）；不放在会把楼层隔断的格子上
+func (f *Floor) placeChests(rng *rand.Rand, spawns []Spawn, budget int) {
+	dist := f.distances(f.Start, f.solid)
+	var candidates []nav.Point
+	for i, d := range dist {
+		if d > 0 {
+			candidates = append(candidates, f.point(i))
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	slices.SortStableFunc(candidates, func(a, b nav.Point) int { return f.wallsAround(b) - f.wallsAround(a) })
+	f.spend(rng, Chest, spawns, budget, candidates, f.safe)
+}
+
+// spend 按预算依次放置对象：每次从买得起的种类中随机选一种，放在下一个空闲且 ok 的候选格子上；
+// 对象之间不相邻，也不放在楼梯和出生格上
+func (f *Floor) spend(rng *rand.Rand, typ string, spawns []Spawn, budget int, candidates []nav.Point, ok func(nav.Point) bool) {
+	for _, p := range candidates {
+		var affordable []Spawn
+		for _, s := range spawns {
+			if s.Cost <= budget {
+				affordable = append(affordable, s)
+			}
+		}
+		if len(affordable) == 0 {
+			return
+		}
+		if !f.free(p) || !ok(p) {
+			continue
+		}
+		s := affordable[rng.IntN(len(affordable))]
+		f.Objects = append(f.Objects, Object{Type: typ, Kind: s.Kind, Cost: s.Cost, At: p})
+		budget -= s.Cost
+	}
+}
+
+// free 格子是否可以放置对象：是地面，不是楼梯或出生格，周围八格没有其他对象
+func (f *Floor) free(p nav.Point) bool {
+	if f.Wall(p) || p == f.Entrance || p == f.Exit || p == f.Start || p == f.End {
+		return false
+	}
+	return !slices.ContainsFunc(f.Objects, func(o Object) bool {
+		return max(abs(o.At.X-p.X), abs(o.At.Y-p.Y)) <= 1
+	})
+}
+
+// safe 格子 p 变为不可通行后，其余可以通行的格子是否仍然连通
+func (f *Floor) safe(p nav.Point) bool {
+	blocked := func(q nav.Point) bool { return q == p || f.solid(q) }
+	dist := f.distances(f.Start, blocked)
+	for i, d := range dist {
+		if d < 0 && !blocked(f.point(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// solid 格子是否挡路：墙、宝箱和楼梯（走上楼梯就会离开这一层）
+func (f *Floor) solid(p nav.Point) bool {
+	return p == f.Entrance || p == f.Exit || f.Blocked(p)
+}
+
+// open 格子和周围八格是否都是地面
+func (f *Floor) open(p nav.Point) bool {
+	return !f.Wall(p) && f.wallsAround(p) == 0
+}
+
+// wallsAround 周围八格中墙的数量，越界的格子算作墙
+func (f *Floor) wallsAround(p nav.Point) int {
+	n := 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx != 0 || dy != 0) && f.Wall(nav.Point{X: p.X + dx, Y: p.Y + dy}) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// distances 从 from 出发按四方向走到每个格子的步数，走不到或被挡住的格子为 -1
+func (f *Floor) distances(from nav.Point, blocked func(nav.Point) bool) []int {
+	dist := make([]int, len(f.wall))
+	for i := range dist {
+		dist[i] = -1
+	}
+	if blocked(from) {
+		return dist
+	}
+	dist[f.index(from)] = 0
+	queue := []nav.Point{from}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range dirs {
+			q := add(p, d)
+			if f.InBounds(q) && dist[f.index(q)] < 0 && !blocked(q) {
+				dist[f.index(q)] = dist[f.index(p)] + 1
+				queue = append(queue, q)
+			}
+		}
+	}
+	return dist
+}
+
+// floorCount 地面格子的数量
+func (f *Floor) floorCount() int {
+	n := 0
+	for _, w := range f.wall {
+		if !w {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *Floor) index(p nav.Point) int {
+	return p.Y*f.Width + p.X
+}
+
+func (f *Floor) point(i int) nav.Point {
+	return nav.Point{X: i % f.Width, Y: i / f.Width}
+}
+
+func add(a, b nav.Point) nav.Point {
+	return nav.Point{X: a.X + b.X, Y: a.Y + b.Y}
+}
+
+func manhattan(a, b nav.Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
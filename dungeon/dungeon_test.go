@@ -0,0 +1,188 @@
+package dungeon
+
+import (
+	"Game/nav"
+	"Game/worldmap"
+	"strings"
+	"testing"
+)
+
+var (
+	testEnemies = []Spawn{{Kind: "slime", Cost: 1}, {Kind: "boss", Cost: 4}}
+	testChests  = []Spawn{{Kind: "small", Cost: 1}, {Kind: "big", Cost: 3}}
+)
+
+func testConfig(gen Generator, seed uint64, w, h int) Config {
+	return Config{Width: w, Height: h, Generator: gen, Seed: seed, Budget: 8, LootBudget: 4, Enemies: testEnemies, Chests: testChests}
+}
+
+func mustGenerate(t *testing.T, cfg Config) *Floor {
+	t.Helper()
+	f, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestDeterministic(t *testing.T) {
+	for _, gen := range Generators {
+		a := mustGenerate(t, testConfig(gen, 42, 40, 30)).ASCII()
+		if b := mustGenerate(t, testConfig(gen, 42, 40, 30)).ASCII(); a != b {
+			t.Fatalf("%s: 同样的种子生成了不同的楼层:\n%s\n%s", gen, a, b)
+		}
+		if c := mustGenerate(t, testConfig(gen, 43, 40, 30)).ASCII(); a == c {
+			t.Fatalf("%s: 不同的种子生成了同样的楼层:\n%s", gen, a)
+		}
+		if strings.Count(a, "<") != 1 || strings.Count(a, ">") != 1 || strings.Count(a, "\n") != 30 {
+			t.Fatalf("%s: 字符画应有 30 行、一个入口和一个出口:\n%s", gen, a)
+		}
+	}
+}
+
+// TestConnectivity 每种生成器在不同尺寸和大量种子下，出口、所有敌人和宝箱都能从入口走到，A* 寻路结果一致
+func TestConnectivity(t *testing.T) {
+	sizes := [][2]int{{MinSize, MinSize}, {25, 18}, {60, 40}}
+	for _, gen := range Generators {
+		for _, size := range sizes {
+			for seed := range uint64(150) {
+				cfg := testConfig(gen, seed, size[0], size[1])
+				f := mustGenerate(t, cfg)
+				for _, err := range f.Validate() {
+					t.Errorf("%s %v 种子 %d: %v\n%s", gen, size, seed, err, f.ASCII())
+				}
+				if _, ok := nav.FindPath(f, f.Start, f.Exit, 0); !ok {
+					t.Errorf("%s %v 种子 %d: 找不到从入口到出口的路径\n%s", gen, size, seed, f.ASCII())
+				}
+				if t.Failed() {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	for _, gen := range Generators {
+		for seed := range uint64(50) {
+			cfg := testConfig(gen, seed, 60, 40)
+			f := mustGenerate(t, cfg)
+			if f.Spent(Enemy) > cfg.Budget || f.Spent(Chest) > cfg.LootBudget {
+				t.Fatalf("%s 种子 %d: 花费 %d/%d 超出预算 %d/%d", gen, seed, f.Spent(Enemy), f.Spent(Chest), cfg.Budget, cfg.LootBudget)
+			}
+			// 大楼层有足够的空间，预算应该花到买不起任何一种为止
+			if cfg.Budget-f.Spent(Enemy) >= 1 || cfg.LootBudget-f.Spent(Chest) >= 1 {
+				t.Fatalf("%s 种子 %d: 预算没有花完 %d/%d", gen, seed, f.Spent(Enemy), f.Spent(Chest))
+			}
+			dist := f.distances(f.Start, f.solid)
+			for _, o := range f.Objects {
+				if o.Type == Enemy && dist[f.index(o.At)] < safeRadius {
+					t.Fatalf("%s 种子 %d: 敌人 %v 离入口太近", gen, seed, o.At)
+				}
+			}
+		}
+	}
+	cfg := testConfig(Rooms, 1, 25, 18)
+	cfg.Budget, cfg.LootBudget = 0, 0
+	if f := mustGenerate(t, cfg); len(f.Objects) != 0 {
+		t.Fatalf("预算为 0 时不应放置对象，实际 %v", f.Objects)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	cfg := testConfig("maze", 1, 5, 40)
+	cfg.Budget = -1
+	cfg.Enemies = []Spawn{{Kind: "slime"}}
+	_, err := Generate(cfg)
+	if err == nil {
+		t.Fatal("参数有误应报错")
+	}
+	for _, want := range []string{"未知的生成器", "至少", "负数", "花费"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("缺少错误 %q，实际 %v", want, err)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	f := mustGenerate(t, testConfig(BSP, 7, 25, 18))
+	m, err := f.Map("b1", "B1", 32, Link{Map: "town", Spawn: "gate"}, Link{Map: "b2", Spawn: SpawnEntrance})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name() != "B1" || m.Width != 25 || m.Solid(f.Start.X, f.Start.Y) != f.Wall(f.Start) {
+		t.Fatalf("地图属性错误: %q %d", m.Name(), m.Width)
+	}
+	start := m.Spawn(SpawnEntrance)
+	if start == nil || start.Col != f.Start.X || start.Row != f.Start.Y || m.Spawn(SpawnExit) == nil {
+		t.Fatalf("出生点 = %+v", start)
+	}
+	if target, spawn := m.WarpAt(f.Entrance.X, f.Entrance.Y).Target(); target != "town" || spawn != "gate" {
+		t.Fatalf("入口通往 %s/%s", target, spawn)
+	}
+	if target, _ := m.WarpAt(f.Exit.X, f.Exit.Y).Target(); target != "b2" {
+		t.Fatalf("出口通往 %s", target)
+	}
+	if len(m.ObjectsOf(Enemy)) == 0 || len(m.ObjectsOf(Chest)) == 0 || m.ObjectsOf(Chest)[0].Name != "chest_1" {
+		t.Fatal("敌人或宝箱没有转换")
+	}
+}
+
+// TestShippedDungeons 校验游戏自带的地下城数据和生成的楼层：楼层之间的楼梯通往存在的出生点，
+// 第一层的入口和最后一层的出口通往地下城的 entry（手工地图由 worldmap 包测试）
+func TestShippedDungeons(t *testing.T) {
+	dungeons, err := Load("../data/dungeons.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range Validate(dungeons) {
+		t.Error(err)
+	}
+	floors, err := Maps(dungeons, 25, 18, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range dungeons {
+		for _, l := range d.Floors {
+			m := floors[l.ID]
+			if m == nil || m.Spawn(SpawnEntrance) == nil || m.Spawn(SpawnExit) == nil {
+				t.Fatalf("楼层 %s 没有生成或缺少出生点", l.ID)
+			}
+			for _, w := range m.ObjectsOf(worldmap.TypeWarp) {
+				target, spawn := w.Target()
+				switch {
+				case target == d.Entry.Map:
+					if spawn != d.Entry.Spawn {
+						t.Errorf("楼层 %s 通往 %s/%s，应为 entry 的出生点 %s", l.ID, target, spawn, d.Entry.Spawn)
+					}
+				case floors[target] == nil || floors[target].Spawn(spawn) == nil:
+					t.Errorf("楼层 %s 通往不存在的 %s/%s", l.ID, target, spawn)
+				}
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	errs := Validate(map[string]*Dungeon{
+		"a": {Floors: []Level{{ID: "x", Generator: "maze", Budget: -1}, {ID: "x", Generator: Cave}}, Enemies: []Spawn{{Cost: 1}}},
+		"b": {Entry: Link{Map: "town", Spawn: "gate"}},
+	})
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	all := strings.Join(got, "\n")
+	for _, want := range []string{"entry 缺少", "没有楼层", "重复", "未知的生成器", "负数", "缺少种类"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("缺少错误 %q，实际:\n%s", want, all)
+		}
+	}
+	d := &Dungeon{Entry: Link{Map: "town", Spawn: "gate"}, Floors: []Level{{ID: "b1"}, {ID: "b2"}, {ID: "b3"}}}
+	if up, down := d.Links(0); up != d.Entry || down != (Link{Map: "b2", Spawn: SpawnEntrance}) {
+		t.Fatalf("第一层连接 %v %v", up, down)
+	}
+	if up, down := d.Links(2); up != (Link{Map: "b2", Spawn: SpawnExit}) || down != d.Entry {
+		t.Fatalf("最后一层连接 %v %v", up, down)
+	}
+}
@@ -0,0 +1,163 @@
+package dungeon
+
+import (
+	"Game/nav"
+	"math/rand/v2"
+	"slices"
+)
+
+// 生成器的参数
+const (
+	roomMin, roomMax = 3, 7 // 房间宽度和高度的范围
+	roomDensity      = 50   // 房间生成器每多少格放一个房间
+	roomTries        = 8    // 每个房间尝试放置的次数
+	bspLeaf          = 6    // BSP 分块的最小边长，边长不到两倍时不再分割
+	caveFill         = 0.45 // 洞穴初始为墙的比例
+	caveSteps        = 5    // 洞穴平滑的次数
+	caveWalls        = 5    // 平滑时自己和周围八格中至少有这么多墙就变成墙
+)
+
+// rect 矩形区域（格子）
+type rect struct {
+	X, Y, W, H int
+}
+
+func (r rect) center() nav.Point {
+	return nav.Point{X: r.X + r.W/2, Y: r.Y + r.H/2}
+}
+
+// overlaps 两个矩形之间是否不足一格的间隔
+func (r rect) overlaps(o rect) bool {
+	return r.X <= o.X+o.W && o.X <= r.X+r.W && r.Y <= o.Y+o.H && o.Y <= r.Y+r.H
+}
+
+// rooms 随机放置互不相邻的房间，按从左到右的顺序依次用走廊连接
+func (f *Floor) rooms(rng *rand.Rand) {
+	var rooms []rect
+	want := max(f.Width*f.Height/roomDensity, 2)
+	for range want * roomTries {
+		if len(rooms) == want {
+			break
+		}
+		w := min(roomMin+rng.IntN(roomMax-roomMin+1), f.Width-2)
+		h := min(roomMin+rng.IntN(roomMax-roomMin+1), f.Height-2)
+		r := rect{X: 1 + rng.IntN(f.Width-w-1), Y: 1 + rng.IntN(f.Height-h-1), W: w, H: h}
+		if !slices.ContainsFunc(rooms, r.overlaps) {
+			rooms = append(rooms, r)
+		}
+	}
+	slices.SortFunc(rooms, func(a, b rect) int { return a.center().X - b.center().X })
+	for i, r := range rooms {
+		f.carve(r)
+		if i > 0 {
+			f.corridor(rng, rooms[i-1].center(), r.center())
+		}
+	}
+}
+
+// bsp 把楼层内部递归地一分为二，每个分块放一个房间，再把兄弟分块的房间用走廊连接
+func (f *Floor) bsp(rng *rand.Rand) {
+	f.split(rng, rect{X: 1, Y: 1, W: f.Width - 2, H: f.Height - 2})
+}
+
+// split 分割区域并在其中挖出房间和走廊，返回区域内某个房间的中心，供上一层连接
+func (f *Floor) split(rng *rand.Rand, r rect) nav.Point {
+	canX, canY := r.W >= 2*bspLeaf, r.H >= 2*bspLeaf
+	if !canX && !canY {
+		// 房间不占分块的最后一行和一列，相邻分块的房间之间至少隔一格墙
+		w, h := roomMin+rng.IntN(r.W-roomMin), roomMin+rng.IntN(r.H-roomMin)
+		room := rect{X: r.X + rng.IntN(r.W-w), Y: r.Y + rng.IntN(r.H-h), W: w, H: h}
+		f.carve(room)
+		return room.center()
+	}
+	vertical := canX && (!canY || r.W > r.H || (r.W == r.H && rng.IntN(2) == 0))
+	var a, b rect
+	if vertical {
+		cut := bspLeaf + rng.IntN(r.W-2*bspLeaf+1)
+		a, b = rect{r.X, r.Y, cut, r.H}, rect{r.X + cut, r.Y, r.W - cut, r.H}
+	} else {
+		cut := bspLeaf + rng.IntN(r.H-2*bspLeaf+1)
+		a, b = rect{r.X, r.Y, r.W, cut}, rect{r.X, r.Y + cut, r.W, r.H - cut}
+	}
+	pa, pb := f.split(rng, a), f.split(rng, b)
+	f.corridor(rng, pa, pb)
+	if rng.IntN(2) == 0 {
+		return pa
+	}
+	return pb
+}
+
+// cave 随机填充墙，再用元胞自动机平滑成洞穴；边框始终是墙
+func (f *Floor) cave(rng *rand.Rand) {
+	for y := 1; y < f.Height-1; y++ {
+		for x := 1; x < f.Width-1; x++ {
+			f.wall[f.index(nav.Point{X: x, Y: y})] = rng.Float64() < caveFill
+		}
+	}
+	next := make([]bool, len(f.wall))
+	for range caveSteps {
+		for i := range f.wall {
+			p := f.point(i)
+			border := p.X == 0 || p.Y == 0 || p.X == f.Width-1 || p.Y == f.Height-1
+			walls := f.wallsAround(p)
+			if f.wall[i] {
+				walls++
+			}
+			next[i] = border || walls >= caveWalls
+		}
+		f.wall, next = next, f.wall
+	}
+}
+
+// carve 把矩形区域挖成地面
+func (f *Floor) carve(r rect) {
+	for y := r.Y; y < r.Y+r.H; y++ {
+		for x := r.X; x < r.X+r.W; x++ {
+			f.wall[f.index(nav.Point{X: x, Y: y})] = false
+		}
+	}
+}
+
+// corridor 用 L 形走廊连接两个格子，随机决定先横走还是先竖走
+func (f *Floor) corridor(rng *rand.Rand, a, b nav.Point) {
+	corner := nav.Point{X: b.X, Y: a.Y}
+	if rng.IntN(2) == 0 {
+		corner = nav.Point{X: a.X, Y: b.Y}
+	}
+	f.line(a, corner)
+	f.line(corner, b)
+}
+
+// line 挖出两个格子之间横向或纵向的直线
+func (f *Floor) line(a, b nav.Point) {
+	f.carve(rect{X: min(a.X, b.X), Y: min(a.Y, b.Y), W: abs(a.X-b.X) + 1, H: abs(a.Y-b.Y) + 1})
+}
+
+// keepLargestRegion 只保留四方向连通的最大一块地面，其余填成墙，保证楼层上每块地面都能走到
+func (f *Floor) keepLargestRegion() {
+	var best []int
+	seen := make([]bool, len(f.wall))
+	for i := range f.wall {
+		if f.wall[i] || seen[i] {
+			continue
+		}
+		dist := f.distances(f.point(i), f.Wall)
+		var region []int
+		for j, d := range dist {
+			if d >= 0 {
+				seen[j] = true
+				region = append(region, j)
+			}
+		}
+		if len(region) > len(best) {
+			best = region
+		}
+	}
+	keep := make([]bool, len(f.wall))
+	for _, i := range best {
+		keep[i] = true
+	}
+	for i := range f.wall {
+		f.wall[i] = !keep[i]
+	}
+}
@@ -0,0 +1,71 @@
+package dungeon
+
+import (
+	"Game/nav"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// 字符画和图片中各种格子的样子
+var (
+	tileChars = map[string]byte{"wall": '#', "floor": '.', "entrance": '<', "exit": '>', Enemy: 'e', Chest: '$'}
+
+	tileColors = map[string]color.RGBA{
+		"wall":     {R: 40, G: 36, B: 48, A: 255},
+		"floor":    {R: 150, G: 140, B: 120, A: 255},
+		"entrance": {R: 80, G: 200, B: 90, A: 255},
+		"exit":     {R: 170, G: 130, B: 255, A: 255},
+		Enemy:      {R: 220, G: 60, B: 50, A: 255},
+		Chest:      {R: 240, G: 200, B: 60, A: 255},
+	}
+)
+
+// ASCII 用字符画出楼层：# 墙，. 地面，< 入口，> 出口，e 敌人，$ 宝箱；每行以换行结尾
+func (f *Floor) ASCII() string {
+	var b strings.Builder
+	for y := range f.Height {
+		for x := range f.Width {
+			b.WriteByte(tileChars[f.tile(nav.Point{X: x, Y: y})])
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Image 把楼层画成图片，每格 scale×scale 像素，格子之间留一像素的缝方便数格子（scale 小于 3 时不留）
+func (f *Floor) Image(scale int) *image.RGBA {
+	scale = max(scale, 1)
+	img := image.NewRGBA(image.Rect(0, 0, f.Width*scale, f.Height*scale))
+	gap := 0
+	if scale >= 3 {
+		gap = 1
+	}
+	for y := range f.Height {
+		for x := range f.Width {
+			c := tileColors[f.tile(nav.Point{X: x, Y: y})]
+			for py := y * scale; py < (y+1)*scale-gap; py++ {
+				for px := x * scale; px < (x+1)*scale-gap; px++ {
+					img.SetRGBA(px, py, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// tile 格子的种类，用于查找字符和颜色
+func (f *Floor) tile(p nav.Point) string {
+	switch {
+	case f.Wall(p):
+		return "wall"
+	case p == f.Entrance:
+		return "entrance"
+	case p == f.Exit:
+		return "exit"
+	}
+	if o := f.ObjectAt(p); o != nil {
+		return o.Type
+	}
+	return "floor"
+}
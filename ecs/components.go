@@ -0,0 +1,85 @@
+package ecs
+
+import (
+	"image"
+)
+
+// Position 世界坐标（实体左上角，像素）
+type Position struct {
+	X, Y float64
+}
+
+// Velocity 速度（像素/秒）
+type Velocity struct {
+	X, Y float64
+}
+
+// Image 精灵使用的图片；实体系统只需要知道它的尺寸，具体的图片类型和绘制由游戏决定
+type Image interface {
+	Bounds() image.Rectangle
+}
+
+// Sprite 实体的图像
+type Sprite struct {
+	Image            Image
+	Rect             image.Rectangle // 使用精灵图中的区域，为空时使用整张图片
+	Width, Height    float64         // 绘制尺寸，为 0 时使用原始尺寸
+	OffsetX, OffsetY float64         // 相对 Position 的绘制偏移
+	Layer            int             // 绘制层，数值大的绘制在上面
+	Hidden           bool            // 是否隐藏
+}
+
+// Collider 轴对齐碰撞盒
+type Collider struct {
+	OffsetX, OffsetY float64 // 相对 Position 的偏移
+	W, H             float64 // 尺寸
+	Solid            bool    // 实心碰撞盒会阻挡其他实心实体移动；非实心的只检测重叠（如拾取物、传送点）
+}
+
+// Health 生命值
+type Health struct {
+	Current, Max int
+}
+
+// ItemStack 一组物品
+type ItemStack struct {
+	ID    int64 // 物品 id，对应物品表
+	Count int64 // 数量
+}
+
+// Inventory 实体携带的物品（NPC、敌人掉落、宝箱等）
+type Inventory struct {
+	Items    []ItemStack
+	Capacity int // 最多可放的格子数，0 表示不限
+}
+
+// AI 非玩家实体的行为
+type AI struct {
+	Behavior     string  // 行为类型，如 wander、guard
+	State        string  // 当前状态
+	HomeX, HomeY float64 // 出生点
+	Target       Entity  // 当前目标
+}
+
+// Rect 轴对齐矩形
+type Rect struct {
+	X, Y, W, H float64
+}
+
+// Overlaps 两个矩形是否相交（边缘相接不算）
+func (r Rect) Overlaps(o Rect) bool {
+	return r.X < o.X+o.W && o.X < r.X+r.W && r.Y < o.Y+o.H && o.Y < r.Y+r.H
+}
+
+// Bounds 返回碰撞盒在世界中的矩形
+func (c *Collider) Bounds(pos *Position) Rect {
+	return Rect{X: pos.X + c.OffsetX, Y: pos.Y + c.OffsetY, W: c.W, H: c.H}
+}
+
+// DrawRect 返回实际使用的图片区域
+func (s *Sprite) DrawRect() image.Rectangle {
+	if s.Rect.Empty() {
+		return s.Image.Bounds()
+	}
+	return s.Rect
+}
@@ -0,0 +1,144 @@
+package ecs
+
+import (
+	"slices"
+	"time"
+)
+
+// MovementSystem 按速度移动实体；实心实体会被其他实心实体、阻挡区域和世界边界挡住
+type MovementSystem struct {
+	Bounds  Rect              // 世界边界，宽高为 0 时不限制
+	Blocked func(r Rect) bool // 额外的阻挡判断（如地图上的墙），可为空
+}
+
+// Update 移动所有拥有 Position 和 Velocity 的实体
+func (s *MovementSystem) Update(w *World, dt time.Duration) {
+	step := dt.Seconds()
+	Each2(w, func(e Entity, pos *Position, vel *Velocity) {
+		if vel.X == 0 && vel.Y == 0 {
+			return
+		}
+		col := Get[Collider](w, e)
+		// 分轴移动，撞墙时仍可沿另一轴滑动
+		s.moveAxis(w, e, pos, col, vel.X*step, 0)
+		s.moveAxis(w, e, pos, col, 0, vel.Y*step)
+	})
+}
+
+// moveAxis 沿一个轴移动，被阻挡时退回原位置
+func (s *MovementSystem) moveAxis(w *World, e Entity, pos *Position, col *Collider, dx, dy float64) {
+	if dx == 0 && dy == 0 {
+		return
+	}
+	oldX, oldY := pos.X, pos.Y
+	pos.X += dx
+	pos.Y += dy
+	s.clampToBounds(pos, col)
+	if col == nil || !col.Solid {
+		return
+	}
+	box := col.Bounds(pos)
+	if (s.Blocked != nil && s.Blocked(box)) || solidAt(w, e, box) {
+		pos.X, pos.Y = oldX, oldY
+	}
+}
+
+// clampToBounds 把实体限制在世界边界内
+func (s *MovementSystem) clampToBounds(pos *Position, col *Collider) {
+	if s.Bounds.W == 0 || s.Bounds.H == 0 {
+		return
+	}
+	w, h := 0.0, 0.0
+	offX, offY := 0.0, 0.0
+	if col != nil {
+		w, h, offX, offY = col.W, col.H, col.OffsetX, col.OffsetY
+	}
+	pos.X = min(max(pos.X, s.Bounds.X-offX), s.Bounds.X+s.Bounds.W-w-offX)
+	pos.Y = min(max(pos.Y, s.Bounds.Y-offY), s.Bounds.Y+s.Bounds.H-h-offY)
+}
+
+// solidAt 矩形是否与除 self 外的任何实心碰撞盒相交
+func solidAt(w *World, self Entity, box Rect) bool {
+	for _, e := range w.Query(MaskOf[Position](w) | MaskOf[Collider](w)) {
+		if e == self {
+			continue
+		}
+		col := Get[Collider](w, e)
+		if col.Solid && col.Bounds(Get[Position](w, e)).Overlaps(box) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contact 两个碰撞盒相交的实体（A 的编号小于 B）
+type Contact struct {
+	A, B Entity
+}
+
+// Contacts 返回所有相交的碰撞盒对，用于拾取、触发器等
+func Contacts(w *World) []Contact {
+	entities := w.Query(MaskOf[Position](w) | MaskOf[Collider](w))
+	boxes := make([]Rect, len(entities))
+	for i, e := range entities {
+		boxes[i] = Get[Collider](w, e).Bounds(Get[Position](w, e))
+	}
+	var contacts []Contact
+	for i := range entities {
+		for j := i + 1; j < len(entities); j++ {
+			if boxes[i].Overlaps(boxes[j]) {
+				contacts = append(contacts, Contact{A: entities[i], B: entities[j]})
+			}
+		}
+	}
+	return contacts
+}
+
+// Touching 返回与实体 e 的碰撞盒相交的所有实体
+func Touching(w *World, e Entity) []Entity {
+	pos, col := Get[Position](w, e), Get[Collider](w, e)
+	if pos == nil || col == nil {
+		return nil
+	}
+	box := col.Bounds(pos)
+	var result []Entity
+	for _, other := range w.Query(MaskOf[Position](w) | MaskOf[Collider](w)) {
+		if other != e && Get[Collider](w, other).Bounds(Get[Position](w, other)).Overlaps(box) {
+			result = append(result, other)
+		}
+	}
+	return result
+}
+
+// RenderOrder 返回需要绘制的实体：先按 Layer，同层按底边 Y 坐标（靠下的后画，遮挡靠上的）
+func RenderOrder(w *World) []Entity {
+	var result []Entity
+	for _, e := range w.Query(MaskOf[Position](w) | MaskOf[Sprite](w)) {
+		if !Get[Sprite](w, e).Hidden {
+			result = append(result, e)
+		}
+	}
+	bottom := func(e Entity) float64 {
+		pos, sp := Get[Position](w, e), Get[Sprite](w, e)
+		h := sp.Height
+		if h == 0 && sp.Image != nil {
+			h = float64(sp.DrawRect().Dy())
+		}
+		return pos.Y + sp.OffsetY + h
+	}
+	slices.SortStableFunc(result, func(a, b Entity) int {
+		la, lb := Get[Sprite](w, a).Layer, Get[Sprite](w, b).Layer
+		if la != lb {
+			return la - lb
+		}
+		ya, yb := bottom(a), bottom(b)
+		switch {
+		case ya < yb:
+			return -1
+		case ya > yb:
+			return 1
+		}
+		return int(a) - int(b)
+	})
+	return result
+}
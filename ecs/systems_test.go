@@ -0,0 +1,95 @@
+package ecs
+
+import (
+	"image"
+	"slices"
+	"testing"
+	"time"
+)
+
+// spawnBox 创建一个 16×16 碰撞盒的实体
+func spawnBox(w *World, x, y float64, solid bool) Entity {
+	e := w.Spawn()
+	Add(w, e, Position{X: x, Y: y})
+	Add(w, e, Collider{W: 16, H: 16, Solid: solid})
+	return e
+}
+
+func TestMovement(t *testing.T) {
+	w := NewWorld()
+	sys := &MovementSystem{Bounds: Rect{W: 100, H: 100}}
+	mover := spawnBox(w, 0, 0, true)
+	Add(w, mover, Velocity{X: 10, Y: 10})
+	wall := spawnBox(w, 20, 0, true)
+	spawnBox(w, 0, 30, false) // 非实心的不阻挡
+
+	// 撞到右边的墙时 X 退回，Y 仍然移动（沿墙滑动）
+	sys.Update(w, time.Second)
+	pos := Get[Position](w, mover)
+	if *pos != (Position{X: 0, Y: 10}) {
+		t.Fatalf("撞墙后位置 = %+v", *pos)
+	}
+	sys.Update(w, time.Second)
+	if *pos != (Position{X: 0, Y: 20}) {
+		t.Fatalf("沿墙滑动后位置 = %+v", *pos)
+	}
+	sys.Update(w, time.Second)
+	if *pos != (Position{X: 10, Y: 30}) {
+		t.Fatalf("离开墙后位置 = %+v", *pos)
+	}
+
+	// 额外的阻挡判断
+	sys.Blocked = func(r Rect) bool { return r.Y+r.H > 50 }
+	sys.Update(w, 2*time.Second)
+	if *pos != (Position{X: 30, Y: 30}) {
+		t.Fatalf("被 Blocked 挡住后位置 = %+v", *pos)
+	}
+
+	// 限制在世界边界内，墙本身没有速度不会移动
+	sys.Blocked = nil
+	sys.Update(w, 10*time.Second)
+	if *pos != (Position{X: 84, Y: 84}) || *Get[Position](w, wall) != (Position{X: 20}) {
+		t.Fatalf("边界 = %+v，墙 %+v", *pos, *Get[Position](w, wall))
+	}
+}
+
+func TestContacts(t *testing.T) {
+	w := NewWorld()
+	a := spawnBox(w, 0, 0, true)
+	b := spawnBox(w, 10, 10, false)
+	spawnBox(w, 16, -10, false) // 与 a 边缘相接不算相交
+	if got := Contacts(w); !slices.Equal(got, []Contact{{A: a, B: b}}) {
+		t.Fatalf("Contacts = %v", got)
+	}
+	if got := Touching(w, b); !slices.Equal(got, []Entity{a}) {
+		t.Fatalf("Touching = %v", got)
+	}
+}
+
+// testImage 只有尺寸的图片
+type testImage struct{ w, h int }
+
+func (i testImage) Bounds() image.Rectangle { return image.Rect(0, 0, i.w, i.h) }
+
+func TestRenderOrder(t *testing.T) {
+	w := NewWorld()
+	sprite := func(y float64, sp Sprite) Entity {
+		e := w.Spawn()
+		Add(w, e, Position{Y: y})
+		Add(w, e, sp)
+		return e
+	}
+	tall := sprite(0, Sprite{Image: testImage{16, 48}})               // 底边 48
+	short := sprite(20, Sprite{Image: testImage{16, 16}})             // 底边 36
+	sized := sprite(0, Sprite{Image: testImage{16, 16}, Height: 40})  // 绘制尺寸优先，底边 40
+	same := sprite(20, Sprite{Image: testImage{16, 16}})              // 底边相同时按编号
+	above := sprite(100, Sprite{Image: testImage{16, 16}, Layer: -1}) // 低层先画
+	top := sprite(0, Sprite{Image: testImage{16, 16}, Layer: 1})
+	sprite(0, Sprite{Image: testImage{16, 16}, Hidden: true})
+	sprite(0, Sprite{Image: testImage{64, 64}, Rect: image.Rect(0, 0, 16, 8), OffsetY: 30}) // 使用区域的高度，底边 38
+
+	want := []Entity{above, short, same, 8, sized, tall, top}
+	if got := RenderOrder(w); !slices.Equal(got, want) {
+		t.Fatalf("RenderOrder = %v，应为 %v", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package ecs
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Entity 实体编号，0 表示空实体
+type Entity uint32
+
+// Mask 组件集合的位掩码，每种组件占一位
+type Mask uint64
+
+// storage 类型擦除后的组件存储，用于销毁实体时统一删除
+type storage interface {
+	remove(e Entity)
+}
+
+// store 某一种组件的存储
+type store[T any] struct {
+	data map[Entity]*T
+}
+
+func (s *store[T]) remove(e Entity) {
+	delete(s.data, e)
+}
+
+// World 实体与组件的注册表，以及不属于任何实体的全局资源
+// 组件类型在第一次使用时自动注册，最多支持 64 种组件
+type World struct {
+	next      Entity
+	entities  map[Entity]Mask // 实体 -> 拥有的组件掩码
+	types     map[reflect.Type]int
+	stores    []storage
+	resources map[reflect.Type]any // 资源类型 -> 指针
+}
+
+// NewWorld 创建空的世界
+func NewWorld() *World {
+	return &World{
+		entities:  map[Entity]Mask{},
+		types:     map[reflect.Type]int{},
+		resources: map[reflect.Type]any{},
+	}
+}
+
+// Spawn 创建新实体；实体编号只增不减，销毁的编号不会再分配，旧的编号不会误指向新实体
+func (w *World) Spawn() Entity {
+	w.next++
+	w.entities[w.next] = 0
+	return w.next
+}
+
+// Despawn 销毁实体及其所有组件
+func (w *World) Despawn(e Entity) {
+	mask, ok := w.entities[e]
+	if !ok {
+		return
+	}
+	for id, s := range w.stores {
+		if mask&(1<<id) != 0 {
+			s.remove(e)
+		}
+	}
+	delete(w.entities, e)
+}
+
+// Alive 实体是否存在
+func (w *World) Alive(e Entity) bool {
+	_, ok := w.entities[e]
+	return ok
+}
+
+// Len 返回实体数量
+func (w *World) Len() int {
+	return len(w.entities)
+}
+
+// Query 返回拥有 mask 中全部组件的实体，按编号升序排列保证遍历顺序稳定
+// 返回的是快照，遍历期间可以安全地创建或销毁实体
+func (w *World) Query(mask Mask) []Entity {
+	var result []Entity
+	for e, m := range w.entities {
+		if m&mask == mask {
+			result = append(result, e)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// componentID 返回组件类型的编号，首次使用时注册
+func componentID[T any](w *World) int {
+	t := reflect.TypeFor[T]()
+	if id, ok := w.types[t]; ok {
+		return id
+	}
+	id := len(w.stores)
+	if id >= 64 {
+		panic("ecs: 组件类型超过 64 种")
+	}
+	w.types[t] = id
+	w.stores = append(w.stores, &store[T]{data: map[Entity]*T{}})
+	return id
+}
+
+// storeOf 返回组件类型的存储
+func storeOf[T any](w *World) (*store[T], int) {
+	id := componentID[T](w)
+	return w.stores[id].(*store[T]), id
+}
+
+// MaskOf 返回单个组件类型的掩码，多个掩码可以用 | 组合后传给 Query
+func MaskOf[T any](w *World) Mask {
+	return 1 << componentID[T](w)
+}
+
+// Add 给实体添加（或替换）组件，返回存储中的组件指针
+func Add[T any](w *World, e Entity, c T) *T {
+	if !w.Alive(e) {
+		return nil
+	}
+	s, id := storeOf[T](w)
+	p := &c
+	s.data[e] = p
+	w.entities[e] |= 1 << id
+	return p
+}
+
+// Get 返回实体的组件，不存在时返回 nil
+func Get[T any](w *World, e Entity) *T {
+	s, _ := storeOf[T](w)
+	return s.data[e]
+}
+
+// Has 实体是否拥有该组件
+func Has[T any](w *World, e Entity) bool {
+	return Get[T](w, e) != nil
+}
+
+// Remove 删除实体的组件
+func Remove[T any](w *World, e Entity) {
+	if !w.Alive(e) {
+		return
+	}
+	s, id := storeOf[T](w)
+	s.remove(e)
+	w.entities[e] &^= 1 << id
+}
+
+// Each 遍历拥有组件 A 的所有实体
+func Each[A any](w *World, fn func(e Entity, a *A)) {
+	for _, e := range w.Query(MaskOf[A](w)) {
+		fn(e, Get[A](w, e))
+	}
+}
+
+// Each2 遍历同时拥有组件 A 和 B 的所有实体
+func Each2[A, B any](w *World, fn func(e Entity, a *A, b *B)) {
+	for _, e := range w.Query(MaskOf[A](w) | MaskOf[B](w)) {
+		fn(e, Get[A](w, e), Get[B](w, e))
+	}
+}
+
+// SetResource 设置（或替换）全局资源，每种类型一个，用于多个系统共享、不属于任何实体的状态；返回存储中的指针
+func SetResource[T any](w *World, r T) *T {
+	p := &r
+	w.resources[reflect.TypeFor[T]()] = p
+	return p
+}
+
+// Resource 返回全局资源，没有设置时返回 nil
+func Resource[T any](w *World) *T {
+	r, _ := w.resources[reflect.TypeFor[T]()].(*T)
+	return r
+}
@@ -0,0 +1,105 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+type (
+	tagA struct{ N int }
+	tagB struct{}
+)
+
+func TestQuery(t *testing.T) {
+	w := NewWorld()
+	a, b, c := w.Spawn(), w.Spawn(), w.Spawn()
+	Add(w, c, tagA{N: 3})
+	Add(w, a, tagA{N: 1})
+	Add(w, a, tagB{})
+	Add(w, b, tagB{})
+
+	maskA, maskB := MaskOf[tagA](w), MaskOf[tagB](w)
+	if maskA == maskB || maskA != MaskOf[tagA](w) {
+		t.Fatalf("掩码 %b %b", maskA, maskB)
+	}
+	if got := w.Query(maskA); !slices.Equal(got, []Entity{a, c}) {
+		t.Fatalf("Query(A) = %v", got)
+	}
+	if got := w.Query(maskA | maskB); !slices.Equal(got, []Entity{a}) {
+		t.Fatalf("Query(A|B) = %v", got)
+	}
+	if got := w.Query(0); !slices.Equal(got, []Entity{a, b, c}) {
+		t.Fatalf("Query(0) 应返回所有实体，实际 %v", got)
+	}
+
+	// 替换组件不改变掩码，删除后不再匹配
+	Add(w, a, tagA{N: 2})
+	if Get[tagA](w, a).N != 2 {
+		t.Fatal("Add 应替换组件")
+	}
+	Remove[tagA](w, a)
+	if Has[tagA](w, a) || !Has[tagB](w, a) {
+		t.Fatal("Remove 只应删除一种组件")
+	}
+	if got := w.Query(maskA); !slices.Equal(got, []Entity{c}) {
+		t.Fatalf("删除后 Query(A) = %v", got)
+	}
+
+	var seen []Entity
+	Each2(w, func(e Entity, _ *tagA, _ *tagB) { seen = append(seen, e) })
+	if len(seen) != 0 {
+		t.Fatalf("Each2 = %v", seen)
+	}
+}
+
+// TestSpawnDespawn 销毁的编号不会再分配，旧编号不会取到新实体的组件
+func TestSpawnDespawn(t *testing.T) {
+	w := NewWorld()
+	a := w.Spawn()
+	Add(w, a, tagA{N: 1})
+	w.Despawn(a)
+	if w.Alive(a) || Has[tagA](w, a) || w.Len() != 0 {
+		t.Fatal("销毁后实体和组件应不存在")
+	}
+	w.Despawn(a) // 重复销毁无影响
+
+	b := w.Spawn()
+	Add(w, b, tagA{N: 2})
+	if b == a || w.Alive(a) || Get[tagA](w, a) != nil {
+		t.Fatalf("新实体 %d 复用了销毁的编号 %d", b, a)
+	}
+	if Add(w, a, tagB{}) != nil || Has[tagB](w, a) {
+		t.Fatal("不能给已销毁的实体添加组件")
+	}
+	if got := w.Query(MaskOf[tagA](w)); !slices.Equal(got, []Entity{b}) {
+		t.Fatalf("Query = %v", got)
+	}
+
+	// 遍历的是快照，遍历中销毁实体不影响其余实体
+	c := w.Spawn()
+	Add(w, c, tagA{})
+	var seen []Entity
+	Each(w, func(e Entity, _ *tagA) {
+		seen = append(seen, e)
+		w.Despawn(e)
+	})
+	if !slices.Equal(seen, []Entity{b, c}) || w.Len() != 0 {
+		t.Fatalf("遍历 %v，剩余 %d", seen, w.Len())
+	}
+}
+
+func TestResource(t *testing.T) {
+	w := NewWorld()
+	if Resource[tagA](w) != nil {
+		t.Fatal("没有设置的资源应为 nil")
+	}
+	r := SetResource(w, tagA{N: 1})
+	r.N++
+	if Resource[tagA](w).N != 2 || Resource[tagB](w) != nil {
+		t.Fatal("Resource 应返回同一个指针")
+	}
+	SetResource(w, tagA{N: 5})
+	if Resource[tagA](w).N != 5 {
+		t.Fatal("SetResource 应替换资源")
+	}
+}
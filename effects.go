@@ -0,0 +1,488 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/nav"
+	"Game/status"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+)
+
+// UseError 物品使用失败的原因，Reason 为界面文字的翻译键
+type UseError struct {
+	Reason string
+}
+
+func (e *UseError) Error() string {
+	return e.Reason
+}
+
+// 物品使用失败的原因
+var (
+	ErrNoItem      = &UseError{Reason: "use.noItem"}      // 物品不存在或数量为 0
+	ErrNotUsable   = &UseError{Reason: "use.notUsable"}   // 物品没有使用效果
+	ErrNoTarget    = &UseError{Reason: "use.noTarget"}    // 目标不能接受该效果
+	ErrFullHealth  = &UseError{Reason: "use.fullHealth"}  // 生命值已满
+	ErrFullMana    = &UseError{Reason: "use.fullMana"}    // 法力值已满
+	ErrFullStamina = &UseError{Reason: "use.fullStamina"} // 体力已满
+	ErrBlocked     = &UseError{Reason: "use.blocked"}     // 目的地被阻挡
+	ErrRecipeKnown = &UseError{Reason: "use.recipeKnown"} // 配方已学会
+	ErrCooldown    = &UseError{Reason: "use.cooldown"}    // 物品还在冷却
+	ErrImmune      = &UseError{Reason: "use.immune"}      // 目标对状态免疫
+	ErrStronger    = &UseError{Reason: "use.stronger"}    // 目标已有更强的同种状态
+	ErrNoStatus    = &UseError{Reason: "use.noStatus"}    // 目标没有可以解除的状态
+)
+
+// useErrorText 返回使用失败的提示文字；效果返回的不是 UseError 时记录日志并显示通用的提示
+func (p *PlayScreen) useErrorText(err error) string {
+	var useErr *UseError
+	if errors.As(err, &useErr) {
+		return p.settings.T(useErr.Reason)
+	}
+	log.Printf("使用失败: %v", err)
+	return p.settings.T("use.failed")
+}
+
+// EffectContext 使用物品时效果的上下文
+type EffectContext struct {
+	screen *PlayScreen
+	user   ecs.Entity // 使用者
+	target ecs.Entity // 效果目标
+}
+
+// Effect 物品使用效果
+type Effect interface {
+	// Check 检查效果能否生效，不能时返回失败原因，不修改任何状态
+	Check(ctx *EffectContext) error
+	// Apply 执行效果（调用前所有效果都已通过 Check）
+	Apply(ctx *EffectContext)
+}
+
+// EffectFactory 根据数据文件中的参数创建效果
+type EffectFactory func(params json.RawMessage) (Effect, error)
+
+// effectRegistry 效果名 -> 创建函数，数据文件通过 "effect" 字段引用
+var effectRegistry = map[string]EffectFactory{
+	"heal":          newHealEffect,
+	"restore":       newRestoreEffect,
+	"teleport":      newTeleportEffect,
+	"spawn":         newSpawnEffect,
+	"nova":          newNovaEffect,
+	"status":        newStatusEffect,
+	"cure":          newCureEffect,
+	"unlock_recipe": newUnlockRecipeEffect,
+}
+
+// newEffect 根据 {"effect": 名称, ...参数} 创建效果
+func newEffect(raw json.RawMessage) (Effect, error) {
+	var head struct {
+		Effect string `json:"effect"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+	factory, ok := effectRegistry[head.Effect]
+	if !ok {
+		return nil, fmt.Errorf("未知的效果 %q", head.Effect)
+	}
+	effect, err := factory(raw)
+	if err != nil {
+		return nil, fmt.Errorf("效果 %s: %w", head.Effect, err)
+	}
+	return effect, nil
+}
+
+// UseItem 对目标使用背包中的物品
+// 所有效果都能生效时才会执行，并消耗一次使用次数；次数用完时数量减一，数量为 0 时移出背包
+func (p *PlayScreen) UseItem(it *item, target ecs.Entity) error {
+	if it == nil || it.count <= 0 {
+		return ErrNoItem
+	}
+	if !it.ItemData.Usable() {
+		return ErrNotUsable
+	}
+	if p.bag().Cooldowns[it.ItemData.id] > 0 {
+		return ErrCooldown
+	}
+
+	ctx := &EffectContext{screen: p, user: p.player, target: target}
+	for _, effect := range it.ItemData.Effects {
+		if err := effect.Check(ctx); err != nil {
+			return err
+		}
+	}
+	for _, effect := range it.ItemData.Effects {
+		effect.Apply(ctx)
+	}
+	if it.ItemData.Cooldown > 0 {
+		p.bag().Cooldowns[it.ItemData.id] = it.ItemData.Cooldown
+	}
+
+	// 消耗使用次数
+	if it.charges <= 0 {
+		it.charges = it.ItemData.Charges
+	}
+	it.charges--
+	if it.charges == 0 {
+		it.count--
+	}
+	if it.count <= 0 {
+		p.removeItem(it)
+	}
+	p.inventoryChanged()
+	return nil
+}
+
+// useSelectedItem 使用背包中选中的物品，并在屏幕上提示结果
+func (p *PlayScreen) useSelectedItem() {
+	if p.bagUI.selected < 0 || p.bagUI.selected >= len(p.bag().Items) {
+		return
+	}
+	p.useItem(p.bag().Items[p.bagUI.selected])
+}
+
+// useItem 使用或装备背包中的物品，并在屏幕上提示结果
+func (p *PlayScreen) useItem(it *item) {
+	if it.ItemData.Slot != "" {
+		if err := p.Equip(it); err != nil {
+			p.notices.Show(p.useErrorText(err))
+			return
+		}
+		p.notices.Show(fmt.Sprintf(p.settings.T("equip.equipped"), it.ItemData.Name))
+		return
+	}
+	if err := p.UseItem(it, p.player); err != nil {
+		p.notices.Show(p.useErrorText(err))
+		return
+	}
+	p.notices.Show(fmt.Sprintf(p.settings.T("use.used"), it.ItemData.Name))
+}
+
+// removeItem 把物品从背包中移除，并保持选中位置有效
+func (p *PlayScreen) removeItem(it *item) {
+	index := slices.Index(p.bag().Items, it)
+	if index < 0 {
+		return
+	}
+	p.bag().Items = slices.Delete(p.bag().Items, index, index+1)
+	if p.bagUI.selected >= len(p.bag().Items) && p.bagUI.selected > 0 {
+		p.bagUI.selected = len(p.bag().Items) - 1
+	}
+	p.inventoryChanged()
+}
+
+// healEffect 恢复生命值
+type healEffect struct {
+	Amount int `json:"amount"`
+}
+
+func newHealEffect(params json.RawMessage) (Effect, error) {
+	e := &healEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if e.Amount <= 0 {
+		return nil, fmt.Errorf("amount 必须大于 0")
+	}
+	return e, nil
+}
+
+func (e *healEffect) Check(ctx *EffectContext) error {
+	health := ecs.Get[ecs.Health](ctx.screen.world, ctx.target)
+	if health == nil {
+		return ErrNoTarget
+	}
+	if health.Current >= health.Max {
+		return ErrFullHealth
+	}
+	return nil
+}
+
+func (e *healEffect) Apply(ctx *EffectContext) {
+	if ctx.target == ctx.screen.player {
+		ctx.screen.restoreVital(VitalHealth, e.Amount)
+		return
+	}
+	health := ecs.Get[ecs.Health](ctx.screen.world, ctx.target)
+	health.Current = min(health.Current+e.Amount, health.Max)
+}
+
+// restoreEffect 恢复玩家的法力值或体力
+type restoreEffect struct {
+	Stat   VitalStat `json:"stat"`
+	Amount int       `json:"amount"`
+}
+
+func newRestoreEffect(params json.RawMessage) (Effect, error) {
+	e := &restoreEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if e.Stat != VitalMana && e.Stat != VitalStamina {
+		return nil, fmt.Errorf("stat 必须是 %s 或 %s", VitalMana, VitalStamina)
+	}
+	if e.Amount <= 0 {
+		return nil, fmt.Errorf("amount 必须大于 0")
+	}
+	return e, nil
+}
+
+func (e *restoreEffect) Check(ctx *EffectContext) error {
+	v := ecs.Get[Vitals](ctx.screen.world, ctx.target)
+	if v == nil {
+		return ErrNoTarget
+	}
+	if pool := v.pool(e.Stat); pool.Current >= pool.Max {
+		if e.Stat == VitalMana {
+			return ErrFullMana
+		}
+		return ErrFullStamina
+	}
+	return nil
+}
+
+func (e *restoreEffect) Apply(ctx *EffectContext) {
+	ctx.screen.restoreVital(e.Stat, e.Amount)
+}
+
+// teleportEffect 把目标传送到指定格子；指定了其他地图时只能传送玩家，淡出后切换地图
+type teleportEffect struct {
+	Map string `json:"map"` // 为空表示当前地图
+	Col int    `json:"col"`
+	Row int    `json:"row"`
+}
+
+func newTeleportEffect(params json.RawMessage) (Effect, error) {
+	e := &teleportEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *teleportEffect) Check(ctx *EffectContext) error {
+	p := ctx.screen
+	if ecs.Get[ecs.Position](p.world, ctx.target) == nil {
+		return ErrNoTarget
+	}
+	if e.Map != "" && e.Map != p.atlas().Current {
+		if ctx.target != p.player {
+			return ErrNoTarget
+		}
+		return nil
+	}
+	if tileGrid(p.gridData).Blocked(nav.Point{X: e.Col, Y: e.Row}) {
+		return ErrBlocked
+	}
+	return nil
+}
+
+func (e *teleportEffect) Apply(ctx *EffectContext) {
+	if e.Map != "" && e.Map != ctx.screen.atlas().Current {
+		ctx.screen.transitionTo(e.Map, e.Col, e.Row)
+		return
+	}
+	pos := ecs.Get[ecs.Position](ctx.screen.world, ctx.target)
+	pos.X = float64(e.Col * ctx.screen.gridSize)
+	pos.Y = float64(e.Row * ctx.screen.gridSize)
+}
+
+// novaEffect 攻击使用者周围一定范围内的所有敌人，伤害按使用者的属性结算
+type novaEffect struct {
+	Radius float64 `json:"radius"` // 范围（格子）
+}
+
+func newNovaEffect(params json.RawMessage) (Effect, error) {
+	e := &novaEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if e.Radius <= 0 {
+		return nil, fmt.Errorf("radius 必须大于 0")
+	}
+	return e, nil
+}
+
+// enemiesInRadius 返回使用者周围 radius 格以内的敌人
+func enemiesInRadius(ctx *EffectContext, radius float64) []ecs.Entity {
+	p := ctx.screen
+	x, y := p.entityBox(ctx.user).Center()
+	reach := radius * float64(p.gridSize)
+	var targets []ecs.Entity
+	for _, enemy := range p.world.Query(ecs.MaskOf[Enemy](p.world) | ecs.MaskOf[ecs.Position](p.world)) {
+		ex, ey := p.entityBox(enemy).Center()
+		if math.Hypot(ex-x, ey-y) <= reach {
+			targets = append(targets, enemy)
+		}
+	}
+	return targets
+}
+
+func (e *novaEffect) Check(ctx *EffectContext) error {
+	if len(enemiesInRadius(ctx, e.Radius)) == 0 {
+		return ErrNoTarget
+	}
+	return nil
+}
+
+func (e *novaEffect) Apply(ctx *EffectContext) {
+	for _, target := range enemiesInRadius(ctx, e.Radius) {
+		ctx.screen.strike(ctx.user, target)
+	}
+}
+
+// statusEffect 施加状态效果：Radius 为 0 时施加给目标，否则施加给使用者周围的所有敌人（跳过免疫的敌人）
+type statusEffect struct {
+	Status  string  `json:"status"`
+	Seconds float64 `json:"seconds"`
+	Power   float64 `json:"power"`  // 强度，省略时使用状态的默认强度
+	Radius  float64 `json:"radius"` // 范围（格子）
+}
+
+func newStatusEffect(params json.RawMessage) (Effect, error) {
+	e := &statusEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if StatusDefs[e.Status] == nil {
+		return nil, fmt.Errorf("状态 %q 不存在", e.Status)
+	}
+	if e.Seconds <= 0 || e.Radius < 0 {
+		return nil, fmt.Errorf("seconds 必须大于 0，radius 不能为负数")
+	}
+	return e, nil
+}
+
+func (e *statusEffect) Check(ctx *EffectContext) error {
+	if e.Radius > 0 {
+		if len(enemiesInRadius(ctx, e.Radius)) == 0 {
+			return ErrNoTarget
+		}
+		return nil
+	}
+	s := ctx.screen.statusSet(ctx.target)
+	if s == nil {
+		return ErrNoTarget
+	}
+	switch err := s.CanApply(StatusDefs[e.Status], e.Power); {
+	case errors.Is(err, status.ErrImmune):
+		return ErrImmune
+	case errors.Is(err, status.ErrWeaker):
+		return ErrStronger
+	}
+	return nil
+}
+
+func (e *statusEffect) Apply(ctx *EffectContext) {
+	if e.Radius == 0 {
+		ctx.screen.applyStatus(ctx.target, e.Status, e.Power, e.Seconds)
+		return
+	}
+	for _, target := range enemiesInRadius(ctx, e.Radius) {
+		ctx.screen.applyStatus(target, e.Status, e.Power, e.Seconds)
+	}
+}
+
+// cureEffect 解除目标身上的状态
+type cureEffect struct {
+	Statuses []string `json:"statuses"`
+}
+
+func newCureEffect(params json.RawMessage) (Effect, error) {
+	e := &cureEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if len(e.Statuses) == 0 {
+		return nil, fmt.Errorf("缺少 statuses")
+	}
+	for _, id := range e.Statuses {
+		if StatusDefs[id] == nil {
+			return nil, fmt.Errorf("状态 %q 不存在", id)
+		}
+	}
+	return e, nil
+}
+
+func (e *cureEffect) Check(ctx *EffectContext) error {
+	s := ctx.screen.statusSet(ctx.target)
+	if s == nil || !slices.ContainsFunc(e.Statuses, s.Has) {
+		return ErrNoStatus
+	}
+	return nil
+}
+
+func (e *cureEffect) Apply(ctx *EffectContext) {
+	s := ctx.screen.statusSet(ctx.target)
+	for _, id := range e.Statuses {
+		s.Remove(id)
+	}
+}
+
+// entityPrototypes 可由 spawn 效果生成的实体：名称 -> 在格子 (col, row) 生成实体
+var entityPrototypes = map[string]func(p *PlayScreen, params spawnEffect, col, row int) ecs.Entity{
+	"pickup": func(p *PlayScreen, params spawnEffect, col, row int) ecs.Entity {
+		return p.spawnPickup(params.Item, params.Count, col, row)
+	},
+}
+
+// spawnEffect 在使用者面前的格子生成实体
+type spawnEffect struct {
+	Prototype string `json:"prototype"`
+	Item      int64  `json:"item"`  // pickup 的物品 id
+	Count     int64  `json:"count"` // pickup 的数量
+}
+
+func newSpawnEffect(params json.RawMessage) (Effect, error) {
+	e := &spawnEffect{Count: 1}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if _, ok := entityPrototypes[e.Prototype]; !ok {
+		return nil, fmt.Errorf("未知的实体原型 %q", e.Prototype)
+	}
+	return e, nil
+}
+
+func (e *spawnEffect) Check(*EffectContext) error {
+	if e.Prototype == "pickup" && ItemImages[e.Item] == nil {
+		return ErrNotUsable
+	}
+	return nil
+}
+
+func (e *spawnEffect) Apply(ctx *EffectContext) {
+	col, row := ctx.screen.frontTile()
+	entityPrototypes[e.Prototype](ctx.screen, *e, col, row)
+}
+
+// unlockRecipeEffect 学会合成配方
+type unlockRecipeEffect struct {
+	Recipe string `json:"recipe"`
+}
+
+func newUnlockRecipeEffect(params json.RawMessage) (Effect, error) {
+	e := &unlockRecipeEffect{}
+	if err := json.Unmarshal(params, e); err != nil {
+		return nil, err
+	}
+	if e.Recipe == "" {
+		return nil, fmt.Errorf("缺少 recipe")
+	}
+	return e, nil
+}
+
+func (e *unlockRecipeEffect) Check(ctx *EffectContext) error {
+	if ctx.screen.story().Recipes[e.Recipe] {
+		return ErrRecipeKnown
+	}
+	return nil
+}
+
+func (e *unlockRecipeEffect) Apply(ctx *EffectContext) {
+	ctx.screen.story().Recipes[e.Recipe] = true
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"Game/combat"
+	"Game/ecs"
+	"Game/nav"
+	"Game/status"
+	"encoding/json"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"log"
+	"os"
+)
+
+// enemyCatalogPath 敌人数据文件
+const enemyCatalogPath = "data/enemies.json"
+
+// EnemyDef 一种敌人的定义
+type EnemyDef struct {
+	Kind            string                        `json:"kind"`
+	Name            string                        `json:"name"`
+	ImagePath       string                        `json:"image"`
+	Size            float64                       `json:"size"`            // 绘制和碰撞尺寸（像素）
+	Health          int                           `json:"health"`          // 最大生命值
+	Stats           Stats                         `json:"stats"`           // 基础属性
+	Resistances     map[combat.DamageType]float64 `json:"resistances"`     // 各类型伤害的抗性
+	KnockbackResist float64                       `json:"knockbackResist"` // 击退抗性 0~1，1 表示不会被击退
+	AI              EnemyAIDef                    `json:"ai"`
+	Loot            string                        `json:"loot"`   // 被击败时使用的掉落表，为空表示不掉落
+	XP              int                           `json:"xp"`     // 被击败时玩家获得的经验
+	Immune          []string                      `json:"immune"` // 免疫的状态
+	OnHit           []StatusHit                   `json:"onHit"`  // 攻击命中玩家时按几率施加的状态
+	Image           *ebiten.Image                 `json:"-"`
+}
+
+// Enemy 敌人组件
+type Enemy struct {
+	Def *EnemyDef
+}
+
+// EnemyDefs 所有敌人定义：种类 -> 定义
+var EnemyDefs map[string]*EnemyDef
+
+func init() {
+	EnemyDefs = loadEnemyCatalog(enemyCatalogPath)
+}
+
+// loadEnemyCatalog 加载敌人数据文件，数据有误时直接退出
+func loadEnemyCatalog(path string) map[string]*EnemyDef {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("加载敌人数据失败 %s: %v", path, err)
+	}
+	var defs []*EnemyDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		log.Fatalf("解析敌人数据失败 %s: %v", path, err)
+	}
+	catalog := make(map[string]*EnemyDef, len(defs))
+	for _, def := range defs {
+		if err := def.validate(); err != nil {
+			log.Fatalf("敌人数据有误 %s: %v", path, err)
+		}
+		if _, ok := catalog[def.Kind]; ok {
+			log.Fatalf("敌人数据有误 %s: 种类 %q 重复", path, def.Kind)
+		}
+		def.Image = loadImage(def.ImagePath)
+		catalog[def.Kind] = def
+	}
+	return catalog
+}
+
+// validate 检查敌人定义
+func (d *EnemyDef) validate() error {
+	if d.Kind == "" {
+		return fmt.Errorf("缺少 kind")
+	}
+	if d.Size <= 0 || d.Health <= 0 {
+		return fmt.Errorf("%s: size 和 health 必须大于 0", d.Kind)
+	}
+	if d.AI.Sight <= 0 || d.AI.AttackReach <= 0 || d.AI.Leash <= 0 {
+		return fmt.Errorf("%s: ai 的 sight、attackReach 和 leash 必须大于 0", d.Kind)
+	}
+	if d.XP < 0 {
+		return fmt.Errorf("%s: xp 不能为负数", d.Kind)
+	}
+	for _, id := range d.Immune {
+		if StatusDefs[id] == nil {
+			return fmt.Errorf("%s: 免疫的状态 %q 不存在", d.Kind, id)
+		}
+	}
+	for _, h := range d.OnHit {
+		if err := h.validate(); err != nil {
+			return fmt.Errorf("%s: onHit: %w", d.Kind, err)
+		}
+	}
+	return nil
+}
+
+// spawnEnemy 在网格坐标 (col, row) 的格子中生成敌人
+func (p *PlayScreen) spawnEnemy(kind string, col, row int) ecs.Entity {
+	def, ok := EnemyDefs[kind]
+	if !ok {
+		log.Printf("未知的敌人种类 %q", kind)
+		return 0
+	}
+	e := p.world.Spawn()
+	offset := (float64(p.gridSize) - def.Size) / 2
+	ecs.Add(p.world, e, ecs.Position{X: float64(col*p.gridSize) + offset, Y: float64(row*p.gridSize) + offset})
+	ecs.Add(p.world, e, ecs.Velocity{})
+	ecs.Add(p.world, e, ecs.Sprite{Image: def.Image, Width: def.Size, Height: def.Size, Layer: layerCharacters})
+	ecs.Add(p.world, e, ecs.Collider{W: def.Size, H: def.Size, Solid: true})
+	ecs.Add(p.world, e, ecs.Health{Current: def.Health, Max: def.Health})
+	ecs.Add(p.world, e, def.Stats)
+	ecs.Add(p.world, e, Combatant{
+		Invulnerability: combat.Invulnerability{Duration: enemyInvulnerability},
+		Resistances:     def.Resistances,
+		KnockbackResist: def.KnockbackResist,
+	})
+	ecs.Add(p.world, e, Statuses{Set: status.Set{Immune: def.Immune}})
+	ecs.Add(p.world, e, Enemy{Def: def})
+	homeX, homeY := p.tileCenter(nav.Point{X: col, Y: row})
+	ecs.Add(p.world, e, ecs.AI{Behavior: "melee", State: aiPatrol, HomeX: homeX, HomeY: homeY})
+	ecs.Add(p.world, e, EnemyBrain{})
+	return e
+}
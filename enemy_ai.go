@@ -0,0 +1,266 @@
+package main
+
+import (
+	"Game/combat"
+	"Game/ecs"
+	"Game/nav"
+	"math"
+	"time"
+)
+
+// 敌人 AI 的状态（ecs.AI.State）
+const (
+	aiPatrol = "patrol" // 在出生点附近巡逻
+	aiChase  = "chase"  // 追击玩家
+	aiAttack = "attack" // 在攻击距离内攻击玩家
+	aiFlee   = "flee"   // 生命值过低时逃离玩家
+	aiReturn = "return" // 放弃追击，回到出生点
+)
+
+const (
+	aiRepathInterval = 400 * time.Millisecond // 追击时重新规划路径的间隔
+	aiLoseSightAfter = 3 * time.Second        // 看不到玩家超过该时间后放弃追击
+	aiArriveDistance = 2.0                    // 到达格子中心的判定距离（像素）
+
+	pathBudget      = 4   // 每帧最多计算的路径数
+	pathCacheSize   = 128 // 缓存的路径数
+	pathMaxExpanded = 600 // 单次寻路最多展开的格子数
+)
+
+// EnemyAIDef 敌人 AI 参数
+type EnemyAIDef struct {
+	Sight          float64 `json:"sight"`          // 视野距离（像素）
+	AttackReach    float64 `json:"attackReach"`    // 攻击距离（像素）
+	AttackCooldown float64 `json:"attackCooldown"` // 攻击间隔（秒）
+	FleeBelow      float64 `json:"fleeBelow"`      // 生命值低于该比例时逃跑，0 表示不逃跑
+	Leash          float64 `json:"leash"`          // 离出生点超过该距离时放弃追击（像素）
+	PatrolRadius   int     `json:"patrolRadius"`   // 巡逻范围（格）
+}
+
+// EnemyBrain 敌人 AI 的运行时数据
+type EnemyBrain struct {
+	path      []nav.Point   // 当前路径（与路径缓存共享，不能修改）
+	pathIndex int           // 下一个要走到的路径点
+	pathGoal  nav.Point     // 当前路径的终点
+	hasPath   bool          // 是否有可用的路径
+	repath    time.Duration // 距下次重新规划路径的时间
+	wait      time.Duration // 巡逻到达后停留的时间
+	cooldown  time.Duration // 距下次攻击的时间
+	lostSight time.Duration // 追击时连续看不到玩家的时间
+}
+
+// initNavigation 创建寻路网格和路径规划器
+func (p *PlayScreen) initNavigation() {
+	p.planner = nav.NewPlanner(tileGrid(p.gridData), pathBudget, pathCacheSize)
+	p.planner.MaxExpanded = pathMaxExpanded
+}
+
+// updateEnemies 运行所有敌人的 AI
+func (p *PlayScreen) updateEnemies(dt time.Duration) {
+	p.planner.BeginTick()
+	mask := ecs.MaskOf[Enemy](p.world) | ecs.MaskOf[ecs.AI](p.world) | ecs.MaskOf[EnemyBrain](p.world)
+	for _, e := range p.world.Query(mask) {
+		p.updateEnemy(e, dt)
+	}
+}
+
+// updateEnemy 敌人 AI 状态机
+func (p *PlayScreen) updateEnemy(e ecs.Entity, dt time.Duration) {
+	def := ecs.Get[Enemy](p.world, e).Def
+	ai := ecs.Get[ecs.AI](p.world, e)
+	brain := ecs.Get[EnemyBrain](p.world, e)
+	vel := ecs.Get[ecs.Velocity](p.world, e)
+	health := ecs.Get[ecs.Health](p.world, e)
+	brain.cooldown = max(brain.cooldown-dt, 0)
+	brain.repath -= dt
+	// 眩晕期间原地不动，冷却照常推进
+	if p.stunned(e) {
+		vel.X, vel.Y = 0, 0
+		return
+	}
+
+	ex, ey := p.entityBox(e).Center()
+	px, py := p.playerCenter()
+	distance := math.Hypot(px-ex, py-ey)
+	fromHome := math.Hypot(ex-ai.HomeX, ey-ai.HomeY)
+	sees := p.canSeePlayer(ex, ey, def.AI.Sight)
+	low := def.AI.FleeBelow > 0 && float64(health.Current) < def.AI.FleeBelow*float64(health.Max)
+	speed := statSheet(p.world, e).Speed
+
+	switch ai.State {
+	case aiChase:
+		if sees {
+			brain.lostSight = 0
+		} else {
+			brain.lostSight += dt
+		}
+		switch {
+		case low:
+			p.setAIState(e, aiFlee)
+		case fromHome > def.AI.Leash || brain.lostSight > aiLoseSightAfter:
+			p.setAIState(e, aiReturn)
+		case p.inAttackReach(e, def.AI.AttackReach):
+			vel.X, vel.Y = 0, 0
+			p.setAIState(e, aiAttack)
+		default:
+			p.followPath(e, p.tileOf(px, py), speed)
+		}
+
+	case aiAttack:
+		vel.X, vel.Y = 0, 0
+		switch {
+		case low:
+			p.setAIState(e, aiFlee)
+		case !p.inAttackReach(e, def.AI.AttackReach):
+			p.setAIState(e, aiChase)
+		case brain.cooldown == 0:
+			if p.strike(e, p.player) {
+				p.applyStatusHits(p.player, def.OnHit)
+			}
+			brain.cooldown = time.Duration(def.AI.AttackCooldown * float64(time.Second))
+		}
+
+	case aiFlee:
+		if distance > def.AI.Sight*1.5 || !p.playerAlive() {
+			p.setAIState(e, aiReturn)
+			break
+		}
+		// 直接朝远离玩家的方向跑，撞墙时由移动系统沿墙滑动
+		vel.X, vel.Y = combat.Knockback(px, py, ex, ey, speed)
+
+	case aiReturn:
+		// 回家途中不理会玩家，避免在追击和返回之间反复切换
+		if p.followPath(e, p.tileOf(ai.HomeX, ai.HomeY), speed) {
+			health.Current = health.Max
+			p.setAIState(e, aiPatrol)
+		}
+
+	default: // aiPatrol
+		if sees && !low {
+			p.setAIState(e, aiChase)
+			break
+		}
+		p.patrol(e, def, speed, dt)
+	}
+}
+
+// setAIState 切换 AI 状态并清除上一个状态的路径
+func (p *PlayScreen) setAIState(e ecs.Entity, state string) {
+	ai := ecs.Get[ecs.AI](p.world, e)
+	brain := ecs.Get[EnemyBrain](p.world, e)
+	ai.State = state
+	ai.Target = 0
+	if state == aiChase || state == aiAttack || state == aiFlee {
+		ai.Target = p.player
+	}
+	brain.hasPath = false
+	brain.repath = 0
+	brain.lostSight = 0
+	brain.wait = 0
+}
+
+// patrol 在出生点附近随机选一个格子走过去，到达后停留一会儿
+func (p *PlayScreen) patrol(e ecs.Entity, def *EnemyDef, speed float64, dt time.Duration) {
+	ai := ecs.Get[ecs.AI](p.world, e)
+	brain := ecs.Get[EnemyBrain](p.world, e)
+	vel := ecs.Get[ecs.Velocity](p.world, e)
+	if brain.wait > 0 {
+		brain.wait -= dt
+		vel.X, vel.Y = 0, 0
+		return
+	}
+	if !brain.hasPath {
+		home := p.tileOf(ai.HomeX, ai.HomeY)
+		r := def.AI.PatrolRadius
+		goal := nav.Point{X: home.X + p.rng.IntN(2*r+1) - r, Y: home.Y + p.rng.IntN(2*r+1) - r}
+		if tileGrid(p.gridData).Blocked(goal) {
+			brain.wait = 500 * time.Millisecond // 选到墙上时稍后重选
+			return
+		}
+		brain.pathGoal = goal
+	}
+	if p.followPath(e, brain.pathGoal, speed) {
+		brain.hasPath = false
+		brain.wait = time.Duration(1000+p.rng.IntN(1500)) * time.Millisecond
+	}
+}
+
+// followPath 沿 A* 路径走向目标格子，到达目标格子中心时返回 true
+// 路径请求被限流时沿用旧路径，下一帧再重新规划
+func (p *PlayScreen) followPath(e ecs.Entity, goal nav.Point, speed float64) bool {
+	brain := ecs.Get[EnemyBrain](p.world, e)
+	vel := ecs.Get[ecs.Velocity](p.world, e)
+	x, y := p.entityBox(e).Center()
+	start := p.tileOf(x, y)
+
+	if !brain.hasPath || (goal != brain.pathGoal && brain.repath <= 0) {
+		path, status := p.planner.Path(start, goal)
+		switch status {
+		case nav.Found:
+			brain.path, brain.pathIndex, brain.pathGoal, brain.hasPath = path, 0, goal, true
+			brain.repath = aiRepathInterval
+		case nav.NoPath, nav.TooFar:
+			brain.hasPath = false
+			brain.pathGoal = goal
+			brain.repath = aiRepathInterval
+			vel.X, vel.Y = 0, 0
+			return false
+		case nav.Throttled:
+			if !brain.hasPath {
+				vel.X, vel.Y = 0, 0
+				return false
+			}
+		}
+	}
+
+	// 走完路径后对准终点格子的中心
+	target := brain.pathGoal
+	if brain.pathIndex < len(brain.path) {
+		target = brain.path[brain.pathIndex]
+	}
+	tx, ty := p.tileCenter(target)
+	if math.Hypot(tx-x, ty-y) <= aiArriveDistance {
+		if brain.pathIndex < len(brain.path) {
+			brain.pathIndex++
+			return false
+		}
+		vel.X, vel.Y = 0, 0
+		if target != goal {
+			brain.hasPath = false // 目标已经移动，下一帧重新规划
+		}
+		return target == goal
+	}
+	vel.X, vel.Y = combat.Knockback(x, y, tx, ty, speed)
+	return false
+}
+
+// canSeePlayer 玩家是否在视野距离内且没有被墙挡住
+func (p *PlayScreen) canSeePlayer(x, y, sight float64) bool {
+	if !p.playerAlive() {
+		return false
+	}
+	px, py := p.playerCenter()
+	if math.Hypot(px-x, py-y) > sight {
+		return false
+	}
+	return nav.LineOfSight(tileGrid(p.gridData), p.tileOf(x, y), p.tileOf(px, py))
+}
+
+// inAttackReach 玩家是否在敌人朝向玩家一侧的攻击判定框内
+func (p *PlayScreen) inAttackReach(e ecs.Entity, reach float64) bool {
+	box := p.entityBox(e)
+	ex, ey := box.Center()
+	px, py := p.playerCenter()
+	dx, dy := px-ex, py-ey
+	if math.Abs(dx) >= math.Abs(dy) {
+		dy = 0
+	} else {
+		dx = 0
+	}
+	return combat.Hitbox(box, dx, dy, reach, math.Max(box.W, box.H)).Overlaps(p.entityBox(p.player))
+}
+
+// playerAlive 玩家生命值是否大于 0
+func (p *PlayScreen) playerAlive() bool {
+	return ecs.Get[ecs.Health](p.world, p.player).Current > 0
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"Game/ecs"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+)
+
+// EquipSlot 装备栏位
+type EquipSlot string
+
+const (
+	SlotWeapon    EquipSlot = "weapon"    // 武器
+	SlotArmor     EquipSlot = "armor"     // 护甲
+	SlotAccessory EquipSlot = "accessory" // 饰品
+)
+
+// equipSlots 装备面板中栏位的显示顺序
+var equipSlots = []EquipSlot{SlotWeapon, SlotArmor, SlotAccessory}
+
+// ErrNoRoom 背包已满，放不下换下来的装备
+var ErrNoRoom = &UseError{Reason: "notice.inventoryFull"}
+
+// Equipment 实体身上穿戴的装备组件
+type Equipment struct {
+	Slots map[EquipSlot]*item
+}
+
+// Stats 返回所有装备提供的属性之和
+func (e *Equipment) Stats() Stats {
+	var stats Stats
+	for _, it := range e.Slots {
+		stats = stats.Add(it.ItemData.Stats)
+	}
+	return stats
+}
+
+// validSlot 是否为已知的装备栏位
+func validSlot(slot EquipSlot) bool {
+	for _, s := range equipSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// Equip 从背包中取出一个物品装备到对应栏位，栏位上原有的装备放回背包
+func (p *PlayScreen) Equip(it *item) error {
+	slot := it.ItemData.Slot
+	if slot == "" {
+		return ErrNotUsable
+	}
+	equipment := ecs.Get[Equipment](p.world, p.player)
+	old := equipment.Slots[slot]
+	// 整组取出时会空出一个格子，否则需要确认换下来的装备放得下
+	if old != nil && it.count > 1 && !p.hasRoomFor(old.ItemData.id) {
+		return ErrNoRoom
+	}
+
+	it.count--
+	if it.count <= 0 {
+		p.removeItem(it)
+	}
+	p.inventoryChanged()
+	equipment.Slots[slot] = &item{id: it.ItemData.id, count: 1, ItemData: it.ItemData}
+	if old != nil {
+		p.addItem(old.ItemData.id, 1)
+	}
+	return nil
+}
+
+// Unequip 卸下栏位上的装备放回背包
+func (p *PlayScreen) Unequip(slot EquipSlot) error {
+	equipment := ecs.Get[Equipment](p.world, p.player)
+	old := equipment.Slots[slot]
+	if old == nil {
+		return nil
+	}
+	if !p.addItem(old.ItemData.id, 1) {
+		return ErrNoRoom
+	}
+	delete(equipment.Slots, slot)
+	return nil
+}
+
+// hasRoomFor 背包能否再放入一个该物品
+func (p *PlayScreen) hasRoomFor(itemID int64) bool {
+	for _, it := range p.bag().Items {
+		if it.ItemData.id == itemID {
+			return true
+		}
+	}
+	return len(p.bag().Items) < p.bag().Size
+}
+
+// equipmentPanelRect 装备面板位置（背包左侧）
+func equipmentPanelRect() [4]int {
+	return [4]int{70, 100, 170, 400}
+}
+
+// equipSlotRect 第 index 个装备栏位的格子位置
+func equipSlotRect(index int) [4]int {
+	panel := equipmentPanelRect()
+	return [4]int{panel[0] + 15, panel[1] + 50 + index*63, 48, 48}
+}
+
+// updateEquipmentPanel 点击装备栏位时卸下装备
+func (p *PlayScreen) updateEquipmentPanel() {
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	equipment := ecs.Get[Equipment](p.world, p.player)
+	for i, slot := range equipSlots {
+		if !inRect(x, y, equipSlotRect(i)) || equipment.Slots[slot] == nil {
+			continue
+		}
+		name := equipment.Slots[slot].ItemData.Name
+		if err := p.Unequip(slot); err != nil {
+			p.notices.Show(p.useErrorText(err))
+			return
+		}
+		p.notices.Show(fmt.Sprintf(p.settings.T("equip.unequipped"), name))
+	}
+}
+
+// drawEquipmentPanel 绘制装备面板和角色属性
+func (p *PlayScreen) drawEquipmentPanel(screen *ebiten.Image) {
+	panel := equipmentPanelRect()
+	x, y, w, h := panel[0], panel[1], panel[2], panel[3]
+	p.drawInventoryBackground(screen, x, y, w, h)
+	drawCenteredText(screen, p.settings.T("equip.title"), x, y+15, w)
+	vector.DrawFilledRect(screen, float32(x+10), float32(y+35), float32(w-20), 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+
+	equipment := ecs.Get[Equipment](p.world, p.player)
+	for i, slot := range equipSlots {
+		r := equipSlotRect(i)
+		ebitenutil.DebugPrintAt(screen, p.settings.T("equip."+string(slot)), r[0]+r[2]+8, r[1]+6)
+
+		it := equipment.Slots[slot]
+		if it == nil {
+			drawRarityFrame(screen, r[0], r[1], r[2], r[3], RarityCommon)
+			ebitenutil.DebugPrintAt(screen, p.settings.T("equip.empty"), r[0]+r[2]+8, r[1]+24)
+			continue
+		}
+		drawRarityFrame(screen, r[0], r[1], r[2], r[3], it.ItemData.Rarity)
+		drawColoredText(screen, it.ItemData.Name, r[0]+r[2]+8, r[1]+24, it.ItemData.Rarity.Color())
+		if cx, cy := ebiten.CursorPosition(); inRect(cx, cy, r) {
+			p.bagUI.hovered = it
+		}
+		if it.ItemData.Image != nil {
+			op := &ebiten.DrawImageOptions{}
+			b := it.ItemData.Image.Bounds()
+			scale := math.Min(float64(r[2]-8)/float64(b.Dx()), float64(r[3]-8)/float64(b.Dy()))
+			op.GeoM.Scale(scale, scale)
+			op.GeoM.Translate(float64(r[0])+(float64(r[2])-float64(b.Dx())*scale)/2,
+				float64(r[1])+(float64(r[3])-float64(b.Dy())*scale)/2)
+			screen.DrawImage(it.ItemData.Image, op)
+		}
+	}
+
+	// 最终属性
+	stats := statSheet(p.world, p.player)
+	statsY := y + 50 + len(equipSlots)*63 + 10
+	vector.DrawFilledRect(screen, float32(x+10), float32(statsY-8), float32(w-20), 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+	lines := []string{
+		fmt.Sprintf("%s: %.0f", p.settings.T("stat.attack"), stats.Attack),
+		fmt.Sprintf("%s: %.0f", p.settings.T("stat.defense"), stats.Defense),
+		fmt.Sprintf("%s: %.0f", p.settings.T("stat.speed"), stats.Speed),
+		fmt.Sprintf("%s: %.0f%%", p.settings.T("stat.crit"), stats.Crit*100),
+	}
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, x+15, statsY+i*18)
+	}
+}
@@ -4,47 +4,108 @@ import (
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"time"
 )
 
+// 游戏逻辑屏幕尺寸（窗口大小只影响缩放）
+const (
+	screenWidth  = 800
+	screenHeight = 600
+)
+
+// tickDuration 返回一次逻辑更新（Update）对应的时间
+func tickDuration() time.Duration {
+	return time.Second / time.Duration(ebiten.TPS())
+}
+
 // GameState 定义所有界面状态类型
 type GameState int
 
 const (
-	StateMenu GameState = iota // 开始菜单界面
-	StatePlay                  // 游戏主界面
+	StateMenu     GameState = iota // 开始菜单界面
+	StatePlay                      // 游戏主界面
+	StateSettings                  // 设置界面
+	StateGameOver                  // 游戏结束界面（玩家死亡）
 )
 
 // Game 结构体：主程序运行载体
 type Game struct {
 	currentState GameState // 当前界面状态
+	settingsFrom GameState // 进入设置界面前的状态，返回时使用
+	settings     *Settings // 玩家设置
+	sound        *Sound    // 背景音乐与音效
 
-	menuScreen *MenuScreen // 菜单界面
-	playScreen *PlayScreen // 游戏界面
+	menuScreen     *MenuScreen     // 菜单界面
+	playScreen     *PlayScreen     // 游戏界面
+	settingsScreen *SettingsScreen // 设置界面
+	gameOverScreen *GameOverScreen // 游戏结束界面
 }
 
 // NewGame 初始化游戏
 func NewGame() *Game {
-	return &Game{
+	g := &Game{
 		currentState: StateMenu,
-		menuScreen:   NewMenuScreen(),
-		playScreen:   NewPlayScreen(),
+		settings:     LoadSettings(),
+		sound:        NewSound(),
+	}
+	g.menuScreen = NewMenuScreen(g.settings, g.sound)
+	g.playScreen = NewPlayScreen(g.settings, g.sound)
+	g.settingsScreen = NewSettingsScreen(g.settings, g.applySettings)
+	g.gameOverScreen = NewGameOverScreen(g.playScreen, g.settings, g.sound)
+	g.applySettings()
+	g.playSceneMusic()
+	return g
+}
+
+// applySettings 立即应用当前设置
+func (g *Game) applySettings() {
+	g.settings.Apply()
+	g.sound.ApplySettings(g.settings)
+}
+
+// playSceneMusic 播放当前界面对应的背景音乐（设置界面沿用来源界面的音乐，游戏结束界面沿用游戏音乐）
+func (g *Game) playSceneMusic() {
+	switch g.currentState {
+	case StateMenu:
+		g.sound.PlayMusic(musicMenu)
+	case StatePlay:
+		g.sound.PlayMusic(musicPlay)
 	}
 }
 
 // Update 每帧更新逻辑
 func (g *Game) Update() error {
+	g.sound.Update()
+
 	switch g.currentState {
 	case StateMenu:
 		// 菜单界面更新逻辑
-		if g.menuScreen.Update() {
-			g.currentState = StatePlay // 切换到游戏界面
-		}
+		g.changeState(g.menuScreen.Update())
 	case StatePlay:
-		g.playScreen.Update()
+		g.changeState(g.playScreen.Update())
+	case StateGameOver:
+		g.changeState(g.gameOverScreen.Update())
+	case StateSettings:
+		if g.settingsScreen.Update() {
+			g.changeState(g.settingsFrom) // 返回进入设置前的界面
+		}
 	}
 	return nil
 }
 
+// changeState 切换界面状态，进入设置界面时记录来源
+func (g *Game) changeState(next GameState) {
+	if next == g.currentState {
+		return
+	}
+	if next == StateSettings {
+		g.settingsFrom = g.currentState
+	}
+	g.currentState = next
+	g.sound.SetWorldPaused(next == StateMenu || next == StateGameOver)
+	g.playSceneMusic()
+}
+
 // Draw 渲染逻辑
 func (g *Game) Draw(screen *ebiten.Image) {
 	switch g.currentState {
@@ -52,14 +113,26 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.menuScreen.Draw(screen)
 	case StatePlay:
 		g.playScreen.Draw(screen)
+	case StateGameOver:
+		g.gameOverScreen.Draw(screen)
+	case StateSettings:
+		// 设置界面叠加在来源界面之上
+		if g.settingsFrom == StatePlay {
+			g.playScreen.Draw(screen)
+		} else {
+			g.menuScreen.Draw(screen)
+		}
+		g.settingsScreen.Draw(screen)
 	}
 
 	// 显示游戏帧率
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("FPS: %.2f", ebiten.ActualFPS()), 10, 10)
+	if g.settings.ShowFPS {
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("FPS: %.2f", ebiten.ActualFPS()), 10, 10)
+	}
 }
 
 // Layout 添加Layout方法实现ebiten.Game接口
-func (g *Game) Layout(int, int) (screenWidth int, screenHeight int) {
+func (g *Game) Layout(int, int) (int, int) {
 	// 返回游戏逻辑屏幕尺寸，可以与窗口尺寸不同
-	return 800, 600
+	return screenWidth, screenHeight
 }
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"time"
+)
+
+// 快捷栏布局（屏幕底部居中）
+const (
+	hotbarSlots   = 10
+	hotbarSlot    = 40 // 格子尺寸
+	hotbarSpacing = 4
+	hotbarY       = screenHeight - hotbarSlot - 8
+)
+
+// hotbarKeys 快捷栏各格对应的数字键
+var hotbarKeys = [hotbarSlots]ebiten.Key{
+	ebiten.KeyDigit1, ebiten.KeyDigit2, ebiten.KeyDigit3, ebiten.KeyDigit4, ebiten.KeyDigit5,
+	ebiten.KeyDigit6, ebiten.KeyDigit7, ebiten.KeyDigit8, ebiten.KeyDigit9, ebiten.KeyDigit0,
+}
+
+// whitePixel 绘制三角形（冷却扇形）用的白色纹理
+var whitePixel = func() *ebiten.Image {
+	img := ebiten.NewImage(3, 3)
+	img.Fill(color.White)
+	return img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+}()
+
+// hotbarSlotRect 第 i 个快捷栏格子
+func hotbarSlotRect(i int) [4]int {
+	width := hotbarSlots*(hotbarSlot+hotbarSpacing) - hotbarSpacing
+	x := (screenWidth-width)/2 + i*(hotbarSlot+hotbarSpacing)
+	return [4]int{x, hotbarY, hotbarSlot, hotbarSlot}
+}
+
+// stackOf 返回背包中某种物品的那一组，没有时返回 nil
+// 快捷栏只记录物品 id，每次都从背包中查找，因此数量总是和背包一致，用完后再获得也会自动对应上
+func (p *PlayScreen) stackOf(itemID int64) *item {
+	for _, it := range p.bag().Items {
+		if it.ItemData.id == itemID {
+			return it
+		}
+	}
+	return nil
+}
+
+// assignHotbar 把物品放到快捷栏的第 slot 格，同一物品原来所在的格子会被清空
+func (p *PlayScreen) assignHotbar(slot int, itemID int64) {
+	for i, id := range p.bag().Hotbar {
+		if id == itemID {
+			p.bag().Hotbar[i] = 0
+		}
+	}
+	p.bag().Hotbar[slot] = itemID
+	p.sound.PlaySFX(sfxClick)
+}
+
+// useHotbarSlot 使用或装备快捷栏第 slot 格的物品
+func (p *PlayScreen) useHotbarSlot(slot int) {
+	itemID := p.bag().Hotbar[slot]
+	if itemID == 0 {
+		return
+	}
+	it := p.stackOf(itemID)
+	if it == nil {
+		p.notices.Show(fmt.Sprintf(p.settings.T("hotbar.none"), ItemImages[itemID].Name))
+		return
+	}
+	p.useItem(it)
+}
+
+// updateItemCooldowns 减少物品的使用冷却时间
+func (p *PlayScreen) updateItemCooldowns(dt time.Duration) {
+	for id, remaining := range p.bag().Cooldowns {
+		if remaining <= dt {
+			delete(p.bag().Cooldowns, id)
+		} else {
+			p.bag().Cooldowns[id] = remaining - dt
+		}
+	}
+}
+
+// updateHotbar 处理快捷栏的输入：
+// 背包关闭时按数字键使用对应格子的物品；背包打开时按数字键把选中的物品放到对应格子，
+// 也可以把背包中的物品拖到格子上；右键点击格子清空
+func (p *PlayScreen) updateHotbar() {
+	for i, key := range hotbarKeys {
+		if !inpututil.IsKeyJustPressed(key) {
+			continue
+		}
+		if !p.bagUI.loaded {
+			p.useHotbarSlot(i)
+		} else if p.bagUI.selected >= 0 && p.bagUI.selected < len(p.bag().Items) {
+			p.assignHotbar(i, p.bag().Items[p.bagUI.selected].ItemData.id)
+		}
+	}
+
+	x, y := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		for i := range p.bag().Hotbar {
+			if inRect(x, y, hotbarSlotRect(i)) && p.bag().Hotbar[i] != 0 {
+				p.bag().Hotbar[i] = 0
+				p.sound.PlaySFX(sfxClick)
+			}
+		}
+	}
+
+	// 拖动：在背包中按下鼠标开始拖动鼠标下的物品（hoveredItem 在上一帧绘制时更新），在快捷栏格子上松开时放入
+	if p.bagUI.loaded && p.bagUI.inspecting == nil && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if index := slices.Index(p.bag().Items, p.bagUI.hovered); index >= 0 {
+			p.bagUI.drag = p.bagUI.hovered
+			p.bagUI.selected = index
+		}
+	}
+	if p.bagUI.drag != nil && inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		for i := range p.bag().Hotbar {
+			if inRect(x, y, hotbarSlotRect(i)) {
+				p.assignHotbar(i, p.bagUI.drag.ItemData.id)
+			}
+		}
+		p.bagUI.drag = nil
+	}
+	if !p.bagUI.loaded {
+		p.bagUI.drag = nil
+	}
+}
+
+// drawHotbar 绘制快捷栏：按键、物品图标、数量（背包中没有时变暗）和冷却扇形
+func (p *PlayScreen) drawHotbar(screen *ebiten.Image) {
+	for i, itemID := range p.bag().Hotbar {
+		r := hotbarSlotRect(i)
+		data := ItemImages[itemID]
+		if data == nil {
+			drawRarityFrame(screen, r[0], r[1], r[2], r[3], RarityCommon)
+		} else {
+			drawRarityFrame(screen, r[0], r[1], r[2], r[3], data.Rarity)
+			drawItemIcon(screen, data, r[0]+4, r[1]+4, r[2]-8)
+			var count int64
+			if it := p.stackOf(itemID); it != nil {
+				count = it.count
+			}
+			if count == 0 {
+				vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), color.RGBA{A: 160}, false)
+			}
+			if count != 1 {
+				text := fmt.Sprintf("%d", count)
+				ebitenutil.DebugPrintAt(screen, text, r[0]+r[2]-len(text)*6-2, r[1]+r[3]-16)
+			}
+			if remaining := p.bag().Cooldowns[itemID]; remaining > 0 && data.Cooldown > 0 {
+				drawCooldownSweep(screen, r, float64(remaining)/float64(data.Cooldown))
+			}
+		}
+		ebitenutil.DebugPrintAt(screen, hotbarKeys[i].String()[len("Digit"):], r[0]+3, r[1]+1)
+	}
+
+	// 正在拖动的物品跟随鼠标
+	if p.bagUI.drag != nil {
+		x, y := ebiten.CursorPosition()
+		drawItemIcon(screen, p.bagUI.drag.ItemData, x-16, y-16, 32)
+	}
+}
+
+// drawCooldownSweep 在格子上绘制剩余冷却的暗色扇形，从12点方向开始顺时针缩小；frac 为剩余比例
+func drawCooldownSweep(screen *ebiten.Image, r [4]int, frac float64) {
+	cx, cy := float32(r[0])+float32(r[2])/2, float32(r[1])+float32(r[3])/2
+	radius := float32(r[2]) / 2
+	start := float32(-math.Pi/2 + 2*math.Pi*(1-frac))
+	var path vector.Path
+	path.MoveTo(cx, cy)
+	path.Arc(cx, cy, radius, start, 3*math.Pi/2, vector.Clockwise)
+	path.Close()
+	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vertices {
+		vertices[i].SrcX, vertices[i].SrcY = 1.5, 1.5
+		vertices[i].ColorR, vertices[i].ColorG, vertices[i].ColorB, vertices[i].ColorA = 0, 0, 0, 0.6
+	}
+	screen.DrawTriangles(vertices, indices, whitePixel, &ebiten.DrawTrianglesOptions{AntiAlias: true})
+}
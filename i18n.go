@@ -0,0 +1,412 @@
+package main
+
+// languageOrder 设置界面中可选语言的顺序
+// 注意：DebugPrint 的内置字体只支持 Latin-1，中文暂时以拼音显示
+var languageOrder = []string{"en", "zh"}
+
+// translations 界面文字翻译表：语言 -> 文本键 -> 文本
+var translations = map[string]map[string]string{
+	"en": {
+		"language.name":         "English",
+		"menu.start":            "START_THE_GAME",
+		"menu.settings":         "SETTINGS",
+		"pause.title":           "Paused",
+		"pause.resume":          "Resume",
+		"pause.save":            "Save Game",
+		"pause.settings":        "Settings",
+		"pause.mainMenu":        "Main Menu",
+		"settings.title":        "Settings",
+		"settings.fullscreen":   "Fullscreen",
+		"settings.windowSize":   "Window Size",
+		"settings.vsync":        "VSync",
+		"settings.showFPS":      "Show FPS",
+		"settings.master":       "Master Volume",
+		"settings.music":        "Music Volume",
+		"settings.sfx":          "SFX Volume",
+		"settings.mute":         "Mute",
+		"settings.language":     "Language",
+		"settings.back":         "Back",
+		"settings.pressKey":     "Press a key...",
+		"settings.hint":         "Up/Down: select  Left/Right: change  Enter: edit  Esc: back",
+		"common.on":             "On",
+		"common.off":            "Off",
+		"action.moveUp":         "Move Up",
+		"action.moveDown":       "Move Down",
+		"action.moveLeft":       "Move Left",
+		"action.moveRight":      "Move Right",
+		"action.attack":         "Attack",
+		"action.sprint":         "Sprint",
+		"action.skills":         "Skills",
+		"action.skill1":         "Skill 1",
+		"action.skill2":         "Skill 2",
+		"action.skill3":         "Skill 3",
+		"action.interact":       "Interact",
+		"action.inventory":      "Inventory",
+		"action.questLog":       "Quest Log",
+		"action.craft":          "Crafting",
+		"action.use":            "Use Item",
+		"use.used":              "Used %s",
+		"use.noItem":            "No item selected",
+		"use.notUsable":         "This item cannot be used",
+		"use.noTarget":          "Nothing to use it on",
+		"use.fullHealth":        "Health is already full",
+		"use.fullMana":          "Mana is already full",
+		"use.fullStamina":       "Stamina is already full",
+		"use.blocked":           "The destination is blocked",
+		"use.recipeKnown":       "You already know this recipe",
+		"use.cooldown":          "Not ready yet",
+		"use.immune":            "Immune to that effect",
+		"use.stronger":          "A stronger effect is already active",
+		"use.noStatus":          "Nothing to cure",
+		"use.failed":            "Cannot use that right now",
+		"hotbar.none":           "You have no %s",
+		"notice.walletFull":     "Your wallet can't hold any more",
+		"notice.containerFull":  "It's full",
+		"container.takeAll":     "Take all",
+		"container.depositAll":  "Deposit all",
+		"container.hint":        "Shift+click: move stack",
+		"wallet.log":            "Recent transactions",
+		"wallet.noLog":          "No transactions yet",
+		"equip.title":           "Equipment",
+		"equip.weapon":          "Weapon",
+		"equip.armor":           "Armor",
+		"equip.accessory":       "Accessory",
+		"equip.empty":           "(empty)",
+		"equip.equipped":        "Equipped %s",
+		"equip.unequipped":      "Unequipped %s",
+		"stat.attack":           "Attack",
+		"stat.defense":          "Defense",
+		"stat.speed":            "Speed",
+		"stat.crit":             "Crit",
+		"rarity.common":         "Common",
+		"rarity.uncommon":       "Uncommon",
+		"rarity.rare":           "Rare",
+		"rarity.epic":           "Epic",
+		"rarity.legendary":      "Legendary",
+		"item.equipped":         "Currently equipped",
+		"item.comparedTo":       "Compared to %s",
+		"item.slotEmpty":        "Compared to nothing equipped",
+		"item.charges":          "Uses left: %d/%d",
+		"item.stack":            "Stack: %d",
+		"item.sellValue":        "Sells for %d gold",
+		"item.sellTotal":        "(%d for all)",
+		"item.noSell":           "Cannot be sold",
+		"item.inspect":          "Inspect",
+		"item.inspectHint":      "R / Esc / click outside: close",
+		"action.inspect":        "Inspect",
+		"bag.tab.all":           "All",
+		"bag.tab.weapon":        "Weapon",
+		"bag.tab.armor":         "Armor",
+		"bag.tab.currency":      "Money",
+		"bag.tab.consumable":    "Usable",
+		"bag.tab.material":      "Misc",
+		"bag.search":            "Search... (/)",
+		"bag.sort.category":     "Category",
+		"bag.sort.rarity":       "Rarity",
+		"bag.sort.name":         "Name",
+		"bag.sort.count":        "Count",
+		"bag.sort.recent":       "Recent",
+		"bag.stack":             "Stack",
+		"combat.noWeapon":       "Equip a weapon to attack",
+		"combat.tired":          "Too tired, wait for stamina",
+		"combat.stunned":        "Stunned!",
+		"status.stacks":         "%d stacks",
+		"vital.health":          "HP",
+		"vital.mana":            "MP",
+		"vital.stamina":         "Stamina",
+		"notice.checkpoint":     "Checkpoint reached",
+		"door.locked":           "Locked. Needs %s",
+		"gameOver.title":        "You were defeated",
+		"gameOver.lost":         "Lost %d %s",
+		"gameOver.noLoss":       "Nothing was lost",
+		"gameOver.respawn":      "Respawn at checkpoint",
+		"gameOver.mainMenu":     "Main Menu",
+		"notice.levelUp":        "Level up! You are now level %d",
+		"skills.title":          "Skills",
+		"skills.level":          "Level %d",
+		"skills.xp":             "XP %d/%d",
+		"skills.maxLevel":       "Max level",
+		"skills.statPoints":     "Stat points: %d",
+		"skills.skillPoints":    "Skill points: %d",
+		"skills.barHint":        "%s/%s/%s: put on skill bar",
+		"skills.respec":         "Reset (%d %s)",
+		"skills.respecDone":     "All points refunded",
+		"skills.nothingToReset": "Nothing to reset",
+		"skills.learn":          "Learn",
+		"skills.learned":        "%s is now rank %d",
+		"skills.assigned":       "%s put on slot %d",
+		"skills.active":         "Active",
+		"skills.passive":        "Passive",
+		"skills.rank":           "Rank %d/%d",
+		"skills.cast":           "%d mana, %.0fs cooldown",
+		"skills.requiresLevel":  "Requires level %d",
+		"skills.requires":       "Requires %s rank %d",
+		"skills.cost":           "Costs %d skill points",
+		"skills.maxRank":        "Already at max rank",
+		"skills.needLevel":      "Level too low",
+		"skills.needRequires":   "Learn the required skills first",
+		"skills.noPoints":       "Not enough points",
+		"skills.unknown":        "Unknown skill",
+		"skills.cooldown":       "Skill is cooling down",
+		"skills.noMana":         "Not enough mana",
+		"combat.immune":         "Immune",
+		"combat.defeated":       "Defeated %s",
+		"notice.received":       "Received %s x%d",
+		"notice.chestEmpty":     "The chest is empty",
+		"quest.started":         "Quest started: %s",
+		"quest.completed":       "Quest completed: %s",
+		"quest.log":             "Quest Log",
+		"quest.none":            "No quests yet. Talk to the villagers!",
+		"quest.active":          "Active",
+		"quest.finished":        "Completed",
+		"quest.objectives":      "Objectives:",
+		"quest.rewards":         "Rewards:",
+		"quest.randomReward":    "Random bonus item",
+		"quest.done":            "This quest is complete.",
+		"notice.saved":          "Game saved",
+		"notice.saveFailed":     "Save failed",
+		"shop.buy":              "Buy",
+		"shop.sell":             "Sell",
+		"shop.gold":             "Gold: %d",
+		"shop.soldOut":          "Sold out",
+		"shop.notEnoughGold":    "Not enough gold",
+		"shop.notBuying":        "The shop won't buy that",
+		"shop.failed":           "Trade failed",
+		"shop.nothingToSell":    "Nothing to sell",
+		"shop.restock":          "Restock in %ds",
+		"shop.hint":             "Up/Down: select  Left/Right: buy/sell  Enter: trade  Esc: close",
+		"shop.confirmBuy":       "Buy %s x%d for %d gold?",
+		"shop.confirmSell":      "Sell %s x%d for %d gold?",
+		"shop.yes":              "Yes",
+		"shop.no":               "No",
+		"shop.bought":           "Bought %s x%d for %d gold",
+		"shop.sold":             "Sold %s x%d for %d gold",
+		"craft.title":           "Crafting",
+		"craft.none":            "You don't know any recipes yet.",
+		"craft.inputs":          "Materials:",
+		"craft.outputs":         "Result:",
+		"craft.chance":          "Success chance: %d%%",
+		"craft.failConsumes":    "(materials lost on failure)",
+		"craft.station":         "Requires: %s",
+		"craft.button":          "Craft",
+		"craft.hint":            "Up/Down: select  Enter: craft  Esc: close",
+		"craft.success":         "Crafted %s!",
+		"craft.failed":          "Crafting failed...",
+		"craft.needStation":     "You need to be next to an %s",
+		"craft.missing":         "Not enough materials",
+		"craft.unknown":         "You don't know this recipe",
+		"station.anvil":         "Anvil",
+		"dialogue.continue":     "Press %s to continue",
+		"action.drop":           "Drop Item",
+		"notice.inventoryFull":  "Inventory full",
+		"notice.pickedUp":       "Picked up %s x%d",
+		"action.pause":          "Pause",
+	},
+	"zh": {
+		"language.name":         "Zhongwen",
+		"menu.start":            "KAISHI YOUXI",
+		"menu.settings":         "SHEZHI",
+		"pause.title":           "Zanting",
+		"pause.resume":          "Jixu",
+		"pause.save":            "Baocun Youxi",
+		"pause.settings":        "Shezhi",
+		"pause.mainMenu":        "Zhu Caidan",
+		"settings.title":        "Shezhi",
+		"settings.fullscreen":   "Quanping",
+		"settings.windowSize":   "Chuangkou Daxiao",
+		"settings.vsync":        "Chuizhi Tongbu",
+		"settings.showFPS":      "Xianshi Zhenlv",
+		"settings.master":       "Zong Yinliang",
+		"settings.music":        "Yinyue Yinliang",
+		"settings.sfx":          "Yinxiao Yinliang",
+		"settings.mute":         "Jingyin",
+		"settings.language":     "Yuyan",
+		"settings.back":         "Fanhui",
+		"settings.pressKey":     "Qing an jian...",
+		"settings.hint":         "Shang/Xia: xuanze  Zuo/You: xiugai  Enter: bianji  Esc: fanhui",
+		"common.on":             "Kai",
+		"common.off":            "Guan",
+		"action.moveUp":         "Xiang Shang",
+		"action.moveDown":       "Xiang Xia",
+		"action.moveLeft":       "Xiang Zuo",
+		"action.moveRight":      "Xiang You",
+		"action.attack":         "Gongji",
+		"action.sprint":         "Chongci",
+		"action.skills":         "Jineng",
+		"action.skill1":         "Jineng 1",
+		"action.skill2":         "Jineng 2",
+		"action.skill3":         "Jineng 3",
+		"action.interact":       "Jiaohu",
+		"action.inventory":      "Beibao",
+		"action.questLog":       "Renwu Rizhi",
+		"action.craft":          "Hecheng",
+		"action.use":            "Shiyong Wupin",
+		"use.used":              "Shiyong le %s",
+		"use.noItem":            "Mei you xuanzhong wupin",
+		"use.notUsable":         "Gai wupin bu neng shiyong",
+		"use.noTarget":          "Mei you keyi shiyong de mubiao",
+		"use.fullHealth":        "Shengming zhi yi man",
+		"use.fullMana":          "Fali zhi yi man",
+		"use.fullStamina":       "Tili yi man",
+		"use.blocked":           "Mudidi bei zudang",
+		"use.recipeKnown":       "Yi xuehui gai peifang",
+		"use.cooldown":          "Hai mei zhunbei hao",
+		"use.immune":            "Mianyi gai xiaoguo",
+		"use.stronger":          "Yijing you geng qiang de xiaoguo",
+		"use.noStatus":          "Meiyou xuyao jiechu de zhuangtai",
+		"use.failed":            "Xianzai bu neng shiyong",
+		"hotbar.none":           "Mei you %s",
+		"notice.walletFull":     "Qianbao zhuang bu xia le",
+		"notice.containerFull":  "Yijing fang man le",
+		"container.takeAll":     "Quanbu qu chu",
+		"container.depositAll":  "Quanbu cun ru",
+		"container.hint":        "Shift+dianji: zhuanyi",
+		"wallet.log":            "Zuijin de jiaoyi",
+		"wallet.noLog":          "Hai mei you jiaoyi",
+		"equip.title":           "Zhuangbei",
+		"equip.weapon":          "Wuqi",
+		"equip.armor":           "Hujia",
+		"equip.accessory":       "Shipin",
+		"equip.empty":           "(kong)",
+		"equip.equipped":        "Zhuangbei le %s",
+		"equip.unequipped":      "Xiexia le %s",
+		"stat.attack":           "Gongji",
+		"stat.defense":          "Fangyu",
+		"stat.speed":            "Sudu",
+		"stat.crit":             "Baoji",
+		"rarity.common":         "Putong",
+		"rarity.uncommon":       "Youxiu",
+		"rarity.rare":           "Xiyou",
+		"rarity.epic":           "Shishi",
+		"rarity.legendary":      "Chuanshuo",
+		"item.equipped":         "Yi zhuangbei",
+		"item.comparedTo":       "Yu %s bijiao",
+		"item.slotEmpty":        "Gai lanwei mei you zhuangbei",
+		"item.charges":          "Shengyu cishu: %d/%d",
+		"item.stack":            "Shuliang: %d",
+		"item.sellValue":        "Shoujia %d jinbi",
+		"item.sellTotal":        "(quanbu %d)",
+		"item.noSell":           "Buneng chushou",
+		"item.inspect":          "Wupin xiangqing",
+		"item.inspectHint":      "R / Esc / dianji waimian: guanbi",
+		"action.inspect":        "Chakan",
+		"bag.tab.all":           "Quanbu",
+		"bag.tab.weapon":        "Wuqi",
+		"bag.tab.armor":         "Hujia",
+		"bag.tab.currency":      "Huobi",
+		"bag.tab.consumable":    "Xiaohao",
+		"bag.tab.material":      "Qita",
+		"bag.search":            "Sousuo... (/)",
+		"bag.sort.category":     "Fenlei",
+		"bag.sort.rarity":       "Xiyoudu",
+		"bag.sort.name":         "Mingcheng",
+		"bag.sort.count":        "Shuliang",
+		"bag.sort.recent":       "Zuijin",
+		"bag.stack":             "Hebing",
+		"combat.noWeapon":       "Xian zhuangbei wuqi cai neng gongji",
+		"combat.tired":          "Tai lei le, deng tili huifu",
+		"combat.stunned":        "Xuanyun zhong!",
+		"status.stacks":         "%d ceng",
+		"vital.health":          "HP",
+		"vital.mana":            "MP",
+		"vital.stamina":         "Tili",
+		"notice.checkpoint":     "Fuhuo dian yi gengxin",
+		"door.locked":           "Men suo zhe, xuyao %s",
+		"gameOver.title":        "Ni bei dabai le",
+		"gameOver.lost":         "Sunshi le %d %s",
+		"gameOver.noLoss":       "Meiyou sunshi",
+		"gameOver.respawn":      "Zai fuhuo dian fuhuo",
+		"gameOver.mainMenu":     "Zhu Caidan",
+		"notice.levelUp":        "Shengji le! Xianzai shi %d ji",
+		"skills.title":          "Jineng",
+		"skills.level":          "Dengji %d",
+		"skills.xp":             "Jingyan %d/%d",
+		"skills.maxLevel":       "Yi man ji",
+		"skills.statPoints":     "Shuxing dian: %d",
+		"skills.skillPoints":    "Jineng dian: %d",
+		"skills.barHint":        "%s/%s/%s: fang dao jineng lan",
+		"skills.respec":         "Chongzhi (%d %s)",
+		"skills.respecDone":     "Suoyou dianshu yi fanhuan",
+		"skills.nothingToReset": "Meiyou keyi chongzhi de dianshu",
+		"skills.learn":          "Xuexi",
+		"skills.learned":        "%s tisheng dao %d ji",
+		"skills.assigned":       "%s fang dao le di %d ge",
+		"skills.active":         "Zhudong",
+		"skills.passive":        "Beidong",
+		"skills.rank":           "Dengji %d/%d",
+		"skills.cast":           "Xiaohao %d fali, lengque %.0f miao",
+		"skills.requiresLevel":  "Xuyao %d ji",
+		"skills.requires":       "Xuyao %s %d ji",
+		"skills.cost":           "Xiaohao %d jineng dian",
+		"skills.maxRank":        "Yijing shi zui gao dengji",
+		"skills.needLevel":      "Dengji bu gou",
+		"skills.needRequires":   "Xian xuexi qianzhi jineng",
+		"skills.noPoints":       "Dianshu bu gou",
+		"skills.unknown":        "Jineng bu cunzai",
+		"skills.cooldown":       "Jineng hai zai lengque",
+		"skills.noMana":         "Fali zhi bu gou",
+		"combat.immune":         "Mianyi",
+		"combat.defeated":       "Jibai le %s",
+		"notice.received":       "Huode %s x%d",
+		"notice.chestEmpty":     "Baoxiang shi kong de",
+		"quest.started":         "Jiequ renwu: %s",
+		"quest.completed":       "Renwu wancheng: %s",
+		"quest.log":             "Renwu Rizhi",
+		"quest.none":            "Zanwu renwu. Qu he cunmin tantan ba!",
+		"quest.active":          "Jinxingzhong",
+		"quest.finished":        "Yiwancheng",
+		"quest.objectives":      "Mubiao:",
+		"quest.rewards":         "Jiangli:",
+		"quest.randomReward":    "Suiji jiangli wupin",
+		"quest.done":            "Ci renwu yi wancheng.",
+		"notice.saved":          "Youxi yi baocun",
+		"notice.saveFailed":     "Baocun shibai",
+		"shop.buy":              "Goumai",
+		"shop.sell":             "Chushou",
+		"shop.gold":             "Jinbi: %d",
+		"shop.soldOut":          "Yi shouqing",
+		"shop.notEnoughGold":    "Jinbi buzu",
+		"shop.notBuying":        "Shangdian bu shougou zhe jian wupin",
+		"shop.failed":           "Jiaoyi shibai",
+		"shop.nothingToSell":    "Meiyou keyi chushou de wupin",
+		"shop.restock":          "%d miao hou buhuo",
+		"shop.hint":             "Shang/Xia: xuanze  Zuo/You: goumai/chushou  Enter: jiaoyi  Esc: guanbi",
+		"shop.confirmBuy":       "Goumai %s x%d, huafei %d jinbi?",
+		"shop.confirmSell":      "Chushou %s x%d, huode %d jinbi?",
+		"shop.yes":              "Queding",
+		"shop.no":               "Quxiao",
+		"shop.bought":           "Goumai %s x%d, huafei %d jinbi",
+		"shop.sold":             "Chushou %s x%d, huode %d jinbi",
+		"craft.title":           "Hecheng",
+		"craft.none":            "Hai meiyou xuehui renhe peifang.",
+		"craft.inputs":          "Cailiao:",
+		"craft.outputs":         "Chanwu:",
+		"craft.chance":          "Chenggonglv: %d%%",
+		"craft.failConsumes":    "(shibai shi xiaohao cailiao)",
+		"craft.station":         "Xuyao: %s",
+		"craft.button":          "Hecheng",
+		"craft.hint":            "Shang/Xia: xuanze  Enter: hecheng  Esc: guanbi",
+		"craft.success":         "Hecheng chenggong: %s!",
+		"craft.failed":          "Hecheng shibai...",
+		"craft.needStation":     "Xuyao kaojin %s",
+		"craft.missing":         "Cailiao buzu",
+		"craft.unknown":         "Hai meiyou xuehui zhege peifang",
+		"station.anvil":         "Tiezhen",
+		"dialogue.continue":     "An %s jixu",
+		"action.drop":           "Diuqi Wupin",
+		"notice.inventoryFull":  "Beibao yi man",
+		"notice.pickedUp":       "Shiqu %s x%d",
+		"action.pause":          "Zanting",
+	},
+}
+
+// T 按当前语言返回界面文字，缺失时回退到英文，再缺失则返回键本身
+func (s *Settings) T(key string) string {
+	if text, ok := translations[s.Language][key]; ok {
+		return text
+	}
+	if text, ok := translations["en"][key]; ok {
+		return text
+	}
+	return key
+}
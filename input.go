@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Action 可重新绑定按键的游戏动作
+type Action string
+
+const (
+	ActionMoveUp    Action = "moveUp"    // 向上移动
+	ActionMoveDown  Action = "moveDown"  // 向下移动
+	ActionMoveLeft  Action = "moveLeft"  // 向左移动
+	ActionMoveRight Action = "moveRight" // 向右移动
+	ActionSprint    Action = "sprint"    // 按住冲刺（消耗体力）
+	ActionAttack    Action = "attack"    // 攻击
+	ActionInteract  Action = "interact"  // 交互（拾取面前的物品）
+	ActionInventory Action = "inventory" // 打开/关闭背包
+	ActionQuestLog  Action = "questLog"  // 打开/关闭任务日志
+	ActionCraft     Action = "craft"     // 打开/关闭合成界面
+	ActionSkills    Action = "skills"    // 打开/关闭技能界面
+	ActionSkill1    Action = "skill1"    // 施放技能栏第 1 格的技能
+	ActionSkill2    Action = "skill2"    // 施放技能栏第 2 格的技能
+	ActionSkill3    Action = "skill3"    // 施放技能栏第 3 格的技能
+	ActionInspect   Action = "inspect"   // 查看背包中选中物品的详情
+	ActionUse       Action = "use"       // 使用背包中选中的物品
+	ActionDrop      Action = "drop"      // 丢弃背包中选中的物品
+	ActionPause     Action = "pause"     // 暂停菜单
+)
+
+// actionOrder 设置界面中按键绑定的显示顺序
+var actionOrder = []Action{
+	ActionMoveUp,
+	ActionMoveDown,
+	ActionMoveLeft,
+	ActionMoveRight,
+	ActionSprint,
+	ActionAttack,
+	ActionInteract,
+	ActionInventory,
+	ActionQuestLog,
+	ActionCraft,
+	ActionSkills,
+	ActionSkill1,
+	ActionSkill2,
+	ActionSkill3,
+	ActionInspect,
+	ActionUse,
+	ActionDrop,
+	ActionPause,
+}
+
+// defaultKeyBindings 默认按键绑定
+func defaultKeyBindings() map[Action]ebiten.Key {
+	return map[Action]ebiten.Key{
+		ActionMoveUp:    ebiten.KeyI,
+		ActionMoveDown:  ebiten.KeyK,
+		ActionMoveLeft:  ebiten.KeyJ,
+		ActionMoveRight: ebiten.KeyL,
+		ActionSprint:    ebiten.KeyShiftLeft,
+		ActionAttack:    ebiten.KeySpace,
+		ActionInteract:  ebiten.KeyE,
+		ActionInventory: ebiten.KeyF,
+		ActionQuestLog:  ebiten.KeyTab,
+		ActionCraft:     ebiten.KeyC,
+		ActionSkills:    ebiten.KeyP,
+		ActionSkill1:    ebiten.KeyZ,
+		ActionSkill2:    ebiten.KeyX,
+		ActionSkill3:    ebiten.KeyV,
+		ActionInspect:   ebiten.KeyR,
+		ActionUse:       ebiten.KeyU,
+		ActionDrop:      ebiten.KeyQ,
+		ActionPause:     ebiten.KeyEscape,
+	}
+}
+
+// isActionPressed 动作对应的按键是否处于按下状态
+func (s *Settings) isActionPressed(action Action) bool {
+	return ebiten.IsKeyPressed(s.Key(action))
+}
+
+// isActionJustPressed 动作对应的按键是否在本帧刚按下
+func (s *Settings) isActionJustPressed(action Action) bool {
+	return inpututil.IsKeyJustPressed(s.Key(action))
+}
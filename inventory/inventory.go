@@ -0,0 +1,86 @@
+// Package inventory 背包的公共部分：物品组、背包接口、按格子规则检查一次修改能否完成，以及背包界面的分类、排序和筛选
+// 商店、合成等需要同时取走和放入物品的系统通过 Exchange 原子地修改背包
+package inventory
+
+import (
+	"errors"
+	"math"
+)
+
+// 修改背包失败的原因
+var (
+	ErrMissing  = errors.New("inventory: 物品数量不足")
+	ErrFull     = errors.New("inventory: 背包已满")
+	ErrOverflow = errors.New("inventory: 物品数量超出上限")
+)
+
+// Stack 一组物品
+type Stack struct {
+	Item  int64 `json:"item"`
+	Count int64 `json:"count"`
+}
+
+// Inventory 交易需要的背包操作
+type Inventory interface {
+	CountItem(id int64) int64
+	// Exchange 原子地取走 remove 并放入 add：任何一项无法完成（数量不足、背包已满、数量超出上限）时不做任何修改并返回错误
+	Exchange(remove, add []Stack) error
+}
+
+// Counts 只记录每种物品数量的背包，同种物品叠加在一个格子里（实现 Inventory）；商店和合成的测试用它代替游戏的背包
+type Counts struct {
+	Items    map[int64]int64 // 每种物品的数量
+	Capacity int             // 格子数，0 表示不限
+}
+
+// CountItem 返回物品的数量
+func (c *Counts) CountItem(id int64) int64 {
+	return c.Items[id]
+}
+
+// Exchange 按 Check 的规则原子地取走 remove 并放入 add，失败时不做任何修改
+func (c *Counts) Exchange(remove, add []Stack) error {
+	next, err := Check(c.Items, c.Capacity, remove, add)
+	if err != nil {
+		return err
+	}
+	c.Items = next
+	return nil
+}
+
+// Check 检查背包按 remove、add 修改后是否仍然合法
+// counts 为每种物品的数量，同种物品叠加在一个格子里；capacity 为格子数，0 表示不限
+// 检查通过时返回修改后的数量，counts 本身不会被修改；某种物品的数量超过 int64 上限时返回 ErrOverflow
+func Check(counts map[int64]int64, capacity int, remove, add []Stack) (map[int64]int64, error) {
+	next := make(map[int64]int64, len(counts)+len(add))
+	for id, n := range counts {
+		if n > 0 {
+			next[id] = n
+		}
+	}
+	for _, s := range remove {
+		if s.Count <= 0 {
+			continue
+		}
+		if next[s.Item] < s.Count {
+			return nil, ErrMissing
+		}
+		next[s.Item] -= s.Count
+		if next[s.Item] == 0 {
+			delete(next, s.Item)
+		}
+	}
+	for _, s := range add {
+		if s.Count <= 0 {
+			continue
+		}
+		if next[s.Item] > math.MaxInt64-s.Count {
+			return nil, ErrOverflow
+		}
+		next[s.Item] += s.Count
+	}
+	if capacity > 0 && len(next) > capacity {
+		return nil, ErrFull
+	}
+	return next, nil
+}
@@ -0,0 +1,64 @@
+package inventory
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	counts := map[int64]int64{1: 10, 2: 1}
+	tests := []struct {
+		name        string
+		capacity    int
+		remove, add []Stack
+		want        map[int64]int64
+		err         error
+	}{
+		{"叠加到已有格子", 2, []Stack{{1, 4}}, []Stack{{2, 3}}, map[int64]int64{1: 6, 2: 4}, nil},
+		{"需要新格子", 2, []Stack{{1, 4}}, []Stack{{3, 1}}, nil, ErrFull},
+		{"取空一格后放得下", 2, []Stack{{2, 1}}, []Stack{{3, 1}}, map[int64]int64{1: 10, 3: 1}, nil},
+		{"数量不足", 5, []Stack{{1, 11}}, nil, nil, ErrMissing},
+		{"没有的物品", 5, []Stack{{9, 1}}, nil, nil, ErrMissing},
+		{"数量为 0 的项被忽略", 2, []Stack{{9, 0}}, []Stack{{8, 0}}, map[int64]int64{1: 10, 2: 1}, nil},
+		{"数量溢出", 0, nil, []Stack{{1, math.MaxInt64 - 9}}, nil, ErrOverflow},
+		{"多项累加后溢出", 0, nil, []Stack{{3, math.MaxInt64}, {3, 1}}, nil, ErrOverflow},
+		{"恰好到上限", 0, []Stack{{1, 10}}, []Stack{{1, math.MaxInt64}}, map[int64]int64{1: math.MaxInt64, 2: 1}, nil},
+		{"不限格子", 0, nil, []Stack{{3, 1}, {4, 1}}, map[int64]int64{1: 10, 2: 1, 3: 1, 4: 1}, nil},
+	}
+	for _, tt := range tests {
+		got, err := Check(counts, tt.capacity, tt.remove, tt.add)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: err = %v, 期望 %v", tt.name, err, tt.err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: 结果 = %v, 期望 %v", tt.name, got, tt.want)
+			continue
+		}
+		for id, n := range tt.want {
+			if got[id] != n {
+				t.Errorf("%s: 结果 = %v, 期望 %v", tt.name, got, tt.want)
+			}
+		}
+	}
+	if counts[1] != 10 || counts[2] != 1 || len(counts) != 2 {
+		t.Fatalf("Check 修改了传入的数量: %v", counts)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	var inv Inventory = &Counts{Items: map[int64]int64{1: 10}, Capacity: 2}
+	if err := inv.Exchange([]Stack{{1, 4}}, []Stack{{2, 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if inv.CountItem(1) != 6 || inv.CountItem(2) != 3 {
+		t.Fatalf("交换后 1 = %d, 2 = %d", inv.CountItem(1), inv.CountItem(2))
+	}
+	if err := inv.Exchange(nil, []Stack{{3, 1}}); !errors.Is(err, ErrFull) {
+		t.Fatalf("err = %v, 期望 ErrFull", err)
+	}
+	if inv.CountItem(3) != 0 || inv.CountItem(1) != 6 {
+		t.Fatal("失败的交换修改了背包")
+	}
+}
@@ -0,0 +1,117 @@
+package inventory
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Category 物品分类，背包按分类筛选和排序
+type Category string
+
+const (
+	CategoryAll        Category = ""           // 不筛选
+	CategoryWeapon     Category = "weapon"     // 武器
+	CategoryArmor      Category = "armor"      // 护甲和饰品
+	CategoryCurrency   Category = "currency"   // 货币
+	CategoryConsumable Category = "consumable" // 消耗品
+	CategoryMaterial   Category = "material"   // 材料和其他物品
+)
+
+// Categories 背包分类标签的显示顺序，也是按分类排序时的顺序
+var Categories = []Category{CategoryAll, CategoryWeapon, CategoryArmor, CategoryCurrency, CategoryConsumable, CategoryMaterial}
+
+// SortMode 背包排序方式
+type SortMode string
+
+const (
+	SortCategory SortMode = "category" // 按分类，同类按名字
+	SortRarity   SortMode = "rarity"   // 稀有度高的在前
+	SortName     SortMode = "name"     // 按名字
+	SortCount    SortMode = "count"    // 数量多的在前
+	SortRecent   SortMode = "recent"   // 最近获得的在前
+)
+
+// SortModes 排序按钮依次切换的排序方式
+var SortModes = []SortMode{SortCategory, SortRarity, SortName, SortCount, SortRecent}
+
+// Entry 背包中一格物品的排序和筛选信息
+type Entry struct {
+	Item     int64
+	Name     string
+	Category Category
+	Rarity   int   // 稀有度等级，越大越稀有
+	Count    int64 // 数量
+	Acquired int   // 获得顺序，越大越新
+}
+
+// Filter 背包筛选条件
+type Filter struct {
+	Category Category // 为空时不按分类筛选
+	Search   string   // 名字中包含的文字（不区分大小写），为空时不按名字筛选
+}
+
+// Match 物品是否满足筛选条件
+func (f Filter) Match(e Entry) bool {
+	if f.Category != CategoryAll && e.Category != f.Category {
+		return false
+	}
+	return strings.Contains(strings.ToLower(e.Name), strings.ToLower(strings.TrimSpace(f.Search)))
+}
+
+// View 返回满足筛选条件的物品按 mode 排序后的下标；排序相同时保持原来的顺序
+func View(entries []Entry, filter Filter, mode SortMode) []int {
+	var indices []int
+	for i, e := range entries {
+		if filter.Match(e) {
+			indices = append(indices, i)
+		}
+	}
+	slices.SortStableFunc(indices, func(a, b int) int {
+		return compare(entries[a], entries[b], mode)
+	})
+	return indices
+}
+
+// compare 按排序方式比较两格物品，主要条件相同时按名字、再按物品 id 排序
+func compare(a, b Entry, mode SortMode) int {
+	var c int
+	switch mode {
+	case SortCategory:
+		c = cmp.Compare(slices.Index(Categories, a.Category), slices.Index(Categories, b.Category))
+	case SortRarity:
+		c = cmp.Compare(b.Rarity, a.Rarity)
+	case SortCount:
+		c = cmp.Compare(b.Count, a.Count)
+	case SortRecent:
+		return cmp.Compare(b.Acquired, a.Acquired)
+	}
+	if c != 0 {
+		return c
+	}
+	if c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Item, b.Item)
+}
+
+// PageCount 返回 n 格物品每页 perPage 格时的页数，至少为 1
+func PageCount(n, perPage int) int {
+	return max((n+perPage-1)/perPage, 1)
+}
+
+// Consolidate 把同种物品的多组合并成一组，返回合并后每组在原列表中的下标和数量
+// 合并后的组保留第一次出现的位置
+func Consolidate(stacks []Stack) (kept []int, counts []int64) {
+	first := map[int64]int{}
+	for i, s := range stacks {
+		if j, ok := first[s.Item]; ok {
+			counts[j] += s.Count
+			continue
+		}
+		first[s.Item] = len(kept)
+		kept = append(kept, i)
+		counts = append(counts, s.Count)
+	}
+	return kept, counts
+}
@@ -0,0 +1,65 @@
+package inventory
+
+import (
+	"slices"
+	"testing"
+)
+
+var testEntries = []Entry{
+	{Item: 1001, Name: "Gold", Category: CategoryCurrency, Count: 500, Acquired: 0},
+	{Item: 1003, Name: "Sword1", Category: CategoryWeapon, Rarity: 1, Count: 1, Acquired: 3},
+	{Item: 2001, Name: "HealthPotion", Category: CategoryConsumable, Count: 3, Acquired: 1},
+	{Item: 1002, Name: "SwordXinShou", Category: CategoryWeapon, Count: 2, Acquired: 2},
+	{Item: 3002, Name: "Ring", Category: CategoryArmor, Rarity: 3, Count: 1, Acquired: 4},
+}
+
+func TestViewSort(t *testing.T) {
+	tests := []struct {
+		mode SortMode
+		want []int
+	}{
+		{SortCategory, []int{1, 3, 4, 0, 2}},
+		{SortRarity, []int{4, 1, 0, 2, 3}},
+		{SortName, []int{0, 2, 4, 1, 3}},
+		{SortCount, []int{0, 2, 3, 4, 1}},
+		{SortRecent, []int{4, 1, 3, 2, 0}},
+	}
+	for _, tt := range tests {
+		if got := View(testEntries, Filter{}, tt.mode); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: View = %v, 期望 %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestViewFilter(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   []int
+	}{
+		{Filter{Category: CategoryWeapon}, []int{1, 3}},
+		{Filter{Search: "sword"}, []int{1, 3}},
+		{Filter{Search: " POTION "}, []int{2}},
+		{Filter{Category: CategoryCurrency, Search: "sword"}, nil},
+		{Filter{Category: CategoryMaterial}, nil},
+	}
+	for _, tt := range tests {
+		if got := View(testEntries, tt.filter, SortName); !slices.Equal(got, tt.want) {
+			t.Errorf("%+v: View = %v, 期望 %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestPageCount(t *testing.T) {
+	for _, tt := range []struct{ n, perPage, want int }{{0, 16, 1}, {16, 16, 1}, {17, 16, 2}, {33, 16, 3}} {
+		if got := PageCount(tt.n, tt.perPage); got != tt.want {
+			t.Errorf("PageCount(%d, %d) = %d, 期望 %d", tt.n, tt.perPage, got, tt.want)
+		}
+	}
+}
+
+func TestConsolidate(t *testing.T) {
+	kept, counts := Consolidate([]Stack{{1, 2}, {2, 1}, {1, 3}, {3, 1}, {2, 4}})
+	if !slices.Equal(kept, []int{0, 1, 3}) || !slices.Equal(counts, []int64{5, 5, 1}) {
+		t.Fatalf("Consolidate = %v %v", kept, counts)
+	}
+}
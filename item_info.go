@@ -0,0 +1,302 @@
+package main
+
+import (
+	"Game/ecs"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"maps"
+	"slices"
+)
+
+// Rarity 物品稀有度
+type Rarity string
+
+const (
+	RarityCommon    Rarity = "common"    // 普通
+	RarityUncommon  Rarity = "uncommon"  // 优秀
+	RarityRare      Rarity = "rare"      // 稀有
+	RarityEpic      Rarity = "epic"      // 史诗
+	RarityLegendary Rarity = "legendary" // 传说
+)
+
+// rarities 稀有度从低到高
+var rarities = []Rarity{RarityCommon, RarityUncommon, RarityRare, RarityEpic, RarityLegendary}
+
+// rarityColors 各稀有度的边框、光晕和名字颜色
+var rarityColors = map[Rarity]color.RGBA{
+	RarityCommon:    {R: 150, G: 150, B: 200, A: 255},
+	RarityUncommon:  {R: 90, G: 210, B: 90, A: 255},
+	RarityRare:      {R: 70, G: 140, B: 255, A: 255},
+	RarityEpic:      {R: 180, G: 90, B: 255, A: 255},
+	RarityLegendary: {R: 255, G: 165, B: 40, A: 255},
+}
+
+// Index 返回稀有度的等级（普通为 0），未知的稀有度返回 -1
+func (r Rarity) Index() int {
+	return slices.Index(rarities, r)
+}
+
+// Color 返回稀有度的颜色
+func (r Rarity) Color() color.RGBA {
+	if c, ok := rarityColors[r]; ok {
+		return c
+	}
+	return rarityColors[RarityCommon]
+}
+
+// 物品详情面板布局
+const (
+	inspectX    = 180
+	inspectY    = 110
+	inspectW    = 440
+	inspectH    = 380
+	inspectIcon = 128 // 大图标尺寸
+)
+
+// tooltipLine 提示框中的一行：正文和跟在后面的注释（如与已装备物品的属性差），颜色为空时为白色
+type tooltipLine struct {
+	text    string
+	clr     color.Color
+	note    string
+	noteClr color.Color
+}
+
+// 提示框文字颜色
+var (
+	tooltipGray  = color.RGBA{R: 160, G: 160, B: 170, A: 255}
+	tooltipGreen = color.RGBA{R: 100, G: 220, B: 100, A: 255}
+	tooltipRed   = color.RGBA{R: 230, G: 90, B: 90, A: 255}
+	tooltipGold  = color.RGBA{R: 255, G: 215, B: 90, A: 255}
+)
+
+// drawRarityFrame 绘制物品格子的背景和稀有度边框；稀有及以上的物品在格子内外带有光晕，稀有度越高光晕越明显
+func drawRarityFrame(screen *ebiten.Image, x, y, w, h int, rarity Rarity) {
+	clr := rarity.Color()
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), color.RGBA{R: 50, G: 50, B: 70, A: 200}, false)
+	level := rarity.Index()
+	for j := 1; j < level; j++ {
+		// 格子内部由边缘向中心渐淡的光晕
+		inset := float32(j * 3)
+		glow := color.RGBA{R: clr.R, G: clr.G, B: clr.B, A: uint8(24 * (level - j))}
+		vector.StrokeRect(screen, float32(x)+inset, float32(y)+inset, float32(w)-2*inset, float32(h)-2*inset, 3, glow, false)
+		// 格子外部的光晕
+		vector.StrokeRect(screen, float32(x-j), float32(y-j), float32(w+2*j), float32(h+2*j), 1, color.RGBA{R: clr.R, G: clr.G, B: clr.B, A: uint8(160 / (j + 1))}, false)
+	}
+	width := float32(1)
+	if level > 0 {
+		width = 2
+	}
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), width, clr, false)
+}
+
+// sellValue 返回物品卖给商店的最高单价，没有商店收购时返回 0
+func (p *PlayScreen) sellValue(itemID int64) int64 {
+	var best int64
+	for _, s := range p.shops {
+		best = max(best, s.SellPrice(itemID))
+	}
+	return best
+}
+
+// itemInfoLines 返回物品的详细信息：名字、稀有度、描述、属性（与同栏位的已装备物品比较）、使用次数、数量和出售价格
+func (p *PlayScreen) itemInfoLines(it *item) []tooltipLine {
+	data := it.ItemData
+	kind := p.settings.T("rarity." + string(data.Rarity))
+	if data.Slot != "" {
+		kind += " " + p.settings.T("equip."+string(data.Slot))
+	}
+	lines := []tooltipLine{
+		{text: data.Name, clr: data.Rarity.Color()},
+		{text: kind, clr: tooltipGray},
+	}
+	if data.Description != "" {
+		lines = append(lines, tooltipLine{})
+		for _, line := range wrapText(data.Description, 36) {
+			lines = append(lines, tooltipLine{text: line})
+		}
+	}
+
+	if data.Slot != "" {
+		lines = append(lines, tooltipLine{})
+		equipped := ecs.Get[Equipment](p.world, p.player).Slots[data.Slot]
+		compare := equipped != it
+		var other Stats
+		if compare && equipped != nil {
+			other = equipped.ItemData.Stats
+		}
+		lines = append(lines, p.statLines(data.Stats, other, compare)...)
+		switch {
+		case !compare:
+			lines = append(lines, tooltipLine{text: p.settings.T("item.equipped"), clr: tooltipGray})
+		case equipped != nil:
+			lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("item.comparedTo"), equipped.ItemData.Name), clr: tooltipGray})
+		default:
+			lines = append(lines, tooltipLine{text: p.settings.T("item.slotEmpty"), clr: tooltipGray})
+		}
+	}
+
+	lines = append(lines, tooltipLine{})
+	if data.Charges > 1 {
+		charges := it.charges
+		if charges <= 0 {
+			charges = data.Charges
+		}
+		lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("item.charges"), charges, data.Charges)})
+	}
+	if it.count > 1 {
+		lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("item.stack"), it.count)})
+	}
+	if price := p.sellValue(data.id); price > 0 {
+		line := tooltipLine{text: fmt.Sprintf(p.settings.T("item.sellValue"), price), clr: tooltipGold}
+		if it.count > 1 {
+			line.note = fmt.Sprintf(p.settings.T("item.sellTotal"), price*it.count)
+			line.noteClr = tooltipGray
+		}
+		lines = append(lines, line)
+	} else {
+		lines = append(lines, tooltipLine{text: p.settings.T("item.noSell"), clr: tooltipGray})
+	}
+	return lines
+}
+
+// statLines 返回装备属性的各行；compare 为 true 时在后面注明与 other 的差值（绿色为提升，红色为下降）
+func (p *PlayScreen) statLines(stats, other Stats, compare bool) []tooltipLine {
+	rows := []struct {
+		key        string
+		value, old float64
+		percent    bool
+	}{
+		{"stat.attack", stats.Attack, other.Attack, false},
+		{"stat.defense", stats.Defense, other.Defense, false},
+		{"stat.speed", stats.Speed, other.Speed, false},
+		{"stat.crit", stats.Crit, other.Crit, true},
+	}
+	format := func(v float64, percent bool) string {
+		if percent {
+			return fmt.Sprintf("%+.0f%%", v*100)
+		}
+		return fmt.Sprintf("%+.0f", v)
+	}
+	var lines []tooltipLine
+	for _, r := range rows {
+		if r.value == 0 && (!compare || r.old == 0) {
+			continue
+		}
+		line := tooltipLine{text: fmt.Sprintf("%s %s", format(r.value, r.percent), p.settings.T(r.key))}
+		if diff := r.value - r.old; compare && diff != 0 {
+			line.note = "(" + format(diff, r.percent) + ")"
+			line.noteClr = tooltipGreen
+			if diff < 0 {
+				line.noteClr = tooltipRed
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawTooltipLines 从 (x, y) 开始绘制各行文字，空行只占半行高度
+func drawTooltipLines(screen *ebiten.Image, lines []tooltipLine, x, y int) {
+	for _, line := range lines {
+		if line.text == "" {
+			y += 8
+			continue
+		}
+		clr := line.clr
+		if clr == nil {
+			clr = color.White
+		}
+		drawColoredText(screen, line.text, x, y, clr)
+		if line.note != "" {
+			drawColoredText(screen, line.note, x+len(line.text)*6+6, y, line.noteClr)
+		}
+		y += 16
+	}
+}
+
+// drawItemTooltip 在光标旁边绘制鼠标悬停物品的提示框，提示框不会超出屏幕
+func (p *PlayScreen) drawItemTooltip(screen *ebiten.Image) {
+	if p.bagUI.hovered == nil {
+		return
+	}
+	lines := p.itemInfoLines(p.bagUI.hovered)
+	w, h := 0, 12
+	for _, line := range lines {
+		w = max(w, len(line.text)*6+len(line.note)*6+6)
+		if line.text == "" {
+			h += 8
+		} else {
+			h += 16
+		}
+	}
+	w += 16
+	cursorX, cursorY := ebiten.CursorPosition()
+	x, y := tooltipPosition(cursorX, cursorY, w, h, screen.Bounds().Dx(), screen.Bounds().Dy())
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), color.RGBA{R: 10, G: 10, B: 20, A: 235}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, p.bagUI.hovered.ItemData.Rarity.Color(), false)
+	drawTooltipLines(screen, lines, x+8, y+6)
+}
+
+// setInspecting 打开或关闭物品详情面板，it 为空时关闭
+func (p *PlayScreen) setInspecting(it *item) {
+	if it == p.bagUI.inspecting {
+		return
+	}
+	p.bagUI.inspecting = it
+	if it != nil {
+		p.sound.PlaySFX(sfxInventoryOpen)
+	} else {
+		p.sound.PlaySFX(sfxInventoryClose)
+	}
+}
+
+// updateInspect 处理物品详情面板的输入，返回面板是否处理了这一帧的输入
+func (p *PlayScreen) updateInspect() bool {
+	if p.bagUI.inspecting == nil {
+		return false
+	}
+	// 物品已经不在背包和装备栏里（用完、丢弃或卖掉）时关闭面板
+	equipped := slices.Contains(slices.Collect(maps.Values(ecs.Get[Equipment](p.world, p.player).Slots)), p.bagUI.inspecting)
+	if !slices.Contains(p.bag().Items, p.bagUI.inspecting) && !equipped {
+		p.setInspecting(nil)
+		return false
+	}
+	if p.settings.isActionJustPressed(ActionPause) || p.settings.isActionJustPressed(ActionInspect) {
+		p.setInspecting(nil)
+		return true
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		if !inRect(x, y, [4]int{inspectX, inspectY, inspectW, inspectH}) {
+			p.setInspecting(nil)
+			return true
+		}
+	}
+	return false
+}
+
+// drawInspect 绘制物品详情面板：左侧大图标，右侧名字和稀有度，下方为完整的物品信息
+func (p *PlayScreen) drawInspect(screen *ebiten.Image) {
+	it := p.bagUI.inspecting
+	if it == nil {
+		return
+	}
+	vector.DrawFilledRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), color.RGBA{A: 100}, false)
+	drawPanel(screen, inspectX, inspectY, inspectW, inspectH)
+	drawCenteredText(screen, p.settings.T("item.inspect"), inspectX, inspectY+12, inspectW)
+
+	iconX, iconY := inspectX+20, inspectY+40
+	drawRarityFrame(screen, iconX, iconY, inspectIcon, inspectIcon, it.ItemData.Rarity)
+	drawItemIcon(screen, it.ItemData, iconX+8, iconY+8, inspectIcon-16)
+
+	lines := p.itemInfoLines(it)
+	textX := iconX + inspectIcon + 20
+	drawTooltipLines(screen, lines[:2], textX, iconY+4)
+	drawTooltipLines(screen, lines[2:], textX, iconY+44)
+
+	ebitenutil.DebugPrintAt(screen, p.settings.T("item.inspectHint"), inspectX+20, inspectY+inspectH-26)
+}
@@ -1,52 +1,188 @@
 package main
 
 import (
+	"Game/combat"
+	"Game/ecs"
+	"Game/inventory"
+	"encoding/json"
+	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"log"
+	"os"
+	"slices"
+	"time"
 )
 
 type item struct {
 	id        int64
 	count     int64 // 数量
+	charges   int   // 当前这一个物品剩余的使用次数（0 表示尚未使用过）
+	acquired  int   // 最近一次获得的顺序，越大越新，背包按最近获得排序时使用
 	*ItemData       // 道具数据
 }
 
+// Bag 玩家的背包组件：携带的物品、快捷栏和物品的使用冷却
+type Bag struct {
+	Items     []*item                 // 背包物品
+	Size      int                     // 背包格子数
+	Acquired  int                     // 最近一次获得物品的顺序
+	Hotbar    [hotbarSlots]int64      // 快捷栏每格的物品 id，0 表示空
+	Cooldowns map[int64]time.Duration // 各种物品剩余的使用冷却时间
+}
+
+// bag 返回玩家的背包组件
+func (p *PlayScreen) bag() *Bag {
+	return ecs.Get[Bag](p.world, p.player)
+}
+
 // ItemData 初始化游戏道具数据的结构体
 type ItemData struct {
-	id    int64
-	Name  string        // 物品名称
-	Image *ebiten.Image // 物品图片
-}
-
-// ItemImages 定义所有的游戏道具
-var (
-	ItemImages = map[int64]*ItemData{
-		1001: {
-			id:    1001,
-			Name:  "Gold", // 金币
-			Image: loadImage("photos/type/jinBi.png"),
-		},
-		1002: {
-			id:    1002,
-			Name:  "SwordXinShou", // 新手剑
-			Image: loadImage("photos/type/SwordXinShou.png"),
-		},
-		1003: {
-			id:    1003,
-			Name:  "Sword1", // 一级剑
-			Image: loadImage("photos/type/Sword1.png"),
-		},
+	id          int64
+	Name        string             // 物品名称
+	Image       *ebiten.Image      // 物品图片
+	Description string             // 物品描述
+	Rarity      Rarity             // 稀有度
+	Category    inventory.Category // 背包中的分类
+	Price       int64              // 基础价格，商店按倍率计算买卖价格，0 表示不能买卖
+	Charges     int                // 每个物品可使用的次数，用完后消耗一个
+	Cooldown    time.Duration      // 使用后同种物品的冷却时间
+	Effects     []Effect           // 使用效果，为空表示不能使用
+	Slot        EquipSlot          // 装备栏位，为空表示不能装备
+	Stats       Stats              // 装备后提供的属性
+	DamageType  combat.DamageType  // 武器的伤害类型，为空表示物理伤害
+}
+
+// Usable 物品是否可以使用
+func (d *ItemData) Usable() bool {
+	return len(d.Effects) > 0
+}
+
+// itemDef 物品数据文件中的一条物品定义
+type itemDef struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	Image       string             `json:"image"`
+	Description string             `json:"description"`
+	Rarity      Rarity             `json:"rarity"`   // 省略表示普通
+	Category    inventory.Category `json:"category"` // 省略时按装备栏位和使用效果推断
+	Price       int64              `json:"price"`
+	Charges     int                `json:"charges"`
+	Cooldown    float64            `json:"cooldown"` // 冷却时间（秒）
+	Use         []json.RawMessage  `json:"use"`      // 使用效果，每项的 "effect" 字段为效果名
+	Slot        EquipSlot          `json:"slot"`
+	Stats       Stats              `json:"stats"`
+	DamageType  combat.DamageType  `json:"damageType"`
+}
+
+// itemCatalogPath 物品数据文件
+const itemCatalogPath = "data/items.json"
+
+// ItemImages 定义所有的游戏道具（从物品数据文件加载）
+var ItemImages map[int64]*ItemData
+
+// 物品效果会引用游戏界面，因此在 init 中加载物品数据，等效果和实体原型注册完成后再解析
+func init() {
+	ItemImages = loadItemCatalog(itemCatalogPath)
+}
+
+// loadItemCatalog 加载物品数据文件，数据有误时直接退出
+func loadItemCatalog(path string) map[int64]*ItemData {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("加载物品数据失败 %s: %v", path, err)
 	}
-)
+	catalog, err := parseItemCatalog(data)
+	if err != nil {
+		log.Fatalf("解析物品数据失败 %s: %v", path, err)
+	}
+	return catalog
+}
+
+// parseItemCatalog 解析物品定义并创建使用效果
+func parseItemCatalog(data []byte) (map[int64]*ItemData, error) {
+	var defs []itemDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, err
+	}
+	catalog := make(map[int64]*ItemData, len(defs))
+	for _, def := range defs {
+		if _, ok := catalog[def.ID]; ok {
+			return nil, fmt.Errorf("物品 id %d 重复", def.ID)
+		}
+		d := &ItemData{
+			id:          def.ID,
+			Name:        def.Name,
+			Image:       loadImage(def.Image),
+			Description: def.Description,
+			Rarity:      def.Rarity,
+			Category:    def.Category,
+			Price:       def.Price,
+			Charges:     max(def.Charges, 1),
+			Cooldown:    time.Duration(def.Cooldown * float64(time.Second)),
+			Slot:        def.Slot,
+			Stats:       def.Stats,
+			DamageType:  def.DamageType,
+		}
+		if d.Cooldown < 0 {
+			return nil, fmt.Errorf("物品 %d (%s): 冷却时间不能为负数", def.ID, def.Name)
+		}
+		if d.Price < 0 {
+			return nil, fmt.Errorf("物品 %d (%s): 价格不能为负数", def.ID, def.Name)
+		}
+		if d.Rarity == "" {
+			d.Rarity = RarityCommon
+		}
+		if d.Rarity.Index() < 0 {
+			return nil, fmt.Errorf("物品 %d (%s): 未知的稀有度 %q", def.ID, def.Name, def.Rarity)
+		}
+		if d.Slot != "" && !validSlot(d.Slot) {
+			return nil, fmt.Errorf("物品 %d (%s): 未知的装备栏位 %q", def.ID, def.Name, def.Slot)
+		}
+		for _, raw := range def.Use {
+			effect, err := newEffect(raw)
+			if err != nil {
+				return nil, fmt.Errorf("物品 %d (%s): %w", def.ID, def.Name, err)
+			}
+			d.Effects = append(d.Effects, effect)
+		}
+		if d.Category == inventory.CategoryAll {
+			d.Category = defaultCategory(d)
+		}
+		if !slices.Contains(inventory.Categories, d.Category) {
+			return nil, fmt.Errorf("物品 %d (%s): 未知的分类 %q", def.ID, def.Name, def.Category)
+		}
+		catalog[def.ID] = d
+	}
+	return catalog, nil
+}
 
-// loadImage 加载图片文件
+// defaultCategory 推断没有写分类的物品的分类：武器、其他装备、能使用的消耗品，其余为材料
+func defaultCategory(d *ItemData) inventory.Category {
+	switch {
+	case d.Slot == SlotWeapon:
+		return inventory.CategoryWeapon
+	case d.Slot != "":
+		return inventory.CategoryArmor
+	case d.Usable():
+		return inventory.CategoryConsumable
+	}
+	return inventory.CategoryMaterial
+}
+
+// loadedImages 已加载的图片：路径 -> 图片
+var loadedImages = map[string]*ebiten.Image{}
+
+// loadImage 加载图片文件（相对路径），同一路径只解码一次；文件缺失时直接退出，
+// 所以放置实体时用到的图片应在初始化时先加载一次，之后只从缓存中取
 func loadImage(filename string) *ebiten.Image {
-	// 使用相对路径
-	imgPath := filename
-	img, _, err := ebitenutil.NewImageFromFile(imgPath)
+	if img := loadedImages[filename]; img != nil {
+		return img
+	}
+	img, _, err := ebitenutil.NewImageFromFile(filename)
 	if err != nil {
-		log.Fatalf("加载图片失败 %s: %v", imgPath, err)
+		log.Fatalf("加载图片失败 %s: %v", filename, err)
 	}
+	loadedImages[filename] = img
 	return img
 }
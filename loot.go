@@ -0,0 +1,143 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/inventory"
+	"Game/loot"
+	"Game/nav"
+	"fmt"
+	"log"
+)
+
+// lootPath 掉落表数据文件
+const lootPath = "data/loot.json"
+
+// 宝箱图片
+const (
+	chestImage     = "photos/chest.png"
+	chestOpenImage = "photos/chest_open.png"
+)
+
+// lootScatter 敌人掉落的物品最多散开到周围几格
+const lootScatter = 2
+
+// Chest 宝箱组件，打开时从掉落表抽取物品，每个宝箱只能打开一次
+type Chest struct {
+	ID     string // 存档中记录打开状态用的 id
+	Table  string // 掉落表 id
+	Opened bool
+}
+
+// initLoot 加载并校验掉落表和宝箱图片，数据有误时直接退出（需要在加载地图和任务之前调用）
+func (p *PlayScreen) initLoot() {
+	tables, err := loot.Load(lootPath)
+	if err != nil {
+		log.Fatalf("加载掉落表失败: %v", err)
+	}
+	errs := loot.Validate(tables, loot.Catalog{Item: func(id int64) bool { return ItemImages[id] != nil }})
+	// 敌人引用的掉落表必须存在
+	for _, def := range EnemyDefs {
+		if def.Loot != "" && tables[def.Loot] == nil {
+			errs = append(errs, fmt.Errorf("敌人 %s: 掉落表 %q 不存在", def.Kind, def.Loot))
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("掉落表数据有误: %s", lootPath)
+	}
+	loadImage(chestImage)
+	loadImage(chestOpenImage)
+	p.loot = tables
+}
+
+// spawnChest 在网格坐标 (col, row) 放置宝箱
+func (p *PlayScreen) spawnChest(id, table string, col, row int) ecs.Entity {
+	if p.loot[table] == nil {
+		log.Fatalf("宝箱 %s: 掉落表 %q 不存在", id, table)
+	}
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: float64(col * p.gridSize), Y: float64(row * p.gridSize)})
+	ecs.Add(p.world, e, ecs.Sprite{Image: loadImage(chestImage), Width: float64(p.gridSize), Height: float64(p.gridSize), Layer: layerItems})
+	ecs.Add(p.world, e, ecs.Collider{W: float64(p.gridSize), H: float64(p.gridSize), Solid: true})
+	ecs.Add(p.world, e, Chest{ID: id, Table: table})
+	return e
+}
+
+// playerLevel 返回玩家等级，用于掉落条件
+func (p *PlayScreen) playerLevel() int {
+	return p.level().Progress.Level
+}
+
+// rollLoot 按玩家当前状态从掉落表抽取物品
+func (p *PlayScreen) rollLoot(table string, firstKill bool) []inventory.Stack {
+	return p.loot.Roll(table, loot.Context{Level: p.playerLevel(), FirstKill: firstKill}, p.rng)
+}
+
+// dropEnemyLoot 记录击败次数，并把敌人的掉落物散落在它所在格子的周围
+func (p *PlayScreen) dropEnemyLoot(def *EnemyDef, pos *ecs.Position) {
+	firstKill := p.story().Kills[def.Kind] == 0
+	p.story().Kills[def.Kind]++
+	if def.Loot == "" {
+		return
+	}
+	col := int(pos.X+def.Size/2) / p.gridSize
+	row := int(pos.Y+def.Size/2) / p.gridSize
+	tiles := p.freeTilesAround(col, row, lootScatter)
+	for i, s := range p.rollLoot(def.Loot, firstKill) {
+		t := tiles[i%len(tiles)]
+		p.spawnPickup(s.Item, s.Count, t.X, t.Y)
+	}
+}
+
+// freeTilesAround 按距离由近到远返回 (col, row) 周围 radius 格内可以通行的格子，至少包含中心格
+func (p *PlayScreen) freeTilesAround(col, row, radius int) []nav.Point {
+	grid := tileGrid(p.gridData)
+	tiles := []nav.Point{{X: col, Y: row}}
+	for r := 1; r <= radius; r++ {
+		for dy := -r; dy <= r; dy++ {
+			for dx := -r; dx <= r; dx++ {
+				t := nav.Point{X: col + dx, Y: row + dy}
+				if max(abs(dx), abs(dy)) != r || t.Y < 0 || t.Y >= len(p.gridData) || t.X < 0 || t.X >= len(p.gridData[0]) || grid.Blocked(t) {
+					continue
+				}
+				tiles = append(tiles, t)
+			}
+		}
+	}
+	return tiles
+}
+
+// chestInFront 查找玩家面前还没打开的宝箱
+func (p *PlayScreen) chestInFront() (ecs.Entity, bool) {
+	col, row := p.frontTile()
+	for _, e := range p.world.Query(ecs.MaskOf[Chest](p.world) | ecs.MaskOf[ecs.Position](p.world)) {
+		pos := ecs.Get[ecs.Position](p.world, e)
+		if int(pos.X)/p.gridSize == col && int(pos.Y)/p.gridSize == row && !ecs.Get[Chest](p.world, e).Opened {
+			return e, true
+		}
+	}
+	return 0, false
+}
+
+// openChest 打开宝箱，抽到的物品放进背包
+func (p *PlayScreen) openChest(e ecs.Entity) {
+	defer p.walletTx("chest")()
+	chest := ecs.Get[Chest](p.world, e)
+	p.setChestOpened(e)
+	p.sound.PlaySFX(sfxInventoryOpen)
+	stacks := p.rollLoot(chest.Table, false)
+	if len(stacks) == 0 {
+		p.notices.Show(p.settings.T("notice.chestEmpty"))
+	}
+	for _, s := range stacks {
+		p.giveItem(s.Item, s.Count)
+	}
+}
+
+// setChestOpened 把宝箱标记为已打开并换成打开的图片
+func (p *PlayScreen) setChestOpened(e ecs.Entity) {
+	ecs.Get[Chest](p.world, e).Opened = true
+	ecs.Get[ecs.Sprite](p.world, e).Image = loadImage(chestOpenImage)
+}
@@ -0,0 +1,244 @@
+// Package loot 掉落表：权重项、必掉项、嵌套掉落表、数量范围和掉落条件
+// 随机数由调用方提供，使用相同种子时结果可以复现
+package loot
+
+import (
+	"Game/inventory"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"slices"
+)
+
+// maxDepth 嵌套掉落表的最大层数，防止数据写错时无限递归
+const maxDepth = 8
+
+// Range 整数范围，JSON 中可以写成单个数字 3 或 [最小值, 最大值]
+type Range struct {
+	Min, Max int64
+	set      bool // 数据中写了这个范围，用于区分省略和明确写成 0
+}
+
+// UnmarshalJSON 解析单个数字或 [min, max]
+func (r *Range) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*r = Range{Min: n, Max: n, set: true}
+		return nil
+	}
+	var pair []int64
+	if err := json.Unmarshal(data, &pair); err != nil || len(pair) != 2 {
+		return fmt.Errorf("范围应为数字或 [最小值, 最大值]: %s", data)
+	}
+	*r = Range{Min: pair[0], Max: pair[1], set: true}
+	return nil
+}
+
+// roll 在范围内均匀取值，省略的范围为 1
+func (r Range) roll(rng *rand.Rand) int64 {
+	if !r.set {
+		return 1
+	}
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + rng.Int64N(r.Max-r.Min+1)
+}
+
+// Condition 掉落条件，所有字段都满足时才会掉落
+type Condition struct {
+	MinLevel  int  `json:"minLevel"`  // 玩家等级至少为多少
+	MaxLevel  int  `json:"maxLevel"`  // 玩家等级至多为多少，0 表示不限
+	FirstKill bool `json:"firstKill"` // 只在第一次击败该敌人时掉落
+}
+
+// Met 条件是否满足
+func (c *Condition) Met(ctx Context) bool {
+	if c == nil {
+		return true
+	}
+	return ctx.Level >= c.MinLevel && (c.MaxLevel == 0 || ctx.Level <= c.MaxLevel) &&
+		(!c.FirstKill || ctx.FirstKill)
+}
+
+// Entry 掉落表中的一项：物品、嵌套掉落表，或两者都没有（表示这次什么都不掉）
+type Entry struct {
+	Item   int64      `json:"item"`
+	Table  string     `json:"table"`  // 嵌套掉落表，Count 为掷这个表的次数
+	Weight int        `json:"weight"` // 权重项的权重
+	Count  Range      `json:"count"`  // 数量范围，省略时为 1
+	If     *Condition `json:"if"`     // 掉落条件，不满足时该项不参与
+}
+
+// Table 掉落表：必掉项全部掉落，再从权重项中按权重抽取 Rolls 次
+type Table struct {
+	Rolls      Range   `json:"rolls"`
+	Guaranteed []Entry `json:"guaranteed"`
+	Entries    []Entry `json:"entries"`
+}
+
+// Tables 所有掉落表，按 id 索引
+type Tables map[string]*Table
+
+// Context 掉落时的条件
+type Context struct {
+	Level     int  // 玩家等级
+	FirstKill bool // 是否第一次击败该敌人
+}
+
+// Load 读取掉落表数据文件
+func Load(path string) (Tables, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tables Tables
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tables, nil
+}
+
+// NewRNG 用种子创建随机数生成器，相同种子得到相同的掉落
+func NewRNG(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+// Roll 掷一次掉落表，返回掉落的物品（同种物品合并，按物品 id 排序）
+func (t Tables) Roll(id string, ctx Context, rng *rand.Rand) []inventory.Stack {
+	counts := map[int64]int64{}
+	t.roll(id, ctx, rng, counts, 0)
+	drops := make([]inventory.Stack, 0, len(counts))
+	for _, item := range slices.Sorted(maps.Keys(counts)) {
+		drops = append(drops, inventory.Stack{Item: item, Count: counts[item]})
+	}
+	return drops
+}
+
+func (t Tables) roll(id string, ctx Context, rng *rand.Rand, counts map[int64]int64, depth int) {
+	table, ok := t[id]
+	if !ok || depth > maxDepth {
+		return
+	}
+	for _, e := range table.Guaranteed {
+		if e.If.Met(ctx) {
+			t.drop(e, ctx, rng, counts, depth)
+		}
+	}
+
+	// 只在满足条件的权重项中抽取
+	var entries []Entry
+	total := 0
+	for _, e := range table.Entries {
+		if e.Weight > 0 && e.If.Met(ctx) {
+			entries = append(entries, e)
+			total += e.Weight
+		}
+	}
+	if total == 0 {
+		return
+	}
+	rolls := table.Rolls.roll(rng)
+	for range rolls {
+		pick := rng.IntN(total)
+		for _, e := range entries {
+			if pick < e.Weight {
+				t.drop(e, ctx, rng, counts, depth)
+				break
+			}
+			pick -= e.Weight
+		}
+	}
+}
+
+// drop 掉落一项：物品按数量范围累加，嵌套掉落表按数量掷多次
+func (t Tables) drop(e Entry, ctx Context, rng *rand.Rand, counts map[int64]int64, depth int) {
+	n := e.Count.roll(rng)
+	switch {
+	case e.Table != "":
+		for range n {
+			t.roll(e.Table, ctx, rng, counts, depth+1)
+		}
+	case e.Item != 0 && n > 0:
+		counts[e.Item] += n
+	}
+}
+
+// Catalog 校验时使用的物品目录，为空时不检查物品是否存在
+type Catalog struct {
+	Item func(id int64) bool
+}
+
+// Validate 检查掉落表，返回所有发现的问题（包括嵌套引用的循环）
+func Validate(t Tables, catalog Catalog) []error {
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(t)) {
+		table := t[id]
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("掉落表 %s: "+format, append([]any{id}, args...)...))
+		}
+		if table.Rolls.Min < 0 || table.Rolls.Max < table.Rolls.Min {
+			fail("rolls 范围无效")
+		}
+		if len(table.Entries) > 0 && !table.Rolls.set {
+			fail("有权重项但没有 rolls")
+		}
+		if table.Rolls.set && table.Rolls.Max == 0 {
+			fail("rolls 不能为 0")
+		}
+		check := func(e Entry, weighted bool) {
+			switch {
+			case e.Item != 0 && e.Table != "":
+				fail("一项不能同时有 item 和 table")
+			case e.Item != 0 && catalog.Item != nil && !catalog.Item(e.Item):
+				fail("物品 %d 不存在", e.Item)
+			case e.Table != "" && t[e.Table] == nil:
+				fail("嵌套的掉落表 %q 不存在", e.Table)
+			case !weighted && e.Item == 0 && e.Table == "":
+				fail("必掉项缺少 item 或 table")
+			}
+			if e.Count.Min < 0 || e.Count.Max < e.Count.Min {
+				fail("count 范围无效")
+			}
+			if e.Count.set && e.Count.Max == 0 {
+				fail("count 不能为 0，省略时为 1")
+			}
+			if weighted && e.Weight <= 0 {
+				fail("权重必须大于 0")
+			}
+		}
+		for _, e := range table.Guaranteed {
+			check(e, false)
+		}
+		for _, e := range table.Entries {
+			check(e, true)
+		}
+		if cycle := t.findCycle(id, nil); cycle != nil {
+			fail("嵌套引用形成循环: %v", cycle)
+		}
+	}
+	return errs
+}
+
+// findCycle 从 id 出发沿嵌套引用查找回到路径上的循环
+func (t Tables) findCycle(id string, path []string) []string {
+	if i := slices.Index(path, id); i >= 0 {
+		return append(path[i:], id)
+	}
+	table, ok := t[id]
+	if !ok {
+		return nil
+	}
+	path = append(path, id)
+	for _, e := range slices.Concat(table.Guaranteed, table.Entries) {
+		if e.Table == "" {
+			continue
+		}
+		if cycle := t.findCycle(e.Table, slices.Clone(path)); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
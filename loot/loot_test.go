@@ -0,0 +1,171 @@
+package loot
+
+import (
+	"Game/inventory"
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testTables = `{
+  "enemy": {
+    "rolls": 1,
+    "guaranteed": [
+      {"item": 1001, "count": [5, 15]},
+      {"item": 9, "if": {"firstKill": true}}
+    ],
+    "entries": [
+      {"weight": 50},
+      {"item": 1, "weight": 30},
+      {"table": "nested", "weight": 20},
+      {"item": 2, "weight": 1000, "if": {"minLevel": 5}}
+    ]
+  },
+  "nested": {
+    "rolls": [2, 2],
+    "entries": [{"item": 3, "weight": 1, "count": 2}]
+  }
+}`
+
+func mustTables(t *testing.T, data string) Tables {
+	t.Helper()
+	var tables Tables
+	if err := json.Unmarshal([]byte(data), &tables); err != nil {
+		t.Fatal(err)
+	}
+	return tables
+}
+
+func TestRollReproducible(t *testing.T) {
+	tables := mustTables(t, testTables)
+	for seed := range uint64(20) {
+		a := tables.Roll("enemy", Context{Level: 1}, NewRNG(seed))
+		b := tables.Roll("enemy", Context{Level: 1}, NewRNG(seed))
+		if !slices.Equal(a, b) {
+			t.Fatalf("种子 %d 两次结果不同: %v %v", seed, a, b)
+		}
+	}
+}
+
+func TestGuaranteedAndConditions(t *testing.T) {
+	tables := mustTables(t, testTables)
+	rng := NewRNG(1)
+	for range 200 {
+		drops := tables.Roll("enemy", Context{Level: 1}, rng)
+		gold := drops[slices.IndexFunc(drops, func(s inventory.Stack) bool { return s.Item == 1001 })]
+		if gold.Count < 5 || gold.Count > 15 {
+			t.Fatalf("金币数量 %d 超出范围", gold.Count)
+		}
+		for _, d := range drops {
+			if d.Item == 9 || d.Item == 2 {
+				t.Fatalf("不满足条件的物品掉落了: %v", drops)
+			}
+			if d.Item == 3 && d.Count != 4 {
+				t.Fatalf("嵌套表掷 2 次每次 2 个，得到 %d", d.Count)
+			}
+		}
+	}
+	// 第一次击败必掉；等级满足时权重很高的物品几乎总会掉
+	report := Simulate(tables, "enemy", Context{Level: 5, FirstKill: true}, 1000, NewRNG(2))
+	if report.Rate(report.Stat(9)) != 1 {
+		t.Fatalf("首杀物品掉落率 = %v", report.Rate(report.Stat(9)))
+	}
+	if report.Rate(report.Stat(2)) < 0.85 {
+		t.Fatalf("满足等级的物品掉落率 = %v", report.Rate(report.Stat(2)))
+	}
+}
+
+// TestDistribution 大量模拟时掉落率接近权重比例
+func TestDistribution(t *testing.T) {
+	tables := mustTables(t, testTables)
+	report := Simulate(tables, "enemy", Context{Level: 1}, 20000, NewRNG(42))
+	want := map[int64]float64{1001: 1, 1: 0.3, 3: 0.2}
+	for item, rate := range want {
+		if got := report.Rate(report.Stat(item)); math.Abs(got-rate) > 0.02 {
+			t.Errorf("物品 %d 掉落率 = %.3f, 期望约 %.2f", item, got, rate)
+		}
+	}
+	gold := report.Stat(1001)
+	if gold.Min != 5 || gold.Max != 15 || math.Abs(report.Mean(gold)-10) > 0.2 {
+		t.Errorf("金币统计 = %+v, 平均 %.2f", gold, report.Mean(gold))
+	}
+	var out strings.Builder
+	report.Print(&out, func(int64) string { return "x" })
+	if !strings.Contains(out.String(), "30.") {
+		t.Errorf("输出中缺少掉落率:\n%s", out.String())
+	}
+}
+
+// TestSimulateZero 模拟 0 次时掉落率为 0，输出中没有 NaN
+func TestSimulateZero(t *testing.T) {
+	tables := mustTables(t, testTables)
+	report := Simulate(tables, "enemy", Context{Level: 1}, 0, NewRNG(42))
+	if report.Rate(Stat{Hits: 3}) != 0 || report.Mean(Stat{Total: 3}) != 0 {
+		t.Fatal("没有模拟过时掉落率和平均数量应为 0")
+	}
+	var out strings.Builder
+	report.Print(&out, func(int64) string { return "x" })
+	if strings.Contains(out.String(), "NaN") {
+		t.Errorf("输出中有 NaN:\n%s", out.String())
+	}
+}
+
+func TestRangeJSON(t *testing.T) {
+	var r struct{ A, B Range }
+	if err := json.Unmarshal([]byte(`{"A": 3, "B": [1, 4]}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.A != (Range{Min: 3, Max: 3, set: true}) || r.B != (Range{Min: 1, Max: 4, set: true}) {
+		t.Fatalf("解析结果 = %+v", r)
+	}
+	if err := json.Unmarshal([]byte(`{"A": [1, 2, 3]}`), &r); err == nil {
+		t.Fatal("三个数字的范围没有报错")
+	}
+
+	// 省略的范围为 1，明确写成 0 的范围为 0
+	var omitted, zero struct{ A Range }
+	if err := json.Unmarshal([]byte(`{}`), &omitted); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"A": [0, 0]}`), &zero); err != nil {
+		t.Fatal(err)
+	}
+	rng := NewRNG(1)
+	if omitted.A.roll(rng) != 1 || zero.A.roll(rng) != 0 {
+		t.Fatalf("省略 = %d，写成 0 = %d", omitted.A.roll(rng), zero.A.roll(rng))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tables := mustTables(t, `{
+	  "a": {"entries": [{"item": 7, "weight": 1}, {"item": 1, "table": "b", "weight": 1}, {"table": "missing", "weight": 1}]},
+	  "b": {"rolls": 1, "guaranteed": [{"count": 1}], "entries": [{"table": "c", "weight": 0}]},
+	  "c": {"rolls": [3, 1], "guaranteed": [{"table": "b", "count": [2, 1]}]},
+	  "d": {"rolls": 0, "entries": [{"item": 1, "weight": 1, "count": [0, 0]}]}
+	}`)
+	var msgs []string
+	for _, err := range Validate(tables, Catalog{Item: func(id int64) bool { return id == 1 }}) {
+		msgs = append(msgs, err.Error())
+	}
+	all := strings.Join(msgs, "\n")
+	for _, want := range []string{"没有 rolls", "物品 7 不存在", "同时有 item 和 table", `"missing" 不存在`,
+		"必掉项缺少", "权重必须大于 0", "rolls 范围无效", "count 范围无效", "循环",
+		"rolls 不能为 0", "count 不能为 0"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("缺少错误 %q:\n%s", want, all)
+		}
+	}
+}
+
+// TestShippedLoot 校验游戏自带的掉落表
+func TestShippedLoot(t *testing.T) {
+	tables, err := Load("../data/loot.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range Validate(tables, Catalog{}) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,93 @@
+package loot
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"text/tabwriter"
+)
+
+// Stat 一种物品在多次掉落中的统计
+type Stat struct {
+	Item     int64
+	Total    int64 // 所有掉落中的总数量
+	Hits     int   // 掉落了该物品的次数
+	Min, Max int64 // 掉落时单次数量的最小值和最大值
+}
+
+// Report 模拟多次掉落的结果
+type Report struct {
+	Table string
+	Rolls int
+	Stats []Stat // 按物品 id 排序
+	Empty int    // 什么都没掉的次数
+}
+
+// Rate 物品的掉落率（掉落次数 / 总次数）
+func (r *Report) Rate(s Stat) float64 {
+	return r.perRoll(int64(s.Hits))
+}
+
+// Mean 每次掉落的平均数量（包括没掉的次数）
+func (r *Report) Mean(s Stat) float64 {
+	return r.perRoll(s.Total)
+}
+
+// perRoll 平均到每次掉落，没有模拟过时为 0
+func (r *Report) perRoll(n int64) float64 {
+	if r.Rolls <= 0 {
+		return 0
+	}
+	return float64(n) / float64(r.Rolls)
+}
+
+// Stat 返回某种物品的统计，没有掉落过时返回零值
+func (r *Report) Stat(item int64) Stat {
+	for _, s := range r.Stats {
+		if s.Item == item {
+			return s
+		}
+	}
+	return Stat{Item: item}
+}
+
+// Simulate 掷 n 次掉落表并统计每种物品的掉落率和数量，用于平衡数值
+func Simulate(t Tables, id string, ctx Context, n int, rng *rand.Rand) *Report {
+	stats := map[int64]*Stat{}
+	report := &Report{Table: id, Rolls: n}
+	for range n {
+		drops := t.Roll(id, ctx, rng)
+		if len(drops) == 0 {
+			report.Empty++
+		}
+		for _, d := range drops {
+			s, ok := stats[d.Item]
+			if !ok {
+				s = &Stat{Item: d.Item, Min: d.Count, Max: d.Count}
+				stats[d.Item] = s
+			}
+			s.Total += d.Count
+			s.Hits++
+			s.Min = min(s.Min, d.Count)
+			s.Max = max(s.Max, d.Count)
+		}
+	}
+	for _, s := range stats {
+		report.Stats = append(report.Stats, *s)
+	}
+	slices.SortFunc(report.Stats, func(a, b Stat) int { return cmp.Compare(a.Item, b.Item) })
+	return report
+}
+
+// Print 以表格形式输出统计结果，name 返回物品名称
+func (r *Report) Print(w io.Writer, name func(item int64) string) {
+	fmt.Fprintf(w, "掉落表 %s，模拟 %d 次，%d 次没有掉落 (%.2f%%)\n", r.Table, r.Rolls, r.Empty, 100*r.perRoll(int64(r.Empty)))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "id\tname\tdrop rate\tavg/roll\tmin\tmax\ttotal\t")
+	for _, s := range r.Stats {
+		fmt.Fprintf(tw, "%d\t%s\t%.2f%%\t%.3f\t%d\t%d\t%d\t\n", s.Item, name(s.Item), 100*r.Rate(s), r.Mean(s), s.Min, s.Max, s.Total)
+	}
+	tw.Flush()
+}
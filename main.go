@@ -9,8 +9,7 @@ func main() {
 	// 初始化游戏主结构体（包含状态）
 	game := NewGame()
 
-	// 设置窗口属性
-	ebiten.SetWindowSize(800, 600)
+	// 设置窗口属性（窗口大小、全屏、垂直同步由玩家设置决定，见 Settings.Apply）
 	ebiten.SetWindowTitle("贾先生的2D游戏 - 开始界面 Demo")
 
 	// 如果需要同步FPS
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"Game/dungeon"
+	"Game/ecs"
+	"Game/worldmap"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"maps"
+	"slices"
+	"time"
+)
+
+// mapsDir 地图数据目录，每个 .json 文件是一张 Tiled 地图，文件名为地图 id
+const mapsDir = "data/maps"
+
+// dungeonsPath 地下城数据文件，每层在启动时按固定的种子生成，和地图文件一起组成整个世界
+const dungeonsPath = "data/dungeons.json"
+
+// startSpawn 新游戏开始时所在的出生点（起始地图中）
+const startSpawn = "start"
+
+// doorImage 门的图片
+const doorImage = "photos/door.png"
+
+// mapFade 切换地图时淡出和淡入各自的时长
+const mapFade = 300 * time.Millisecond
+
+// 地图对象的类型（传送点、门和出生点见 worldmap 包）
+const (
+	objectEnemy      = "enemy"      // 敌人：属性 kind
+	objectPickup     = "pickup"     // 地上的物品：属性 item、count（省略时为 1）
+	objectChest      = "chest"      // 宝箱：名字为存档用的 id，属性 table
+	objectNPC        = "npc"        // NPC：名字，属性 dialogue、portrait
+	objectCheckpoint = "checkpoint" // 复活点：名字为 id
+	objectStation    = "station"    // 合成台：名字为种类
+	objectAmbient    = "ambient"    // 环境声源：属性 sound（音频名称）、radius（可听距离，像素，省略时为默认值）
+)
+
+// Door 门组件，面对门按交互键切换到另一张地图；Key 不为 0 时需要背包里有这个物品
+type Door struct {
+	Map   string
+	Spawn string
+	Key   int64
+}
+
+// mapState 离开地图时记住的状态：剩余的物品与敌人和打开过的宝箱，再次进入时恢复
+type mapState struct {
+	Pickups []savedPickup `json:"pickups"`
+	Enemies []savedEnemy  `json:"enemies"`
+	Chests  []string      `json:"chests"` // 已打开的宝箱
+}
+
+// mapTransition 正在进行的地图切换：先淡出，全黑时换地图，再淡入
+type mapTransition struct {
+	Map      string
+	Col, Row int
+	elapsed  time.Duration
+	switched bool
+}
+
+// Atlas 地图资源：所有地图、当前地图和离开的地图的状态
+type Atlas struct {
+	Maps       map[string]*worldmap.Map // 所有地图：id -> 地图
+	Current    string                   // 当前地图 id
+	States     map[string]*mapState     // 去过但不在其中的地图离开时的状态
+	Transition *mapTransition           // 正在进行的地图切换，为空表示没有
+}
+
+// atlas 返回地图资源
+func (p *PlayScreen) atlas() *Atlas {
+	return ecs.Resource[Atlas](p.world)
+}
+
+// initMaps 加载并校验所有地图和门的图片，数据有误时直接退出（需要在加载掉落表之后调用）
+func (p *PlayScreen) initMaps() {
+	loaded, err := worldmap.LoadDir(mapsDir)
+	if err != nil {
+		log.Fatalf("加载地图失败: %v", err)
+	}
+	cols, rows := screenWidth/p.gridSize, screenHeight/p.gridSize
+	floors := loadDungeons(cols, rows, p.gridSize)
+	for _, id := range slices.Sorted(maps.Keys(floors)) {
+		if loaded[id] != nil {
+			log.Fatalf("地下城楼层 %s 和地图文件重名", id)
+		}
+		loaded[id] = floors[id]
+	}
+	errs := worldmap.Validate(loaded, startMap)
+	for _, id := range slices.Sorted(maps.Keys(loaded)) {
+		m := loaded[id]
+		if m.Width != cols || m.Height != rows || m.TileWidth != p.gridSize || m.TileHeight != p.gridSize {
+			errs = append(errs, fmt.Errorf("地图 %s: 尺寸必须为 %d×%d 格、每格 %d 像素", id, cols, rows, p.gridSize))
+		}
+		chests := map[string]bool{}
+		for _, o := range m.Objects {
+			if err := p.validateObject(m, o, chests); err != nil {
+				errs = append(errs, fmt.Errorf("地图 %s 对象 %d (%s %q): %v", id, o.ID, o.Type, o.Name, err))
+			}
+		}
+	}
+	// 传送到其他地图的物品效果必须指向存在的地图中可以站立的格子
+	for _, data := range ItemImages {
+		for _, effect := range data.Effects {
+			if e, ok := effect.(*teleportEffect); ok && e.Map != "" && (loaded[e.Map] == nil || loaded[e.Map].Solid(e.Col, e.Row)) {
+				errs = append(errs, fmt.Errorf("物品 %d (%s): 传送目标 %s (%d, %d) 不存在或在墙上", data.id, data.Name, e.Map, e.Col, e.Row))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("地图数据有误: %s、%s", mapsDir, dungeonsPath)
+	}
+	loadImage(doorImage)
+	ecs.SetResource(p.world, Atlas{Maps: loaded, States: map[string]*mapState{}})
+}
+
+// loadDungeons 读取地下城数据并生成所有楼层的地图，数据有误时直接退出
+func loadDungeons(cols, rows, tileSize int) map[string]*worldmap.Map {
+	dungeons, err := dungeon.Load(dungeonsPath)
+	if err != nil {
+		log.Fatalf("加载地下城失败: %v", err)
+	}
+	if errs := dungeon.Validate(dungeons); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("地下城数据有误: %s", dungeonsPath)
+	}
+	floors, err := dungeon.Maps(dungeons, cols, rows, tileSize)
+	if err != nil {
+		log.Fatalf("生成地下城失败: %v", err)
+	}
+	return floors
+}
+
+// validateObject 检查游戏解释的地图对象：引用的敌人、物品、掉落表和合成台存在，并且不在墙上
+func (p *PlayScreen) validateObject(m *worldmap.Map, o *worldmap.Object, chests map[string]bool) error {
+	switch o.Type {
+	case worldmap.TypeSpawn, worldmap.TypeWarp:
+		return nil
+	case worldmap.TypeDoor:
+		if key := o.Int("key"); key != 0 && ItemImages[key] == nil {
+			return fmt.Errorf("钥匙 %d 不存在", key)
+		}
+	case objectEnemy:
+		if EnemyDefs[o.String("kind")] == nil {
+			return fmt.Errorf("敌人种类 %q 不存在", o.String("kind"))
+		}
+	case objectPickup:
+		if ItemImages[o.Int("item")] == nil {
+			return fmt.Errorf("物品 %q 不存在", o.String("item"))
+		}
+		if o.String("count") != "" && o.Int("count") <= 0 {
+			return fmt.Errorf("count 必须大于 0")
+		}
+	case objectChest:
+		if o.Name == "" || chests[o.Name] {
+			return fmt.Errorf("宝箱缺少名字或名字重复")
+		}
+		chests[o.Name] = true
+		if p.loot[o.String("table")] == nil {
+			return fmt.Errorf("掉落表 %q 不存在", o.String("table"))
+		}
+	case objectNPC:
+		if o.Name == "" || o.String("dialogue") == "" || o.String("portrait") == "" {
+			return fmt.Errorf("NPC 缺少名字、dialogue 或 portrait")
+		}
+	case objectCheckpoint:
+		if o.Name == "" {
+			return fmt.Errorf("复活点缺少名字")
+		}
+	case objectStation:
+		if stationImages[o.Name] == "" {
+			return fmt.Errorf("合成台种类不存在")
+		}
+	case objectAmbient:
+		// 声源可以放在墙上（如河流、建筑内部），不检查位置
+		if soundFiles[o.String("sound")] == "" {
+			return fmt.Errorf("音频 %q 不存在", o.String("sound"))
+		}
+		if o.String("radius") != "" && o.Int("radius") <= 0 {
+			return fmt.Errorf("radius 必须大于 0")
+		}
+		return nil
+	default:
+		return fmt.Errorf("未知的对象类型")
+	}
+	if m.Solid(o.Col, o.Row) {
+		return fmt.Errorf("位置 (%d, %d) 在墙上", o.Col, o.Row)
+	}
+	return nil
+}
+
+// mapObjects 返回所有地图中某种类型的对象，用于校验对话、任务等引用
+func (p *PlayScreen) mapObjects(typ string) []*worldmap.Object {
+	var objects []*worldmap.Object
+	for _, m := range p.atlas().Maps {
+		objects = append(objects, m.ObjectsOf(typ)...)
+	}
+	return objects
+}
+
+// currentMap 返回当前地图
+func (p *PlayScreen) currentMap() *worldmap.Map {
+	return p.atlas().Maps[p.atlas().Current]
+}
+
+// loadMap 进入地图：按地图数据生成格子、寻路网格和实体，去过的地图再恢复离开时的状态
+func (p *PlayScreen) loadMap(id string) {
+	m := p.atlas().Maps[id]
+	p.atlas().Current = id
+	p.initGridData(m.Height, m.Width)
+	for row := range m.Height {
+		for col := range m.Width {
+			if m.Solid(col, row) {
+				p.gridData[row][col] |= tileSolid
+			}
+		}
+	}
+	p.initNavigation()
+
+	for _, o := range m.Objects {
+		switch o.Type {
+		case worldmap.TypeDoor:
+			p.spawnDoor(o)
+		case objectEnemy:
+			p.spawnEnemy(o.String("kind"), o.Col, o.Row)
+		case objectPickup:
+			p.spawnPickup(o.Int("item"), max(o.Int("count"), 1), o.Col, o.Row)
+		case objectChest:
+			p.spawnChest(o.Name, o.String("table"), o.Col, o.Row)
+		case objectNPC:
+			p.spawnNPC(o.Name, o.String("dialogue"), o.String("portrait"), o.Col, o.Row)
+		case objectCheckpoint:
+			p.spawnCheckpoint(o.Name, o.Col, o.Row)
+		case objectStation:
+			p.spawnStation(o.Name, o.Col, o.Row)
+		case objectAmbient:
+			p.spawnAmbient(o)
+		}
+	}
+	for _, c := range p.containers {
+		if c.Def.Map == id {
+			p.spawnStorage(c.Def)
+		}
+	}
+	if state := p.atlas().States[id]; state != nil {
+		p.restoreMapState(state)
+		delete(p.atlas().States, id)
+	}
+	p.setCheckpoint(p.respawner().Point)
+}
+
+// clearMap 移除当前地图上除玩家以外的所有实体和环境声源
+func (p *PlayScreen) clearMap() {
+	p.removeAmbients()
+	for _, e := range p.world.Query(0) {
+		if e != p.player {
+			p.world.Despawn(e)
+		}
+	}
+	p.damageNumbers = DamageNumbers{}
+}
+
+// leaveMap 记住当前地图的状态并移除它的实体
+func (p *PlayScreen) leaveMap() {
+	p.atlas().States[p.atlas().Current] = p.captureMap()
+	p.clearMap()
+}
+
+// switchMap 立即切换到地图 id，把玩家放在格子 (col, row)
+func (p *PlayScreen) switchMap(id string, col, row int) {
+	if id != p.atlas().Current {
+		p.leaveMap()
+		p.loadMap(id)
+		p.notices.Show(p.atlas().Maps[id].Name())
+	}
+	pos := p.playerPos()
+	pos.X, pos.Y = float64(col*p.gridSize), float64(row*p.gridSize)
+	*ecs.Get[ecs.Velocity](p.world, p.player) = ecs.Velocity{}
+	// 换了地图后即使格子相同也要重新触发到达事件
+	p.playerTile = [2]int{-1, -1}
+}
+
+// captureMap 收集当前地图上剩余的物品与敌人和打开过的宝箱
+func (p *PlayScreen) captureMap() *mapState {
+	state := &mapState{}
+	ecs.Each2(p.world, func(e ecs.Entity, pickup *Pickup, pos *ecs.Position) {
+		state.Pickups = append(state.Pickups, savedPickup{Item: pickup.ItemID, Count: pickup.Count, X: pos.X, Y: pos.Y})
+	})
+	ecs.Each2(p.world, func(e ecs.Entity, enemy *Enemy, ai *ecs.AI) {
+		pos := ecs.Get[ecs.Position](p.world, e)
+		col, row := int(ai.HomeX)/p.gridSize, int(ai.HomeY)/p.gridSize
+		state.Enemies = append(state.Enemies, savedEnemy{
+			Kind: enemy.Def.Kind, HomeCol: col, HomeRow: row,
+			X: pos.X, Y: pos.Y, Health: ecs.Get[ecs.Health](p.world, e).Current,
+		})
+	})
+	ecs.Each(p.world, func(e ecs.Entity, chest *Chest) {
+		if chest.Opened {
+			state.Chests = append(state.Chests, chest.ID)
+		}
+	})
+	return state
+}
+
+// restoreMapState 用记住的状态替换地图数据中的物品和敌人；已经不存在的物品和敌人会被忽略
+func (p *PlayScreen) restoreMapState(state *mapState) {
+	for _, e := range p.world.Query(ecs.MaskOf[Pickup](p.world)) {
+		p.world.Despawn(e)
+	}
+	for _, s := range state.Pickups {
+		if ItemImages[s.Item] == nil || s.Count <= 0 {
+			continue
+		}
+		e := p.spawnPickup(s.Item, s.Count, 0, 0)
+		pos := ecs.Get[ecs.Position](p.world, e)
+		pos.X, pos.Y = s.X, s.Y
+	}
+	for _, e := range p.world.Query(ecs.MaskOf[Enemy](p.world)) {
+		p.world.Despawn(e)
+	}
+	for _, s := range state.Enemies {
+		if EnemyDefs[s.Kind] == nil {
+			continue
+		}
+		e := p.spawnEnemy(s.Kind, s.HomeCol, s.HomeRow)
+		pos := ecs.Get[ecs.Position](p.world, e)
+		pos.X, pos.Y = s.X, s.Y
+		health := ecs.Get[ecs.Health](p.world, e)
+		health.Current = min(max(s.Health, 1), health.Max)
+	}
+	for _, e := range p.world.Query(ecs.MaskOf[Chest](p.world)) {
+		if slices.Contains(state.Chests, ecs.Get[Chest](p.world, e).ID) {
+			p.setChestOpened(e)
+		}
+	}
+}
+
+// spawnDoor 在地图上放置门
+func (p *PlayScreen) spawnDoor(o *worldmap.Object) ecs.Entity {
+	target, spawn := o.Target()
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: float64(o.Col * p.gridSize), Y: float64(o.Row * p.gridSize)})
+	ecs.Add(p.world, e, ecs.Sprite{Image: loadImage(doorImage), Width: float64(p.gridSize), Height: float64(p.gridSize), Layer: layerGround})
+	ecs.Add(p.world, e, ecs.Collider{W: float64(p.gridSize), H: float64(p.gridSize), Solid: true})
+	ecs.Add(p.world, e, Door{Map: target, Spawn: spawn, Key: o.Int("key")})
+	return e
+}
+
+// doorInFront 查找玩家面前的门
+func (p *PlayScreen) doorInFront() (*Door, bool) {
+	col, row := p.frontTile()
+	for _, e := range p.world.Query(ecs.MaskOf[Door](p.world) | ecs.MaskOf[ecs.Position](p.world)) {
+		pos := ecs.Get[ecs.Position](p.world, e)
+		if int(pos.X)/p.gridSize == col && int(pos.Y)/p.gridSize == row {
+			return ecs.Get[Door](p.world, e), true
+		}
+	}
+	return nil, false
+}
+
+// openDoor 穿过门；门上了锁而背包里没有钥匙时给出提示
+func (p *PlayScreen) openDoor(d *Door) {
+	if d.Key != 0 && p.CountItem(d.Key) == 0 {
+		p.notices.Show(fmt.Sprintf(p.settings.T("door.locked"), ItemImages[d.Key].Name))
+		return
+	}
+	p.sound.PlaySFX(sfxInventoryOpen)
+	p.startTransition(d.Map, d.Spawn)
+}
+
+// updateWarps 玩家中心走进传送点时切换地图
+func (p *PlayScreen) updateWarps() {
+	cx, cy := p.playerCenter()
+	t := p.tileOf(cx, cy)
+	if warp := p.currentMap().WarpAt(t.X, t.Y); warp != nil {
+		p.startTransition(warp.Target())
+	}
+}
+
+// startTransition 开始淡出，全黑时切换到地图 id 的出生点 spawn
+func (p *PlayScreen) startTransition(id, spawn string) {
+	s := p.atlas().Maps[id].Spawn(spawn)
+	p.transitionTo(id, s.Col, s.Row)
+}
+
+// transitionTo 开始淡出，全黑时切换到地图 id 的格子 (col, row)；切换期间游戏世界暂停
+func (p *PlayScreen) transitionTo(id string, col, row int) {
+	if p.atlas().Transition != nil {
+		return
+	}
+	p.setInventoryOpen(false)
+	p.playerVitals().Sprinting = false
+	*ecs.Get[ecs.Velocity](p.world, p.player) = ecs.Velocity{}
+	p.atlas().Transition = &mapTransition{Map: id, Col: col, Row: row}
+}
+
+// updateTransition 推进地图切换，淡入结束后恢复游戏
+func (p *PlayScreen) updateTransition(dt time.Duration) {
+	t := p.atlas().Transition
+	t.elapsed += dt
+	if !t.switched && t.elapsed >= mapFade {
+		t.switched = true
+		p.switchMap(t.Map, t.Col, t.Row)
+	}
+	if t.elapsed >= 2*mapFade {
+		p.atlas().Transition = nil
+	}
+	p.notices.Update(dt)
+}
+
+// drawTransition 切换地图时在整个画面上叠加逐渐变黑再变亮的遮罩
+func (p *PlayScreen) drawTransition(screen *ebiten.Image) {
+	t := p.atlas().Transition
+	if t == nil {
+		return
+	}
+	alpha := float64(t.elapsed) / float64(mapFade)
+	if alpha > 1 {
+		alpha = max(2-alpha, 0)
+	}
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	vector.DrawFilledRect(screen, 0, 0, float32(w), float32(h), color.RGBA{A: uint8(alpha * 255)}, false)
+}
+
+// drawWarps 绘制当前地图上的传送点
+func (p *PlayScreen) drawWarps(screen *ebiten.Image) {
+	size := float32(p.gridSize)
+	for _, o := range p.currentMap().ObjectsOf(worldmap.TypeWarp) {
+		x, y, w, h := float32(o.Col)*size, float32(o.Row)*size, float32(o.Cols)*size, float32(o.Rows)*size
+		vector.DrawFilledRect(screen, x, y, w, h, color.RGBA{R: 60, G: 40, B: 110, A: 110}, false)
+		vector.StrokeRect(screen, x+1, y+1, w-2, h-2, 2, color.RGBA{R: 170, G: 130, B: 255, A: 200}, false)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"Game/anim"
+	"Game/combat"
+	"Game/ecs"
+	"Game/quest"
+	"fmt"
+	"image/color"
+	"time"
+)
+
+const (
+	playerInvulnerability = 800 * time.Millisecond // 玩家受击后的无敌时间
+	enemyInvulnerability  = 300 * time.Millisecond // 敌人受击后的无敌时间
+	blinkInterval         = 80 * time.Millisecond  // 无敌期间精灵闪烁的间隔
+
+	weaponReach       = 28.0                   // 武器攻击距离（像素）
+	weaponWidth       = 36.0                   // 武器判定框宽度（像素）
+	knockbackForce    = 260.0                  // 击退速度（像素/秒）
+	knockbackDuration = 150 * time.Millisecond // 击退持续时间
+)
+
+// Combatant 可以受到攻击的实体组件
+type Combatant struct {
+	combat.Invulnerability
+	Resistances     map[combat.DamageType]float64 // 各类型伤害的抗性
+	KnockbackResist float64                       // 击退抗性 0~1
+
+	knockX, knockY float64       // 击退速度
+	knockRemaining time.Duration // 剩余击退时间
+}
+
+// updateCombat 推进无敌时间和击退，击退期间覆盖实体自身的速度
+func (p *PlayScreen) updateCombat(dt time.Duration) {
+	ecs.Each2(p.world, func(e ecs.Entity, c *Combatant, vel *ecs.Velocity) {
+		c.Update(dt)
+		if c.knockRemaining <= 0 {
+			return
+		}
+		c.knockRemaining -= dt
+		if c.knockRemaining > 0 {
+			vel.X, vel.Y = c.knockX, c.knockY
+		} else {
+			vel.X, vel.Y = 0, 0
+		}
+	})
+	// 无敌期间闪烁
+	ecs.Each2(p.world, func(e ecs.Entity, c *Combatant, sprite *ecs.Sprite) {
+		sprite.Hidden = c.Active() && (c.Remaining/blinkInterval)%2 == 1
+	})
+	p.damageNumbers.Update(dt)
+}
+
+// equippedWeapon 返回实体装备的武器，没有时返回 nil
+func (p *PlayScreen) equippedWeapon(e ecs.Entity) *item {
+	equipment := ecs.Get[Equipment](p.world, e)
+	if equipment == nil {
+		return nil
+	}
+	return equipment.Slots[SlotWeapon]
+}
+
+// tryPlayerAttack 按下攻击键时开始攻击动画，实际伤害在动画的 hit 帧结算
+func (p *PlayScreen) tryPlayerAttack() {
+	if p.equippedWeapon(p.player) == nil {
+		p.notices.Show(p.settings.T("combat.noWeapon"))
+		return
+	}
+	if p.stunned(p.player) {
+		p.notices.Show(p.settings.T("combat.stunned"))
+		return
+	}
+	// 体力不够时不能攻击，攻击动作开始时才消耗体力
+	if p.playerVitals().Stamina.Current < playerConfig.AttackCost {
+		p.notices.Show(p.settings.T("combat.tired"))
+		return
+	}
+	if p.playerAnim.Attack() {
+		p.spendStamina(playerConfig.AttackCost)
+	}
+}
+
+// playerStrike 用武器攻击玩家面前判定框内的所有敌人
+func (p *PlayScreen) playerStrike() {
+	dx, dy := facingVector(p.playerAnim.Facing())
+	hitbox := combat.Hitbox(p.entityBox(p.player), dx, dy, weaponReach, weaponWidth)
+	mask := ecs.MaskOf[Enemy](p.world) | ecs.MaskOf[ecs.Position](p.world) | ecs.MaskOf[ecs.Collider](p.world)
+	for _, e := range p.world.Query(mask) {
+		if hitbox.Overlaps(p.entityBox(e)) {
+			p.strike(p.player, e)
+		}
+	}
+}
+
+// strike 攻击者对目标结算一次伤害，目标处于无敌状态时返回 false
+func (p *PlayScreen) strike(attacker, target ecs.Entity) bool {
+	c := ecs.Get[Combatant](p.world, target)
+	health := ecs.Get[ecs.Health](p.world, target)
+	if c == nil || health == nil || c.Active() {
+		return false
+	}
+
+	atk := statSheet(p.world, attacker)
+	def := statSheet(p.world, target)
+	hit := combat.Resolve(combat.Attacker{
+		Attack:     atk.Attack,
+		CritChance: atk.Crit,
+		Type:       p.damageType(attacker),
+	}, combat.Defender{
+		Defense:     def.Defense,
+		Resistances: c.Resistances,
+	}, p.rng.Float64())
+	c.Trigger()
+
+	x, y := p.entityBox(target).Center()
+	if hit.Immune {
+		p.damageNumbers.Add(p.settings.T("combat.immune"), x, y, color.RGBA{R: 170, G: 170, B: 170, A: 255}, 1)
+		return true
+	}
+	damage := min(hit.Damage, health.Current)
+	health.Current -= damage
+	if target == p.player {
+		p.vitalEvent(VitalEvent{Stat: VitalHealth, Amount: -damage})
+	}
+
+	ax, ay := p.entityBox(attacker).Center()
+	force := knockbackForce * (1 - clamp01(c.KnockbackResist))
+	c.knockX, c.knockY = combat.Knockback(ax, ay, x, y, force)
+	c.knockRemaining = knockbackDuration
+
+	switch {
+	case target == p.player:
+		p.damageNumbers.Add(fmt.Sprint(hit.Damage), x, y, color.RGBA{R: 255, G: 80, B: 80, A: 255}, 1)
+	case hit.Crit:
+		p.damageNumbers.Add(fmt.Sprintf("%d!", hit.Damage), x, y, color.RGBA{R: 255, G: 220, B: 60, A: 255}, 1.5)
+	default:
+		p.damageNumbers.Add(fmt.Sprint(hit.Damage), x, y, color.White, 1)
+	}
+	p.sound.PlaySFXAt(sfxHit, x, y)
+
+	if health.Current == 0 {
+		p.onKilled(target)
+	}
+	return true
+}
+
+// damageType 返回实体攻击的伤害类型（由武器决定，没有武器时为物理伤害）
+func (p *PlayScreen) damageType(e ecs.Entity) combat.DamageType {
+	if weapon := p.equippedWeapon(e); weapon != nil && weapon.ItemData.DamageType != "" {
+		return weapon.ItemData.DamageType
+	}
+	return combat.Physical
+}
+
+// onKilled 处理生命值归零的实体
+func (p *PlayScreen) onKilled(e ecs.Entity) {
+	if enemy := ecs.Get[Enemy](p.world, e); enemy != nil {
+		p.notices.Show(fmt.Sprintf(p.settings.T("combat.defeated"), enemy.Def.Name))
+		p.dropEnemyLoot(enemy.Def, ecs.Get[ecs.Position](p.world, e))
+		p.gainXP(enemy.Def.XP)
+		p.world.Despawn(e)
+		p.questEvent(quest.Event{Type: quest.EventKill, Enemy: enemy.Def.Kind})
+	}
+	if e == p.player {
+		p.die()
+	}
+}
+
+// entityBox 返回实体碰撞盒（没有碰撞盒时使用精灵尺寸）在世界中的矩形
+func (p *PlayScreen) entityBox(e ecs.Entity) combat.Box {
+	pos := ecs.Get[ecs.Position](p.world, e)
+	if col := ecs.Get[ecs.Collider](p.world, e); col != nil {
+		r := col.Bounds(pos)
+		return combat.Box{X: r.X, Y: r.Y, W: r.W, H: r.H}
+	}
+	box := combat.Box{X: pos.X, Y: pos.Y}
+	if sprite := ecs.Get[ecs.Sprite](p.world, e); sprite != nil {
+		box.W, box.H = sprite.Width, sprite.Height
+	}
+	return box
+}
+
+// facingVector 把角色朝向转换为单位方向
+func facingVector(dir anim.Direction) (float64, float64) {
+	switch dir {
+	case anim.DirUp:
+		return 0, -1
+	case anim.DirLeft:
+		return -1, 0
+	case anim.DirRight:
+		return 1, 0
+	}
+	return 0, 1
+}
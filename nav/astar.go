@@ -0,0 +1,193 @@
+package nav
+
+// 移动代价：直走 10，斜走 14（约等于 10×√2）
+const (
+	costStraight = 10
+	costDiagonal = 14
+)
+
+// neighbors 八个方向，前四个为直走
+var neighbors = [8]Point{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// Searcher A* 搜索器，重复使用内部缓冲区以减少分配；不能并发使用
+type Searcher struct {
+	w, h   int
+	gScore []int32
+	parent []int32
+	closed []uint32
+	seen   []uint32
+	gen    uint32 // 本次搜索的编号，用于免清空地标记 closed/seen
+	open   openList
+	cutoff bool // 上一次搜索是否因为达到展开上限而停止
+}
+
+// FindPath 在网格上搜索从 start 到 goal 的最短路径，使用八方向移动，斜走不能穿过墙角
+// 返回的路径不含起点、含终点；maxExpanded 限制展开的格子数（0 表示不限制），超出时视为找不到
+func FindPath(g Grid, start, goal Point, maxExpanded int) ([]Point, bool) {
+	var s Searcher
+	return s.FindPath(g, start, goal, maxExpanded)
+}
+
+// FindPath 同包级函数 FindPath
+func (s *Searcher) FindPath(g Grid, start, goal Point, maxExpanded int) ([]Point, bool) {
+	s.cutoff = false
+	if !walkable(g, start) || !walkable(g, goal) {
+		return nil, false
+	}
+	if start == goal {
+		return nil, true
+	}
+	s.reset(g)
+
+	startIndex := s.index(start)
+	s.gScore[startIndex] = 0
+	s.parent[startIndex] = -1
+	s.seen[startIndex] = s.gen
+	s.open.push(openNode{index: int32(startIndex), f: heuristic(start, goal)})
+
+	expanded := 0
+	goalIndex := s.index(goal)
+	for len(s.open) > 0 {
+		node := s.open.pop()
+		current := int(node.index)
+		if s.closed[current] == s.gen {
+			continue // 同一格子的旧记录
+		}
+		if current == goalIndex {
+			return s.buildPath(goalIndex), true
+		}
+		s.closed[current] = s.gen
+		expanded++
+		if maxExpanded > 0 && expanded > maxExpanded {
+			s.cutoff = true
+			return nil, false
+		}
+
+		p := s.point(current)
+		for i, d := range neighbors {
+			next := Point{p.X + d.X, p.Y + d.Y}
+			if !walkable(g, next) {
+				continue
+			}
+			cost := int32(costStraight)
+			if i >= 4 {
+				// 斜走时两侧的直走格子都必须可通行，避免穿过墙角
+				if !walkable(g, Point{p.X + d.X, p.Y}) || !walkable(g, Point{p.X, p.Y + d.Y}) {
+					continue
+				}
+				cost = costDiagonal
+			}
+			nextIndex := s.index(next)
+			if s.closed[nextIndex] == s.gen {
+				continue
+			}
+			score := s.gScore[current] + cost
+			if s.seen[nextIndex] == s.gen && score >= s.gScore[nextIndex] {
+				continue
+			}
+			s.seen[nextIndex] = s.gen
+			s.gScore[nextIndex] = score
+			s.parent[nextIndex] = int32(current)
+			s.open.push(openNode{index: int32(nextIndex), f: score + heuristic(next, goal)})
+		}
+	}
+	return nil, false
+}
+
+// reset 准备新的搜索，网格尺寸变化时重新分配缓冲区
+func (s *Searcher) reset(g Grid) {
+	w, h := g.Size()
+	if w != s.w || h != s.h {
+		s.w, s.h = w, h
+		s.gScore = make([]int32, w*h)
+		s.parent = make([]int32, w*h)
+		s.closed = make([]uint32, w*h)
+		s.seen = make([]uint32, w*h)
+		s.gen = 0
+	}
+	s.gen++
+	if s.gen == 0 {
+		// 编号溢出回绕时清空标记
+		clear(s.closed)
+		clear(s.seen)
+		s.gen = 1
+	}
+	s.open = s.open[:0]
+}
+
+func (s *Searcher) index(p Point) int {
+	return p.Y*s.w + p.X
+}
+
+func (s *Searcher) point(index int) Point {
+	return Point{index % s.w, index / s.w}
+}
+
+// buildPath 从终点沿父节点回溯出路径（不含起点）
+func (s *Searcher) buildPath(goalIndex int) []Point {
+	n := 0
+	for i := goalIndex; s.parent[i] >= 0; i = int(s.parent[i]) {
+		n++
+	}
+	path := make([]Point, n)
+	for i := goalIndex; s.parent[i] >= 0; i = int(s.parent[i]) {
+		n--
+		path[n] = s.point(i)
+	}
+	return path
+}
+
+// heuristic 八方向移动的估计代价（octile 距离）
+func heuristic(a, b Point) int32 {
+	dx, dy := abs(a.X-b.X), abs(a.Y-b.Y)
+	return int32(costStraight*(dx+dy) + (costDiagonal-2*costStraight)*min(dx, dy))
+}
+
+// openNode 待展开的格子
+type openNode struct {
+	index int32
+	f     int32
+}
+
+// openList 按 f 值排序的最小堆（不使用 container/heap，避免每次入堆的接口分配）
+type openList []openNode
+
+func (o *openList) push(n openNode) {
+	*o = append(*o, n)
+	h := *o
+	for i := len(h) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if h[parent].f <= h[i].f {
+			break
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+func (o *openList) pop() openNode {
+	h := *o
+	top := h[0]
+	last := len(h) - 1
+	h[0] = h[last]
+	h = h[:last]
+	for i := 0; ; {
+		smallest := i
+		if l := 2*i + 1; l < len(h) && h[l].f < h[smallest].f {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h) && h[r].f < h[smallest].f {
+			smallest = r
+		}
+		if smallest == i {
+			break
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+	*o = h
+	return top
+}
@@ -0,0 +1,130 @@
+package nav
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// pathCost 计算路径代价，并检查每一步都相邻、可通行且没有穿过墙角
+func pathCost(t *testing.T, g *testGrid, start Point, path []Point) int {
+	t.Helper()
+	cost := 0
+	prev := start
+	for _, p := range path {
+		dx, dy := p.X-prev.X, p.Y-prev.Y
+		if abs(dx) > 1 || abs(dy) > 1 || (dx == 0 && dy == 0) {
+			t.Fatalf("路径不连续: %v -> %v\n%s", prev, p, drawPath(g, path))
+		}
+		if g.Blocked(p) {
+			t.Fatalf("路径穿过墙 %v\n%s", p, drawPath(g, path))
+		}
+		if dx != 0 && dy != 0 {
+			if g.Blocked(Point{prev.X + dx, prev.Y}) || g.Blocked(Point{prev.X, prev.Y + dy}) {
+				t.Fatalf("路径穿过墙角 %v -> %v\n%s", prev, p, drawPath(g, path))
+			}
+			cost += costDiagonal
+		} else {
+			cost += costStraight
+		}
+		prev = p
+	}
+	return cost
+}
+
+func TestFindPathAroundWall(t *testing.T) {
+	g := parseGrid(
+		".......",
+		".#####.",
+		".....#.",
+		"####.#.",
+		".......",
+	)
+	start, goal := Point{0, 2}, Point{6, 2}
+	path, ok := FindPath(g, start, goal, 0)
+	if !ok {
+		t.Fatal("应找到路径")
+	}
+	if path[len(path)-1] != goal {
+		t.Fatalf("路径终点 = %v; want %v", path[len(path)-1], goal)
+	}
+	// 墙角不能斜穿，无论从上方还是下方绕过都要直走十步
+	if cost := pathCost(t, g, start, path); cost != 10*costStraight {
+		t.Errorf("路径代价 = %d; want %d\n%s", cost, 10*costStraight, drawPath(g, path))
+	}
+}
+
+func TestFindPathNoCornerCutting(t *testing.T) {
+	g := parseGrid(
+		"..",
+		"#.",
+	)
+	// 从 (0,0) 到 (1,1) 斜走会擦过 (0,1) 的墙角，必须先走到 (1,0)
+	path, ok := FindPath(g, Point{0, 0}, Point{1, 1}, 0)
+	if !ok || len(path) != 2 || path[0] != (Point{1, 0}) {
+		t.Fatalf("路径 = %v, %v; want [{1 0} {1 1}]", path, ok)
+	}
+}
+
+func TestFindPathUnreachable(t *testing.T) {
+	g := parseGrid(
+		"..#..",
+		"..#..",
+		"..#..",
+	)
+	if _, ok := FindPath(g, Point{0, 0}, Point{4, 0}, 0); ok {
+		t.Error("被墙隔开时不应找到路径")
+	}
+	if _, ok := FindPath(g, Point{0, 0}, Point{2, 0}, 0); ok {
+		t.Error("终点是墙时不应找到路径")
+	}
+	if _, ok := FindPath(g, Point{0, 0}, Point{9, 0}, 0); ok {
+		t.Error("终点越界时不应找到路径")
+	}
+	if path, ok := FindPath(g, Point{1, 1}, Point{1, 1}, 0); !ok || len(path) != 0 {
+		t.Errorf("起点即终点时应返回空路径: %v, %v", path, ok)
+	}
+}
+
+func TestFindPathMaxExpanded(t *testing.T) {
+	g := parseGrid(
+		"..........",
+		"..........",
+	)
+	if _, ok := FindPath(g, Point{0, 0}, Point{9, 1}, 3); ok {
+		t.Error("超过展开上限时不应找到路径")
+	}
+	if _, ok := FindPath(g, Point{0, 0}, Point{9, 1}, 100); !ok {
+		t.Error("展开上限足够时应找到路径")
+	}
+}
+
+// TestSearcherReuse 重复使用同一个搜索器时结果应与新建的一致
+func TestSearcherReuse(t *testing.T) {
+	g := randomGrid(32, 32, 0.25, 1)
+	var s Searcher
+	rng := rand.New(rand.NewPCG(2, 2))
+	for range 200 {
+		start := Point{rng.IntN(32), rng.IntN(32)}
+		goal := Point{rng.IntN(32), rng.IntN(32)}
+		got, gotOK := s.FindPath(g, start, goal, 0)
+		want, wantOK := FindPath(g, start, goal, 0)
+		if gotOK != wantOK {
+			t.Fatalf("%v -> %v: ok = %v; want %v", start, goal, gotOK, wantOK)
+		}
+		if gotOK && pathCost(t, g, start, got) != pathCost(t, g, start, want) {
+			t.Fatalf("%v -> %v: 重复使用搜索器得到的路径代价不同", start, goal)
+		}
+	}
+}
+
+// randomGrid 生成随机障碍的网格，density 为墙的比例
+func randomGrid(w, h int, density float64, seed uint64) *testGrid {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	g := newTestGrid(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			g.setWall(Point{x, y}, rng.Float64() < density)
+		}
+	}
+	return g
+}
@@ -0,0 +1,71 @@
+package nav
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+// benchGrid 基准测试使用的网格：矩形房间，中间若干道带缺口的墙，以及随机障碍
+func benchGrid(w, h int) *testGrid {
+	g := randomGrid(w, h, 0.15, 7)
+	for x := 8; x < w; x += 8 {
+		gap := (x * 7) % h
+		for y := 0; y < h; y++ {
+			if abs(y-gap) > 1 {
+				g.setWall(Point{x, y}, true)
+			}
+		}
+	}
+	// 保证四角可以通行
+	for _, p := range []Point{{0, 0}, {w - 1, h - 1}, {0, h - 1}, {w - 1, 0}} {
+		g.setWall(p, false)
+	}
+	return g
+}
+
+func BenchmarkFindPath(b *testing.B) {
+	for _, size := range []int{25, 64, 128} {
+		g := benchGrid(size, size)
+		start, goal := Point{0, 0}, Point{size - 1, size - 1}
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			var s Searcher
+			b.ReportAllocs()
+			for b.Loop() {
+				s.FindPath(g, start, goal, 0)
+			}
+		})
+	}
+}
+
+// BenchmarkPlannerCrowd 模拟 50 个敌人每帧追踪同一个移动的目标
+func BenchmarkPlannerCrowd(b *testing.B) {
+	const size, enemies = 64, 50
+	g := benchGrid(size, size)
+	rng := rand.New(rand.NewPCG(3, 3))
+	starts := make([]Point, enemies)
+	for i := range starts {
+		for {
+			starts[i] = Point{rng.IntN(size), rng.IntN(size)}
+			if !g.Blocked(starts[i]) {
+				break
+			}
+		}
+	}
+	for _, budget := range []int{0, 4} {
+		b.Run("budget="+strconv.Itoa(budget), func(b *testing.B) {
+			p := NewPlanner(g, budget, 256)
+			frame := 0
+			b.ReportAllocs()
+			for b.Loop() {
+				// 目标每 30 帧移动一格
+				goal := Point{(frame / 30) % size, size - 1}
+				p.BeginTick()
+				for _, start := range starts {
+					p.Path(start, goal)
+				}
+				frame++
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package nav 网格寻路：A* 路径搜索、视线检测，以及带缓存和限流的路径规划器
+// 地图通过 Grid 接口提供尺寸和每个格子能否通行，敌人寻路和地下城楼层的连通性检查都使用它
+package nav
+
+// Point 格子坐标
+type Point struct {
+	X, Y int
+}
+
+// Grid 可寻路的网格
+type Grid interface {
+	// Size 返回网格的列数和行数
+	Size() (w, h int)
+	// Blocked 格子是否不可通行，越界的格子视为不可通行
+	Blocked(p Point) bool
+}
+
+// inBounds 格子是否在网格范围内
+func inBounds(g Grid, p Point) bool {
+	w, h := g.Size()
+	return p.X >= 0 && p.Y >= 0 && p.X < w && p.Y < h
+}
+
+// walkable 格子是否在范围内且可以通行
+func walkable(g Grid, p Point) bool {
+	return inBounds(g, p) && !g.Blocked(p)
+}
+
+// LineOfSight 两个格子之间的视线是否没有被阻挡（端点格子本身不检查）
+// 沿 Bresenham 直线逐格检查，斜向穿过两个阻挡格子之间的缝隙也视为被阻挡
+func LineOfSight(g Grid, a, b Point) bool {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	err := dx + dy
+	p := a
+	for p != b {
+		prev := p
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+		// 斜向一步：两侧的格子都被阻挡时视线穿不过去
+		if p.X != prev.X && p.Y != prev.Y &&
+			!walkable(g, Point{p.X, prev.Y}) && !walkable(g, Point{prev.X, p.Y}) {
+			return false
+		}
+		if p != b && !walkable(g, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package nav
+
+import (
+	"strings"
+	"testing"
+)
+
+// testGrid 测试用的网格，walls 按行存储
+type testGrid struct {
+	w, h  int
+	walls []bool
+}
+
+func newTestGrid(w, h int) *testGrid {
+	return &testGrid{w: w, h: h, walls: make([]bool, w*h)}
+}
+
+// parseGrid 用字符串描述网格，'#' 为墙
+func parseGrid(rows ...string) *testGrid {
+	g := newTestGrid(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, c := range row {
+			g.setWall(Point{x, y}, c == '#')
+		}
+	}
+	return g
+}
+
+func (g *testGrid) setWall(p Point, wall bool) { g.walls[p.Y*g.w+p.X] = wall }
+
+func (g *testGrid) Size() (int, int) { return g.w, g.h }
+
+func (g *testGrid) Blocked(p Point) bool {
+	return !inBounds(g, p) || g.walls[p.Y*g.w+p.X]
+}
+
+func TestLineOfSight(t *testing.T) {
+	g := parseGrid(
+		"......",
+		"..#...",
+		"......",
+		".#....",
+		"#.....",
+	)
+	tests := []struct {
+		a, b Point
+		want bool
+	}{
+		{Point{0, 0}, Point{5, 0}, true},
+		{Point{0, 1}, Point{5, 1}, false}, // 被 (2,1) 挡住
+		{Point{2, 0}, Point{2, 4}, false},
+		{Point{0, 0}, Point{0, 3}, true},
+		{Point{0, 3}, Point{1, 4}, false}, // 从 (0,4) 和 (1,3) 两堵墙之间的缝隙斜穿
+		{Point{3, 0}, Point{3, 4}, true},
+		{Point{2, 1}, Point{5, 1}, true}, // 端点格子本身不检查
+	}
+	for _, tt := range tests {
+		if got := LineOfSight(g, tt.a, tt.b); got != tt.want {
+			t.Errorf("LineOfSight(%v, %v) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := LineOfSight(g, tt.b, tt.a); got != tt.want {
+			t.Errorf("LineOfSight(%v, %v) = %v; want %v（视线应对称）", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+// drawPath 把路径画到网格上，便于在失败时查看
+func drawPath(g *testGrid, path []Point) string {
+	on := map[Point]bool{}
+	for _, p := range path {
+		on[p] = true
+	}
+	var b strings.Builder
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			switch p := (Point{x, y}); {
+			case g.Blocked(p):
+				b.WriteByte('#')
+			case on[p]:
+				b.WriteByte('*')
+			default:
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
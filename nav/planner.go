@@ -0,0 +1,100 @@
+package nav
+
+// Status 路径请求的结果
+type Status int
+
+const (
+	Found     Status = iota // 找到路径
+	NoPath                  // 没有可达路径
+	Throttled               // 本帧的计算次数已用完，下一帧再请求
+	TooFar                  // 展开的格子数达到 MaxExpanded，目标可能可达但太远；这个结果不缓存
+)
+
+// PlannerStats 路径规划器的统计数据
+type PlannerStats struct {
+	Hits      int // 命中缓存的次数
+	Searches  int // 实际进行 A* 搜索的次数
+	Throttled int // 因限流被推迟的次数
+}
+
+// Planner 带缓存和限流的路径规划器
+// 相同起点和终点的结果（TooFar 除外）会被缓存；每帧最多进行 Budget 次搜索，超出的请求返回 Throttled
+// 这样几十个敌人同时寻路时，计算量会被分摊到多帧
+type Planner struct {
+	Grid        Grid
+	Budget      int // 每帧最多搜索的次数，0 表示不限制
+	CacheSize   int // 最多缓存的路径数，0 表示不缓存
+	MaxExpanded int // 单次搜索最多展开的格子数，0 表示不限制
+	Stats       PlannerStats
+
+	searcher Searcher
+	used     int
+	cache    map[[2]Point]cachedPath
+	order    [][2]Point // 按加入顺序记录的缓存键，满了先淘汰最早的
+}
+
+// cachedPath 缓存的搜索结果
+type cachedPath struct {
+	path  []Point
+	found bool
+}
+
+// NewPlanner 创建路径规划器
+func NewPlanner(grid Grid, budget, cacheSize int) *Planner {
+	return &Planner{Grid: grid, Budget: budget, CacheSize: cacheSize}
+}
+
+// BeginTick 每帧开始时调用，重置本帧的搜索次数
+func (p *Planner) BeginTick() {
+	p.used = 0
+}
+
+// Invalidate 地图变化后清空缓存
+func (p *Planner) Invalidate() {
+	p.cache = nil
+	p.order = p.order[:0]
+}
+
+// Path 请求从 start 到 goal 的路径，返回的路径可能与其他请求共享，调用方不能修改
+func (p *Planner) Path(start, goal Point) ([]Point, Status) {
+	key := [2]Point{start, goal}
+	if cached, ok := p.cache[key]; ok {
+		p.Stats.Hits++
+		return cached.path, statusOf(cached.found)
+	}
+	if p.Budget > 0 && p.used >= p.Budget {
+		p.Stats.Throttled++
+		return nil, Throttled
+	}
+	p.used++
+	p.Stats.Searches++
+	path, found := p.searcher.FindPath(p.Grid, start, goal, p.MaxExpanded)
+	if p.searcher.cutoff {
+		return nil, TooFar
+	}
+	p.store(key, cachedPath{path: path, found: found})
+	return path, statusOf(found)
+}
+
+// store 加入缓存，超出容量时淘汰最早的记录
+func (p *Planner) store(key [2]Point, value cachedPath) {
+	if p.CacheSize <= 0 {
+		return
+	}
+	if p.cache == nil {
+		p.cache = make(map[[2]Point]cachedPath, p.CacheSize)
+	}
+	if len(p.order) >= p.CacheSize {
+		delete(p.cache, p.order[0])
+		p.order = p.order[1:]
+	}
+	p.cache[key] = value
+	p.order = append(p.order, key)
+}
+
+func statusOf(found bool) Status {
+	if found {
+		return Found
+	}
+	return NoPath
+}
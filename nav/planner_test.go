@@ -0,0 +1,98 @@
+package nav
+
+import (
+	"testing"
+)
+
+func TestPlannerCache(t *testing.T) {
+	g := parseGrid(
+		".....",
+		".###.",
+		".....",
+	)
+	p := NewPlanner(g, 0, 8)
+	first, status := p.Path(Point{0, 1}, Point{4, 1})
+	if status != Found {
+		t.Fatalf("status = %v; want Found", status)
+	}
+	second, status := p.Path(Point{0, 1}, Point{4, 1})
+	if status != Found || len(second) != len(first) {
+		t.Fatalf("缓存的结果不一致: %v %v", second, status)
+	}
+	if p.Stats.Searches != 1 || p.Stats.Hits != 1 {
+		t.Errorf("stats = %+v; want 1 次搜索 1 次命中", p.Stats)
+	}
+
+	// 没有路径的结果同样缓存
+	p.Path(Point{0, 0}, Point{2, 1})
+	if _, status := p.Path(Point{0, 0}, Point{2, 1}); status != NoPath || p.Stats.Searches != 2 {
+		t.Errorf("status = %v, stats = %+v; want NoPath 且只搜索一次", status, p.Stats)
+	}
+
+	p.Invalidate()
+	p.Path(Point{0, 1}, Point{4, 1})
+	if p.Stats.Searches != 3 {
+		t.Errorf("清空缓存后应重新搜索: %+v", p.Stats)
+	}
+}
+
+// TestPlannerTooFar 因展开上限停止的搜索不当作没有路径，也不缓存
+func TestPlannerTooFar(t *testing.T) {
+	g := parseGrid(
+		"..........",
+		"..........",
+	)
+	p := NewPlanner(g, 0, 8)
+	p.MaxExpanded = 3
+	for range 2 {
+		if _, status := p.Path(Point{0, 0}, Point{9, 1}); status != TooFar {
+			t.Fatalf("status = %v; want TooFar", status)
+		}
+	}
+	if p.Stats.Searches != 2 || p.Stats.Hits != 0 {
+		t.Errorf("stats = %+v; want 2 次搜索 0 次命中", p.Stats)
+	}
+
+	p.MaxExpanded = 100
+	if _, status := p.Path(Point{0, 0}, Point{9, 1}); status != Found {
+		t.Errorf("展开上限足够时 status = %v; want Found", status)
+	}
+}
+
+func TestPlannerCacheEviction(t *testing.T) {
+	g := parseGrid("......")
+	p := NewPlanner(g, 0, 2)
+	p.Path(Point{0, 0}, Point{1, 0})
+	p.Path(Point{0, 0}, Point{2, 0})
+	p.Path(Point{0, 0}, Point{3, 0}) // 淘汰最早的 {0,0}->{1,0}
+	p.Path(Point{0, 0}, Point{1, 0})
+	if p.Stats.Searches != 4 || p.Stats.Hits != 0 {
+		t.Errorf("stats = %+v; want 4 次搜索 0 次命中", p.Stats)
+	}
+	p.Path(Point{0, 0}, Point{3, 0})
+	if p.Stats.Hits != 1 {
+		t.Errorf("最近的记录应仍在缓存中: %+v", p.Stats)
+	}
+}
+
+func TestPlannerBudget(t *testing.T) {
+	g := parseGrid("......")
+	p := NewPlanner(g, 2, 16)
+	p.BeginTick()
+	p.Path(Point{0, 0}, Point{1, 0})
+	p.Path(Point{0, 0}, Point{2, 0})
+	if _, status := p.Path(Point{0, 0}, Point{3, 0}); status != Throttled {
+		t.Fatalf("超出每帧次数时 status = %v; want Throttled", status)
+	}
+	// 命中缓存不消耗次数
+	if _, status := p.Path(Point{0, 0}, Point{1, 0}); status != Found {
+		t.Fatalf("缓存命中不应被限流: %v", status)
+	}
+	p.BeginTick()
+	if _, status := p.Path(Point{0, 0}, Point{3, 0}); status != Found {
+		t.Fatalf("新的一帧应可以继续搜索: %v", status)
+	}
+	if p.Stats.Throttled != 1 {
+		t.Errorf("stats = %+v; want 1 次限流", p.Stats)
+	}
+}
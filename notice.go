@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"time"
+)
+
+// noticeDuration 屏幕提示的显示时长
+const noticeDuration = 2 * time.Second
+
+// notice 屏幕上方的临时提示（如 "背包已满"）
+type notice struct {
+	text      string
+	remaining time.Duration
+}
+
+// Notices 按时间先后显示的临时提示列表
+type Notices struct {
+	list []notice
+}
+
+// Show 添加一条提示；与最新一条相同时只重置显示时间
+func (n *Notices) Show(text string) {
+	if len(n.list) > 0 && n.list[len(n.list)-1].text == text {
+		n.list[len(n.list)-1].remaining = noticeDuration
+		return
+	}
+	n.list = append(n.list, notice{text: text, remaining: noticeDuration})
+	if len(n.list) > 4 {
+		n.list = n.list[1:]
+	}
+}
+
+// Update 推进显示时间，移除过期的提示
+func (n *Notices) Update(dt time.Duration) {
+	remaining := n.list[:0]
+	for _, item := range n.list {
+		item.remaining -= dt
+		if item.remaining > 0 {
+			remaining = append(remaining, item)
+		}
+	}
+	n.list = remaining
+}
+
+// Draw 在屏幕上方居中绘制提示，最后半秒逐渐淡出
+func (n *Notices) Draw(screen *ebiten.Image) {
+	for i, item := range n.list {
+		alpha := 1.0
+		if item.remaining < 500*time.Millisecond {
+			alpha = item.remaining.Seconds() / 0.5
+		}
+		width := len(item.text)*6 + 16
+		x := (screen.Bounds().Dx() - width) / 2
+		y := 40 + i*22
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), 18, color.RGBA{A: uint8(180 * alpha)}, false)
+		ebitenutil.DebugPrintAt(screen, item.text, x+8, y+1)
+	}
+}
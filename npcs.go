@@ -0,0 +1,65 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/quest"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NPC 可以对话的非玩家角色组件
+type NPC struct {
+	Name     string // 名字
+	Dialogue string // 对话树 id
+}
+
+// npcSize NPC 的绘制和碰撞尺寸（像素）
+const npcSize = 32
+
+// spawnNPC 在网格坐标 (col, row) 的格子中放置 NPC，精灵使用头像图片
+func (p *PlayScreen) spawnNPC(name, dialogueID, portrait string, col, row int) ecs.Entity {
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: float64(col * p.gridSize), Y: float64(row * p.gridSize)})
+	ecs.Add(p.world, e, ecs.Sprite{Image: p.portrait(portrait), Width: npcSize, Height: npcSize, Layer: layerCharacters})
+	ecs.Add(p.world, e, ecs.Collider{W: npcSize, H: npcSize, Solid: true})
+	ecs.Add(p.world, e, NPC{Name: name, Dialogue: dialogueID})
+	return e
+}
+
+// portrait 返回头像图片（photos/portraits/<name>.png），加载过的会被缓存
+func (p *PlayScreen) portrait(name string) *ebiten.Image {
+	if img, ok := p.portraits[name]; ok {
+		return img
+	}
+	img := loadImage("photos/portraits/" + name + ".png")
+	p.portraits[name] = img
+	return img
+}
+
+// npcInFront 查找玩家面前格子里的 NPC
+func (p *PlayScreen) npcInFront() (ecs.Entity, bool) {
+	col, row := p.frontTile()
+	front := ecs.Rect{X: float64(col * p.gridSize), Y: float64(row * p.gridSize), W: float64(p.gridSize), H: float64(p.gridSize)}
+	for _, e := range p.world.Query(ecs.MaskOf[NPC](p.world) | ecs.MaskOf[ecs.Position](p.world) | ecs.MaskOf[ecs.Collider](p.world)) {
+		box := ecs.Get[ecs.Collider](p.world, e).Bounds(ecs.Get[ecs.Position](p.world, e))
+		if box.Overlaps(front) {
+			return e, true
+		}
+	}
+	return 0, false
+}
+
+// talkToNPCInFront 与面前的 NPC 开始对话，没有 NPC 时返回 false
+func (p *PlayScreen) talkToNPCInFront() bool {
+	e, ok := p.npcInFront()
+	if !ok {
+		return false
+	}
+	npc := ecs.Get[NPC](p.world, e)
+	tree, ok := p.dialogues[npc.Dialogue]
+	if !ok {
+		return false
+	}
+	p.questEvent(quest.Event{Type: quest.EventTalk, NPC: npc.Name})
+	p.startDialogue(tree)
+	return true
+}
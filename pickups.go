@@ -0,0 +1,256 @@
+package main
+
+import (
+	"Game/anim"
+	"Game/ecs"
+	"Game/inventory"
+	"Game/nav"
+	"Game/wallet"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"maps"
+	"math"
+	"slices"
+)
+
+// pickupSize 地上物品的绘制和碰撞尺寸（像素）
+const pickupSize = 24
+
+// Pickup 地上可拾取的物品组件
+type Pickup struct {
+	ItemID int64   // 物品 id
+	Count  int64   // 数量
+	phase  float64 // 上下浮动的相位（秒）
+	locked bool    // 背包已满时玩家仍站在上面，离开前不再自动拾取
+}
+
+// spawnPickup 在网格坐标 (col, row) 的格子中放置物品
+func (p *PlayScreen) spawnPickup(itemID, count int64, col, row int) ecs.Entity {
+	data := ItemImages[itemID]
+	e := p.world.Spawn()
+	offset := float64(p.gridSize-pickupSize) / 2
+	ecs.Add(p.world, e, ecs.Position{X: float64(col*p.gridSize) + offset, Y: float64(row*p.gridSize) + offset})
+	ecs.Add(p.world, e, ecs.Sprite{Image: data.Image, Width: pickupSize, Height: pickupSize, Layer: layerItems})
+	ecs.Add(p.world, e, ecs.Collider{W: pickupSize, H: pickupSize})
+	ecs.Add(p.world, e, Pickup{ItemID: itemID, Count: count, phase: float64(col+row) * 0.7})
+	return e
+}
+
+// updatePickups 更新地上物品的浮动效果，并处理自动拾取和按键拾取
+func (p *PlayScreen) updatePickups() {
+	dt := tickDuration().Seconds()
+	ecs.Each2(p.world, func(e ecs.Entity, pickup *Pickup, sprite *ecs.Sprite) {
+		pickup.phase += dt
+		sprite.OffsetY = math.Sin(pickup.phase*3) * 3
+	})
+
+	// 走到物品上自动拾取
+	touching := map[ecs.Entity]bool{}
+	for _, e := range ecs.Touching(p.world, p.player) {
+		if ecs.Has[Pickup](p.world, e) {
+			touching[e] = true
+			p.tryPickup(e)
+		}
+	}
+	// 离开后解除锁定，下次走上去再尝试拾取
+	ecs.Each(p.world, func(e ecs.Entity, pickup *Pickup) {
+		if !touching[e] {
+			pickup.locked = false
+		}
+	})
+
+	// 按交互键与面前的 NPC 对话、使用面前的合成台、打开宝箱或储物箱、穿过门，都没有时拾取面前格子里的物品
+	if p.settings.isActionJustPressed(ActionInteract) {
+		chest, hasChest := p.chestInFront()
+		storage, hasStorage := p.storageInFront()
+		door, hasDoor := p.doorInFront()
+		switch {
+		case p.talkToNPCInFront():
+		case p.stationInFront():
+			p.setCraftingOpen(true)
+		case hasChest:
+			p.openChest(chest)
+		case hasStorage:
+			p.openContainer(storage)
+		case hasDoor:
+			p.openDoor(door)
+		default:
+			if e, ok := p.pickupInFront(); ok {
+				ecs.Get[Pickup](p.world, e).locked = false
+				p.tryPickup(e)
+			}
+		}
+	}
+}
+
+// tryPickup 尝试把地上物品放进背包，背包已满时给出提示
+func (p *PlayScreen) tryPickup(e ecs.Entity) {
+	pickup := ecs.Get[Pickup](p.world, e)
+	if pickup.locked {
+		return
+	}
+	defer p.walletTx("pickup")()
+	if !p.addItem(pickup.ItemID, pickup.Count) {
+		pickup.locked = true
+		p.notices.Show(p.settings.T(fullNoticeKey(pickup.ItemID)))
+		return
+	}
+	p.notices.Show(fmt.Sprintf(p.settings.T("notice.pickedUp"), ItemImages[pickup.ItemID].Name, pickup.Count))
+	p.sound.PlaySFX(sfxPickup)
+	p.world.Despawn(e)
+}
+
+// frontTile 返回玩家面前的格子坐标（限制在地图范围内）
+func (p *PlayScreen) frontTile() (int, int) {
+	cx, cy := p.playerCenter()
+	col := int(cx) / p.gridSize
+	row := int(cy) / p.gridSize
+	switch p.playerAnim.Facing() {
+	case anim.DirUp:
+		row--
+	case anim.DirDown:
+		row++
+	case anim.DirLeft:
+		col--
+	case anim.DirRight:
+		col++
+	}
+	col = min(max(col, 0), len(p.gridData[0])-1)
+	row = min(max(row, 0), len(p.gridData)-1)
+	// 面前是墙时使用玩家所在的格子
+	if tileGrid(p.gridData).Blocked(nav.Point{X: col, Y: row}) {
+		return int(cx) / p.gridSize, int(cy) / p.gridSize
+	}
+	return col, row
+}
+
+// pickupInFront 查找玩家面前格子或脚下的物品
+func (p *PlayScreen) pickupInFront() (ecs.Entity, bool) {
+	col, row := p.frontTile()
+	front := ecs.Rect{X: float64(col * p.gridSize), Y: float64(row * p.gridSize), W: float64(p.gridSize), H: float64(p.gridSize)}
+	playerBox := ecs.Get[ecs.Collider](p.world, p.player).Bounds(p.playerPos())
+	for _, e := range p.world.Query(ecs.MaskOf[Pickup](p.world) | ecs.MaskOf[ecs.Position](p.world)) {
+		box := ecs.Get[ecs.Collider](p.world, e).Bounds(ecs.Get[ecs.Position](p.world, e))
+		if box.Overlaps(front) || box.Overlaps(playerBox) {
+			return e, true
+		}
+	}
+	return 0, false
+}
+
+// dropSelectedItem 把背包中选中的整组物品丢到玩家面前的格子上
+func (p *PlayScreen) dropSelectedItem() {
+	if p.bagUI.selected < 0 || p.bagUI.selected >= len(p.bag().Items) {
+		return
+	}
+	it := p.bag().Items[p.bagUI.selected]
+	col, row := p.frontTile()
+	e := p.spawnPickup(it.ItemData.id, it.count, col, row)
+	// 丢下的物品就在玩家面前，离开之前不自动捡回
+	ecs.Get[Pickup](p.world, e).locked = true
+
+	p.bag().Items = append(p.bag().Items[:p.bagUI.selected], p.bag().Items[p.bagUI.selected+1:]...)
+	if p.bagUI.selected >= len(p.bag().Items) && p.bagUI.selected > 0 {
+		p.bagUI.selected--
+	}
+	p.inventoryChanged()
+}
+
+// addItem 把物品放进背包：优先叠加到同种物品上，否则占用新格子；背包已满时返回 false
+// 货币放进钱包，不占格子
+func (p *PlayScreen) addItem(itemID, count int64) bool {
+	if isCurrency(itemID) {
+		return p.addCurrency(itemID, count)
+	}
+	return p.putItem(&item{id: itemID, count: count, ItemData: ItemImages[itemID]})
+}
+
+// putItem 把一组物品放进背包，新占用格子时保留这组物品的使用次数；背包已满或叠加后数量溢出时返回 false
+func (p *PlayScreen) putItem(it *item) bool {
+	if existing := p.stackOf(it.ItemData.id); existing != nil {
+		if existing.count > math.MaxInt64-it.count {
+			return false
+		}
+		existing.count += it.count
+		existing.acquired = p.nextAcquired()
+		p.inventoryChanged()
+		return true
+	}
+	if len(p.bag().Items) >= p.bag().Size {
+		return false
+	}
+	it.acquired = p.nextAcquired()
+	p.bag().Items = append(p.bag().Items, it)
+	p.inventoryChanged()
+	return true
+}
+
+// Exchange 原子地从背包取走 remove 并放入 add（实现 inventory.Inventory）
+// 货币的变化交给钱包，其余物品先按背包规则（同种物品叠加在一个格子里）检查一遍，
+// 确认数量足够、放得下且钱包不会溢出之后才真正修改；修改完成后才通知任务，
+// 任务完成时发放或收走的物品不会占用这次交易预留的格子
+func (p *PlayScreen) Exchange(remove, add []inventory.Stack) error {
+	coins := map[int64]int64{}
+	remove = splitCurrency(remove, -1, coins)
+	add = splitCurrency(add, 1, coins)
+	counts := map[int64]int64{}
+	for _, it := range p.bag().Items {
+		counts[it.ItemData.id] += it.count
+	}
+	next, err := inventory.Check(counts, p.bag().Size, remove, add)
+	if err != nil {
+		return err
+	}
+	if err := p.purse().Wallet.Check(coins); errors.Is(err, wallet.ErrInsufficient) {
+		return inventory.ErrMissing
+	} else if err != nil {
+		return err
+	}
+	p.purse().Wallet.Apply(coins, p.walletTxReason())
+	p.setBagCounts(counts, next)
+	p.inventoryChanged()
+	return nil
+}
+
+// setBagCounts 把背包中每种物品的数量从 from 改为 to（inventory.Check 的结果），不通知任务：
+// 减少的从前面的物品组扣除，扣完的物品组移出背包；增加的叠加到已有的物品组，没有时占用新格子
+func (p *PlayScreen) setBagCounts(from, to map[int64]int64) {
+	bag := p.bag()
+	for _, it := range bag.Items {
+		id := it.ItemData.id
+		n := min(it.count, from[id]-to[id])
+		if n > 0 {
+			it.count -= n
+			from[id] -= n
+		}
+	}
+	bag.Items = slices.DeleteFunc(bag.Items, func(it *item) bool { return it.count == 0 })
+	if p.bagUI.selected >= len(bag.Items) && p.bagUI.selected > 0 {
+		p.bagUI.selected = len(bag.Items) - 1
+	}
+	for _, id := range slices.Sorted(maps.Keys(to)) {
+		n := to[id] - from[id]
+		if n <= 0 {
+			continue
+		}
+		if existing := p.stackOf(id); existing != nil {
+			existing.count += n
+			existing.acquired = p.nextAcquired()
+		} else {
+			bag.Items = append(bag.Items, &item{id: id, count: n, acquired: p.nextAcquired(), ItemData: ItemImages[id]})
+		}
+	}
+}
+
+// drawPickupGlow 在地上物品下方绘制呼吸光晕
+func (p *PlayScreen) drawPickupGlow(screen *ebiten.Image) {
+	ecs.Each2(p.world, func(e ecs.Entity, pickup *Pickup, pos *ecs.Position) {
+		alpha := 60 + 40*math.Sin(pickup.phase*4)
+		cx := float32(pos.X + pickupSize/2)
+		cy := float32(pos.Y + pickupSize - 2)
+		vector.DrawFilledCircle(screen, cx, cy, pickupSize/2+2, color.RGBA{R: 255, G: 220, B: 100, A: uint8(alpha)}, true)
+	})
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/vitals"
+	"encoding/json"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"os"
+	"time"
+)
+
+// playerConfigPath 玩家数值配置文件
+const playerConfigPath = "data/player.json"
+
+// VitalConfig 一种数值的上限和恢复规则
+type VitalConfig struct {
+	Max   int     `json:"max"`
+	Regen float64 `json:"regen"` // 每秒恢复
+	Delay float64 `json:"delay"` // 减少后多少秒开始恢复
+}
+
+// regen 返回恢复规则
+func (c VitalConfig) regen() vitals.Regen {
+	return vitals.Regen{PerSecond: c.Regen, Delay: time.Duration(c.Delay * float64(time.Second))}
+}
+
+// PlayerConfig 玩家的生命值、法力值、体力、冲刺、攻击消耗和死亡惩罚
+type PlayerConfig struct {
+	Health  VitalConfig `json:"health"`
+	Mana    VitalConfig `json:"mana"`
+	Stamina VitalConfig `json:"stamina"`
+	Sprint  struct {
+		Multiplier float64 `json:"multiplier"` // 冲刺时的速度倍率
+		Cost       float64 `json:"cost"`       // 冲刺时每秒消耗的体力
+	} `json:"sprint"`
+	AttackCost   int `json:"attackCost"` // 每次攻击消耗的体力
+	DeathPenalty struct {
+		Currency int64   `json:"currency"` // 死亡时损失的货币
+		Percent  float64 `json:"percent"`  // 损失余额的比例 0~1
+		Max      int64   `json:"max"`      // 最多损失多少，0 表示不限
+	} `json:"deathPenalty"`
+}
+
+// playerConfig 玩家数值配置
+var playerConfig = loadPlayerConfig(playerConfigPath)
+
+// loadPlayerConfig 加载玩家数值配置，数据有误时直接退出
+func loadPlayerConfig(path string) *PlayerConfig {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("加载玩家数值配置失败 %s: %v", path, err)
+	}
+	var c PlayerConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		log.Fatalf("解析玩家数值配置失败 %s: %v", path, err)
+	}
+	switch {
+	case c.Health.Max <= 0 || c.Mana.Max < 0 || c.Stamina.Max < 0:
+		log.Fatalf("玩家数值配置有误 %s: health.max 必须大于 0，mana.max 和 stamina.max 不能为负数", path)
+	case c.Sprint.Multiplier < 1 || c.Sprint.Cost < 0 || c.AttackCost < 0:
+		log.Fatalf("玩家数值配置有误 %s: sprint.multiplier 不能小于 1，消耗不能为负数", path)
+	case c.DeathPenalty.Percent < 0 || c.DeathPenalty.Percent > 1 || c.DeathPenalty.Max < 0:
+		log.Fatalf("玩家数值配置有误 %s: deathPenalty.percent 必须在 0~1 之间，max 不能为负数", path)
+	}
+	return &c
+}
+
+// VitalStat 玩家的一种数值
+type VitalStat string
+
+const (
+	VitalHealth  VitalStat = "health"  // 生命值
+	VitalMana    VitalStat = "mana"    // 法力值
+	VitalStamina VitalStat = "stamina" // 体力
+)
+
+// vitalStats HUD 中数值条的顺序
+var vitalStats = []VitalStat{VitalHealth, VitalMana, VitalStamina}
+
+// Vitals 玩家的法力值、体力和生命恢复规则组件（生命值本身在 ecs.Health 中，与敌人共用战斗逻辑）
+type Vitals struct {
+	HealthRegen vitals.Regen
+	Mana        vitals.Pool
+	Stamina     vitals.Pool
+	Sprinting   bool    // 是否正在冲刺
+	SprintCost  float64 // 冲刺消耗中不足 1 点的体力
+}
+
+// newVitals 按配置创建已满的玩家数值
+func newVitals(c *PlayerConfig) Vitals {
+	return Vitals{
+		HealthRegen: c.Health.regen(),
+		Mana:        vitals.NewPool(c.Mana.Max, c.Mana.regen()),
+		Stamina:     vitals.NewPool(c.Stamina.Max, c.Stamina.regen()),
+	}
+}
+
+// pool 返回法力值或体力的数值池，生命值返回 nil
+func (v *Vitals) pool(stat VitalStat) *vitals.Pool {
+	switch stat {
+	case VitalMana:
+		return &v.Mana
+	case VitalStamina:
+		return &v.Stamina
+	}
+	return nil
+}
+
+// VitalEvent 玩家数值变化事件：Amount 为正数表示恢复，负数表示受到伤害或消耗
+type VitalEvent struct {
+	Stat   VitalStat
+	Amount int
+}
+
+// vitalFlashDuration 数值变化时数值条闪烁的时间
+const vitalFlashDuration = 300 * time.Millisecond
+
+// vitalFlash 数值条的闪烁
+type vitalFlash struct {
+	remaining time.Duration
+	heal      bool // 恢复时闪绿色，减少时闪白色
+}
+
+// 屏幕左上角的数值条
+const (
+	vitalBarX   = 10
+	vitalBarY   = 30
+	vitalBarW   = 160
+	vitalBarH   = 12
+	vitalBarGap = 4
+)
+
+// vitalColors 各数值条的颜色
+var vitalColors = map[VitalStat]color.RGBA{
+	VitalHealth:  {R: 210, G: 50, B: 50, A: 255},
+	VitalMana:    {R: 60, G: 110, B: 230, A: 255},
+	VitalStamina: {R: 200, G: 190, B: 60, A: 255},
+}
+
+// playerVitals 返回玩家的数值组件
+func (p *PlayScreen) playerVitals() *Vitals {
+	return ecs.Get[Vitals](p.world, p.player)
+}
+
+// vitalValue 返回玩家某种数值的当前值和上限
+func (p *PlayScreen) vitalValue(stat VitalStat) (int, int) {
+	if stat == VitalHealth {
+		health := ecs.Get[ecs.Health](p.world, p.player)
+		return health.Current, health.Max
+	}
+	pool := p.playerVitals().pool(stat)
+	return pool.Current, pool.Max
+}
+
+// vitalEvent 处理玩家数值的变化：受到伤害时暂停生命恢复，数值条闪烁，恢复生命时飘出绿色数字
+func (p *PlayScreen) vitalEvent(e VitalEvent) {
+	if e.Amount == 0 {
+		return
+	}
+	if e.Stat == VitalHealth && e.Amount < 0 {
+		p.playerVitals().HealthRegen.Interrupt()
+	}
+	p.vitalFlashes[e.Stat] = vitalFlash{remaining: vitalFlashDuration, heal: e.Amount > 0}
+	if e.Stat == VitalHealth && e.Amount > 0 {
+		x, y := p.playerCenter()
+		p.damageNumbers.Add(fmt.Sprintf("+%d", e.Amount), x, y, color.RGBA{R: 90, G: 230, B: 90, A: 255}, 1)
+	}
+}
+
+// restoreVital 恢复玩家的某种数值，返回实际恢复的量
+func (p *PlayScreen) restoreVital(stat VitalStat, amount int) int {
+	var restored int
+	if stat == VitalHealth {
+		health := ecs.Get[ecs.Health](p.world, p.player)
+		restored = min(amount, health.Max-health.Current)
+		health.Current += restored
+	} else {
+		restored = p.playerVitals().pool(stat).Restore(amount)
+	}
+	p.vitalEvent(VitalEvent{Stat: stat, Amount: restored})
+	return restored
+}
+
+// spendStamina 消耗体力，不够时给出提示并返回 false
+func (p *PlayScreen) spendStamina(amount int) bool {
+	if !p.playerVitals().Stamina.Spend(amount) {
+		p.notices.Show(p.settings.T("combat.tired"))
+		return false
+	}
+	p.vitalEvent(VitalEvent{Stat: VitalStamina, Amount: -amount})
+	return true
+}
+
+// updateSprint 按住冲刺键移动时消耗体力加速，体力耗尽后不能冲刺
+func (p *PlayScreen) updateSprint(dx, dy float64, dt time.Duration) {
+	stamina := &p.playerVitals().Stamina
+	p.playerVitals().Sprinting = p.settings.isActionPressed(ActionSprint) && (dx != 0 || dy != 0) && stamina.Current > 0
+	if !p.playerVitals().Sprinting {
+		p.playerVitals().SprintCost = 0
+		return
+	}
+	p.playerVitals().SprintCost += playerConfig.Sprint.Cost * dt.Seconds()
+	if n := int(p.playerVitals().SprintCost); n > 0 {
+		p.playerVitals().SprintCost -= float64(n)
+		p.vitalEvent(VitalEvent{Stat: VitalStamina, Amount: -stamina.Drain(n)})
+	}
+}
+
+// updateVitals 按恢复规则恢复玩家的生命值、法力值和体力，推进数值条的闪烁
+func (p *PlayScreen) updateVitals(dt time.Duration) {
+	v := p.playerVitals()
+	health := ecs.Get[ecs.Health](p.world, p.player)
+	health.Current = v.HealthRegen.Tick(dt, health.Current, health.Max)
+	v.Mana.Update(dt)
+	v.Stamina.Update(dt)
+	for stat, flash := range p.vitalFlashes {
+		if flash.remaining <= dt {
+			delete(p.vitalFlashes, stat)
+		} else {
+			flash.remaining -= dt
+			p.vitalFlashes[stat] = flash
+		}
+	}
+}
+
+// drawVitals 在屏幕左上角绘制生命值、法力值和体力条
+func (p *PlayScreen) drawVitals(screen *ebiten.Image) {
+	for i, stat := range vitalStats {
+		current, maxValue := p.vitalValue(stat)
+		if maxValue <= 0 {
+			continue
+		}
+		x, y := float32(vitalBarX), float32(vitalBarY+i*(vitalBarH+vitalBarGap))
+		vector.DrawFilledRect(screen, x, y, vitalBarW, vitalBarH, color.RGBA{R: 20, G: 20, B: 30, A: 200}, false)
+		vector.DrawFilledRect(screen, x, y, vitalBarW*float32(current)/float32(maxValue), vitalBarH, vitalColors[stat], false)
+		if flash, ok := p.vitalFlashes[stat]; ok {
+			alpha := uint8(160 * flash.remaining / vitalFlashDuration)
+			clr := color.RGBA{R: alpha, G: alpha, B: alpha, A: alpha}
+			if flash.heal {
+				clr = color.RGBA{G: alpha, A: alpha}
+			}
+			vector.DrawFilledRect(screen, x, y, vitalBarW, vitalBarH, clr, false)
+		}
+		vector.StrokeRect(screen, x, y, vitalBarW, vitalBarH, 1, color.RGBA{R: 200, G: 200, B: 220, A: 255}, false)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s %d/%d", p.settings.T("vital."+string(stat)), current, maxValue), vitalBarX+vitalBarW+6, int(y)-2)
+	}
+}
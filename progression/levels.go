@@ -0,0 +1,96 @@
+// Package progression 角色成长：经验值和等级、每级获得的属性点和技能点、技能树（主动技能和被动加成）与洗点费用
+// 经验曲线和技能树从数据文件加载，Validate 检查技能引用的属性、物品和前置技能；技能的具体效果由游戏根据数据执行
+package progression
+
+import (
+	"fmt"
+	"math"
+)
+
+// Curve 升级曲线：从 n 级升到 n+1 级需要 Base × Growth^(n-1) 点经验（向下取整）
+type Curve struct {
+	MaxLevel int     `json:"maxLevel"`
+	Base     int     `json:"base"`
+	Growth   float64 `json:"growth"`
+}
+
+// Next 返回从 level 级升到下一级需要的经验，已经满级时返回 0
+func (c Curve) Next(level int) int {
+	if level >= c.MaxLevel {
+		return 0
+	}
+	return int(float64(c.Base) * math.Pow(c.Growth, float64(level-1)))
+}
+
+// validate 检查升级曲线
+func (c Curve) validate() error {
+	switch {
+	case c.MaxLevel < 1:
+		return fmt.Errorf("maxLevel 必须大于 0")
+	case c.Base <= 0:
+		return fmt.Errorf("base 必须大于 0")
+	case c.Growth < 1:
+		return fmt.Errorf("growth 不能小于 1")
+	}
+	return nil
+}
+
+// Progress 当前等级和这一级已获得的经验
+type Progress struct {
+	Level int `json:"level"`
+	XP    int `json:"xp"`
+}
+
+// Start 新角色的进度
+func Start() Progress {
+	return Progress{Level: 1}
+}
+
+// Gain 获得经验，经验够时连续升级，返回升了几级；满级后不再累积经验
+func (p *Progress) Gain(c Curve, xp int) int {
+	if xp <= 0 {
+		return 0
+	}
+	levels := 0
+	p.XP += xp
+	for next := c.Next(p.Level); next > 0 && p.XP >= next; next = c.Next(p.Level) {
+		p.XP -= next
+		p.Level++
+		levels++
+	}
+	if p.Level >= c.MaxLevel {
+		p.XP = 0
+	}
+	return levels
+}
+
+// Clamp 修正读档得到的进度：等级在 1 到满级之间，经验不超过升级所需
+func (p *Progress) Clamp(c Curve) {
+	p.Level = min(max(p.Level, 1), c.MaxLevel)
+	if next := c.Next(p.Level); next > 0 {
+		p.XP = min(max(p.XP, 0), next-1)
+	} else {
+		p.XP = 0
+	}
+}
+
+// Fraction 返回这一级经验的进度 0~1，满级时为 1
+func (p Progress) Fraction(c Curve) float64 {
+	next := c.Next(p.Level)
+	if next == 0 {
+		return 1
+	}
+	return float64(p.XP) / float64(next)
+}
+
+// Respec 洗点费用：Base + PerLevel × (等级 - 1)
+type Respec struct {
+	Currency int64 `json:"currency"` // 支付的货币（物品 id）
+	Base     int64 `json:"base"`
+	PerLevel int64 `json:"perLevel"`
+}
+
+// Cost 返回 level 级时洗点需要的货币
+func (r Respec) Cost(level int) int64 {
+	return r.Base + r.PerLevel*int64(max(level-1, 0))
+}
@@ -0,0 +1,199 @@
+package progression
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testConfig = `{
+	"levels": {"maxLevel": 5, "base": 100, "growth": 2},
+	"statPoints": 2,
+	"skillPoints": 1,
+	"attributes": {"attack": 1, "crit": 0.01},
+	"respec": {"currency": 1001, "base": 50, "perLevel": 10},
+	"skills": [
+		{"id": "might", "name": "Might", "kind": "passive", "maxRank": 3, "stats": {"attack": 2}, "col": 0, "row": 0},
+		{"id": "slam", "name": "Slam", "kind": "active", "level": 3, "mana": 10, "cooldown": 5,
+		 "requires": [{"skill": "might", "rank": 2}], "use": [{"effect": "nova", "radius": 2}], "col": 0, "row": 1},
+		{"id": "guard", "name": "Guard", "kind": "passive", "cost": 2, "stats": {"defense": 3}, "col": 1, "row": 0}
+	]
+}`
+
+func mustConfig(t *testing.T, data string) *Config {
+	t.Helper()
+	c, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCurve(t *testing.T) {
+	c := Curve{MaxLevel: 4, Base: 100, Growth: 1.5}
+	for level, want := range map[int]int{1: 100, 2: 150, 3: 225, 4: 0, 9: 0} {
+		if got := c.Next(level); got != want {
+			t.Errorf("Next(%d) = %d，应为 %d", level, got, want)
+		}
+	}
+}
+
+func TestGain(t *testing.T) {
+	c := Curve{MaxLevel: 4, Base: 100, Growth: 1.5}
+	p := Start()
+	if n := p.Gain(c, 99); n != 0 || p.Level != 1 || p.XP != 99 {
+		t.Fatalf("经验不够时不应升级: %+v", p)
+	}
+	// 99 + 200 = 299：升到 2 级剩 199，再升到 3 级剩 49
+	if n := p.Gain(c, 200); n != 2 || p.Level != 3 || p.XP != 49 {
+		t.Fatalf("应连续升两级: %d, %+v", n, p)
+	}
+	if p.Fraction(c) != 49.0/225 {
+		t.Fatalf("经验进度 = %v", p.Fraction(c))
+	}
+	if n := p.Gain(c, 10000); n != 1 || p.Level != 4 || p.XP != 0 {
+		t.Fatalf("满级后不应累积经验: %+v", p)
+	}
+	if p.Gain(c, 50) != 0 || p.XP != 0 || p.Fraction(c) != 1 {
+		t.Fatalf("满级后获得经验不应有变化: %+v", p)
+	}
+
+	saved := Progress{Level: 2, XP: 999}
+	saved.Clamp(c)
+	if saved.XP != 149 {
+		t.Fatalf("读档时经验应小于升级所需，实际 %d", saved.XP)
+	}
+	saved = Progress{Level: 0, XP: -5}
+	saved.Clamp(c)
+	if saved != (Progress{Level: 1}) {
+		t.Fatalf("读档时等级至少为 1，实际 %+v", saved)
+	}
+}
+
+func TestLearn(t *testing.T) {
+	c := mustConfig(t, testConfig)
+	b := NewBuild()
+	if err := c.Learn(&b, "might", 1); !errors.Is(err, ErrPoints) {
+		t.Fatalf("1 级时没有技能点，应返回 ErrPoints，实际 %v", err)
+	}
+	if err := c.Learn(&b, "might", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Learn(&b, "slam", 3); !errors.Is(err, ErrRequires) {
+		t.Fatalf("前置技能等级不够时应返回 ErrRequires，实际 %v", err)
+	}
+	if err := c.Learn(&b, "might", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Learn(&b, "slam", 3); !errors.Is(err, ErrPoints) {
+		t.Fatalf("3 级时只有 2 个技能点，实际 %v", err)
+	}
+	if err := c.Learn(&b, "slam", 2); !errors.Is(err, ErrLevel) {
+		t.Fatalf("等级不够时应返回 ErrLevel，实际 %v", err)
+	}
+	if err := c.Learn(&b, "slam", 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Learn(&b, "slam", 5); !errors.Is(err, ErrMaxRank) {
+		t.Fatalf("主动技能只有 1 级，实际 %v", err)
+	}
+	if err := c.Learn(&b, "guard", 5); !errors.Is(err, ErrPoints) {
+		t.Fatalf("guard 每级需要 2 点，只剩 1 点，实际 %v", err)
+	}
+	if err := c.Learn(&b, "nope", 5); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("不存在的技能应返回 ErrUnknown，实际 %v", err)
+	}
+	if c.FreeSkillPoints(b, 4) != 0 {
+		t.Fatalf("剩余技能点 = %d", c.FreeSkillPoints(b, 4))
+	}
+}
+
+func TestAllocateAndBonus(t *testing.T) {
+	c := mustConfig(t, testConfig)
+	b := NewBuild()
+	if !b.Empty() {
+		t.Fatal("新的 Build 应为空")
+	}
+	for range 2 {
+		if err := c.Allocate(&b, "attack", 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Allocate(&b, "crit", 2); !errors.Is(err, ErrPoints) {
+		t.Fatalf("2 级时只有 2 个属性点，实际 %v", err)
+	}
+	if err := c.Allocate(&b, "speed", 3); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("不能加点的属性应返回 ErrUnknown，实际 %v", err)
+	}
+	b.Skills["might"] = 2
+	bonus := c.Bonus(b)
+	if bonus["attack"] != 2+4 {
+		t.Fatalf("攻击加成 = %v，应为属性点 2 + 被动 4", bonus["attack"])
+	}
+	if b.Empty() {
+		t.Fatal("分配后不应为空")
+	}
+	if cost := c.Respec.Cost(4); cost != 80 {
+		t.Fatalf("4 级洗点费用 = %d", cost)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	c := mustConfig(t, testConfig)
+	b := Build{Attributes: map[string]int{"attack": 1, "luck": 3}, Skills: map[string]int{"might": 9, "gone": 1}}
+	if c.Sanitize(&b, 5) {
+		t.Fatal("点数足够时不应重置")
+	}
+	if b.Skills["might"] != 3 || len(b.Skills) != 1 || len(b.Attributes) != 1 {
+		t.Fatalf("修正后的 Build = %+v", b)
+	}
+
+	b = Build{Skills: map[string]int{"slam": 1}}
+	if !c.Sanitize(&b, 5) || !b.Empty() {
+		t.Fatalf("前置技能不满足时应全部重置: %+v", b)
+	}
+	b = Build{Attributes: map[string]int{"attack": 5}}
+	if !c.Sanitize(&b, 2) || !b.Empty() {
+		t.Fatalf("点数超出时应全部重置: %+v", b)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := mustConfig(t, `{
+		"levels": {"maxLevel": 3, "base": 0, "growth": 1},
+		"attributes": {"luck": 1},
+		"respec": {"currency": 9},
+		"skills": [
+			{"id": "a", "name": "A", "kind": "active", "maxRank": 2, "requires": [{"skill": "d", "rank": 1}]},
+			{"id": "d", "name": "D", "kind": "passive", "stats": {"attack": 1}, "requires": [{"skill": "a", "rank": 1}], "col": 3},
+			{"id": "b", "name": "B", "kind": "passive", "stats": {"attack": 1}, "col": 1},
+			{"id": "b", "kind": "magic", "level": 9, "requires": [{"skill": "x", "rank": 1}], "col": 1},
+			{"id": "c", "name": "C", "kind": "passive", "stats": {"attack": 1}, "mana": 5, "requires": [{"skill": "b", "rank": 4}], "col": 2}
+		]
+	}`)
+	errs := Validate(c, Catalog{
+		Stat: func(name string) bool { return name == "attack" },
+		Item: func(id int64) bool { return id == 1001 },
+	})
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	all := strings.Join(msgs, "\n")
+	for _, want := range []string{"base", `属性 "luck"`, "货币 9", "只能有 1 级", "没有效果", "循环", "id 重复", "缺少 name", `"magic"`, "超过最高等级", `"x" 不存在`, "不能有效果", "等级 4 无效", "重叠"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("缺少错误 %q:\n%s", want, all)
+		}
+	}
+}
+
+// TestShippedSkills 校验游戏自带的成长数据
+func TestShippedSkills(t *testing.T) {
+	c, err := Load("../data/skills.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range Validate(c, Catalog{}) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,369 @@
+package progression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+)
+
+// 学习技能或分配属性点失败的原因
+var (
+	ErrUnknown  = errors.New("progression: 技能或属性不存在")
+	ErrMaxRank  = errors.New("progression: 技能已达到最高等级")
+	ErrLevel    = errors.New("progression: 角色等级不够")
+	ErrRequires = errors.New("progression: 前置技能等级不够")
+	ErrPoints   = errors.New("progression: 点数不够")
+)
+
+// Kind 技能类型
+type Kind string
+
+const (
+	Active  Kind = "active"  // 主动技能：消耗法力值施放，有冷却时间
+	Passive Kind = "passive" // 被动技能：学会后一直提高属性
+)
+
+// Requirement 学习技能前需要的前置技能等级
+type Requirement struct {
+	Skill string `json:"skill"`
+	Rank  int    `json:"rank"`
+}
+
+// Skill 技能树中的一个技能
+type Skill struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Kind        Kind               `json:"kind"`
+	MaxRank     int                `json:"maxRank"`  // 最高等级，省略时为 1
+	Cost        int                `json:"cost"`     // 每级消耗的技能点，省略时为 1
+	Level       int                `json:"level"`    // 需要的角色等级
+	Requires    []Requirement      `json:"requires"` // 前置技能
+	Col         int                `json:"col"`      // 在技能树界面中的位置
+	Row         int                `json:"row"`
+	Stats       map[string]float64 `json:"stats"`    // 被动技能每级提高的属性
+	Mana        int                `json:"mana"`     // 主动技能消耗的法力值
+	Cooldown    float64            `json:"cooldown"` // 主动技能的冷却时间（秒）
+	Effects     []json.RawMessage  `json:"use"`      // 主动技能的效果，格式与物品的使用效果相同
+}
+
+// Config 成长数据：升级曲线、每级获得的点数、属性点的加成、洗点费用和技能树
+type Config struct {
+	Levels      Curve              `json:"levels"`
+	StatPoints  int                `json:"statPoints"`  // 每升一级获得的属性点
+	SkillPoints int                `json:"skillPoints"` // 每升一级获得的技能点
+	Attributes  map[string]float64 `json:"attributes"`  // 每个属性点提高的属性
+	Respec      Respec             `json:"respec"`
+	Skills      []*Skill           `json:"skills"`
+
+	byID map[string]*Skill
+}
+
+// Load 读取成长数据文件
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse 解析成长数据，并补上省略的最高等级和技能点消耗
+func Parse(data []byte) (*Config, error) {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	c.byID = map[string]*Skill{}
+	for _, s := range c.Skills {
+		if s.MaxRank == 0 {
+			s.MaxRank = 1
+		}
+		if s.Cost == 0 {
+			s.Cost = 1
+		}
+		c.byID[s.ID] = s
+	}
+	return &c, nil
+}
+
+// Skill 返回技能，不存在时返回 nil
+func (c *Config) Skill(id string) *Skill {
+	return c.byID[id]
+}
+
+// Catalog 校验时用于检查引用是否存在，字段为空时不检查对应的引用
+type Catalog struct {
+	Stat func(name string) bool
+	Item func(id int64) bool
+}
+
+// Validate 检查成长数据，返回所有发现的问题（包括前置技能形成的循环）
+func Validate(c *Config, catalog Catalog) []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	if err := c.Levels.validate(); err != nil {
+		fail("levels: %v", err)
+	}
+	if c.StatPoints < 0 || c.SkillPoints < 0 {
+		fail("statPoints 和 skillPoints 不能为负数")
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Attributes)) {
+		if catalog.Stat != nil && !catalog.Stat(name) {
+			fail("attributes: 未知的属性 %q", name)
+		}
+		if c.Attributes[name] <= 0 {
+			fail("attributes: %s 的加成必须大于 0", name)
+		}
+	}
+	if c.Respec.Base < 0 || c.Respec.PerLevel < 0 {
+		fail("respec: 费用不能为负数")
+	}
+	if catalog.Item != nil && !catalog.Item(c.Respec.Currency) {
+		fail("respec: 货币 %d 不存在", c.Respec.Currency)
+	}
+
+	seen := map[string]bool{}
+	cells := map[[2]int]string{}
+	for _, s := range c.Skills {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("技能 %s: "+format, append([]any{s.ID}, args...)...))
+		}
+		if s.ID == "" {
+			fail("缺少 id")
+		}
+		if seen[s.ID] {
+			fail("id 重复")
+		}
+		seen[s.ID] = true
+		if s.Name == "" {
+			fail("缺少 name")
+		}
+		if s.MaxRank < 1 || s.Cost < 1 {
+			fail("maxRank 和 cost 必须大于 0")
+		}
+		if s.Level > c.Levels.MaxLevel {
+			fail("需要的等级 %d 超过最高等级 %d", s.Level, c.Levels.MaxLevel)
+		}
+		switch s.Kind {
+		case Active:
+			if s.MaxRank != 1 {
+				fail("主动技能只能有 1 级")
+			}
+			if len(s.Effects) == 0 {
+				fail("主动技能没有效果")
+			}
+			if s.Mana < 0 || s.Cooldown < 0 {
+				fail("mana 和 cooldown 不能为负数")
+			}
+		case Passive:
+			if len(s.Stats) == 0 {
+				fail("被动技能没有属性加成")
+			}
+			if len(s.Effects) > 0 || s.Mana != 0 || s.Cooldown != 0 {
+				fail("被动技能不能有效果、法力值消耗和冷却时间")
+			}
+			for _, name := range slices.Sorted(maps.Keys(s.Stats)) {
+				if catalog.Stat != nil && !catalog.Stat(name) {
+					fail("未知的属性 %q", name)
+				}
+			}
+		default:
+			fail("未知的技能类型 %q", s.Kind)
+		}
+		for _, r := range s.Requires {
+			req := c.byID[r.Skill]
+			switch {
+			case req == nil:
+				fail("前置技能 %q 不存在", r.Skill)
+			case req == s:
+				fail("不能以自己为前置技能")
+			case r.Rank < 1 || r.Rank > req.MaxRank:
+				fail("前置技能 %s 的等级 %d 无效", r.Skill, r.Rank)
+			}
+		}
+		cell := [2]int{s.Col, s.Row}
+		if other, ok := cells[cell]; ok {
+			fail("位置 (%d, %d) 和技能 %s 重叠", s.Col, s.Row, other)
+		}
+		cells[cell] = s.ID
+		if s.Col < 0 || s.Row < 0 {
+			fail("位置不能为负数")
+		}
+		if cycle := c.findCycle(s.ID, nil); cycle != nil {
+			fail("前置技能形成循环: %v", cycle)
+		}
+	}
+	return errs
+}
+
+// findCycle 从技能 id 出发沿前置技能查找回到路径上的循环
+func (c *Config) findCycle(id string, path []string) []string {
+	if i := slices.Index(path, id); i >= 0 {
+		return append(path[i:], id)
+	}
+	s := c.byID[id]
+	if s == nil {
+		return nil
+	}
+	path = append(path, id)
+	for _, r := range s.Requires {
+		if r.Skill == id {
+			continue
+		}
+		if cycle := c.findCycle(r.Skill, slices.Clone(path)); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Build 角色分配的属性点和已学会的技能等级
+type Build struct {
+	Attributes map[string]int `json:"attributes"`
+	Skills     map[string]int `json:"skills"`
+}
+
+// NewBuild 返回还没有分配任何点数的 Build
+func NewBuild() Build {
+	return Build{Attributes: map[string]int{}, Skills: map[string]int{}}
+}
+
+// Empty 是否还没有分配任何点数（洗点没有意义）
+func (b Build) Empty() bool {
+	for _, n := range b.Attributes {
+		if n > 0 {
+			return false
+		}
+	}
+	for _, n := range b.Skills {
+		if n > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// FreeStatPoints 返回 level 级时还没有分配的属性点
+func (c *Config) FreeStatPoints(b Build, level int) int {
+	spent := 0
+	for _, n := range b.Attributes {
+		spent += n
+	}
+	return c.StatPoints*(level-1) - spent
+}
+
+// FreeSkillPoints 返回 level 级时还没有使用的技能点
+func (c *Config) FreeSkillPoints(b Build, level int) int {
+	spent := 0
+	for id, rank := range b.Skills {
+		if s := c.byID[id]; s != nil {
+			spent += rank * s.Cost
+		}
+	}
+	return c.SkillPoints*(level-1) - spent
+}
+
+// Allocate 把一个属性点分配给属性 name
+func (c *Config) Allocate(b *Build, name string, level int) error {
+	if c.Attributes[name] <= 0 {
+		return ErrUnknown
+	}
+	if c.FreeStatPoints(*b, level) <= 0 {
+		return ErrPoints
+	}
+	b.Attributes[name]++
+	return nil
+}
+
+// CanLearn 检查能否把技能 id 提高一级，不修改 Build
+func (c *Config) CanLearn(b Build, id string, level int) error {
+	s := c.byID[id]
+	switch {
+	case s == nil:
+		return ErrUnknown
+	case b.Skills[id] >= s.MaxRank:
+		return ErrMaxRank
+	case level < s.Level:
+		return ErrLevel
+	}
+	for _, r := range s.Requires {
+		if b.Skills[r.Skill] < r.Rank {
+			return ErrRequires
+		}
+	}
+	if c.FreeSkillPoints(b, level) < s.Cost {
+		return ErrPoints
+	}
+	return nil
+}
+
+// Learn 把技能 id 提高一级
+func (c *Config) Learn(b *Build, id string, level int) error {
+	if err := c.CanLearn(*b, id, level); err != nil {
+		return err
+	}
+	b.Skills[id]++
+	return nil
+}
+
+// Bonus 返回属性点和被动技能提高的属性总和
+func (c *Config) Bonus(b Build) map[string]float64 {
+	bonus := map[string]float64{}
+	for name, n := range b.Attributes {
+		bonus[name] += c.Attributes[name] * float64(n)
+	}
+	for id, rank := range b.Skills {
+		if s := c.byID[id]; s != nil && s.Kind == Passive {
+			for name, v := range s.Stats {
+				bonus[name] += v * float64(rank)
+			}
+		}
+	}
+	return bonus
+}
+
+// Sanitize 修正读档得到的 Build：去掉已经不存在的技能和属性，技能等级不超过最高等级；
+// 数据改动后点数不够或前置条件不再满足时全部重置，返回是否重置了
+func (c *Config) Sanitize(b *Build, level int) bool {
+	if b.Attributes == nil {
+		b.Attributes = map[string]int{}
+	}
+	if b.Skills == nil {
+		b.Skills = map[string]int{}
+	}
+	maps.DeleteFunc(b.Attributes, func(name string, n int) bool {
+		return n <= 0 || c.Attributes[name] <= 0
+	})
+	maps.DeleteFunc(b.Skills, func(id string, rank int) bool {
+		return rank <= 0 || c.byID[id] == nil
+	})
+	for id, rank := range b.Skills {
+		b.Skills[id] = min(rank, c.byID[id].MaxRank)
+	}
+	valid := c.FreeStatPoints(*b, level) >= 0 && c.FreeSkillPoints(*b, level) >= 0
+	for id := range b.Skills {
+		s := c.byID[id]
+		if level < s.Level {
+			valid = false
+		}
+		for _, r := range s.Requires {
+			if b.Skills[r.Skill] < r.Rank {
+				valid = false
+			}
+		}
+	}
+	if !valid {
+		*b = NewBuild()
+	}
+	return !valid
+}
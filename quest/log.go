@@ -0,0 +1,216 @@
+package quest
+
+import (
+	"fmt"
+	"slices"
+)
+
+// EventType 游戏事件类型
+type EventType int
+
+const (
+	EventInventory EventType = iota // 背包内容变化
+	EventKill                       // 击败敌人
+	EventMove                       // 玩家进入新的格子
+	EventTalk                       // 与 NPC 对话
+)
+
+// Event 推动任务进度的游戏事件
+type Event struct {
+	Type     EventType
+	Enemy    string // EventKill：敌人种类
+	Map      string // EventMove：玩家所在地图
+	Col, Row int    // EventMove：玩家所在格子
+	NPC      string // EventTalk：NPC 名字
+}
+
+// Inventory 计算收集目标需要的背包查询
+type Inventory interface {
+	CountItem(id int64) int64
+}
+
+// Progress 一个任务的进度
+type Progress struct {
+	Def      *Def
+	State    State
+	Progress []int // 每个目标当前的进度
+}
+
+// Done 第 i 个目标是否已完成
+func (p *Progress) Done(i int) bool {
+	return p.Progress[i] >= p.Def.Objectives[i].Required()
+}
+
+// allDone 所有目标是否都已完成
+func (p *Progress) allDone() bool {
+	for i := range p.Def.Objectives {
+		if !p.Done(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Log 任务日志：所有任务定义和玩家的任务进度
+type Log struct {
+	defs   map[string]*Def
+	order  []string             // 任务定义的顺序
+	quests map[string]*Progress // 已接取的任务
+	taken  []string             // 接取顺序
+}
+
+// NewLog 创建任务日志
+func NewLog(defs []*Def) *Log {
+	l := &Log{defs: map[string]*Def{}, quests: map[string]*Progress{}}
+	for _, d := range defs {
+		l.defs[d.ID] = d
+		l.order = append(l.order, d.ID)
+	}
+	return l
+}
+
+// Def 返回任务定义，不存在时返回 nil
+func (l *Log) Def(id string) *Def {
+	return l.defs[id]
+}
+
+// Defs 按数据文件中的顺序返回所有任务定义
+func (l *Log) Defs() []*Def {
+	defs := make([]*Def, 0, len(l.order))
+	for _, id := range l.order {
+		defs = append(defs, l.defs[id])
+	}
+	return defs
+}
+
+// State 返回任务状态
+func (l *Log) State(id string) State {
+	if q, ok := l.quests[id]; ok {
+		return q.State
+	}
+	return NotStarted
+}
+
+// Get 返回已接取任务的进度，未接取时返回 nil
+func (l *Log) Get(id string) *Progress {
+	return l.quests[id]
+}
+
+// Start 接取任务，并立即按背包内容计算收集目标
+// 接取后可能马上完成（例如已经带着需要的物品），此时返回 true，由调用方发放奖励
+func (l *Log) Start(id string, inv Inventory) (completed bool, err error) {
+	def, ok := l.defs[id]
+	if !ok {
+		return false, fmt.Errorf("任务 %q 不存在", id)
+	}
+	if _, ok := l.quests[id]; ok {
+		return false, fmt.Errorf("任务 %q 已经接取", id)
+	}
+	q := &Progress{Def: def, State: Active, Progress: make([]int, len(def.Objectives))}
+	l.quests[id] = q
+	l.taken = append(l.taken, id)
+	return l.update(q, Event{Type: EventInventory}, inv), nil
+}
+
+// Notify 用游戏事件推进所有进行中的任务，返回本次完成的任务
+// 完成的任务状态变为 Completed，由调用方发放奖励和收走需要上交的物品
+func (l *Log) Notify(ev Event, inv Inventory) []*Progress {
+	var completed []*Progress
+	for _, id := range l.taken {
+		q := l.quests[id]
+		if q.State == Active && l.update(q, ev, inv) {
+			completed = append(completed, q)
+		}
+	}
+	return completed
+}
+
+// update 更新一个任务的进度，所有目标都完成时把任务标记为完成并返回 true
+func (l *Log) update(q *Progress, ev Event, inv Inventory) bool {
+	for i, o := range q.Def.Objectives {
+		switch {
+		case o.Type == Collect:
+			// 收集目标随背包内容变化，完成前丢掉物品会降低进度
+			q.Progress[i] = int(min(inv.CountItem(o.Item), int64(o.Required())))
+		case o.Type == Kill && ev.Type == EventKill && ev.Enemy == o.Enemy:
+			q.Progress[i] = min(q.Progress[i]+1, o.Required())
+		case o.Type == Reach && ev.Type == EventMove && (o.Map == "" || o.Map == ev.Map) &&
+			abs(ev.Col-o.Col) <= o.Radius && abs(ev.Row-o.Row) <= o.Radius:
+			q.Progress[i] = 1
+		case o.Type == Talk && ev.Type == EventTalk && ev.NPC == o.NPC:
+			q.Progress[i] = 1
+		}
+		if q.Def.Sequential && !q.Done(i) {
+			break
+		}
+	}
+	if !q.allDone() {
+		return false
+	}
+	q.State = Completed
+	return true
+}
+
+// Active 按接取顺序返回进行中的任务
+func (l *Log) Active() []*Progress {
+	return l.filter(Active)
+}
+
+// Finished 按接取顺序返回已完成的任务
+func (l *Log) Finished() []*Progress {
+	return l.filter(Completed)
+}
+
+func (l *Log) filter(state State) []*Progress {
+	var result []*Progress
+	for _, id := range l.taken {
+		if q := l.quests[id]; q.State == state {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// Saved 存档中的一个任务
+type Saved struct {
+	ID       string `json:"id"`
+	State    State  `json:"state"`
+	Progress []int  `json:"progress"`
+}
+
+// Save 返回存档数据（按接取顺序）
+func (l *Log) Save() []Saved {
+	saved := make([]Saved, 0, len(l.taken))
+	for _, id := range l.taken {
+		q := l.quests[id]
+		saved = append(saved, Saved{ID: id, State: q.State, Progress: slices.Clone(q.Progress)})
+	}
+	return saved
+}
+
+// Load 从存档恢复任务进度，替换当前的进度
+// 已经不存在的任务会被忽略；任务目标数量变化时按新的目标数调整进度
+func (l *Log) Load(saved []Saved) {
+	l.quests = map[string]*Progress{}
+	l.taken = nil
+	for _, s := range saved {
+		def, ok := l.defs[s.ID]
+		if !ok || l.quests[s.ID] != nil {
+			continue
+		}
+		progress := make([]int, len(def.Objectives))
+		copy(progress, s.Progress)
+		for i, o := range def.Objectives {
+			progress[i] = min(max(progress[i], 0), o.Required())
+		}
+		l.quests[s.ID] = &Progress{Def: def, State: s.State, Progress: progress}
+		l.taken = append(l.taken, s.ID)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,171 @@
+// Package quest 任务：数据定义、目标进度、完成判定、存档数据和校验
+// Log 接收游戏发来的背包变化、击杀、移动和对话事件来推进目标，完成后的奖励由游戏发放
+package quest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// 目标类型
+const (
+	Collect = "collect" // 背包中有 Count 个 Item（按背包中的数量计算，丢掉会减少进度）
+	Kill    = "kill"    // 击败 Count 个 Enemy 类型的敌人
+	Reach   = "reach"   // 到达地图 Map 上以 (Col, Row) 为中心、Radius 格以内的位置
+	Talk    = "talk"    // 与名为 NPC 的角色对话
+)
+
+// State 任务状态
+type State string
+
+const (
+	NotStarted State = ""          // 未接取
+	Active     State = "active"    // 进行中
+	Completed  State = "completed" // 已完成并领取奖励
+)
+
+// Objective 任务目标
+type Objective struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`              // 显示在任务日志和追踪栏中的描述
+	Item    int64  `json:"item,omitempty"`    // collect：物品 id
+	Enemy   string `json:"enemy,omitempty"`   // kill：敌人种类
+	Count   int    `json:"count,omitempty"`   // collect / kill：需要的数量，为 0 时按 1 计算
+	Consume bool   `json:"consume,omitempty"` // collect：完成时是否收走物品
+	Map     string `json:"map,omitempty"`     // reach：目标所在的地图，为空时任意地图都算
+	Col     int    `json:"col,omitempty"`     // reach：目标格子
+	Row     int    `json:"row,omitempty"`
+	Radius  int    `json:"radius,omitempty"` // reach：允许的距离（格）
+	NPC     string `json:"npc,omitempty"`    // talk：NPC 名字
+}
+
+// Required 返回完成目标需要的数量
+func (o Objective) Required() int {
+	switch o.Type {
+	case Collect, Kill:
+		return max(o.Count, 1)
+	}
+	return 1
+}
+
+// Reward 任务奖励：固定的物品，或者完成时从掉落表随机抽取
+type Reward struct {
+	Item  int64  `json:"item,omitempty"`
+	Count int64  `json:"count,omitempty"`
+	Table string `json:"table,omitempty"` // 掉落表 id，和 item 二选一
+}
+
+// Def 任务定义
+type Def struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	AutoStart   bool        `json:"autoStart"`  // 新游戏开始时自动接取
+	Sequential  bool        `json:"sequential"` // 目标需要按顺序完成，前面的目标完成前后面的不计进度
+	Objectives  []Objective `json:"objectives"`
+	Rewards     []Reward    `json:"rewards"`
+	XP          int         `json:"xp"` // 完成时获得的经验
+}
+
+// Load 读取任务数据文件
+func Load(path string) ([]*Def, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []*Def
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// Catalog 校验时用于检查引用是否存在，字段为空时不检查对应的引用
+type Catalog struct {
+	Item  func(id int64) bool
+	Enemy func(kind string) bool
+	NPC   func(name string) bool
+	Table func(id string) bool
+	Map   func(id string) bool
+}
+
+// Validate 检查任务定义，返回所有发现的问题
+func Validate(defs []*Def, catalog Catalog) []error {
+	var errs []error
+	seen := map[string]bool{}
+	for _, d := range defs {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("任务 %s: "+format, append([]any{d.ID}, args...)...))
+		}
+		if d.ID == "" {
+			fail("缺少 id")
+		}
+		if seen[d.ID] {
+			fail("id 重复")
+		}
+		seen[d.ID] = true
+		if d.Title == "" {
+			fail("缺少 title")
+		}
+		if len(d.Objectives) == 0 {
+			fail("没有目标")
+		}
+		if d.XP < 0 {
+			fail("xp 不能为负数")
+		}
+		for i, o := range d.Objectives {
+			if err := validateObjective(o, catalog); err != nil {
+				fail("第 %d 个目标: %v", i+1, err)
+			}
+		}
+		for i, r := range d.Rewards {
+			if r.Table != "" {
+				if r.Item != 0 || r.Count != 0 {
+					fail("第 %d 个奖励不能同时有 table 和 item", i+1)
+				}
+				if catalog.Table != nil && !catalog.Table(r.Table) {
+					fail("第 %d 个奖励的掉落表 %q 不存在", i+1, r.Table)
+				}
+				continue
+			}
+			if r.Count <= 0 {
+				fail("第 %d 个奖励的数量必须大于 0", i+1)
+			}
+			if catalog.Item != nil && !catalog.Item(r.Item) {
+				fail("第 %d 个奖励的物品 %d 不存在", i+1, r.Item)
+			}
+		}
+	}
+	return errs
+}
+
+func validateObjective(o Objective, catalog Catalog) error {
+	if o.Text == "" {
+		return fmt.Errorf("缺少 text")
+	}
+	switch o.Type {
+	case Collect:
+		if catalog.Item != nil && !catalog.Item(o.Item) {
+			return fmt.Errorf("物品 %d 不存在", o.Item)
+		}
+	case Kill:
+		if o.Enemy == "" || (catalog.Enemy != nil && !catalog.Enemy(o.Enemy)) {
+			return fmt.Errorf("敌人 %q 不存在", o.Enemy)
+		}
+	case Reach:
+		if o.Radius < 0 {
+			return fmt.Errorf("radius 不能为负数")
+		}
+		if o.Map != "" && catalog.Map != nil && !catalog.Map(o.Map) {
+			return fmt.Errorf("地图 %q 不存在", o.Map)
+		}
+	case Talk:
+		if o.NPC == "" || (catalog.NPC != nil && !catalog.NPC(o.NPC)) {
+			return fmt.Errorf("NPC %q 不存在", o.NPC)
+		}
+	default:
+		return fmt.Errorf("未知的目标类型 %q", o.Type)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package quest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// bag 测试用的背包
+type bag map[int64]int64
+
+func (b bag) CountItem(id int64) int64 { return b[id] }
+
+const testQuests = `[
+  {"id": "mixed", "title": "Mixed", "objectives": [
+    {"type": "collect", "item": 1001, "count": 10, "text": "Gold"},
+    {"type": "kill", "enemy": "slime", "count": 2, "text": "Slimes"},
+    {"type": "reach", "col": 5, "row": 5, "radius": 1, "text": "Go"},
+    {"type": "talk", "npc": "Elder", "text": "Talk"}
+  ], "rewards": [{"item": 1001, "count": 100}]},
+  {"id": "ordered", "title": "Ordered", "sequential": true, "objectives": [
+    {"type": "kill", "enemy": "slime", "text": "Slime"},
+    {"type": "talk", "npc": "Elder", "text": "Report"}
+  ]}
+]`
+
+func mustDefs(t *testing.T) []*Def {
+	t.Helper()
+	var defs []*Def
+	if err := json.Unmarshal([]byte(testQuests), &defs); err != nil {
+		t.Fatal(err)
+	}
+	return defs
+}
+
+func TestObjectives(t *testing.T) {
+	l := NewLog(mustDefs(t))
+	inv := bag{1001: 4}
+	if done, err := l.Start("mixed", inv); err != nil || done {
+		t.Fatalf("Start = %v, %v", done, err)
+	}
+	q := l.Get("mixed")
+	if q.Progress[0] != 4 {
+		t.Fatalf("接取时收集进度 = %d, 期望 4", q.Progress[0])
+	}
+
+	events := []Event{
+		{Type: EventKill, Enemy: "slime"},
+		{Type: EventKill, Enemy: "bigSlime"}, // 其他敌人不计数
+		{Type: EventKill, Enemy: "slime"},
+		{Type: EventKill, Enemy: "slime"}, // 超出的不计数
+		{Type: EventMove, Col: 7, Row: 5}, // 超出半径
+		{Type: EventMove, Col: 6, Row: 4},
+		{Type: EventTalk, NPC: "Merchant"},
+		{Type: EventTalk, NPC: "Elder"},
+	}
+	for _, ev := range events {
+		if done := l.Notify(ev, inv); len(done) > 0 {
+			t.Fatalf("事件 %+v 后任务提前完成", ev)
+		}
+	}
+	if got := q.Progress; got[1] != 2 || got[2] != 1 || got[3] != 1 {
+		t.Fatalf("进度 = %v", got)
+	}
+
+	// 背包变化后收集目标完成，整个任务完成
+	inv[1001] = 12
+	done := l.Notify(Event{Type: EventInventory}, inv)
+	if len(done) != 1 || done[0].Def.ID != "mixed" || l.State("mixed") != Completed {
+		t.Fatalf("任务没有完成: %v %v", done, l.State("mixed"))
+	}
+	// 完成后不再重复完成
+	if done := l.Notify(Event{Type: EventInventory}, inv); len(done) != 0 {
+		t.Fatal("已完成的任务再次完成")
+	}
+}
+
+// TestReachMap 指定了地图的到达目标只在那张地图上完成
+func TestReachMap(t *testing.T) {
+	l := NewLog([]*Def{{ID: "cave", Title: "Cave", Objectives: []Objective{
+		{Type: Reach, Map: "cave", Col: 3, Row: 3, Text: "Go"},
+	}}})
+	l.Start("cave", bag{})
+	if done := l.Notify(Event{Type: EventMove, Map: "start", Col: 3, Row: 3}, bag{}); len(done) != 0 {
+		t.Fatal("在其他地图的同一格子不应完成")
+	}
+	if done := l.Notify(Event{Type: EventMove, Map: "cave", Col: 3, Row: 3}, bag{}); len(done) != 1 {
+		t.Fatal("到达目标地图的格子应完成")
+	}
+}
+
+func TestCollectFollowsInventory(t *testing.T) {
+	l := NewLog(mustDefs(t))
+	inv := bag{1001: 8}
+	l.Start("mixed", inv)
+	inv[1001] = 3
+	l.Notify(Event{Type: EventInventory}, inv)
+	if got := l.Get("mixed").Progress[0]; got != 3 {
+		t.Fatalf("丢掉物品后进度 = %d, 期望 3", got)
+	}
+}
+
+func TestSequential(t *testing.T) {
+	l := NewLog(mustDefs(t))
+	l.Start("ordered", bag{})
+	l.Notify(Event{Type: EventTalk, NPC: "Elder"}, bag{})
+	if l.Get("ordered").Progress[1] != 0 {
+		t.Fatal("按顺序的任务跳过了前面的目标")
+	}
+	l.Notify(Event{Type: EventKill, Enemy: "slime"}, bag{})
+	if done := l.Notify(Event{Type: EventTalk, NPC: "Elder"}, bag{}); len(done) != 1 {
+		t.Fatal("按顺序完成后任务没有完成")
+	}
+}
+
+func TestStartErrors(t *testing.T) {
+	l := NewLog(mustDefs(t))
+	if _, err := l.Start("missing", bag{}); err == nil {
+		t.Fatal("接取不存在的任务没有报错")
+	}
+	l.Start("mixed", bag{})
+	if _, err := l.Start("mixed", bag{}); err == nil {
+		t.Fatal("重复接取没有报错")
+	}
+	// 接取时已经满足条件会立即完成
+	l2 := NewLog([]*Def{{ID: "easy", Title: "Easy", Objectives: []Objective{{Type: Collect, Item: 1, Text: "x"}}}})
+	if done, _ := l2.Start("easy", bag{1: 1}); !done || l2.State("easy") != Completed {
+		t.Fatal("接取时满足条件的任务没有立即完成")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	defs := mustDefs(t)
+	l := NewLog(defs)
+	l.Start("ordered", bag{})
+	l.Start("mixed", bag{1001: 2})
+	l.Notify(Event{Type: EventKill, Enemy: "slime"}, bag{1001: 2})
+	l.Notify(Event{Type: EventTalk, NPC: "Elder"}, bag{1001: 2})
+
+	data, err := json.Marshal(l.Save())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved []Saved
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	restored := NewLog(defs)
+	restored.Load(saved)
+	if restored.State("ordered") != Completed || restored.State("mixed") != Active {
+		t.Fatalf("状态 = %v %v", restored.State("ordered"), restored.State("mixed"))
+	}
+	if got := restored.Get("mixed").Progress; got[0] != 2 || got[1] != 1 || got[3] != 1 {
+		t.Fatalf("进度 = %v", got)
+	}
+	if active := restored.Active(); len(active) != 1 || active[0].Def.ID != "mixed" {
+		t.Fatal("恢复后进行中的任务不对")
+	}
+
+	// 不存在的任务被忽略，超出范围的进度被修正
+	restored.Load([]Saved{{ID: "gone", State: Active}, {ID: "mixed", State: Active, Progress: []int{99, -1}}})
+	if restored.Get("gone") != nil {
+		t.Fatal("恢复了不存在的任务")
+	}
+	if got := restored.Get("mixed").Progress; got[0] != 10 || got[1] != 0 || len(got) != 4 {
+		t.Fatalf("修正后的进度 = %v", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defs := []*Def{
+		{ID: "a", Title: "A", Objectives: []Objective{
+			{Type: "fly", Text: "x"},
+			{Type: Kill, Enemy: "dragon", Text: "x"},
+			{Type: Collect, Item: 9, Text: "x"},
+			{Type: Talk, NPC: "Elder"},
+			{Type: Reach, Map: "moon", Text: "x"},
+		}, Rewards: []Reward{{Item: 9, Count: 1}, {Item: 1, Count: 0}, {Table: "none"}, {Table: "chest", Item: 1, Count: 1}}},
+		{ID: "a", XP: -1},
+	}
+	catalog := Catalog{
+		Item:  func(id int64) bool { return id == 1 },
+		Enemy: func(kind string) bool { return kind == "slime" },
+		NPC:   func(name string) bool { return name == "Elder" },
+		Table: func(id string) bool { return id == "chest" },
+		Map:   func(id string) bool { return id == "start" },
+	}
+	errs := Validate(defs, catalog)
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	all := strings.Join(msgs, "\n")
+	for _, want := range []string{"fly", "dragon", "物品 9", "缺少 text", "数量必须大于 0", "id 重复", "缺少 title", "没有目标", `掉落表 "none"`, "同时有 table 和 item", "xp 不能为负数", `地图 "moon"`} {
+		if !strings.Contains(all, want) {
+			t.Errorf("缺少错误 %q:\n%s", want, all)
+		}
+	}
+}
+
+// TestShippedQuests 校验游戏自带的任务数据
+func TestShippedQuests(t *testing.T) {
+	defs, err := Load("../data/quests.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range Validate(defs, Catalog{}) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"Game/quest"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+)
+
+// 任务日志面板布局
+const (
+	questLogX     = 120
+	questLogY     = 80
+	questLogW     = 560
+	questLogH     = 440
+	questListW    = 180 // 左侧任务列表宽度
+	questRowH     = 18
+	questTextCols = (questLogW - questListW - 40) / 6
+)
+
+// questLogEntries 任务日志中列出的任务：进行中的在前，已完成的在后
+func (p *PlayScreen) questLogEntries() []*quest.Progress {
+	return append(p.quests.Active(), p.quests.Finished()...)
+}
+
+// questRowRect 左侧列表第 index 行的点击区域（已完成的任务前有一行标题）
+func questRowRect(index, activeCount int) [4]int {
+	row := index + 1
+	if index >= activeCount {
+		row++
+	}
+	return [4]int{questLogX + 10, questLogY + 40 + row*questRowH, questListW - 20, questRowH}
+}
+
+// setQuestLogOpen 打开或关闭任务日志
+func (p *PlayScreen) setQuestLogOpen(open bool) {
+	if open == p.questLogOpen {
+		return
+	}
+	p.questLogOpen = open
+	p.sound.PlaySFX(sfxClick)
+}
+
+// updateQuestLog 处理任务日志中的选择：方向键上下或鼠标点击
+func (p *PlayScreen) updateQuestLog() {
+	entries := p.questLogEntries()
+	if len(entries) == 0 {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		p.questLogIndex = (p.questLogIndex - 1 + len(entries)) % len(entries)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		p.questLogIndex = (p.questLogIndex + 1) % len(entries)
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		active := len(p.quests.Active())
+		for i := range entries {
+			if inRect(x, y, questRowRect(i, active)) {
+				p.questLogIndex = i
+			}
+		}
+	}
+	p.questLogIndex = min(p.questLogIndex, len(entries)-1)
+}
+
+// drawQuestLog 绘制任务日志：左侧任务列表，右侧选中任务的描述、目标和奖励
+func (p *PlayScreen) drawQuestLog(screen *ebiten.Image) {
+	drawPanel(screen, questLogX, questLogY, questLogW, questLogH)
+	drawCenteredText(screen, p.settings.T("quest.log"), questLogX, questLogY+12, questLogW)
+	vector.DrawFilledRect(screen, questLogX+questListW, questLogY+36, 1, questLogH-50, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+
+	entries := p.questLogEntries()
+	if len(entries) == 0 {
+		ebitenutil.DebugPrintAt(screen, p.settings.T("quest.none"), questLogX+16, questLogY+44)
+		return
+	}
+
+	active := len(p.quests.Active())
+	ebitenutil.DebugPrintAt(screen, p.settings.T("quest.active"), questLogX+10, questLogY+40)
+	if active < len(entries) {
+		ebitenutil.DebugPrintAt(screen, p.settings.T("quest.finished"), questLogX+10, questLogY+40+(active+1)*questRowH)
+	}
+	for i, q := range entries {
+		rect := questRowRect(i, active)
+		if i == p.questLogIndex {
+			vector.DrawFilledRect(screen, float32(rect[0]), float32(rect[1]), float32(rect[2]), float32(rect[3]), color.RGBA{R: 100, G: 150, B: 255, A: 80}, false)
+		}
+		ebitenutil.DebugPrintAt(screen, q.Def.Title, rect[0]+6, rect[1]+1)
+	}
+
+	// 选中任务的详情
+	q := entries[p.questLogIndex]
+	x := questLogX + questListW + 20
+	y := questLogY + 40
+	ebitenutil.DebugPrintAt(screen, q.Def.Title, x, y)
+	y += 24
+	for _, line := range wrapText(q.Def.Description, questTextCols) {
+		ebitenutil.DebugPrintAt(screen, line, x, y)
+		y += 16
+	}
+
+	y += 12
+	ebitenutil.DebugPrintAt(screen, p.settings.T("quest.objectives"), x, y)
+	y += 20
+	for i := range q.Def.Objectives {
+		mark := "[ ]"
+		if q.Done(i) {
+			mark = "[x]"
+		}
+		ebitenutil.DebugPrintAt(screen, mark+" "+objectiveText(q, i), x+8, y)
+		y += 16
+	}
+
+	if len(q.Def.Rewards) > 0 {
+		y += 12
+		ebitenutil.DebugPrintAt(screen, p.settings.T("quest.rewards"), x, y)
+		y += 20
+		for _, r := range q.Def.Rewards {
+			if r.Table != "" {
+				ebitenutil.DebugPrintAt(screen, p.settings.T("quest.randomReward"), x+30, y+1)
+				y += 20
+				continue
+			}
+			data := ItemImages[r.Item]
+			drawItemIcon(screen, data, x+8, y, 16)
+			ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s x%d", data.Name, r.Count), x+30, y+1)
+			y += 20
+		}
+	}
+
+	if q.State == quest.Completed {
+		ebitenutil.DebugPrintAt(screen, p.settings.T("quest.done"), x, questLogY+questLogH-30)
+	}
+}
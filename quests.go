@@ -0,0 +1,171 @@
+package main
+
+import (
+	"Game/quest"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+)
+
+// questsPath 任务数据文件
+const questsPath = "data/quests.json"
+
+// 屏幕右上角的任务追踪栏
+const (
+	questTrackerW   = 220
+	questTrackerMax = 3 // 最多追踪的任务数
+)
+
+// initQuests 加载并校验任务数据，数据有误时直接退出（需要在加载地图和掉落表之后调用）
+func (p *PlayScreen) initQuests() {
+	defs, err := quest.Load(questsPath)
+	if err != nil {
+		log.Fatalf("加载任务数据失败: %v", err)
+	}
+	npcs := map[string]bool{}
+	for _, o := range p.mapObjects(objectNPC) {
+		npcs[o.Name] = true
+	}
+	catalog := quest.Catalog{
+		Item:  func(id int64) bool { return ItemImages[id] != nil },
+		Enemy: func(kind string) bool { return EnemyDefs[kind] != nil },
+		NPC:   func(name string) bool { return npcs[name] },
+		Table: func(id string) bool { return p.loot[id] != nil },
+		Map:   func(id string) bool { return p.atlas().Maps[id] != nil },
+	}
+	if errs := quest.Validate(defs, catalog); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("任务数据有误: %s", questsPath)
+	}
+	p.quests = quest.NewLog(defs)
+}
+
+// startAutoQuests 新游戏开始时接取自动接取的任务
+func (p *PlayScreen) startAutoQuests() {
+	for _, def := range p.quests.Defs() {
+		if def.AutoStart {
+			p.startQuest(def.ID)
+		}
+	}
+}
+
+// startQuest 接取任务
+func (p *PlayScreen) startQuest(id string) {
+	if p.quests.State(id) != quest.NotStarted {
+		return
+	}
+	completed, err := p.quests.Start(id, p)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	p.notices.Show(fmt.Sprintf(p.settings.T("quest.started"), p.quests.Def(id).Title))
+	if completed {
+		p.completeQuest(p.quests.Get(id))
+	}
+}
+
+// questEvent 用游戏事件推进任务进度，并为完成的任务发放奖励
+func (p *PlayScreen) questEvent(ev quest.Event) {
+	for _, q := range p.quests.Notify(ev, p) {
+		p.completeQuest(q)
+	}
+}
+
+// inventoryChanged 背包内容变化时更新收集类任务
+func (p *PlayScreen) inventoryChanged() {
+	if p.quests != nil {
+		p.questEvent(quest.Event{Type: quest.EventInventory})
+	}
+}
+
+// updateQuests 玩家进入新的格子（或者换了地图）时触发到达事件
+func (p *PlayScreen) updateQuests() {
+	cx, cy := p.playerCenter()
+	tile := [2]int{int(cx) / p.gridSize, int(cy) / p.gridSize}
+	if tile == p.playerTile {
+		return
+	}
+	p.playerTile = tile
+	p.questEvent(quest.Event{Type: quest.EventMove, Map: p.atlas().Current, Col: tile[0], Row: tile[1]})
+}
+
+// completeQuest 收走需要上交的物品并发放奖励
+func (p *PlayScreen) completeQuest(q *quest.Progress) {
+	defer p.walletTx("quest")()
+	p.notices.Show(fmt.Sprintf(p.settings.T("quest.completed"), q.Def.Title))
+	for _, o := range q.Def.Objectives {
+		if o.Type == quest.Collect && o.Consume {
+			p.takeItem(o.Item, int64(o.Required()))
+		}
+	}
+	for _, r := range q.Def.Rewards {
+		if r.Table != "" {
+			for _, s := range p.rollLoot(r.Table, false) {
+				p.giveItem(s.Item, s.Count)
+			}
+			continue
+		}
+		p.giveItem(r.Item, r.Count)
+	}
+	p.gainXP(q.Def.XP)
+}
+
+// CountItem 返回背包中某种物品的总数，货币返回钱包余额（实现 quest.Inventory）
+func (p *PlayScreen) CountItem(itemID int64) int64 {
+	if isCurrency(itemID) {
+		return p.purse().Wallet.Balance(itemID)
+	}
+	var total int64
+	for _, it := range p.bag().Items {
+		if it.ItemData.id == itemID {
+			total += it.count
+		}
+	}
+	return total
+}
+
+// objectiveText 返回任务目标的描述和进度，如 "Defeat slimes 1/3"
+func objectiveText(q *quest.Progress, i int) string {
+	o := q.Def.Objectives[i]
+	if o.Required() > 1 {
+		return fmt.Sprintf("%s %d/%d", o.Text, q.Progress[i], o.Required())
+	}
+	return o.Text
+}
+
+// drawQuestTracker 在屏幕右上角显示进行中任务的目标和进度
+func (p *PlayScreen) drawQuestTracker(screen *ebiten.Image) {
+	active := p.quests.Active()
+	if len(active) == 0 {
+		return
+	}
+	active = active[:min(len(active), questTrackerMax)]
+
+	rows := 0
+	for _, q := range active {
+		rows += 1 + len(q.Def.Objectives)
+	}
+
+	x := screenWidth - questTrackerW - 10
+	y := 10
+	vector.DrawFilledRect(screen, float32(x), float32(y), questTrackerW, float32(rows*16+8), color.RGBA{A: 140}, false)
+	row := 0
+	for _, q := range active {
+		ebitenutil.DebugPrintAt(screen, q.Def.Title, x+6, y+4+row*16)
+		row++
+		for i := range q.Def.Objectives {
+			mark := "[ ]"
+			if q.Done(i) {
+				mark = "[x]"
+			}
+			ebitenutil.DebugPrintAt(screen, mark+" "+objectiveText(q, i), x+12, y+4+row*16)
+			row++
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/progression"
+	"Game/quest"
+	"Game/savegame"
+	"Game/shop"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// SaveData 存档内容：玩家、背包、钱包、装备、剧情标记、任务进度、商店库存、所在地图和每张地图的状态、击败次数、快捷栏、容器、复活点、等级和技能
+type SaveData struct {
+	Version   int                     `json:"version"`
+	SavedAt   time.Time               `json:"savedAt"`
+	Player    savedPlayer             `json:"player"`
+	Items     []savedItem             `json:"items"`
+	Wallet    map[int64]int64         `json:"wallet"` // 货币余额（版本 2 起；之前货币放在背包里）
+	Equipment map[EquipSlot]savedItem `json:"equipment"`
+	Flags     []string                `json:"flags"`
+	Quests    []quest.Saved           `json:"quests"`
+	Recipes   []string                `json:"recipes"`
+	Shops     map[string]shop.Saved   `json:"shops"`
+	Kills     map[string]int          `json:"kills"`
+	Hotbar    []int64                 `json:"hotbar"`  // 快捷栏每格的物品 id，0 表示空
+	Respawn   respawnPoint            `json:"respawn"` // 死亡后复活的位置（版本 3 起）
+
+	// 玩家所在的地图，以及每张去过的地图上剩余的物品与敌人和打开过的宝箱：地图 id -> 状态（版本 5 起）
+	Map  string               `json:"map"`
+	Maps map[string]*mapState `json:"maps"`
+
+	// 等级和经验、分配的属性点和学会的技能、技能栏（版本 4 起）
+	Progress progression.Progress `json:"progress"`
+	Build    progression.Build    `json:"build"`
+	SkillBar []string             `json:"skillBar"`
+
+	// 容器中的物品：地图 id -> 容器 id -> 物品，共享容器（银行）的地图 id 为空字符串
+	Containers map[string]map[string][]savedItem `json:"containers"`
+}
+
+// savedPlayer 存档中的玩家状态
+type savedPlayer struct {
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Health  int     `json:"health"`
+	Mana    int     `json:"mana"`    // 版本 3 起
+	Stamina int     `json:"stamina"` // 版本 3 起
+}
+
+// savedItem 存档中的一组物品
+type savedItem struct {
+	ID       int64 `json:"id"`
+	Count    int64 `json:"count"`
+	Charges  int   `json:"charges,omitempty"`
+	Acquired int   `json:"acquired,omitempty"` // 获得顺序，背包按最近获得排序时使用
+}
+
+// savedPickup 存档中地上的物品
+type savedPickup struct {
+	Item  int64   `json:"item"`
+	Count int64   `json:"count"`
+	X     float64 `json:"x"` // 位置（像素）
+	Y     float64 `json:"y"`
+}
+
+// savedEnemy 存档中还活着的敌人
+type savedEnemy struct {
+	Kind    string  `json:"kind"`
+	HomeCol int     `json:"homeCol"` // 出生点（格子）
+	HomeRow int     `json:"homeRow"`
+	X       float64 `json:"x"` // 位置（像素）
+	Y       float64 `json:"y"`
+	Health  int     `json:"health"`
+}
+
+// savePath 返回存档文件路径
+func savePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName, "save.json"), nil
+}
+
+// SaveGame 把当前游戏状态写入存档；先写临时文件再替换，避免写到一半时损坏旧存档
+func (p *PlayScreen) SaveGame() error {
+	path, err := savePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p.snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// saveWithNotice 保存游戏并在屏幕上提示结果
+func (p *PlayScreen) saveWithNotice() {
+	if err := p.SaveGame(); err != nil {
+		log.Printf("保存游戏失败: %v", err)
+		p.notices.Show(p.settings.T("notice.saveFailed"))
+		return
+	}
+	p.notices.Show(p.settings.T("notice.saved"))
+}
+
+// loadGame 读取存档并恢复游戏状态；没有存档或存档损坏时返回 false，保持新游戏的状态
+func (p *PlayScreen) loadGame() bool {
+	path, err := savePath()
+	if err != nil {
+		log.Printf("无法获取配置目录: %v", err)
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("读取存档失败 %s: %v", path, err)
+		}
+		return false
+	}
+	save, err := loadSave(data)
+	if err != nil {
+		log.Printf("解析存档失败 %s: %v", path, err)
+		return false
+	}
+	p.restore(save)
+	return true
+}
+
+// loadSave 把旧版本的存档迁移到当前版本（见 savegame 包）后解析
+func loadSave(data []byte) (*SaveData, error) {
+	data, err := savegame.Migrate(data, savegame.Defaults{
+		IsCurrency: isCurrency,
+		Mana:       playerConfig.Mana.Max,
+		Stamina:    playerConfig.Stamina.Max,
+		StartMap:   startMap,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var save SaveData
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+	return &save, nil
+}
+
+// snapshot 收集需要存档的游戏状态
+func (p *PlayScreen) snapshot() *SaveData {
+	pos := p.playerPos()
+	v := p.playerVitals()
+	save := &SaveData{
+		Version: savegame.Version,
+		SavedAt: time.Now(),
+		Player: savedPlayer{
+			X: pos.X, Y: pos.Y,
+			Health:  ecs.Get[ecs.Health](p.world, p.player).Current,
+			Mana:    v.Mana.Current,
+			Stamina: v.Stamina.Current,
+		},
+		Equipment: map[EquipSlot]savedItem{},
+		Flags:     slices.Sorted(maps.Keys(p.story().Flags)),
+		Quests:    p.quests.Save(),
+		Recipes:   slices.Sorted(maps.Keys(p.story().Recipes)),
+		Shops:     map[string]shop.Saved{},
+		Wallet:    p.purse().Wallet.Balances(),
+		Kills:     maps.Clone(p.story().Kills),
+		Hotbar:    slices.Clone(p.bag().Hotbar[:]),
+		Respawn:   p.respawner().Point,
+		Progress:  p.level().Progress,
+		Build:     p.level().Build,
+		SkillBar:  slices.Clone(p.level().Bar[:]),
+		Map:       p.atlas().Current,
+		Maps:      maps.Clone(p.atlas().States),
+	}
+	save.Maps[p.atlas().Current] = p.captureMap()
+	save.Containers = p.saveContainers()
+	for id, s := range p.shops {
+		save.Shops[id] = s.Save()
+	}
+	for _, it := range p.bag().Items {
+		save.Items = append(save.Items, savedItem{ID: it.ItemData.id, Count: it.count, Charges: it.charges, Acquired: it.acquired})
+	}
+	for slot, it := range ecs.Get[Equipment](p.world, p.player).Slots {
+		save.Equipment[slot] = savedItem{ID: it.ItemData.id, Count: it.count, Charges: it.charges}
+	}
+	return save
+}
+
+// restore 用存档替换新游戏的状态；存档中已经不存在的地图、物品和敌人会被忽略
+func (p *PlayScreen) restore(save *SaveData) {
+	health := ecs.Get[ecs.Health](p.world, p.player)
+	health.Current = min(max(save.Player.Health, 1), health.Max)
+	v := p.playerVitals()
+	v.Mana.Current = min(max(save.Player.Mana, 0), v.Mana.Max)
+	v.Stamina.Current = min(max(save.Player.Stamina, 0), v.Stamina.Max)
+	p.respawner().Point = save.Respawn
+	// 版本 3 之前的存档没有复活点，复活点所在的地图被删除时同样回到新游戏的复活位置
+	if save.Respawn.Map == "" || p.atlas().Maps[save.Respawn.Map] == nil {
+		p.respawner().Point = p.defaultRespawn()
+	}
+
+	p.bag().Items = nil
+	p.bag().Acquired = 0
+	for _, s := range save.Items {
+		if it := restoreItem(s); it != nil {
+			p.bag().Items = append(p.bag().Items, it)
+			p.bag().Acquired = max(p.bag().Acquired, it.acquired)
+		}
+	}
+
+	p.purse().Wallet.Load(save.Wallet)
+
+	equipment := ecs.Get[Equipment](p.world, p.player)
+	equipment.Slots = map[EquipSlot]*item{}
+	for slot, s := range save.Equipment {
+		if it := restoreItem(s); it != nil && it.ItemData.Slot == slot {
+			equipment.Slots[slot] = it
+		}
+	}
+
+	p.story().Flags = map[string]bool{}
+	for _, flag := range save.Flags {
+		p.story().Flags[flag] = true
+	}
+	p.story().Recipes = map[string]bool{}
+	for _, recipe := range save.Recipes {
+		p.story().Recipes[recipe] = true
+	}
+	p.bag().Hotbar = [hotbarSlots]int64{}
+	for i, id := range save.Hotbar {
+		if i < hotbarSlots && ItemImages[id] != nil {
+			p.bag().Hotbar[i] = id
+		}
+	}
+	p.story().Kills = map[string]int{}
+	maps.Copy(p.story().Kills, save.Kills)
+	p.restoreProgression(save)
+	p.loadContainers(save.Containers)
+	p.quests.Load(save.Quests)
+	for id, saved := range save.Shops {
+		if s, ok := p.shops[id]; ok {
+			s.Load(saved)
+		}
+	}
+
+	// 地图上的物品、敌人和宝箱以存档为准；所在地图已不存在时回到新游戏的起点
+	p.atlas().States = map[string]*mapState{}
+	for id, state := range save.Maps {
+		if p.atlas().Maps[id] != nil && state != nil {
+			p.atlas().States[id] = state
+		}
+	}
+	x, y := save.Player.X, save.Player.Y
+	if p.atlas().Maps[save.Map] == nil {
+		log.Printf("存档中的地图 %q 已不存在，回到起点", save.Map)
+		save.Map = startMap
+		spawn := p.atlas().Maps[startMap].Spawn(startSpawn)
+		x, y = float64(spawn.Col*p.gridSize), float64(spawn.Row*p.gridSize)
+	}
+	p.clearMap()
+	p.loadMap(save.Map)
+	pos := p.playerPos()
+	pos.X, pos.Y = x, y
+	// 旧存档中可能有同种物品的多组
+	p.consolidateItems()
+}
+
+// restoreItem 根据存档创建物品，物品已不存在时返回 nil
+func restoreItem(s savedItem) *item {
+	data := ItemImages[s.ID]
+	if data == nil || s.Count <= 0 {
+		log.Printf("存档中的物品 %d 已不存在，忽略", s.ID)
+		return nil
+	}
+	return &item{id: s.ID, count: s.Count, charges: s.Charges, acquired: s.Acquired, ItemData: data}
+}
@@ -0,0 +1,156 @@
+// Package savegame 存档格式的版本迁移：按版本逐步把旧存档的 JSON 升级为当前格式，
+// 游戏再把结果解析为自己的存档结构；旧存档缺少的数据由游戏通过 Defaults 提供
+package savegame
+
+import (
+	"Game/progression"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Version 当前存档格式版本，格式变化时递增并在 steps 中加入迁移
+const Version = 5
+
+// Defaults 迁移时填入旧存档缺少的数据，由游戏按自己的配置提供
+type Defaults struct {
+	IsCurrency    func(itemID int64) bool // 物品是否是货币，版本 2 起货币从背包移到钱包
+	Mana, Stamina int                     // 法力值和体力的上限，版本 3 之前的存档按已满处理
+	StartMap      string                  // 起始地图，版本 5 之前的存档只有这一张地图
+}
+
+// doc 存档的 JSON 对象，只解析迁移需要修改的字段，其余字段原样保留
+type doc map[string]json.RawMessage
+
+// get 读取字段，字段不存在时 v 保持不变
+func (d doc) get(key string, v any) error {
+	raw, ok := d[key]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// set 写入字段
+func (d doc) set(key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	d[key] = raw
+	return nil
+}
+
+// step 把存档升级到版本 to 的迁移
+type step struct {
+	to      int
+	migrate func(d doc, defaults *Defaults) error
+}
+
+// steps 所有迁移，按版本从低到高
+var steps = []step{
+	{2, migrateWallet},
+	{3, migrateVitals},
+	{4, migrateProgress},
+	{5, migrateMaps},
+}
+
+// Migrate 依次执行存档版本之后的每一步迁移，返回升级后的 JSON，版本号为最后执行的迁移的版本；
+// 比 Version 新的存档返回错误
+func Migrate(data []byte, defaults Defaults) ([]byte, error) {
+	var d doc
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	var version int
+	if err := d.get("version", &version); err != nil {
+		return nil, err
+	}
+	if version > Version {
+		return nil, fmt.Errorf("存档版本 %d 比游戏支持的版本 %d 新", version, Version)
+	}
+	for _, s := range steps {
+		if version >= s.to {
+			continue
+		}
+		if err := s.migrate(d, &defaults); err != nil {
+			return nil, fmt.Errorf("迁移到版本 %d: %w", s.to, err)
+		}
+		version = s.to
+	}
+	if err := d.set("version", version); err != nil {
+		return nil, err
+	}
+	return json.Marshal(d)
+}
+
+// migrateWallet 版本 1 的货币放在背包里：移到钱包，同种货币的多组相加，超出上限时取上限
+func migrateWallet(d doc, defaults *Defaults) error {
+	var items []doc
+	wallet := map[int64]int64{}
+	if err := d.get("items", &items); err != nil {
+		return err
+	}
+	if err := d.get("wallet", &wallet); err != nil {
+		return err
+	}
+	kept := items[:0]
+	for _, it := range items {
+		var id, count int64
+		if err := it.get("id", &id); err != nil {
+			return err
+		}
+		if err := it.get("count", &count); err != nil {
+			return err
+		}
+		if !defaults.IsCurrency(id) {
+			kept = append(kept, it)
+			continue
+		}
+		if count > 0 {
+			wallet[id] = min(wallet[id], math.MaxInt64-count) + count
+		}
+	}
+	if err := d.set("items", kept); err != nil {
+		return err
+	}
+	return d.set("wallet", wallet)
+}
+
+// migrateVitals 版本 3 之前没有法力值和体力：按已满处理；复活点留空，由游戏使用新游戏的复活位置
+func migrateVitals(d doc, defaults *Defaults) error {
+	player := doc{}
+	if err := d.get("player", &player); err != nil {
+		return err
+	}
+	if err := player.set("mana", defaults.Mana); err != nil {
+		return err
+	}
+	if err := player.set("stamina", defaults.Stamina); err != nil {
+		return err
+	}
+	return d.set("player", player)
+}
+
+// migrateProgress 版本 4 之前没有等级：从新角色的进度开始
+func migrateProgress(d doc, defaults *Defaults) error {
+	return d.set("progress", progression.Start())
+}
+
+// migrateMaps 版本 5 之前只有一张地图，地上的物品、敌人和打开过的宝箱直接放在存档里：移到起始地图的状态中
+func migrateMaps(d doc, defaults *Defaults) error {
+	state := doc{}
+	for _, key := range []string{"pickups", "enemies", "chests"} {
+		if raw, ok := d[key]; ok {
+			state[key] = raw
+			delete(d, key)
+		}
+	}
+	if err := d.set("map", defaults.StartMap); err != nil {
+		return err
+	}
+	return d.set("maps", map[string]doc{defaults.StartMap: state})
+}
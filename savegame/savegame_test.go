@@ -0,0 +1,189 @@
+package savegame
+
+import (
+	"Game/progression"
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const gold, gem, sword = 1001, 1005, 1002
+
+var testDefaults = Defaults{
+	IsCurrency: func(id int64) bool { return id == gold || id == gem },
+	Mana:       30,
+	Stamina:    80,
+	StartMap:   "start",
+}
+
+// 各版本的存档：版本 1 的货币在背包里，版本 2 起有钱包，版本 3 起有法力值、体力和复活点，
+// 版本 4 起有等级，版本 5 起地图的状态在 maps 中
+const (
+	saveV1 = `{"version": 1, "player": {"x": 64, "y": 96, "health": 40},
+		"items": [
+			{"id": 1001, "count": 9223372036854775000},
+			{"id": 1002, "count": 1, "charges": 2},
+			{"id": 1001, "count": 1000},
+			{"id": 1005, "count": 3},
+			{"id": 1005, "count": 0}
+		],
+		"pickups": [{"item": 2001, "count": 1, "x": 32, "y": 64}],
+		"enemies": [{"kind": "slime", "homeCol": 3, "homeRow": 4, "x": 96, "y": 128, "health": 5}],
+		"chests": ["corner"]}`
+	saveV2 = `{"version": 2, "player": {"x": 64, "y": 96, "health": 40},
+		"items": [{"id": 1002, "count": 1}], "wallet": {"1001": 500},
+		"pickups": [{"item": 2001, "count": 1, "x": 32, "y": 64}]}`
+	saveV3 = `{"version": 3, "player": {"x": 64, "y": 96, "health": 40, "mana": 7, "stamina": 9},
+		"wallet": {"1001": 500}, "respawn": {"id": "village", "map": "start", "col": 5, "row": 5},
+		"chests": ["corner"]}`
+	saveV4 = `{"version": 4, "player": {"x": 64, "y": 96, "health": 40, "mana": 7, "stamina": 9},
+		"wallet": {"1001": 500}, "respawn": {"id": "village", "map": "start", "col": 5, "row": 5},
+		"progress": {"level": 3, "xp": 12},
+		"enemies": [{"kind": "slime", "homeCol": 3, "homeRow": 4, "x": 96, "y": 128, "health": 5}]}`
+	saveV5 = `{"version": 5, "player": {"x": 64, "y": 96, "health": 40, "mana": 7, "stamina": 9},
+		"wallet": {"1001": 500}, "respawn": {"id": "cave", "map": "cave", "col": 1, "row": 2},
+		"progress": {"level": 3, "xp": 12},
+		"map": "cave", "maps": {"cave": {"chests": ["hoard"]}}}`
+)
+
+// migrated 迁移后需要检查的字段
+type migrated struct {
+	Version int
+	Player  struct {
+		X                     float64
+		Health, Mana, Stamina int
+	}
+	Items []struct {
+		ID, Count int64
+		Charges   int
+	}
+	Wallet  map[int64]int64
+	Respawn struct {
+		ID, Map  string
+		Col, Row int
+	}
+	Progress progression.Progress
+	Map      string
+	Maps     map[string]struct {
+		Pickups []struct{ Item int64 }
+		Enemies []struct{ Kind string }
+		Chests  []string
+	}
+	Pickups, Enemies, Chests json.RawMessage // 迁移后应不存在
+}
+
+func TestMigrate(t *testing.T) {
+	cases := []struct {
+		name  string
+		save  string
+		check func(t *testing.T, m migrated)
+	}{
+		{"v1 货币移到钱包并在上限处截断", saveV1, func(t *testing.T, m migrated) {
+			if len(m.Items) != 1 || m.Items[0].ID != sword || m.Items[0].Charges != 2 {
+				t.Errorf("背包 = %+v，应只剩下剑并保留其他字段", m.Items)
+			}
+			if m.Wallet[gold] != math.MaxInt64 || m.Wallet[gem] != 3 || len(m.Wallet) != 2 {
+				t.Errorf("钱包 = %v", m.Wallet)
+			}
+		}},
+		{"v1 没有版本号", strings.Replace(saveV1, `"version": 1,`, "", 1), func(t *testing.T, m migrated) {
+			if m.Wallet[gold] != math.MaxInt64 || len(m.Items) != 1 {
+				t.Errorf("钱包 = %v，背包 = %+v", m.Wallet, m.Items)
+			}
+		}},
+		{"v2 钱包保留，数值和复活点使用默认值", saveV2, func(t *testing.T, m migrated) {
+			if m.Wallet[gold] != 500 || len(m.Items) != 1 {
+				t.Errorf("钱包 = %v，背包 = %+v", m.Wallet, m.Items)
+			}
+			if m.Player.Mana != 30 || m.Player.Stamina != 80 || m.Player.Health != 40 || m.Player.X != 64 {
+				t.Errorf("玩家 = %+v", m.Player)
+			}
+			// 复活点留空，游戏读档时换成新游戏的复活位置（起始地图的出生点），而不是起始地图的 (0, 0)
+			if m.Respawn.Map != "" || m.Respawn.Col != 0 || m.Respawn.Row != 0 {
+				t.Errorf("复活点 = %+v，应留空", m.Respawn)
+			}
+		}},
+		{"v3 保留数值和复活点，从 1 级开始", saveV3, func(t *testing.T, m migrated) {
+			if m.Player.Mana != 7 || m.Player.Stamina != 9 || m.Respawn.ID != "village" || m.Respawn.Col != 5 {
+				t.Errorf("玩家 = %+v，复活点 = %+v", m.Player, m.Respawn)
+			}
+			if m.Progress != progression.Start() {
+				t.Errorf("进度 = %+v", m.Progress)
+			}
+		}},
+		{"v4 保留等级", saveV4, func(t *testing.T, m migrated) {
+			if m.Progress != (progression.Progress{Level: 3, XP: 12}) {
+				t.Errorf("进度 = %+v", m.Progress)
+			}
+			if len(m.Maps["start"].Enemies) != 1 {
+				t.Errorf("敌人没有移到起始地图: %+v", m.Maps)
+			}
+		}},
+		{"v5 不变", saveV5, func(t *testing.T, m migrated) {
+			if m.Map != "cave" || len(m.Maps) != 1 || !slices.Equal(m.Maps["cave"].Chests, []string{"hoard"}) {
+				t.Errorf("地图 = %s %+v", m.Map, m.Maps)
+			}
+			if m.Respawn.Map != "cave" || m.Progress.Level != 3 || m.Player.Mana != 7 {
+				t.Errorf("玩家 = %+v，复活点 = %+v", m.Player, m.Respawn)
+			}
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := Migrate([]byte(c.save), testDefaults)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var m migrated
+			if err := json.Unmarshal(data, &m); err != nil {
+				t.Fatal(err)
+			}
+			if m.Version != Version {
+				t.Errorf("版本 = %d", m.Version)
+			}
+			// 版本 5 之前的地图状态都移到起始地图
+			if m.Pickups != nil || m.Enemies != nil || m.Chests != nil {
+				t.Errorf("旧的地图状态字段没有删除: %s", data)
+			}
+			if c.save != saveV5 && (m.Map != "start" || len(m.Maps) != 1) {
+				t.Errorf("地图 = %q %+v，应只有起始地图", m.Map, m.Maps)
+			}
+			c.check(t, m)
+		})
+	}
+}
+
+// TestMigrateMaps 版本 1 中的物品、敌人和宝箱全部移到起始地图的状态中
+func TestMigrateMaps(t *testing.T) {
+	data, err := Migrate([]byte(saveV1), testDefaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m migrated
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	start := m.Maps["start"]
+	if len(start.Pickups) != 1 || start.Pickups[0].Item != 2001 || len(start.Enemies) != 1 || start.Enemies[0].Kind != "slime" ||
+		!slices.Equal(start.Chests, []string{"corner"}) {
+		t.Fatalf("起始地图 = %+v", start)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	for _, save := range []string{
+		`{"version": 6}`,
+		`{"version": 99, "player": {}}`,
+	} {
+		if _, err := Migrate([]byte(save), testDefaults); err == nil || !strings.Contains(err.Error(), "新") {
+			t.Errorf("%s: 比当前版本新的存档应被拒绝，err = %v", save, err)
+		}
+	}
+	for _, save := range []string{`not json`, `{"version": "1"}`, `{"version": 1, "items": [{"id": "x"}]}`} {
+		if _, err := Migrate([]byte(save), testDefaults); err == nil {
+			t.Errorf("%s: 损坏的存档应报错", save)
+		}
+	}
+}
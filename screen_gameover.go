@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+)
+
+// GameOverScreen 玩家死亡后的游戏结束界面，叠加在游戏画面之上
+type GameOverScreen struct {
+	respawnButtonRect [4]int      // 复活按钮 [x, y, width, height]
+	menuButtonRect    [4]int      // 返回主菜单按钮
+	play              *PlayScreen // 死亡的游戏界面
+	settings          *Settings   // 玩家设置（用于界面语言）
+	sound             *Sound      // 按钮点击音效
+}
+
+// NewGameOverScreen 构造函数
+func NewGameOverScreen(play *PlayScreen, settings *Settings, sound *Sound) *GameOverScreen {
+	return &GameOverScreen{
+		respawnButtonRect: [4]int{300, 290, 200, 40},
+		menuButtonRect:    [4]int{300, 345, 200, 40},
+		play:              play,
+		settings:          settings,
+		sound:             sound,
+	}
+}
+
+// Update 处理按钮点击，返回下一个界面状态
+func (g *GameOverScreen) Update() GameState {
+	if g.settings.isActionJustPressed(ActionInteract) {
+		g.play.respawn()
+		return StatePlay
+	}
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return StateGameOver
+	}
+	x, y := ebiten.CursorPosition()
+	switch {
+	case inRect(x, y, g.respawnButtonRect):
+		g.sound.PlaySFX(sfxClick)
+		g.play.respawn()
+		return StatePlay
+	case inRect(x, y, g.menuButtonRect): // 复活后自动保存再返回主菜单，继续游戏时不会停在死亡状态
+		g.sound.PlaySFX(sfxClick)
+		g.play.respawn()
+		g.play.saveWithNotice()
+		return StateMenu
+	}
+	return StateGameOver
+}
+
+// Draw 在变暗的游戏画面上绘制死亡信息和按钮
+func (g *GameOverScreen) Draw(screen *ebiten.Image) {
+	g.play.Draw(screen)
+	vector.DrawFilledRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), color.RGBA{R: 60, A: 170}, false)
+	drawPanel(screen, 250, 180, 300, 230)
+	drawCenteredText(screen, g.settings.T("gameOver.title"), 250, 205, 300)
+	drawCenteredText(screen, g.play.deathSummary(), 250, 240, 300)
+	drawButton(screen, g.respawnButtonRect, g.settings.T("gameOver.respawn"))
+	drawButton(screen, g.menuButtonRect, g.settings.T("gameOver.mainMenu"))
+}
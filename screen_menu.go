@@ -4,20 +4,24 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"image/color"
 )
 
 // MenuScreen 定义菜单界面结构
 type MenuScreen struct {
-	startButtonRect [4]int        // [x, y, width, height]
-	clicked         bool          // 是否被点击
-	backgroundImage *ebiten.Image // 背景图片（新增字段）
+	startButtonRect    [4]int        // [x, y, width, height]
+	settingsButtonRect [4]int        // 设置按钮 [x, y, width, height]
+	backgroundImage    *ebiten.Image // 背景图片（新增字段）
+	settings           *Settings     // 玩家设置（用于界面语言）
+	sound              *Sound        // 按钮点击音效
 }
 
 // NewMenuScreen 构造函数
-func NewMenuScreen() *MenuScreen {
+func NewMenuScreen(settings *Settings, sound *Sound) *MenuScreen {
 	m := &MenuScreen{
-		startButtonRect: [4]int{220, 200, 200, 50},
+		startButtonRect:    [4]int{220, 200, 200, 50},
+		settingsButtonRect: [4]int{220, 270, 200, 50},
+		settings:           settings,
+		sound:              sound,
 	}
 
 	// 预加载背景图片（新增代码）
@@ -30,25 +34,30 @@ func NewMenuScreen() *MenuScreen {
 	return m
 }
 
-// Update 处理鼠标点击逻辑，点击后返回 true 切换场景
-func (m *MenuScreen) Update() bool {
+// Update 处理鼠标点击逻辑，返回下一个界面状态
+func (m *MenuScreen) Update() GameState {
 	x, y := ebiten.CursorPosition()
-	btnX, btnY, btnW, btnH := m.startButtonRect[0], m.startButtonRect[1], m.startButtonRect[2], m.startButtonRect[3]
 
 	// 检测鼠标是否在按钮范围内且点击
-	if x >= btnX && x <= btnX+btnW && y >= btnY && y <= btnY+btnH {
-		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-			m.clicked = true
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if inRect(x, y, m.startButtonRect) {
+			m.sound.PlaySFX(sfxClick)
+			return StatePlay
+		}
+		if inRect(x, y, m.settingsButtonRect) {
+			m.sound.PlaySFX(sfxClick)
+			return StateSettings
 		}
 	}
 
-	return m.clicked
+	return StateMenu
 }
 
 // Draw 渲染菜单界面
 func (m *MenuScreen) Draw(screen *ebiten.Image) {
 	m.DrawBackground(screen)
 	m.DrawStartButton(screen)
+	m.DrawSettingsButton(screen)
 }
 
 // DrawBackground 绘制背景图片并使其铺满整个屏幕
@@ -76,65 +85,10 @@ func (m *MenuScreen) DrawBackground(screen *ebiten.Image) {
 
 // DrawStartButton 绘制开始按钮及其交互效果
 func (m *MenuScreen) DrawStartButton(screen *ebiten.Image) {
-	btnX, btnY, btnW, btnH := m.startButtonRect[0], m.startButtonRect[1], m.startButtonRect[2], m.startButtonRect[3]
-
-	// 检查鼠标是否在按钮上
-	x, y := ebiten.CursorPosition()
-	mouseHover := x >= btnX && x <= btnX+btnW && y >= btnY && y <= btnY+btnH
-
-	// 根据鼠标状态设置按钮颜色和大小
-	btnColor := color.RGBA{R: 100, G: 200, B: 100, A: 255}
-	scale := 1.0
-	if mouseHover {
-		btnColor = color.RGBA{R: 150, G: 255, B: 150, A: 255} // 鼠标悬停时改变颜色
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-			scale = 0.95 // 点击时缩小
-		} else {
-			scale = 1.05 // 悬停时放大
-		}
-	}
-
-	// 绘制按钮背景
-	btnImage := ebiten.NewImage(btnW, btnH)
-	btnImage.Fill(btnColor)
-
-	// 应用缩放和位置变换
-	btnOp := &ebiten.DrawImageOptions{}
-
-	if scale != 1.0 {
-		// 计算缩放后的按钮中心点
-		centerX := float64(btnX) + float64(btnW)/2
-		centerY := float64(btnY) + float64(btnH)/2
-
-		// 先平移到中心点，再缩放，最后平移回正确位置
-		btnOp.GeoM.Translate(-float64(btnW)/2, -float64(btnH)/2)
-		btnOp.GeoM.Scale(scale, scale)
-		btnOp.GeoM.Translate(centerX, centerY)
-	} else {
-		btnOp.GeoM.Translate(float64(btnX), float64(btnY))
-	}
-	screen.DrawImage(btnImage, btnOp)
-
-	// 按钮文字居中显示
-	text := "START_THE_GAME"
-	const charWidth = 6   // 假设每个字符宽度为6像素
-	const charHeight = 16 // 假设字体高度为16像素
-	textWidth := len(text) * charWidth
-	textHeight := charHeight
-
-	// 计算文字的居中位置
-	var textX, textY int
-	if scale != 1.0 {
-		// 缩放时，基于按钮中心点计算文字位置
-		centerX := float64(btnX) + float64(btnW)/2
-		centerY := float64(btnY) + float64(btnH)/2
-		textX = int(centerX - float64(textWidth)/2)
-		textY = int(centerY - float64(textHeight)/2)
-	} else {
-		// 无缩放时，直接基于按钮位置计算
-		textX = btnX + (btnW-textWidth)/2
-		textY = btnY + (btnH-textHeight)/2
-	}
+	drawButton(screen, m.startButtonRect, m.settings.T("menu.start"))
+}
 
-	ebitenutil.DebugPrintAt(screen, text, textX, textY)
+// DrawSettingsButton 绘制设置按钮
+func (m *MenuScreen) DrawSettingsButton(screen *ebiten.Image) {
+	drawButton(screen, m.settingsButtonRect, m.settings.T("menu.settings"))
 }
@@ -1,50 +1,112 @@
 package main
 
 import (
+	"Game/anim"
+	"Game/craft"
+	"Game/dialogue"
+	"Game/ecs"
+	"Game/inventory"
+	"Game/loot"
+	"Game/nav"
+	"Game/progression"
+	"Game/quest"
+	"Game/shop"
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"image/color"
-	"math"
+	"log"
+	"math/rand/v2"
+	"time"
 )
 
 // PlayScreen 游戏运行界面
 type PlayScreen struct {
-	playerX, playerY  float64       // 玩家位置坐标
-	gridSize          int           // 网格单元格大小
-	mainChar          *ebiten.Image // 玩家角色图像
-	inventoryLoaded   bool          // 背包是否需要加载
-	currentPage       int           // 当前背包页码
-	selectedItemIndex int           // 当前选中的背包物品索引
-	backgroundImage   *ebiten.Image // 背景图片（新增字段）
-	gridData          [][]int       // 网格数据数组（新增字段）
-	inventorySize     int           // 背包大小
-	items             []*item       // 背包物品数组
+	gridSize        int            // 网格单元格大小
+	mainChar        *ebiten.Image  // 玩家角色精灵图
+	playerAnim      *anim.Animator // 玩家角色动画状态机
+	backgroundImage *ebiten.Image  // 背景图片（新增字段）
+	gridData        [][]int        // 网格数据数组（新增字段）
+	settings        *Settings      // 玩家设置（按键绑定、界面语言）
+	sound           *Sound         // 音效
+	paused          bool           // 是否打开了暂停菜单
+	pauseButtons    [4][4]int      // 暂停菜单按钮：继续、保存、设置、主菜单
+	bagUI           bagPanel       // 背包界面的状态
+
+	world    *ecs.World          // 实体世界（玩家、NPC、物品等）
+	player   ecs.Entity          // 玩家实体
+	movement *ecs.MovementSystem // 移动与碰撞系统
+	notices  Notices             // 屏幕上方的临时提示
+	rng      *rand.Rand          // 战斗等使用的随机数
+	planner  *nav.Planner        // 敌人寻路（带缓存和限流）
+
+	damageNumbers DamageNumbers // 受击时飘出的伤害数字
+
+	dialogues    map[string]*dialogue.Tree // 所有对话树
+	dialog       *dialogue.Runner          // 进行中的对话，为空表示没有对话
+	dialogChoice int                       // 对话中选中的选项
+	portraits    map[string]*ebiten.Image  // 已加载的头像
+
+	quests        *quest.Log // 任务定义和进度
+	questLogOpen  bool       // 是否打开了任务日志
+	questLogIndex int        // 任务日志中选中的任务
+	playerTile    [2]int     // 玩家所在格子，变化时触发任务的到达事件
+
+	shops map[string]*shop.Shop // 所有商店
+	trade *tradeView            // 打开的商店界面，为空表示没有打开
+
+	recipes    *craft.Book // 所有合成配方
+	craftOpen  bool        // 是否打开了合成界面
+	craftIndex int         // 合成界面中选中的配方
+
+	loot loot.Tables // 所有掉落表
+
+	containers map[string]*Container // 所有容器（储物箱、银行）：id -> 容器
+	container  *containerView        // 打开的容器界面，为空表示没有打开
+
+	vitalFlashes map[VitalStat]vitalFlash // 正在闪烁的数值条
+
+	skillTree    *progression.Config // 升级曲线、属性点和技能树
+	skillEffects map[string][]Effect // 主动技能的效果
+	skillsOpen   bool                // 是否打开了技能界面
+	skillIndex   int                 // 技能界面中选中的技能
 }
 
-func NewPlayScreen() *PlayScreen {
+func NewPlayScreen(settings *Settings, sound *Sound) *PlayScreen {
 	p := &PlayScreen{
-		gridSize: 32,
-		playerX:  0,
-		playerY:  0,
+		gridSize:     32,
+		bagUI:        bagPanel{sort: inventory.SortCategory},
+		portraits:    map[string]*ebiten.Image{},
+		vitalFlashes: map[VitalStat]vitalFlash{},
+		settings:     settings,
+		sound:        sound,
+		rng:          rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+		pauseButtons: [4][4]int{
+			{300, 200, 200, 40},
+			{300, 255, 200, 40},
+			{300, 310, 200, 40},
+			{300, 365, 200, 40},
+		},
 	}
 
-	// 初始化网格数据数组
-	screenW, screenH := 800, 600 // 默认窗口大小
-	gridWidth := screenW / p.gridSize
-	gridHeight := screenH / p.gridSize
-
-	// 创建二维数组并初始化为默认值（例如0表示空地）
-	p.initGridData(gridHeight, gridWidth)
+	// 预加载主角精灵图和动画
+	p.initPlayerAnim()
+
+	// 创建实体世界和玩家实体，加载地图
+	p.initWorld()
+	p.initLoot()
+	p.initMaps()
+	p.initContainers()
+	p.initCheckpoints()
+	p.initQuests()
+	p.initShops()
+	p.initDialogues()
+	p.initCrafting()
+	p.initProgression()
 
-	// 预加载主角图片
 	var err error
-	p.mainChar, _, err = ebitenutil.NewImageFromFile("photos/zhu.png")
-	if err != nil {
-		panic(err)
-	}
 
 	// 预加载背景图片
 	p.backgroundImage, _, err = ebitenutil.NewImageFromFile("photos/playBeijing.png")
@@ -55,69 +117,208 @@ func NewPlayScreen() *PlayScreen {
 	// 初始化背包物品
 	p.initBag()
 
+	// 有存档时继续上次的进度，否则在起始地图的出生点开始新游戏
+	p.loadMap(startMap)
+	if !p.loadGame() {
+		spawn := p.currentMap().Spawn(startSpawn)
+		p.switchMap(startMap, spawn.Col, spawn.Row)
+		p.startAutoQuests()
+	}
 	return p
 }
 
-func (p *PlayScreen) Update() {
+// Update 每帧更新游戏逻辑，返回下一个界面状态
+func (p *PlayScreen) Update() GameState {
+	// 玩家死亡后由游戏结束界面处理输入
+	if p.respawner().Dead {
+		return StateGameOver
+	}
+	// 切换地图的淡入淡出期间游戏世界暂停
+	if p.atlas().Transition != nil {
+		p.updateTransition(tickDuration())
+		return StatePlay
+	}
+	// 对话期间只处理对话输入，游戏世界暂停
+	if p.dialog != nil {
+		p.updateDialogue()
+		return StatePlay
+	}
+	// 商店界面打开时游戏世界同样暂停
+	if p.trade != nil {
+		p.updateTrade()
+		return StatePlay
+	}
+	if p.craftOpen {
+		p.updateCrafting()
+		return StatePlay
+	}
+	if p.skillsOpen {
+		p.updateSkillTree()
+		return StatePlay
+	}
+
+	// 输入背包搜索文字时按键只用于输入
+	if p.bagUI.searching {
+		p.updateBagSearch()
+		return StatePlay
+	}
+
+	// 物品详情面板打开时按暂停键只关闭面板
+	if p.updateInspect() {
+		return StatePlay
+	}
+
+	// 任务日志打开时按暂停键只关闭任务日志
+	if p.questLogOpen && p.settings.isActionJustPressed(ActionPause) {
+		p.setQuestLogOpen(false)
+		return StatePlay
+	}
+
+	// 暂停菜单
+	if p.settings.isActionJustPressed(ActionPause) {
+		p.paused = !p.paused
+		return StatePlay
+	}
+	if p.paused {
+		return p.updatePauseMenu()
+	}
+
 	// 事件监听
-	if ebiten.IsKeyPressed(ebiten.KeyJ) {
-		p.MovePlayer(-1, 0)
+	var dx, dy float64
+	if p.settings.isActionPressed(ActionMoveLeft) {
+		dx--
+	}
+	if p.settings.isActionPressed(ActionMoveRight) {
+		dx++
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyL) {
-		p.MovePlayer(1, 0)
+	if p.settings.isActionPressed(ActionMoveUp) {
+		dy--
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyI) {
-		p.MovePlayer(0, -1)
+	if p.settings.isActionPressed(ActionMoveDown) {
+		dy++
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyK) {
-		p.MovePlayer(0, 1)
+	// 眩晕期间不能移动
+	if p.stunned(p.player) {
+		dx, dy = 0, 0
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+	p.updateSprint(dx, dy, tickDuration())
+	p.MovePlayer(dx, dy)
+	p.updateEnemies(tickDuration())
+	p.updateCombat(tickDuration())
+	p.updateStatuses()
+	p.updateItemCooldowns(tickDuration())
+	p.updateVitals(tickDuration())
+	p.updateWorld()
+	p.updateQuests()
+	p.updateShops()
+	p.updatePlayerAnim(dx, dy)
+	p.updatePickups()
+	p.updateCheckpoints()
+	p.updateWarps()
+	p.notices.Update(tickDuration())
+	// 世界声源的衰减以玩家中心为听者位置
+	p.sound.SetListener(p.playerCenter())
+	if p.settings.isActionJustPressed(ActionInventory) {
 		// 打开或关闭背包
-		p.inventoryLoaded = !p.inventoryLoaded
+		p.setInventoryOpen(!p.bagUI.loaded)
+	}
+	if p.settings.isActionJustPressed(ActionCraft) {
+		p.setCraftingOpen(true)
+	}
+	if p.settings.isActionJustPressed(ActionSkills) {
+		p.setSkillsOpen(true)
+	}
+	if p.settings.isActionJustPressed(ActionQuestLog) {
+		p.setQuestLogOpen(!p.questLogOpen)
+	}
+	if p.questLogOpen {
+		p.updateQuestLog()
+	}
+	p.updateHotbar()
+	p.updateSkills(tickDuration())
+	// 被敌人打倒后从下一帧开始显示游戏结束界面
+	if p.respawner().Dead {
+		return StateGameOver
 	}
 
 	// 背包物品选择逻辑
-	if p.inventoryLoaded {
+	if p.bagUI.loaded {
 		if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW) {
-			p.selectedItemIndex--
-			if p.selectedItemIndex < 0 {
-				p.selectedItemIndex = len(p.items) - 1
-			}
+			p.moveBagSelection(-1)
 		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS) {
-			p.selectedItemIndex++
-			if p.selectedItemIndex >= len(p.items) {
-				p.selectedItemIndex = 0
-			}
+			p.moveBagSelection(1)
+		}
+		if p.settings.isActionJustPressed(ActionDrop) {
+			p.dropSelectedItem()
 		}
+		if p.settings.isActionJustPressed(ActionUse) {
+			p.useSelectedItem()
+		}
+		if p.settings.isActionJustPressed(ActionInspect) && p.bagUI.selected >= 0 && p.bagUI.selected < len(p.bag().Items) {
+			p.setInspecting(p.bag().Items[p.bagUI.selected])
+		}
+		if p.container != nil {
+			p.updateContainer()
+		}
+		p.updateEquipmentPanel()
+		p.updateInventoryControls()
 	}
+	return StatePlay
 }
 
-// MovePlayer 处理角色移动逻辑
-func (p *PlayScreen) MovePlayer(dx, dy float64) {
-	// 基于实际帧时间计算速度（像素/秒）
-	speed := 96.0                              // 每秒移动96像素
-	delta := 1.0 / float64(ebiten.ActualTPS()) // 获取真实帧时间
-
-	// 更新位置
-	p.playerX += dx * speed * delta
-	p.playerY += dy * speed * delta
+// updatePauseMenu 处理暂停菜单的按钮点击
+func (p *PlayScreen) updatePauseMenu() GameState {
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return StatePlay
+	}
+	x, y := ebiten.CursorPosition()
+	switch {
+	case inRect(x, y, p.pauseButtons[0]): // 继续游戏
+		p.sound.PlaySFX(sfxClick)
+		p.paused = false
+	case inRect(x, y, p.pauseButtons[1]): // 保存游戏
+		p.sound.PlaySFX(sfxClick)
+		p.saveWithNotice()
+	case inRect(x, y, p.pauseButtons[2]): // 设置（返回时仍保持暂停）
+		p.sound.PlaySFX(sfxClick)
+		return StateSettings
+	case inRect(x, y, p.pauseButtons[3]): // 返回主菜单（自动保存）
+		p.sound.PlaySFX(sfxClick)
+		p.paused = false
+		p.saveWithNotice()
+		return StateMenu
+	}
+	return StatePlay
+}
 
-	// 限制角色在屏幕范围内
-	screenW, screenH := ebiten.WindowSize()
-	if p.playerX < 0 {
-		p.playerX = 0
+// setInventoryOpen 打开或关闭背包并播放对应音效
+func (p *PlayScreen) setInventoryOpen(open bool) {
+	if open == p.bagUI.loaded {
+		return
 	}
-	if p.playerY < 0 {
-		p.playerY = 0
+	p.bagUI.loaded = open
+	p.bagUI.inspecting = nil
+	if !open {
+		p.container = nil
 	}
-	if p.playerX > float64(screenW-32) {
-		p.playerX = float64(screenW - 32)
+	p.bagUI.searching = false
+	if open {
+		p.sound.PlaySFX(sfxInventoryOpen)
+	} else {
+		p.sound.PlaySFX(sfxInventoryClose)
 	}
-	if p.playerY > float64(screenH-32) {
-		p.playerY = float64(screenH - 32)
+}
+
+// MovePlayer 处理角色移动逻辑：设置玩家速度，实际移动和碰撞由移动系统处理
+func (p *PlayScreen) MovePlayer(dx, dy float64) {
+	vel := ecs.Get[ecs.Velocity](p.world, p.player)
+	speed := p.playerSpeed()
+	if p.playerVitals().Sprinting {
+		speed *= playerConfig.Sprint.Multiplier
 	}
+	vel.X = dx * speed
+	vel.Y = dy * speed
 }
 
 // Draw 绘制网格
@@ -125,24 +326,112 @@ func (p *PlayScreen) Draw(screen *ebiten.Image) {
 	// 绘制背景
 	p.DrawBackground(screen)
 	p.DrawGrid(screen)
-	p.DrawPlayer(screen)
+	p.drawTiles(screen)
+	p.drawWarps(screen)
+	p.drawPickupGlow(screen)
+	p.DrawEntities(screen)
+	p.drawEnemyStatuses(screen)
+	p.damageNumbers.Draw(screen)
+	p.notices.Draw(screen)
+	if !p.questLogOpen {
+		p.drawQuestTracker(screen)
+	}
+	p.drawVitals(screen)
+	p.drawXPBar(screen)
+	p.drawStatusBar(screen)
+	p.drawHotbar(screen)
+	p.drawSkillBar(screen)
+	p.drawWallet(screen)
+	if p.dialog != nil {
+		p.drawDialogue(screen)
+	}
+	if p.trade != nil {
+		p.drawTrade(screen)
+	}
+	if p.craftOpen {
+		p.drawCrafting(screen)
+	}
+	if p.skillsOpen {
+		p.drawSkillTree(screen)
+	}
 
 	// 判断是否需要渲染背包
-	if p.inventoryLoaded {
+	p.bagUI.hovered = nil
+	if p.bagUI.loaded {
 		p.DrawInventory(screen)
+		p.drawEquipmentPanel(screen)
+		if p.container != nil {
+			p.drawContainer(screen)
+		}
+		if p.bagUI.inspecting != nil {
+			p.drawInspect(screen)
+		} else {
+			p.drawItemTooltip(screen)
+		}
+	}
+	if p.questLogOpen {
+		p.drawQuestLog(screen)
+	}
+
+	if p.paused {
+		p.DrawPauseMenu(screen)
 	}
+	p.drawTransition(screen)
 }
 
-// DrawPlayer 绘制玩家
-func (p *PlayScreen) DrawPlayer(screen *ebiten.Image) {
-	// 绘制角色（使用浮点坐标）
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Scale(
-		32.0/float64(p.mainChar.Bounds().Dx()),
-		32.0/float64(p.mainChar.Bounds().Dy()),
-	)
-	op.GeoM.Translate(p.playerX, p.playerY) // 直接使用浮点数
-	screen.DrawImage(p.mainChar, op)
+// DrawPauseMenu 绘制暂停菜单
+func (p *PlayScreen) DrawPauseMenu(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), color.RGBA{A: 120}, false)
+	drawPanel(screen, 270, 140, 260, 290)
+	drawCenteredText(screen, p.settings.T("pause.title"), 270, 160, 260)
+
+	labels := [4]string{"pause.resume", "pause.save", "pause.settings", "pause.mainMenu"}
+	for i, rect := range p.pauseButtons {
+		drawButton(screen, rect, p.settings.T(labels[i]))
+	}
+}
+
+// staticAttack 没有精灵图时攻击动作的时长
+const staticAttack = 300 * time.Millisecond
+
+// initPlayerAnim 加载主角精灵图（Aseprite 导出）和动画，失败时退回单帧静态图片
+func (p *PlayScreen) initPlayerAnim() {
+	sheet, err := anim.LoadAseprite("photos/zhu_sheet.json")
+	if err != nil {
+		log.Printf("加载主角动画失败，使用静态图片: %v", err)
+		p.mainChar = loadImage("photos/zhu.png")
+		w, h := p.mainChar.Bounds().Dx(), p.mainChar.Bounds().Dy()
+		// 静态图片没有攻击动作：用同一帧做一段短的攻击动画，保证 hit 事件照常触发
+		attack := anim.GridClip("attack", w, h, 0, 0, 1, staticAttack, false)
+		attack.AddEvent(0, "hit")
+		sheet = &anim.Sheet{Clips: map[string]*anim.Clip{
+			"idle":   anim.GridClip("idle", w, h, 0, 0, 1, 0, true),
+			"attack": attack,
+		}}
+	} else {
+		p.mainChar = loadImage(sheet.Image)
+	}
+	p.playerAnim = anim.NewAnimator(sheet)
+}
+
+// updatePlayerAnim 根据移动方向和攻击输入驱动主角动画，并处理动画帧事件
+func (p *PlayScreen) updatePlayerAnim(dx, dy float64) {
+	p.playerAnim.SetMovement(dx, dy)
+	if p.settings.isActionJustPressed(ActionAttack) {
+		p.tryPlayerAttack()
+	}
+	for _, event := range p.playerAnim.Update(tickDuration()) {
+		switch event {
+		case "footstep":
+			pos := p.playerPos()
+			p.sound.PlaySFXAt(sfxFootstep, pos.X+playerSize/2, pos.Y+playerSize)
+		case "hit":
+			p.playerStrike()
+		}
+	}
+
+	// 玩家精灵显示当前动画帧
+	ecs.Get[ecs.Sprite](p.world, p.player).Rect = p.playerAnim.Frame().Rect
 }
 
 // DrawGrid 绘制网格
@@ -161,13 +450,12 @@ func (p *PlayScreen) DrawGrid(screen *ebiten.Image) {
 
 // DrawInventory 绘制背包
 func (p *PlayScreen) DrawInventory(screen *ebiten.Image) {
-	inventoryWidth := 300
-	inventoryHeight := 400
-	inventoryX := (screen.Bounds().Dx() - inventoryWidth) / 2
-	inventoryY := (screen.Bounds().Dy() - inventoryHeight) / 2
+	r := inventoryRect()
+	inventoryX, inventoryY, inventoryWidth, inventoryHeight := r[0], r[1], r[2], r[3]
 
 	p.drawInventoryBackground(screen, inventoryX, inventoryY, inventoryWidth, inventoryHeight)
 	p.drawInventoryHeader(screen, inventoryX, inventoryY, inventoryWidth)
+	p.drawInventoryControls(screen)
 	p.drawInventoryItems(screen, inventoryX, inventoryY, inventoryWidth, inventoryHeight)
 	p.drawInventoryPagination(screen, inventoryX, inventoryY, inventoryWidth, inventoryHeight)
 	p.drawInventoryCloseButton(screen, inventoryX, inventoryY, inventoryWidth, inventoryHeight)
@@ -194,84 +482,23 @@ func (p *PlayScreen) drawInventoryHeader(screen *ebiten.Image, inventoryX, inven
 	vector.DrawFilledRect(screen, float32(inventoryX+10), float32(titleY+20), float32(inventoryWidth-20), 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
 }
 
-// drawInventoryItems 绘制物品列表
+// drawInventoryItems 绘制当前页筛选和排序后的物品
 func (p *PlayScreen) drawInventoryItems(screen *ebiten.Image, inventoryX, inventoryY, inventoryWidth, inventoryHeight int) {
-	itemStartX := inventoryX + 20
-	itemStartY := inventoryY + 60 // 15 (标题) + 20 (分隔线) + 25 (间距)
-	itemWidth := 48
-	itemHeight := 48
-	itemSpacing := 15
-
-	// 计算每行最多显示的物品数量
-	itemsPerRow := (inventoryWidth - 40) / (itemWidth + itemSpacing)
-
-	// 当前页码和每页显示的物品数
-	itemsPerPage := itemsPerRow * 5 // 假设最多显示5行
-	currentPage := p.currentPage
-	startIndex := currentPage * itemsPerPage
-	endIndex := startIndex + itemsPerPage
+	// 当前页码和每页显示的物品数（筛选后物品变少时退回到最后一页）
+	view := p.bagView()
+	itemsPerPage := bagPerPage()
+	p.bagUI.page = min(p.bagUI.page, inventory.PageCount(len(view), itemsPerPage)-1)
+	currentPage := p.bagUI.page
+	endIndex := (currentPage + 1) * itemsPerPage
 
 	cursorX, cursorY := ebiten.CursorPosition()
 
-	for i := startIndex; i < endIndex && i < len(p.items); i++ {
-		item := p.items[i]
-		// 计算物品在当前页面的位置
-		localIndex := i - startIndex
-		row := localIndex / itemsPerRow
-		col := localIndex % itemsPerRow
-		x := itemStartX + col*(itemWidth+itemSpacing)
-		y := itemStartY + row*(itemHeight+itemSpacing)
-
-		// 确保物品不会超出背包边界
-		if x+itemWidth > inventoryX+inventoryWidth-20 {
-			continue
-		}
-
-		// 绘制物品背景阴影
-		vector.DrawFilledRect(screen, float32(x+2), float32(y+2), float32(itemWidth), float32(itemHeight), color.RGBA{A: 100}, false)
-
-		// 如果是选中的物品，绘制高亮背景
-		if i == p.selectedItemIndex {
-			// 绘制选中效果的发光边框
-			for j := 0; j < 3; j++ {
-				vector.StrokeRect(screen, float32(x-j), float32(y-j), float32(itemWidth+2*j), float32(itemHeight+2*j), 1, color.RGBA{R: 100, G: 150, B: 255, A: uint8(150 - j*50)}, false)
-			}
-		}
-
-		// 绘制物品背景框
-		vector.DrawFilledRect(screen, float32(x), float32(y), float32(itemWidth), float32(itemHeight), color.RGBA{R: 50, G: 50, B: 70, A: 200}, false)
-		vector.StrokeRect(screen, float32(x), float32(y), float32(itemWidth), float32(itemHeight), 1, color.RGBA{R: 150, G: 150, B: 200, A: 255}, false)
-
-		// 绘制物品图片
-		if item.ItemData.Image != nil {
-			op := &ebiten.DrawImageOptions{}
-			// 缩放图片以适应物品框，保持一定边距
-			imgBounds := item.ItemData.Image.Bounds()
-			scaleX := float64(itemWidth-8) / float64(imgBounds.Dx())
-			scaleY := float64(itemHeight-8) / float64(imgBounds.Dy())
-			finalScale := math.Min(scaleX, scaleY) // 保持图片比例
-			op.GeoM.Scale(finalScale, finalScale)
-			op.GeoM.Translate(float64(x)+(float64(itemWidth)-float64(imgBounds.Dx())*finalScale)/2,
-				float64(y)+(float64(itemHeight)-float64(imgBounds.Dy())*finalScale)/2)
-			screen.DrawImage(item.ItemData.Image, op)
-		}
-
-		// 绘制物品数量（右下角）
-		if item.count > 1 {
-			countText := fmt.Sprintf("%d", item.count)
-			textWidth := len(countText) * 6
-			// 绘制数量背景
-			vector.DrawFilledRect(screen, float32(x+itemWidth-textWidth-4), float32(y+itemHeight-16), float32(textWidth+4), 16, color.RGBA{A: 200}, false)
-			ebitenutil.DebugPrintAt(screen, countText, x+itemWidth-textWidth-2, y+itemHeight-14)
-		}
-
-		// 绘制物品名称（鼠标悬停时显示）
-		if cursorX >= x && cursorX <= x+itemWidth && cursorY >= y && cursorY <= y+itemHeight {
-			// 绘制名称背景
-			nameWidth := len(item.ItemData.Name)*6 + 4
-			nameX := x + (itemWidth-nameWidth)/2
-			vector.DrawFilledRect(screen, float32(nameX), float32(y-20), float32(nameWidth), 16, color.RGBA{A: 200}, false)
-			ebitenutil.DebugPrintAt(screen, item.ItemData.Name, nameX+2, y-18)
+	// 鼠标悬停时显示物品提示框（在背包和装备面板之后绘制），右键打开物品详情
+	if i := p.drawItemGrid(screen, bagGrid(), p.bag().Items, view, currentPage, p.bagUI.selected); i >= 0 {
+		p.bagUI.hovered = p.bag().Items[i]
+		if p.bagUI.inspecting == nil && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+			p.bagUI.selected = i
+			p.setInspecting(p.bag().Items[i])
 		}
 	}
 
@@ -283,7 +510,7 @@ func (p *PlayScreen) drawInventoryItems(screen *ebiten.Image, inventoryX, invent
 		if cursorX >= prevBtnX && cursorX <= prevBtnX+60 &&
 			cursorY >= prevBtnY && cursorY <= prevBtnY+20 {
 			if currentPage > 0 {
-				p.currentPage--
+				p.bagUI.page--
 			}
 		}
 		// 下一页按钮点击
@@ -291,9 +518,8 @@ func (p *PlayScreen) drawInventoryItems(screen *ebiten.Image, inventoryX, invent
 		nextBtnY := inventoryY + inventoryHeight - 30
 		if cursorX >= nextBtnX && cursorX <= nextBtnX+60 &&
 			cursorY >= nextBtnY && cursorY <= nextBtnY+20 {
-			totalItems := len(p.items)
-			if endIndex < totalItems {
-				p.currentPage++
+			if endIndex < len(view) {
+				p.bagUI.page++
 			}
 		}
 	}
@@ -313,15 +539,10 @@ func (p *PlayScreen) drawInventoryPagination(screen *ebiten.Image, inventoryX, i
 	vector.DrawFilledRect(screen, float32(nextBtnX), float32(nextBtnY), 60, 20, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
 	ebitenutil.DebugPrintAt(screen, "Next", nextBtnX+15, nextBtnY+5)
 
-	// 绘制分页信息和背包容量信息
-	itemsPerRow := (inventoryWidth - 40) / (48 + 15)
-	itemsPerPage := itemsPerRow * 5 // 假设最多显示5行
-	currentPage := p.currentPage
-	totalItems := len(p.items)
-	totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage // 向上取整计算总页数
-	if totalPages == 0 {
-		totalPages = 1 // 至少有一页
-	}
+	// 绘制分页信息和背包容量信息，页数按筛选后的物品计算
+	currentPage := p.bagUI.page
+	totalItems := len(p.bag().Items)
+	totalPages := inventory.PageCount(len(p.bagView()), bagPerPage())
 
 	// 显示当前页码和总页数
 	pageInfoText := fmt.Sprintf("Page %d/%d", currentPage+1, totalPages)
@@ -330,7 +551,7 @@ func (p *PlayScreen) drawInventoryPagination(screen *ebiten.Image, inventoryX, i
 	ebitenutil.DebugPrintAt(screen, pageInfoText, pageInfoX, pageInfoY)
 
 	// 显示背包容量信息
-	capacityInfoText := fmt.Sprintf("Items: %d/%d", totalItems, p.inventorySize)
+	capacityInfoText := fmt.Sprintf("Items: %d/%d", totalItems, p.bag().Size)
 	capacityInfoX := inventoryX + inventoryWidth - len(capacityInfoText)*6 - 20
 	capacityInfoY := pageInfoY
 	ebitenutil.DebugPrintAt(screen, capacityInfoText, capacityInfoX, capacityInfoY)
@@ -350,12 +571,12 @@ func (p *PlayScreen) drawInventoryCloseButton(screen *ebiten.Image, inventoryX,
 	ebitenutil.DebugPrintAt(screen, "X", closeBtnX+7, closeBtnY+2)
 
 	// 检查关闭按钮点击
-	if p.inventoryLoaded {
+	if p.bagUI.loaded {
 		cursorX, cursorY := ebiten.CursorPosition()
 		if cursorX >= closeBtnX && cursorX <= closeBtnX+closeBtnSize &&
 			cursorY >= closeBtnY && cursorY <= closeBtnY+closeBtnSize &&
 			inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-			p.inventoryLoaded = false
+			p.setInventoryOpen(false)
 		}
 	}
 }
@@ -393,14 +614,11 @@ func (p *PlayScreen) initGridData(height int, width int) {
 	}
 }
 
-// 初始化背包物品 金币 50000
+// 初始化背包物品，钱包中放入金币 50000
 func (p *PlayScreen) initBag() {
-	p.inventorySize = 5
-	p.items = []*item{
-		{
-			ItemData: ItemImages[1001], // 金币
-			count:    50000,
-		},
+	p.bag().Size = 5
+	p.purse().Wallet.Load(map[int64]int64{1001: 50000}) // 金币
+	p.bag().Items = []*item{
 		{
 			ItemData: ItemImages[1002], // 新手剑
 			count:    1000,
@@ -410,4 +628,7 @@ func (p *PlayScreen) initBag() {
 			count:    1,
 		},
 	}
+	for _, it := range p.bag().Items {
+		it.acquired = p.nextAcquired()
+	}
 }
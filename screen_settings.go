@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"math"
+)
+
+// settingsRow 设置界面中的一行
+type settingsRow struct {
+	label    func() string   // 左侧名称
+	value    func() string   // 右侧当前值
+	change   func(delta int) // 左右键修改（可为空）
+	activate func()          // 回车或鼠标点击（可为空，为空时等同 change(1)）
+}
+
+// SettingsScreen 设置界面：画面、音频、语言与按键绑定
+type SettingsScreen struct {
+	settings  *Settings     // 正在编辑的设置
+	onChange  func()        // 设置变化后立即应用的回调
+	rows      []settingsRow // 所有设置项
+	selected  int           // 当前选中的行
+	scroll    int           // 第一行可见的设置项下标
+	rebinding Action        // 正在等待新按键的动作（为空表示未在绑定）
+	done      bool          // 是否点击了返回
+	panelRect [4]int        // 面板位置 [x, y, width, height]
+}
+
+const (
+	settingsRowHeight   = 22 // 每行高度
+	settingsRowsY       = 50 // 第一行相对面板顶部的偏移
+	settingsVisibleRows = 18 // 一屏最多显示的行数，超出时随选中行滚动
+)
+
+// NewSettingsScreen 构造函数，onChange 在每次修改设置后调用
+func NewSettingsScreen(settings *Settings, onChange func()) *SettingsScreen {
+	s := &SettingsScreen{
+		settings:  settings,
+		onChange:  onChange,
+		panelRect: [4]int{140, 40, 520, 500},
+	}
+	s.initRows()
+	return s
+}
+
+// initRows 构建设置项列表
+func (s *SettingsScreen) initRows() {
+	st := s.settings
+	onOff := func(v bool) string {
+		if v {
+			return st.T("common.on")
+		}
+		return st.T("common.off")
+	}
+	volume := func(v *float64) settingsRow {
+		return settingsRow{
+			value: func() string { return fmt.Sprintf("%3d%%", int(math.Round(*v*100))) },
+			change: func(delta int) {
+				*v = clamp01(math.Round((*v+float64(delta)*0.1)*10) / 10)
+			},
+		}
+	}
+	label := func(key string) func() string {
+		return func() string { return st.T(key) }
+	}
+
+	s.rows = []settingsRow{
+		{
+			label:  label("settings.fullscreen"),
+			value:  func() string { return onOff(st.Fullscreen) },
+			change: func(int) { st.Fullscreen = !st.Fullscreen },
+		},
+		{
+			label: label("settings.windowSize"),
+			value: func() string {
+				size := windowSizes[st.WindowSize]
+				return fmt.Sprintf("%dx%d", size[0], size[1])
+			},
+			change: func(delta int) {
+				st.WindowSize = (st.WindowSize + delta + len(windowSizes)) % len(windowSizes)
+			},
+		},
+		{
+			label:  label("settings.vsync"),
+			value:  func() string { return onOff(st.VSync) },
+			change: func(int) { st.VSync = !st.VSync },
+		},
+		{
+			label:  label("settings.showFPS"),
+			value:  func() string { return onOff(st.ShowFPS) },
+			change: func(int) { st.ShowFPS = !st.ShowFPS },
+		},
+	}
+
+	master := volume(&st.MasterVolume)
+	master.label = label("settings.master")
+	music := volume(&st.MusicVolume)
+	music.label = label("settings.music")
+	sfx := volume(&st.SFXVolume)
+	sfx.label = label("settings.sfx")
+	s.rows = append(s.rows, master, music, sfx, settingsRow{
+		label:  label("settings.mute"),
+		value:  func() string { return onOff(st.Muted) },
+		change: func(int) { st.Muted = !st.Muted },
+	})
+
+	s.rows = append(s.rows, settingsRow{
+		label: label("settings.language"),
+		value: func() string { return st.T("language.name") },
+		change: func(delta int) {
+			index := 0
+			for i, lang := range languageOrder {
+				if lang == st.Language {
+					index = i
+				}
+			}
+			index = (index + delta + len(languageOrder)) % len(languageOrder)
+			st.Language = languageOrder[index]
+		},
+	})
+
+	// 按键绑定
+	for _, action := range actionOrder {
+		s.rows = append(s.rows, settingsRow{
+			label: label("action." + string(action)),
+			value: func() string {
+				if s.rebinding == action {
+					return st.T("settings.pressKey")
+				}
+				return st.Key(action).String()
+			},
+			activate: func() { s.rebinding = action },
+		})
+	}
+
+	s.rows = append(s.rows, settingsRow{
+		label:    label("settings.back"),
+		value:    func() string { return "" },
+		activate: func() { s.done = true },
+	})
+}
+
+// Update 处理设置界面输入，返回 true 表示离开设置界面
+func (s *SettingsScreen) Update() bool {
+	s.done = false
+
+	// 等待新按键
+	if s.rebinding != "" {
+		s.updateRebinding()
+		return false
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.done = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		s.selected = (s.selected - 1 + len(s.rows)) % len(s.rows)
+		s.scrollToSelected()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		s.selected = (s.selected + 1) % len(s.rows)
+		s.scrollToSelected()
+	}
+	if _, wheelY := ebiten.Wheel(); wheelY != 0 {
+		s.scroll -= int(wheelY)
+		s.scroll = min(max(s.scroll, 0), max(len(s.rows)-settingsVisibleRows, 0))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		s.changeRow(s.selected, -1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		s.changeRow(s.selected, 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		s.activateRow(s.selected)
+	}
+
+	// 鼠标点击选中并激活
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		for i := s.scroll; i < s.lastVisibleRow(); i++ {
+			if inRect(x, y, s.rowRect(i)) {
+				s.selected = i
+				s.activateRow(i)
+			}
+		}
+	}
+
+	if s.done {
+		if err := s.settings.Save(); err != nil {
+			log.Printf("保存设置失败: %v", err)
+		}
+	}
+	return s.done
+}
+
+// updateRebinding 捕获下一个按下的键并绑定到当前动作
+func (s *SettingsScreen) updateRebinding() {
+	keys := inpututil.AppendJustPressedKeys(nil)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[0]
+
+	// Esc 取消绑定（暂停动作除外，允许把暂停重新绑回 Esc）
+	if key == ebiten.KeyEscape && s.rebinding != ActionPause {
+		s.rebinding = ""
+		return
+	}
+
+	// 若该键已被其他动作占用，则交换两者的绑定
+	bindings := s.settings.KeyBindings
+	for action, bound := range bindings {
+		if bound == key && action != s.rebinding {
+			bindings[action] = bindings[s.rebinding]
+		}
+	}
+	bindings[s.rebinding] = key
+	s.rebinding = ""
+	s.onChange()
+}
+
+// changeRow 左右键修改设置项
+func (s *SettingsScreen) changeRow(index, delta int) {
+	row := s.rows[index]
+	if row.change == nil {
+		return
+	}
+	row.change(delta)
+	s.onChange()
+}
+
+// activateRow 回车或点击设置项
+func (s *SettingsScreen) activateRow(index int) {
+	row := s.rows[index]
+	if row.activate != nil {
+		row.activate()
+		return
+	}
+	s.changeRow(index, 1)
+}
+
+// scrollToSelected 滚动列表使选中行可见
+func (s *SettingsScreen) scrollToSelected() {
+	if s.selected < s.scroll {
+		s.scroll = s.selected
+	}
+	if s.selected >= s.scroll+settingsVisibleRows {
+		s.scroll = s.selected - settingsVisibleRows + 1
+	}
+}
+
+// lastVisibleRow 返回最后一个可见行的下一行下标
+func (s *SettingsScreen) lastVisibleRow() int {
+	return min(s.scroll+settingsVisibleRows, len(s.rows))
+}
+
+// rowRect 返回第 index 行的点击区域（按当前滚动位置计算）
+func (s *SettingsScreen) rowRect(index int) [4]int {
+	x, y, w := s.panelRect[0], s.panelRect[1], s.panelRect[2]
+	return [4]int{x + 20, y + settingsRowsY + (index-s.scroll)*settingsRowHeight, w - 40, settingsRowHeight - 2}
+}
+
+// Draw 渲染设置界面
+func (s *SettingsScreen) Draw(screen *ebiten.Image) {
+	// 压暗背后的画面
+	vector.DrawFilledRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), color.RGBA{A: 150}, false)
+
+	x, y, w, h := s.panelRect[0], s.panelRect[1], s.panelRect[2], s.panelRect[3]
+	drawPanel(screen, x, y, w, h)
+
+	// 标题和分隔线
+	drawCenteredText(screen, s.settings.T("settings.title"), x, y+15, w)
+	vector.DrawFilledRect(screen, float32(x+10), float32(y+35), float32(w-20), 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+
+	for i := s.scroll; i < s.lastVisibleRow(); i++ {
+		row := s.rows[i]
+		r := s.rowRect(i)
+		if i == s.selected {
+			vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), color.RGBA{R: 100, G: 150, B: 255, A: 80}, false)
+		}
+		ebitenutil.DebugPrintAt(screen, row.label(), r[0]+6, r[1]+3)
+		value := row.value()
+		if row.change != nil && i == s.selected {
+			value = "< " + value + " >"
+		}
+		ebitenutil.DebugPrintAt(screen, value, r[0]+r[2]-len(value)*6-6, r[1]+3)
+	}
+
+	// 还有未显示的行时提示可以滚动
+	if s.scroll > 0 {
+		drawCenteredText(screen, "^", x, y+settingsRowsY-14, w)
+	}
+	if s.lastVisibleRow() < len(s.rows) {
+		drawCenteredText(screen, "v", x, y+settingsRowsY+settingsVisibleRows*settingsRowHeight-4, w)
+	}
+
+	// 底部操作提示
+	drawCenteredText(screen, s.settings.T("settings.hint"), x, y+h-25, w)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/hajimehoshi/ebiten/v2"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// configDirName 用户配置目录下本游戏使用的子目录名
+const configDirName = "JiaGame"
+
+// windowSizes 可选的窗口尺寸（逻辑分辨率固定为 800x600，只影响窗口缩放）
+var windowSizes = [][2]int{
+	{800, 600},
+	{1024, 768},
+	{1200, 900},
+	{1600, 1200},
+}
+
+// Settings 玩家设置：画面、音频、语言和按键绑定
+type Settings struct {
+	Fullscreen   bool                  `json:"fullscreen"`
+	WindowSize   int                   `json:"windowSize"` // windowSizes 中的下标
+	VSync        bool                  `json:"vsync"`
+	ShowFPS      bool                  `json:"showFPS"`
+	MasterVolume float64               `json:"masterVolume"` // 0~1
+	MusicVolume  float64               `json:"musicVolume"`  // 0~1
+	SFXVolume    float64               `json:"sfxVolume"`    // 0~1
+	Muted        bool                  `json:"muted"`
+	Language     string                `json:"language"`
+	KeyBindings  map[Action]ebiten.Key `json:"keyBindings"`
+}
+
+// DefaultSettings 返回默认设置
+func DefaultSettings() *Settings {
+	return &Settings{
+		WindowSize:   0,
+		VSync:        true,
+		ShowFPS:      true,
+		MasterVolume: 1,
+		MusicVolume:  0.8,
+		SFXVolume:    0.8,
+		Language:     "en",
+		KeyBindings:  defaultKeyBindings(),
+	}
+}
+
+// settingsPath 返回设置文件路径
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName, "settings.json"), nil
+}
+
+// LoadSettings 从用户配置目录读取设置，文件不存在或损坏时返回默认设置
+func LoadSettings() *Settings {
+	s := DefaultSettings()
+	path, err := settingsPath()
+	if err != nil {
+		log.Printf("无法获取配置目录: %v", err)
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("读取设置失败 %s: %v", path, err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		log.Printf("解析设置失败 %s: %v", path, err)
+		return DefaultSettings()
+	}
+	s.normalize()
+	return s
+}
+
+// Save 将设置写入用户配置目录
+func (s *Settings) Save() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// normalize 修正越界的值，并为旧设置文件补齐新增的按键绑定
+func (s *Settings) normalize() {
+	if s.WindowSize < 0 || s.WindowSize >= len(windowSizes) {
+		s.WindowSize = 0
+	}
+	s.MasterVolume = clamp01(s.MasterVolume)
+	s.MusicVolume = clamp01(s.MusicVolume)
+	s.SFXVolume = clamp01(s.SFXVolume)
+	if _, ok := translations[s.Language]; !ok {
+		s.Language = "en"
+	}
+	if s.KeyBindings == nil {
+		s.KeyBindings = map[Action]ebiten.Key{}
+	}
+	for action, key := range defaultKeyBindings() {
+		if _, ok := s.KeyBindings[action]; !ok {
+			s.KeyBindings[action] = key
+		}
+	}
+}
+
+// Apply 立即应用画面相关的设置
+func (s *Settings) Apply() {
+	size := windowSizes[s.WindowSize]
+	ebiten.SetWindowSize(size[0], size[1])
+	ebiten.SetFullscreen(s.Fullscreen)
+	ebiten.SetVsyncEnabled(s.VSync)
+}
+
+// Key 返回动作绑定的按键
+func (s *Settings) Key(action Action) ebiten.Key {
+	return s.KeyBindings[action]
+}
+
+// clamp01 将数值限制在 0~1 之间
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
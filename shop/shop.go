@@ -0,0 +1,295 @@
+// Package shop 商店：每个商店的货物表、价格倍率、限量库存与补货，以及原子的买卖交易
+// 每笔买卖通过 inventory.Inventory 的 Exchange 一次交换货币和物品，失败时背包和库存都不变；库存和补货计时可以存档
+package shop
+
+import (
+	"Game/inventory"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// 交易失败的原因
+var (
+	ErrSoldOut       = errors.New("shop: 已售罄")
+	ErrNotEnoughGold = errors.New("shop: 金币不足")
+	ErrNotBuying     = errors.New("shop: 商店不收购该物品")
+	ErrQuantity      = errors.New("shop: 数量无效")
+)
+
+// Entry 商店货物表中的一项
+type Entry struct {
+	Item int64 `json:"item"`
+	Max  int   `json:"max"` // 库存上限，0 表示不限量
+}
+
+// Def 商店定义
+type Def struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Currency       int64   `json:"currency"`       // 用作货币的物品 id
+	BuyMultiplier  float64 `json:"buyMultiplier"`  // 玩家购买价格 = 物品价格 × 倍率
+	SellMultiplier float64 `json:"sellMultiplier"` // 玩家出售价格 = 物品价格 × 倍率
+	RestockSeconds float64 `json:"restockSeconds"` // 每隔多少秒所有未满的货物补充一个
+	Stock          []Entry `json:"stock"`
+	Buys           []int64 `json:"buys"` // 收购的物品，为空表示收购所有有价格的物品
+}
+
+// Load 读取商店数据文件
+func Load(path string) ([]*Def, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []*Def
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// Catalog 校验时使用的物品目录：Price 返回物品价格，物品不存在时 ok 为 false
+type Catalog struct {
+	Price func(id int64) (price int64, ok bool)
+}
+
+// Validate 检查商店定义，返回所有发现的问题
+func Validate(defs []*Def, catalog Catalog) []error {
+	var errs []error
+	seen := map[string]bool{}
+	for _, d := range defs {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("商店 %s: "+format, append([]any{d.ID}, args...)...))
+		}
+		if d.ID == "" {
+			fail("缺少 id")
+		}
+		if seen[d.ID] {
+			fail("id 重复")
+		}
+		seen[d.ID] = true
+		if d.BuyMultiplier <= 0 || d.SellMultiplier < 0 {
+			fail("价格倍率无效")
+		}
+		if d.RestockSeconds <= 0 {
+			fail("restockSeconds 必须大于 0")
+		}
+		if catalog.Price == nil {
+			continue
+		}
+		if _, ok := catalog.Price(d.Currency); !ok {
+			fail("货币物品 %d 不存在", d.Currency)
+		}
+		items := map[int64]bool{}
+		for _, e := range d.Stock {
+			price, ok := catalog.Price(e.Item)
+			switch {
+			case !ok:
+				fail("货物 %d 不存在", e.Item)
+			case price <= 0:
+				fail("货物 %d 没有价格", e.Item)
+			case e.Item == d.Currency:
+				fail("不能出售货币")
+			case items[e.Item]:
+				fail("货物 %d 重复", e.Item)
+			case e.Max < 0:
+				fail("货物 %d 的库存上限不能为负数", e.Item)
+			}
+			items[e.Item] = true
+		}
+		for _, id := range d.Buys {
+			if _, ok := catalog.Price(id); !ok {
+				fail("收购的物品 %d 不存在", id)
+			}
+		}
+	}
+	return errs
+}
+
+// Shop 商店的运行状态：当前库存和补货计时
+type Shop struct {
+	Def     *Def
+	price   func(id int64) int64
+	stock   []int         // 与 Def.Stock 对应的当前库存
+	elapsed time.Duration // 距离上次补货的时间
+}
+
+// New 创建满库存的商店，price 返回物品在目录中的价格
+func New(def *Def, price func(id int64) int64) *Shop {
+	s := &Shop{Def: def, price: price, stock: make([]int, len(def.Stock))}
+	for i, e := range def.Stock {
+		s.stock[i] = e.Max
+	}
+	return s
+}
+
+// Stock 返回第 i 项货物的当前库存，unlimited 表示不限量
+func (s *Shop) Stock(i int) (count int, unlimited bool) {
+	return s.stock[i], s.Def.Stock[i].Max == 0
+}
+
+// BuyPrice 玩家购买一个物品的价格（至少为 1）
+func (s *Shop) BuyPrice(item int64) int64 {
+	return max(int64(math.Round(float64(s.price(item))*s.Def.BuyMultiplier)), 1)
+}
+
+// SellPrice 玩家出售一个物品的价格，商店不收购时为 0
+func (s *Shop) SellPrice(item int64) int64 {
+	if item == s.Def.Currency || !s.buys(item) {
+		return 0
+	}
+	return int64(math.Floor(float64(s.price(item)) * s.Def.SellMultiplier))
+}
+
+// buys 商店是否收购该物品
+func (s *Shop) buys(item int64) bool {
+	if len(s.Def.Buys) == 0 {
+		return true
+	}
+	for _, id := range s.Def.Buys {
+		if id == item {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxBuy 第 i 项货物当前最多能买多少个（受库存和金币限制）
+func (s *Shop) MaxBuy(inv inventory.Inventory, i int) int64 {
+	n := inv.CountItem(s.Def.Currency) / s.BuyPrice(s.Def.Stock[i].Item)
+	if count, unlimited := s.Stock(i); !unlimited {
+		n = min(n, int64(count))
+	}
+	return n
+}
+
+// Buy 购买第 i 项货物 qty 个，成功时返回花费的金币
+// 金币和物品的变化通过 inventory.Inventory.Exchange 一次完成，失败时背包和库存都不变
+func (s *Shop) Buy(inv inventory.Inventory, i int, qty int64) (int64, error) {
+	if qty <= 0 {
+		return 0, ErrQuantity
+	}
+	entry := s.Def.Stock[i]
+	if count, unlimited := s.Stock(i); !unlimited && int64(count) < qty {
+		return 0, ErrSoldOut
+	}
+	cost, ok := mul(s.BuyPrice(entry.Item), qty)
+	if !ok || inv.CountItem(s.Def.Currency) < cost {
+		return 0, ErrNotEnoughGold
+	}
+	err := inv.Exchange([]inventory.Stack{{Item: s.Def.Currency, Count: cost}}, []inventory.Stack{{Item: entry.Item, Count: qty}})
+	if err != nil {
+		return 0, err
+	}
+	if entry.Max > 0 {
+		s.stock[i] -= int(qty)
+	}
+	return cost, nil
+}
+
+// Sell 出售 qty 个物品，成功时返回得到的金币
+// 商店货物表中有该物品时，卖出的物品会补充库存（不超过上限）
+func (s *Shop) Sell(inv inventory.Inventory, item, qty int64) (int64, error) {
+	if qty <= 0 {
+		return 0, ErrQuantity
+	}
+	price := s.SellPrice(item)
+	if price <= 0 {
+		return 0, ErrNotBuying
+	}
+	if inv.CountItem(item) < qty {
+		return 0, inventory.ErrMissing
+	}
+	earned, ok := mul(price, qty)
+	if !ok {
+		return 0, ErrQuantity
+	}
+	err := inv.Exchange([]inventory.Stack{{Item: item, Count: qty}}, []inventory.Stack{{Item: s.Def.Currency, Count: earned}})
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range s.Def.Stock {
+		if e.Item == item && e.Max > 0 {
+			s.stock[i] = int(min(int64(s.stock[i])+qty, int64(e.Max)))
+		}
+	}
+	return earned, nil
+}
+
+// Update 推进补货计时：每隔 RestockSeconds 秒所有未满的货物补充一个，库存都满时不计时
+func (s *Shop) Update(dt time.Duration) {
+	if s.full() {
+		s.elapsed = 0
+		return
+	}
+	s.elapsed += dt
+	interval := s.restockInterval()
+	for s.elapsed >= interval && !s.full() {
+		s.elapsed -= interval
+		for i, e := range s.Def.Stock {
+			if s.stock[i] < e.Max {
+				s.stock[i]++
+			}
+		}
+	}
+}
+
+// RestockIn 返回距离下次补货的时间，库存都满时返回 0
+func (s *Shop) RestockIn() time.Duration {
+	if s.full() {
+		return 0
+	}
+	return s.restockInterval() - s.elapsed
+}
+
+func (s *Shop) restockInterval() time.Duration {
+	return time.Duration(s.Def.RestockSeconds * float64(time.Second))
+}
+
+// full 所有限量货物的库存是否都已满
+func (s *Shop) full() bool {
+	for i, e := range s.Def.Stock {
+		if s.stock[i] < e.Max {
+			return false
+		}
+	}
+	return true
+}
+
+// Saved 存档中的商店状态
+type Saved struct {
+	Stock   map[int64]int `json:"stock"`   // 物品 id -> 当前库存（只保存限量货物）
+	Elapsed float64       `json:"elapsed"` // 距离上次补货的秒数
+}
+
+// Save 返回存档数据
+func (s *Shop) Save() Saved {
+	saved := Saved{Stock: map[int64]int{}, Elapsed: s.elapsed.Seconds()}
+	for i, e := range s.Def.Stock {
+		if e.Max > 0 {
+			saved.Stock[e.Item] = s.stock[i]
+		}
+	}
+	return saved
+}
+
+// Load 从存档恢复库存；存档中没有的货物保持满库存，超出上限的库存被修正
+func (s *Shop) Load(saved Saved) {
+	for i, e := range s.Def.Stock {
+		if count, ok := saved.Stock[e.Item]; ok && e.Max > 0 {
+			s.stock[i] = min(max(count, 0), e.Max)
+		}
+	}
+	s.elapsed = time.Duration(saved.Elapsed * float64(time.Second))
+}
+
+// mul 计算 a × b，溢出时 ok 为 false
+func mul(a, b int64) (int64, bool) {
+	if a < 0 || b < 0 || (a > 0 && b > math.MaxInt64/a) {
+		return 0, false
+	}
+	return a * b, true
+}
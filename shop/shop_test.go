@@ -0,0 +1,188 @@
+package shop
+
+import (
+	"Game/inventory"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const gold = 1001
+
+var prices = map[int64]int64{gold: 0, 1: 40, 2: 100, 3: 7}
+
+func newShop() *Shop {
+	def := &Def{
+		ID: "test", Currency: gold, BuyMultiplier: 1.5, SellMultiplier: 0.5, RestockSeconds: 10,
+		Stock: []Entry{{Item: 1, Max: 3}, {Item: 2}},
+	}
+	return New(def, func(id int64) int64 { return prices[id] })
+}
+
+func TestPrices(t *testing.T) {
+	s := newShop()
+	if got := s.BuyPrice(1); got != 60 {
+		t.Errorf("BuyPrice = %d, 期望 60", got)
+	}
+	if got := s.SellPrice(3); got != 3 {
+		t.Errorf("SellPrice = %d, 期望 3（向下取整）", got)
+	}
+	if got := s.SellPrice(gold); got != 0 {
+		t.Errorf("货币的出售价格 = %d, 期望 0", got)
+	}
+	s.Def.Buys = []int64{1}
+	if got := s.SellPrice(2); got != 0 {
+		t.Errorf("不收购的物品价格 = %d, 期望 0", got)
+	}
+}
+
+func TestBuy(t *testing.T) {
+	s := newShop()
+	inv := &inventory.Counts{Items: map[int64]int64{gold: 200}, Capacity: 5}
+	if max := s.MaxBuy(inv, 0); max != 3 {
+		t.Fatalf("MaxBuy = %d, 期望 3", max)
+	}
+	cost, err := s.Buy(inv, 0, 2)
+	if err != nil || cost != 120 {
+		t.Fatalf("Buy = %d, %v", cost, err)
+	}
+	if inv.Items[gold] != 80 || inv.Items[1] != 2 {
+		t.Fatalf("背包 = %v", inv.Items)
+	}
+	if count, _ := s.Stock(0); count != 1 {
+		t.Fatalf("库存 = %d, 期望 1", count)
+	}
+	if _, err := s.Buy(inv, 0, 2); !errors.Is(err, ErrSoldOut) {
+		t.Fatalf("超出库存 err = %v", err)
+	}
+	if _, err := s.Buy(inv, 1, 1); !errors.Is(err, ErrNotEnoughGold) {
+		t.Fatalf("金币不足 err = %v", err)
+	}
+	if _, err := s.Buy(inv, 1, math.MaxInt64/2); !errors.Is(err, ErrNotEnoughGold) {
+		t.Fatalf("溢出 err = %v", err)
+	}
+	if _, err := s.Buy(inv, 0, 0); !errors.Is(err, ErrQuantity) {
+		t.Fatalf("数量为 0 err = %v", err)
+	}
+}
+
+// TestBuyBagFull 背包已满时交易失败，金币、物品和库存都不变
+func TestBuyBagFull(t *testing.T) {
+	s := newShop()
+	inv := &inventory.Counts{Items: map[int64]int64{gold: 500, 3: 1}, Capacity: 2}
+	if _, err := s.Buy(inv, 0, 1); !errors.Is(err, inventory.ErrFull) {
+		t.Fatalf("err = %v, 期望背包已满", err)
+	}
+	if inv.Items[gold] != 500 || inv.Items[1] != 0 {
+		t.Fatalf("失败的交易修改了背包: %v", inv.Items)
+	}
+	if count, _ := s.Stock(0); count != 3 {
+		t.Fatalf("失败的交易修改了库存: %d", count)
+	}
+
+	// 花光所有金币会空出格子，这时可以买
+	inv = &inventory.Counts{Items: map[int64]int64{gold: 60, 3: 1}, Capacity: 2}
+	if _, err := s.Buy(inv, 0, 1); err != nil {
+		t.Fatalf("花光金币空出格子后仍然失败: %v", err)
+	}
+}
+
+func TestSell(t *testing.T) {
+	s := newShop()
+	s.Buy(&inventory.Counts{Items: map[int64]int64{gold: 1000}, Capacity: 5}, 0, 3)
+	inv := &inventory.Counts{Items: map[int64]int64{1: 2}, Capacity: 1}
+	// 背包只有一个格子，卖掉一部分后没地方放金币
+	if _, err := s.Sell(inv, 1, 1); !errors.Is(err, inventory.ErrFull) {
+		t.Fatalf("err = %v, 期望背包已满", err)
+	}
+	// 全部卖掉会空出格子
+	earned, err := s.Sell(inv, 1, 2)
+	if err != nil || earned != 40 {
+		t.Fatalf("Sell = %d, %v", earned, err)
+	}
+	if inv.Items[gold] != 40 || inv.Items[1] != 0 {
+		t.Fatalf("背包 = %v", inv.Items)
+	}
+	if count, _ := s.Stock(0); count != 2 {
+		t.Fatalf("卖出后库存 = %d, 期望 2", count)
+	}
+	if _, err := s.Sell(inv, 1, 1); !errors.Is(err, inventory.ErrMissing) {
+		t.Fatalf("没有物品 err = %v", err)
+	}
+	if _, err := s.Sell(inv, gold, 1); !errors.Is(err, ErrNotBuying) {
+		t.Fatalf("出售货币 err = %v", err)
+	}
+}
+
+func TestRestock(t *testing.T) {
+	s := newShop()
+	s.Buy(&inventory.Counts{Items: map[int64]int64{gold: 1000}, Capacity: 5}, 0, 3)
+	if s.RestockIn() != 10*time.Second {
+		t.Fatalf("RestockIn = %v", s.RestockIn())
+	}
+	s.Update(9 * time.Second)
+	if count, _ := s.Stock(0); count != 0 {
+		t.Fatalf("补货时间未到库存 = %d", count)
+	}
+	s.Update(12 * time.Second)
+	if count, _ := s.Stock(0); count != 2 {
+		t.Fatalf("21 秒后库存 = %d, 期望 2", count)
+	}
+	s.Update(time.Minute)
+	if count, _ := s.Stock(0); count != 3 || s.RestockIn() != 0 {
+		t.Fatalf("补满后库存 = %d, RestockIn = %v", count, s.RestockIn())
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	s := newShop()
+	s.Buy(&inventory.Counts{Items: map[int64]int64{gold: 1000}, Capacity: 5}, 0, 2)
+	s.Update(4 * time.Second)
+	restored := newShop()
+	restored.Load(s.Save())
+	if count, _ := restored.Stock(0); count != 1 || restored.RestockIn() != 6*time.Second {
+		t.Fatalf("恢复后库存 = %d, RestockIn = %v", count, restored.RestockIn())
+	}
+	restored.Load(Saved{Stock: map[int64]int{1: 99}})
+	if count, _ := restored.Stock(0); count != 3 {
+		t.Fatalf("超出上限的库存没有被修正: %d", count)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defs := []*Def{
+		{ID: "a", Currency: gold, BuyMultiplier: 1, RestockSeconds: 1,
+			Stock: []Entry{{Item: 9}, {Item: gold}, {Item: 1}, {Item: 1}}, Buys: []int64{8}},
+		{ID: "a"},
+	}
+	catalog := Catalog{Price: func(id int64) (int64, bool) {
+		p, ok := prices[id]
+		return p, ok
+	}}
+	want := []string{"货物 9 不存在", "货物 1001 没有价格", "货物 1 重复", "收购的物品 8 不存在", "id 重复", "价格倍率无效", "restockSeconds"}
+	errs := Validate(defs, catalog)
+	for _, w := range want {
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Error(), w) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("缺少错误 %q: %v", w, errs)
+		}
+	}
+}
+
+// TestShippedShops 校验游戏自带的商店数据
+func TestShippedShops(t *testing.T) {
+	defs, err := Load("../data/shops.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range Validate(defs, Catalog{}) {
+		t.Error(err)
+	}
+}
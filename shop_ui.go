@@ -0,0 +1,318 @@
+package main
+
+import (
+	"Game/shop"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+)
+
+// 商店界面布局
+const (
+	tradeX        = 100
+	tradeY        = 60
+	tradeW        = 600
+	tradeH        = 470
+	tradeRowH     = 32
+	tradeRows     = 8 // 列表最多显示的行数
+	tradeConfirmX = 240
+	tradeConfirmY = 210
+	tradeConfirmW = 320
+	tradeConfirmH = 150
+)
+
+// 商店界面中的按钮
+var (
+	tradeTabRects   = [2][4]int{{tradeX + 20, tradeY + 40, 100, 24}, {tradeX + 130, tradeY + 40, 100, 24}} // 购买、出售
+	tradeActionRect = [4]int{tradeX + tradeW - 140, tradeY + tradeH - 44, 120, 28}
+	tradeYesRect    = [4]int{tradeConfirmX + 30, tradeConfirmY + 105, 120, 30}
+	tradeNoRect     = [4]int{tradeConfirmX + tradeConfirmW - 150, tradeConfirmY + 105, 120, 30}
+	tradeLessRect   = [4]int{tradeConfirmX + 90, tradeConfirmY + 66, 20, 20}
+	tradeMoreRect   = [4]int{tradeConfirmX + tradeConfirmW - 110, tradeConfirmY + 66, 20, 20}
+)
+
+// tradeView 打开的商店界面状态
+type tradeView struct {
+	shop    *shop.Shop
+	selling bool  // 当前是出售页
+	index   int   // 列表中选中的行
+	scroll  int   // 列表滚动的行数
+	confirm bool  // 是否正在确认交易
+	qty     int64 // 确认交易的数量
+}
+
+// tradeRowRect 列表中第 row 个可见行的点击区域
+func tradeRowRect(row int) [4]int {
+	return [4]int{tradeX + 20, tradeY + 76 + row*tradeRowH, tradeW - 40, tradeRowH - 2}
+}
+
+// sellItems 出售页列出的背包物品（不包括货币）
+func (p *PlayScreen) sellItems() []*item {
+	var items []*item
+	for _, it := range p.bag().Items {
+		if it.ItemData.id != p.trade.shop.Def.Currency {
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+// tradeRowCount 当前页的行数
+func (p *PlayScreen) tradeRowCount() int {
+	if p.trade.selling {
+		return len(p.sellItems())
+	}
+	return len(p.trade.shop.Def.Stock)
+}
+
+// tradeSelectedItem 返回选中行的物品 id，没有选中时返回 false
+func (p *PlayScreen) tradeSelectedItem() (int64, bool) {
+	t := p.trade
+	if t.selling {
+		items := p.sellItems()
+		if t.index >= len(items) {
+			return 0, false
+		}
+		return items[t.index].ItemData.id, true
+	}
+	if t.index >= len(t.shop.Def.Stock) {
+		return 0, false
+	}
+	return t.shop.Def.Stock[t.index].Item, true
+}
+
+// tradeMaxQty 选中的物品最多能交易多少个（至少为 1，交易失败时再提示原因）
+func (p *PlayScreen) tradeMaxQty() int64 {
+	t := p.trade
+	if t.selling {
+		id, _ := p.tradeSelectedItem()
+		return max(p.CountItem(id), 1)
+	}
+	return max(t.shop.MaxBuy(p, t.index), 1)
+}
+
+// closeShop 关闭商店界面
+func (p *PlayScreen) closeShop() {
+	p.trade = nil
+	p.sound.PlaySFX(sfxInventoryClose)
+}
+
+// updateTrade 处理商店界面的输入；商店打开期间游戏世界暂停
+func (p *PlayScreen) updateTrade() {
+	t := p.trade
+	if t.confirm {
+		p.updateTradeConfirm()
+		return
+	}
+	if p.settings.isActionJustPressed(ActionPause) {
+		p.closeShop()
+		return
+	}
+
+	x, y := ebiten.CursorPosition()
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	// 切换购买/出售页
+	selling := t.selling
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) ||
+		p.settings.isActionJustPressed(ActionMoveLeft) || p.settings.isActionJustPressed(ActionMoveRight) {
+		selling = !selling
+	}
+	for i, rect := range tradeTabRects {
+		if clicked && inRect(x, y, rect) {
+			selling = i == 1
+		}
+	}
+	if selling != t.selling {
+		p.sound.PlaySFX(sfxClick)
+		t.selling = selling
+		t.index, t.scroll = 0, 0
+	}
+
+	rows := p.tradeRowCount()
+	if rows > 0 {
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || p.settings.isActionJustPressed(ActionMoveUp) {
+			t.index = (t.index - 1 + rows) % rows
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || p.settings.isActionJustPressed(ActionMoveDown) {
+			t.index = (t.index + 1) % rows
+		}
+	}
+	for row := range min(rows-t.scroll, tradeRows) {
+		if clicked && inRect(x, y, tradeRowRect(row)) {
+			t.index = t.scroll + row
+		}
+	}
+	// 卖掉最后一件物品后选中位置可能越界
+	t.index = min(t.index, max(rows-1, 0))
+	t.scroll = min(max(t.scroll, t.index-tradeRows+1), t.index)
+
+	if _, ok := p.tradeSelectedItem(); ok &&
+		(p.settings.isActionJustPressed(ActionInteract) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+			(clicked && inRect(x, y, tradeActionRect))) {
+		p.sound.PlaySFX(sfxClick)
+		t.confirm = true
+		t.qty = 1
+	}
+}
+
+// updateTradeConfirm 处理确认交易：左右调整数量，确认后执行交易
+func (p *PlayScreen) updateTradeConfirm() {
+	t := p.trade
+	x, y := ebiten.CursorPosition()
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	maxQty := p.tradeMaxQty()
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || p.settings.isActionJustPressed(ActionMoveLeft) ||
+		(clicked && inRect(x, y, tradeLessRect)) {
+		t.qty = max(t.qty-1, 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || p.settings.isActionJustPressed(ActionMoveRight) ||
+		(clicked && inRect(x, y, tradeMoreRect)) {
+		t.qty = min(t.qty+1, maxQty)
+	}
+	t.qty = min(t.qty, maxQty)
+
+	switch {
+	case p.settings.isActionJustPressed(ActionPause) || (clicked && inRect(x, y, tradeNoRect)):
+		p.sound.PlaySFX(sfxClick)
+		t.confirm = false
+	case p.settings.isActionJustPressed(ActionInteract) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		(clicked && inRect(x, y, tradeYesRect)):
+		t.confirm = false
+		p.completeTrade()
+	}
+}
+
+// completeTrade 执行确认过的交易并提示结果
+func (p *PlayScreen) completeTrade() {
+	defer p.walletTx("shop")()
+	t := p.trade
+	id, ok := p.tradeSelectedItem()
+	if !ok {
+		return
+	}
+	name := ItemImages[id].Name
+	if t.selling {
+		earned, err := t.shop.Sell(p, id, t.qty)
+		if err != nil {
+			p.notices.Show(p.settings.T(tradeErrorKey(err)))
+			return
+		}
+		p.notices.Show(fmt.Sprintf(p.settings.T("shop.sold"), name, t.qty, earned))
+	} else {
+		cost, err := t.shop.Buy(p, t.index, t.qty)
+		if err != nil {
+			p.notices.Show(p.settings.T(tradeErrorKey(err)))
+			return
+		}
+		p.notices.Show(fmt.Sprintf(p.settings.T("shop.bought"), name, t.qty, cost))
+	}
+	p.sound.PlaySFX(sfxPickup)
+}
+
+// drawTrade 绘制商店界面：购买/出售页、物品列表、物品描述和确认框
+func (p *PlayScreen) drawTrade(screen *ebiten.Image) {
+	t := p.trade
+	s := t.shop
+	drawPanel(screen, tradeX, tradeY, tradeW, tradeH)
+	drawCenteredText(screen, s.Def.Name, tradeX, tradeY+12, tradeW)
+	gold := fmt.Sprintf(p.settings.T("shop.gold"), p.CountItem(s.Def.Currency))
+	ebitenutil.DebugPrintAt(screen, gold, tradeX+tradeW-len(gold)*6-20, tradeY+44)
+
+	for i, rect := range tradeTabRects {
+		clr := color.RGBA{R: 60, G: 60, B: 90, A: 255}
+		if (i == 1) == t.selling {
+			clr = color.RGBA{R: 100, G: 150, B: 255, A: 255}
+		}
+		vector.DrawFilledRect(screen, float32(rect[0]), float32(rect[1]), float32(rect[2]), float32(rect[3]), clr, false)
+		label := p.settings.T([2]string{"shop.buy", "shop.sell"}[i])
+		drawCenteredText(screen, label, rect[0], rect[1]+4, rect[2])
+	}
+
+	rows := p.tradeRowCount()
+	if rows == 0 {
+		ebitenutil.DebugPrintAt(screen, p.settings.T("shop.nothingToSell"), tradeX+30, tradeY+84)
+	}
+	for row := range min(rows-t.scroll, tradeRows) {
+		i := t.scroll + row
+		rect := tradeRowRect(row)
+		if i == t.index {
+			vector.DrawFilledRect(screen, float32(rect[0]), float32(rect[1]), float32(rect[2]), float32(rect[3]), color.RGBA{R: 100, G: 150, B: 255, A: 80}, false)
+		}
+		var id int64
+		var price, extra string
+		if t.selling {
+			it := p.sellItems()[i]
+			id = it.ItemData.id
+			extra = fmt.Sprintf("x%d", it.count)
+			price = "-"
+			if v := s.SellPrice(id); v > 0 {
+				price = fmt.Sprintf("%d", v)
+			}
+		} else {
+			id = s.Def.Stock[i].Item
+			price = fmt.Sprintf("%d", s.BuyPrice(id))
+			if count, unlimited := s.Stock(i); !unlimited {
+				extra = fmt.Sprintf("x%d", count)
+				if count == 0 {
+					extra = p.settings.T("shop.soldOut")
+				}
+			}
+		}
+		data := ItemImages[id]
+		drawItemIcon(screen, data, rect[0]+4, rect[1]+3, 24)
+		ebitenutil.DebugPrintAt(screen, data.Name, rect[0]+36, rect[1]+8)
+		ebitenutil.DebugPrintAt(screen, extra, rect[0]+300, rect[1]+8)
+		ebitenutil.DebugPrintAt(screen, price, rect[0]+rect[2]-len(price)*6-10, rect[1]+8)
+	}
+
+	// 选中物品的描述
+	descY := tradeY + 76 + tradeRows*tradeRowH + 10
+	vector.DrawFilledRect(screen, tradeX+10, float32(descY-6), tradeW-20, 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+	if id, ok := p.tradeSelectedItem(); ok {
+		for i, line := range wrapText(ItemImages[id].Description, (tradeW-40)/6) {
+			ebitenutil.DebugPrintAt(screen, line, tradeX+20, descY+i*16)
+		}
+	}
+	if left := s.RestockIn(); left > 0 && !t.selling {
+		text := fmt.Sprintf(p.settings.T("shop.restock"), int(math.Ceil(left.Seconds())))
+		ebitenutil.DebugPrintAt(screen, text, tradeX+20, tradeY+tradeH-62)
+	}
+	ebitenutil.DebugPrintAt(screen, p.settings.T("shop.hint"), tradeX+20, tradeY+tradeH-38)
+	action := "shop.buy"
+	if t.selling {
+		action = "shop.sell"
+	}
+	drawButton(screen, tradeActionRect, p.settings.T(action))
+
+	if t.confirm {
+		p.drawTradeConfirm(screen)
+	}
+}
+
+// drawTradeConfirm 绘制确认框：交易内容、数量和总价
+func (p *PlayScreen) drawTradeConfirm(screen *ebiten.Image) {
+	t := p.trade
+	id, ok := p.tradeSelectedItem()
+	if !ok {
+		return
+	}
+	vector.DrawFilledRect(screen, tradeX, tradeY, tradeW, tradeH, color.RGBA{A: 120}, false)
+	drawPanel(screen, tradeConfirmX, tradeConfirmY, tradeConfirmW, tradeConfirmH)
+
+	key, price := "shop.confirmBuy", t.shop.BuyPrice(id)
+	if t.selling {
+		key, price = "shop.confirmSell", t.shop.SellPrice(id)
+	}
+	text := fmt.Sprintf(p.settings.T(key), ItemImages[id].Name, t.qty, price*t.qty)
+	drawCenteredText(screen, text, tradeConfirmX, tradeConfirmY+24, tradeConfirmW)
+
+	drawButton(screen, tradeLessRect, "<")
+	drawButton(screen, tradeMoreRect, ">")
+	drawCenteredText(screen, fmt.Sprintf("%d", t.qty), tradeConfirmX, tradeConfirmY+68, tradeConfirmW)
+	drawButton(screen, tradeYesRect, p.settings.T("shop.yes"))
+	drawButton(screen, tradeNoRect, p.settings.T("shop.no"))
+}
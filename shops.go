@@ -0,0 +1,72 @@
+package main
+
+import (
+	"Game/inventory"
+	"Game/shop"
+	"Game/wallet"
+	"errors"
+	"log"
+)
+
+// shopsPath 商店数据文件
+const shopsPath = "data/shops.json"
+
+// initShops 加载并校验商店数据，数据有误时直接退出
+func (p *PlayScreen) initShops() {
+	defs, err := shop.Load(shopsPath)
+	if err != nil {
+		log.Fatalf("加载商店数据失败: %v", err)
+	}
+	catalog := shop.Catalog{Price: func(id int64) (int64, bool) {
+		data, ok := ItemImages[id]
+		if !ok {
+			return 0, false
+		}
+		return data.Price, true
+	}}
+	if errs := shop.Validate(defs, catalog); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("商店数据有误: %s", shopsPath)
+	}
+	p.shops = map[string]*shop.Shop{}
+	for _, def := range defs {
+		p.shops[def.ID] = shop.New(def, func(id int64) int64 { return ItemImages[id].Price })
+	}
+}
+
+// updateShops 推进所有商店的补货计时
+func (p *PlayScreen) updateShops() {
+	for _, s := range p.shops {
+		s.Update(tickDuration())
+	}
+}
+
+// openShop 打开商店界面
+func (p *PlayScreen) openShop(id string) {
+	s, ok := p.shops[id]
+	if !ok {
+		log.Printf("商店 %q 不存在", id)
+		return
+	}
+	p.trade = &tradeView{shop: s}
+	p.sound.PlaySFX(sfxInventoryOpen)
+}
+
+// tradeErrorKey 返回交易失败原因对应的提示文字键
+func tradeErrorKey(err error) string {
+	switch {
+	case errors.Is(err, inventory.ErrFull), errors.Is(err, inventory.ErrOverflow):
+		return "notice.inventoryFull"
+	case errors.Is(err, shop.ErrSoldOut):
+		return "shop.soldOut"
+	case errors.Is(err, shop.ErrNotEnoughGold):
+		return "shop.notEnoughGold"
+	case errors.Is(err, shop.ErrNotBuying):
+		return "shop.notBuying"
+	case errors.Is(err, wallet.ErrOverflow):
+		return "notice.walletFull"
+	}
+	return "shop.failed"
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"Game/progression"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"slices"
+)
+
+// 技能界面布局：左侧为等级和属性点，右侧为技能树和选中技能的说明
+const (
+	skillsX     = 60
+	skillsY     = 50
+	skillsW     = 680
+	skillsH     = 500
+	skillsSideW = 220 // 左侧宽度
+	skillNode   = 44  // 技能树中技能格子的尺寸
+	skillCellW  = 130 // 技能树每列的宽度
+	skillCellH  = 76  // 技能树每行的高度
+)
+
+// skillTreeX, skillTreeY 技能树左上角
+const (
+	skillTreeX = skillsX + skillsSideW + 40
+	skillTreeY = skillsY + 50
+)
+
+// skillRespecRect 洗点按钮
+var skillRespecRect = [4]int{skillsX + 16, skillsY + skillsH - 44, skillsSideW - 32, 28}
+
+// skillLearnRect 学习按钮
+var skillLearnRect = [4]int{skillsX + skillsW - 140, skillsY + skillsH - 44, 120, 28}
+
+// skillNodeRect 技能在技能树中的格子
+func skillNodeRect(s *progression.Skill) [4]int {
+	return [4]int{skillTreeX + s.Col*skillCellW, skillTreeY + s.Row*skillCellH, skillNode, skillNode}
+}
+
+// skillStatRect 左侧第 i 个属性的加点按钮
+func skillStatRect(i int) [4]int {
+	return [4]int{skillsX + skillsSideW - 44, skillsY + 106 + i*22, 24, 18}
+}
+
+// setSkillsOpen 打开或关闭技能界面
+func (p *PlayScreen) setSkillsOpen(open bool) {
+	if open == p.skillsOpen {
+		return
+	}
+	p.skillsOpen = open
+	if open {
+		p.setQuestLogOpen(false)
+		p.setInventoryOpen(false)
+		p.sound.PlaySFX(sfxInventoryOpen)
+	} else {
+		p.sound.PlaySFX(sfxInventoryClose)
+	}
+}
+
+// updateSkillTree 处理技能界面的输入；技能界面打开期间游戏世界暂停
+// 左右方向键或点击选择技能，回车、交互键或学习按钮学习，按技能键把选中的主动技能放到技能栏对应的格子
+func (p *PlayScreen) updateSkillTree() {
+	if p.settings.isActionJustPressed(ActionPause) || p.settings.isActionJustPressed(ActionSkills) {
+		p.setSkillsOpen(false)
+		return
+	}
+	skills := p.skillTree.Skills
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || p.settings.isActionJustPressed(ActionMoveLeft) {
+		p.skillIndex = (p.skillIndex - 1 + len(skills)) % len(skills)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || p.settings.isActionJustPressed(ActionMoveRight) {
+		p.skillIndex = (p.skillIndex + 1) % len(skills)
+	}
+	selected := skills[p.skillIndex]
+	for i, action := range skillActions {
+		if p.settings.isActionJustPressed(action) && selected.Kind == progression.Active && p.level().Build.Skills[selected.ID] > 0 {
+			if j := slices.Index(p.level().Bar[:], selected.ID); j >= 0 {
+				p.level().Bar[j] = p.level().Bar[i]
+			}
+			p.level().Bar[i] = selected.ID
+			p.notices.Show(fmt.Sprintf(p.settings.T("skills.assigned"), selected.Name, i+1))
+		}
+	}
+
+	x, y := ebiten.CursorPosition()
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	if p.settings.isActionJustPressed(ActionInteract) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		(clicked && inRect(x, y, skillLearnRect)) {
+		p.learnSkill(selected)
+		return
+	}
+	if !clicked {
+		return
+	}
+	for i, s := range skills {
+		if inRect(x, y, skillNodeRect(s)) {
+			p.skillIndex = i
+		}
+	}
+	for i, name := range statNames {
+		if inRect(x, y, skillStatRect(i)) {
+			p.allocateStat(name)
+		}
+	}
+	if inRect(x, y, skillRespecRect) {
+		p.respec()
+	}
+}
+
+// skillState 技能在技能树中的状态颜色：已学满为金色，可以学习为绿色，已学会但未满为蓝色，不能学习为灰色
+func (p *PlayScreen) skillState(s *progression.Skill) color.RGBA {
+	rank := p.level().Build.Skills[s.ID]
+	switch {
+	case rank >= s.MaxRank:
+		return tooltipGold
+	case p.skillTree.CanLearn(p.level().Build, s.ID, p.level().Progress.Level) == nil:
+		return tooltipGreen
+	case rank > 0:
+		return color.RGBA{R: 100, G: 150, B: 255, A: 255}
+	}
+	return tooltipGray
+}
+
+// drawSkillTree 绘制技能界面
+func (p *PlayScreen) drawSkillTree(screen *ebiten.Image) {
+	drawPanel(screen, skillsX, skillsY, skillsW, skillsH)
+	drawCenteredText(screen, p.settings.T("skills.title"), skillsX, skillsY+12, skillsW)
+	vector.DrawFilledRect(screen, skillsX+skillsSideW, skillsY+36, 1, skillsH-50, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+	p.drawSkillSide(screen)
+
+	// 前置技能的连线
+	for _, s := range p.skillTree.Skills {
+		to := skillNodeRect(s)
+		for _, r := range s.Requires {
+			from := skillNodeRect(p.skillTree.Skill(r.Skill))
+			clr := tooltipGray
+			if p.level().Build.Skills[r.Skill] >= r.Rank {
+				clr = tooltipGreen
+			}
+			vector.StrokeLine(screen, float32(from[0]+skillNode/2), float32(from[1]+skillNode/2), float32(to[0]+skillNode/2), float32(to[1]+skillNode/2), 2, clr, false)
+		}
+	}
+	for i, s := range p.skillTree.Skills {
+		r := skillNodeRect(s)
+		fill := color.RGBA{R: 40, G: 40, B: 60, A: 255}
+		if s.Kind == progression.Active {
+			fill = color.RGBA{R: 70, G: 40, B: 30, A: 255}
+		}
+		vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), skillNode, skillNode, fill, false)
+		width := float32(1)
+		if i == p.skillIndex {
+			width = 3
+		}
+		vector.StrokeRect(screen, float32(r[0]), float32(r[1]), skillNode, skillNode, width, p.skillState(s), false)
+		drawCenteredText(screen, skillAbbrev(s), r[0], r[1]+8, skillNode)
+		drawCenteredText(screen, fmt.Sprintf("%d/%d", p.level().Build.Skills[s.ID], s.MaxRank), r[0], r[1]+24, skillNode)
+	}
+	p.drawSkillDetails(screen, p.skillTree.Skills[p.skillIndex])
+}
+
+// drawSkillSide 绘制左侧的等级、经验、属性点和洗点按钮
+func (p *PlayScreen) drawSkillSide(screen *ebiten.Image) {
+	level := p.level().Progress.Level
+	x, y := skillsX+16, skillsY+44
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(p.settings.T("skills.level"), level), x, y)
+	if next := p.skillTree.Levels.Next(level); next > 0 {
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf(p.settings.T("skills.xp"), p.level().Progress.XP, next), x, y+16)
+	} else {
+		ebitenutil.DebugPrintAt(screen, p.settings.T("skills.maxLevel"), x, y+16)
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(p.settings.T("skills.statPoints"), p.skillTree.FreeStatPoints(p.level().Build, level)), x, y+40)
+
+	stats := statSheet(p.world, p.player)
+	values := []string{
+		fmt.Sprintf("%.0f", stats.Attack),
+		fmt.Sprintf("%.0f", stats.Defense),
+		fmt.Sprintf("%.0f", stats.Speed),
+		fmt.Sprintf("%.0f%%", stats.Crit*100),
+	}
+	for i, name := range statNames {
+		r := skillStatRect(i)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s: %s", p.settings.T("stat."+name), values[i]), x, r[1]+2)
+		if p.skillTree.Attributes[name] > 0 {
+			drawButton(screen, r, "+")
+		}
+	}
+
+	y = skillStatRect(len(statNames))[1] + 16
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(p.settings.T("skills.skillPoints"), p.skillTree.FreeSkillPoints(p.level().Build, level)), x, y)
+	y += 24
+	keys := make([]any, skillSlots)
+	for i, action := range skillActions {
+		keys[i] = p.settings.Key(action).String()
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(p.settings.T("skills.barHint"), keys...), x, y)
+
+	respec := p.skillTree.Respec
+	drawButton(screen, skillRespecRect, fmt.Sprintf(p.settings.T("skills.respec"), respec.Cost(level), ItemImages[respec.Currency].Name))
+}
+
+// drawSkillDetails 在技能树下方绘制选中技能的说明和学习条件
+func (p *PlayScreen) drawSkillDetails(screen *ebiten.Image, s *progression.Skill) {
+	x, y := skillsX+skillsSideW+20, skillsY+skillsH-180
+	vector.DrawFilledRect(screen, float32(x), float32(y-8), skillsW-skillsSideW-40, 1, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+	kind := p.settings.T("skills.passive")
+	if s.Kind == progression.Active {
+		kind = p.settings.T("skills.active")
+	}
+	lines := []tooltipLine{
+		{text: s.Name, clr: p.skillState(s), note: kind, noteClr: tooltipGray},
+		{text: fmt.Sprintf(p.settings.T("skills.rank"), p.level().Build.Skills[s.ID], s.MaxRank)},
+		{text: s.Description},
+	}
+	if s.Kind == progression.Active {
+		lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("skills.cast"), s.Mana, s.Cooldown), clr: vitalColors[VitalMana]})
+	}
+	met := func(ok bool) color.RGBA {
+		if ok {
+			return tooltipGreen
+		}
+		return tooltipRed
+	}
+	if s.Level > 1 {
+		lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("skills.requiresLevel"), s.Level), clr: met(p.level().Progress.Level >= s.Level)})
+	}
+	for _, r := range s.Requires {
+		req := p.skillTree.Skill(r.Skill)
+		lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("skills.requires"), req.Name, r.Rank), clr: met(p.level().Build.Skills[r.Skill] >= r.Rank)})
+	}
+	lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("skills.cost"), s.Cost), clr: met(p.skillTree.FreeSkillPoints(p.level().Build, p.level().Progress.Level) >= s.Cost)})
+	drawTooltipLines(screen, lines, x, y)
+	drawButton(screen, skillLearnRect, p.settings.T("skills.learn"))
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/progression"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"slices"
+	"time"
+)
+
+// skillsPath 成长数据文件：升级曲线、属性点、洗点费用和技能树
+const skillsPath = "data/skills.json"
+
+// 技能栏布局（快捷栏右侧）
+const (
+	skillSlots = 3
+	skillBarX  = 640
+)
+
+// skillActions 技能栏各格对应的动作
+var skillActions = [skillSlots]Action{ActionSkill1, ActionSkill2, ActionSkill3}
+
+// xpColor 经验条和获得经验时飘出的文字颜色
+var xpColor = color.RGBA{R: 190, G: 120, B: 255, A: 255}
+
+// StatBonus 属性点和被动技能提高的属性组件
+type StatBonus struct {
+	Stats Stats
+}
+
+// Level 玩家的成长组件：等级、属性点、技能和技能栏
+type Level struct {
+	Progress  progression.Progress     // 等级和经验
+	Build     progression.Build        // 分配的属性点和学会的技能
+	Bar       [skillSlots]string       // 技能栏每格的技能 id，空字符串表示空
+	Cooldowns map[string]time.Duration // 各主动技能剩余的冷却时间
+}
+
+// level 返回玩家的成长组件
+func (p *PlayScreen) level() *Level {
+	return ecs.Get[Level](p.world, p.player)
+}
+
+// statsFromMap 把 {"attack": 1, ...} 形式的属性转换为 Stats
+func statsFromMap(m map[string]float64) Stats {
+	return Stats{Attack: m["attack"], Defense: m["defense"], Speed: m["speed"], Crit: m["crit"]}
+}
+
+// initProgression 加载并校验成长数据和主动技能的效果，数据有误时直接退出
+func (p *PlayScreen) initProgression() {
+	tree, err := progression.Load(skillsPath)
+	if err != nil {
+		log.Fatalf("加载技能数据失败: %v", err)
+	}
+	errs := progression.Validate(tree, progression.Catalog{
+		Stat: func(name string) bool { return slices.Contains(statNames, name) },
+		Item: isCurrency,
+	})
+	p.skillEffects = map[string][]Effect{}
+	for _, s := range tree.Skills {
+		for _, raw := range s.Effects {
+			effect, err := newEffect(raw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("技能 %s: %w", s.ID, err))
+				continue
+			}
+			p.skillEffects[s.ID] = append(p.skillEffects[s.ID], effect)
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		log.Fatalf("技能数据有误: %s", skillsPath)
+	}
+	p.skillTree = tree
+	ecs.Add(p.world, p.player, Level{
+		Progress:  progression.Start(),
+		Build:     progression.NewBuild(),
+		Cooldowns: map[string]time.Duration{},
+	})
+	ecs.Add(p.world, p.player, StatBonus{})
+}
+
+// gainXP 获得经验，升级时提示并恢复所有数值
+func (p *PlayScreen) gainXP(xp int) {
+	if xp <= 0 {
+		return
+	}
+	x, y := p.playerCenter()
+	p.damageNumbers.Add(fmt.Sprintf("+%d XP", xp), x, y-12, xpColor, 1)
+	if p.level().Progress.Gain(p.skillTree.Levels, xp) == 0 {
+		return
+	}
+	p.notices.Show(fmt.Sprintf(p.settings.T("notice.levelUp"), p.level().Progress.Level))
+	p.sound.PlaySFX(sfxPickup)
+	for _, stat := range vitalStats {
+		current, maxValue := p.vitalValue(stat)
+		p.restoreVital(stat, maxValue-current)
+	}
+}
+
+// buildChanged 加点、学会技能或洗点后更新属性加成和技能栏：新学会的主动技能放进空着的格子
+func (p *PlayScreen) buildChanged() {
+	ecs.Get[StatBonus](p.world, p.player).Stats = statsFromMap(p.skillTree.Bonus(p.level().Build))
+	for i, id := range p.level().Bar {
+		if p.level().Build.Skills[id] == 0 {
+			p.level().Bar[i] = ""
+		}
+	}
+	for _, s := range p.skillTree.Skills {
+		if s.Kind != progression.Active || p.level().Build.Skills[s.ID] == 0 || slices.Contains(p.level().Bar[:], s.ID) {
+			continue
+		}
+		if i := slices.Index(p.level().Bar[:], ""); i >= 0 {
+			p.level().Bar[i] = s.ID
+		}
+	}
+}
+
+// restoreProgression 从存档恢复等级、加点和技能栏；技能数据改动后加点不再有效时返还所有点数
+func (p *PlayScreen) restoreProgression(save *SaveData) {
+	p.level().Progress = save.Progress
+	p.level().Progress.Clamp(p.skillTree.Levels)
+	p.level().Build = save.Build
+	if p.skillTree.Sanitize(&p.level().Build, p.level().Progress.Level) {
+		log.Printf("存档中的加点与技能数据不符，已返还所有点数")
+	}
+	p.level().Bar = [skillSlots]string{}
+	for i, id := range save.SkillBar {
+		if s := p.skillTree.Skill(id); i < skillSlots && s != nil && s.Kind == progression.Active {
+			p.level().Bar[i] = id
+		}
+	}
+	p.level().Cooldowns = map[string]time.Duration{}
+	p.buildChanged()
+}
+
+// progressionErrorKey 把学习技能或加点失败的原因转换为提示文字键
+func progressionErrorKey(err error) string {
+	switch {
+	case errors.Is(err, progression.ErrMaxRank):
+		return "skills.maxRank"
+	case errors.Is(err, progression.ErrLevel):
+		return "skills.needLevel"
+	case errors.Is(err, progression.ErrRequires):
+		return "skills.needRequires"
+	case errors.Is(err, progression.ErrPoints):
+		return "skills.noPoints"
+	}
+	return "skills.unknown"
+}
+
+// allocateStat 把一个属性点分配给属性 name
+func (p *PlayScreen) allocateStat(name string) {
+	if err := p.skillTree.Allocate(&p.level().Build, name, p.level().Progress.Level); err != nil {
+		p.notices.Show(p.settings.T(progressionErrorKey(err)))
+		return
+	}
+	p.sound.PlaySFX(sfxClick)
+	p.buildChanged()
+}
+
+// learnSkill 把技能提高一级
+func (p *PlayScreen) learnSkill(s *progression.Skill) {
+	if err := p.skillTree.Learn(&p.level().Build, s.ID, p.level().Progress.Level); err != nil {
+		p.notices.Show(p.settings.T(progressionErrorKey(err)))
+		return
+	}
+	p.notices.Show(fmt.Sprintf(p.settings.T("skills.learned"), s.Name, p.level().Build.Skills[s.ID]))
+	p.sound.PlaySFX(sfxPickup)
+	p.buildChanged()
+}
+
+// respec 花费货币返还所有属性点和技能点
+func (p *PlayScreen) respec() {
+	if p.level().Build.Empty() {
+		p.notices.Show(p.settings.T("skills.nothingToReset"))
+		return
+	}
+	r := p.skillTree.Respec
+	if cost := r.Cost(p.level().Progress.Level); cost > 0 {
+		defer p.walletTx("respec")()
+		if !p.spendCurrency(r.Currency, cost) {
+			p.notices.Show(p.settings.T("shop.notEnoughGold"))
+			return
+		}
+	}
+	p.level().Build = progression.NewBuild()
+	p.level().Cooldowns = map[string]time.Duration{}
+	p.buildChanged()
+	p.notices.Show(p.settings.T("skills.respecDone"))
+	p.sound.PlaySFX(sfxClick)
+}
+
+// castSkill 施放技能栏第 slot 格的主动技能：检查冷却和法力值，所有效果都能生效时才消耗法力值
+func (p *PlayScreen) castSkill(slot int) {
+	s := p.skillTree.Skill(p.level().Bar[slot])
+	if s == nil {
+		return
+	}
+	if p.stunned(p.player) {
+		p.notices.Show(p.settings.T("combat.stunned"))
+		return
+	}
+	if p.level().Cooldowns[s.ID] > 0 {
+		p.notices.Show(p.settings.T("skills.cooldown"))
+		return
+	}
+	mana := &p.playerVitals().Mana
+	if mana.Current < s.Mana {
+		p.notices.Show(p.settings.T("skills.noMana"))
+		return
+	}
+	ctx := &EffectContext{screen: p, user: p.player, target: p.player}
+	for _, effect := range p.skillEffects[s.ID] {
+		if err := effect.Check(ctx); err != nil {
+			p.notices.Show(p.useErrorText(err))
+			return
+		}
+	}
+	mana.Spend(s.Mana)
+	p.vitalEvent(VitalEvent{Stat: VitalMana, Amount: -s.Mana})
+	for _, effect := range p.skillEffects[s.ID] {
+		effect.Apply(ctx)
+	}
+	if s.Cooldown > 0 {
+		p.level().Cooldowns[s.ID] = time.Duration(s.Cooldown * float64(time.Second))
+	}
+}
+
+// updateSkills 推进技能冷却，按技能键施放技能栏中的技能
+func (p *PlayScreen) updateSkills(dt time.Duration) {
+	for id, remaining := range p.level().Cooldowns {
+		if remaining <= dt {
+			delete(p.level().Cooldowns, id)
+		} else {
+			p.level().Cooldowns[id] = remaining - dt
+		}
+	}
+	for i, action := range skillActions {
+		if p.settings.isActionJustPressed(action) {
+			p.castSkill(i)
+		}
+	}
+}
+
+// skillSlotRect 技能栏第 i 格
+func skillSlotRect(i int) [4]int {
+	return [4]int{skillBarX + i*(hotbarSlot+hotbarSpacing), hotbarY, hotbarSlot, hotbarSlot}
+}
+
+// skillAbbrev 技能名的缩写，用于技能栏和技能树中的图标
+func skillAbbrev(s *progression.Skill) string {
+	abbrev := ""
+	for i, r := range s.Name {
+		if i == 0 || s.Name[i-1] == ' ' {
+			abbrev += string(r)
+		}
+	}
+	if len(abbrev) < 2 && len(s.Name) >= 2 {
+		abbrev = s.Name[:2]
+	}
+	return abbrev
+}
+
+// drawSkillBar 在快捷栏右侧绘制技能栏：技能缩写、按键、冷却扇形，法力值不够时变暗
+func (p *PlayScreen) drawSkillBar(screen *ebiten.Image) {
+	mana := p.playerVitals().Mana.Current
+	for i, id := range p.level().Bar {
+		r := skillSlotRect(i)
+		vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), color.RGBA{R: 30, G: 20, B: 50, A: 200}, false)
+		vector.StrokeRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), 1, xpColor, false)
+		if s := p.skillTree.Skill(id); s != nil {
+			drawCenteredText(screen, skillAbbrev(s), r[0], r[1]+14, r[2])
+			if remaining := p.level().Cooldowns[id]; remaining > 0 {
+				drawCooldownSweep(screen, r, remaining.Seconds()/s.Cooldown)
+			} else if mana < s.Mana {
+				vector.DrawFilledRect(screen, float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3]), color.RGBA{A: 150}, false)
+			}
+		}
+		ebitenutil.DebugPrintAt(screen, p.settings.Key(skillActions[i]).String(), r[0]+2, r[1])
+	}
+}
+
+// drawXPBar 在数值条下方绘制等级和经验条
+func (p *PlayScreen) drawXPBar(screen *ebiten.Image) {
+	x, y := float32(vitalBarX), float32(vitalBarY+len(vitalStats)*(vitalBarH+vitalBarGap))
+	vector.DrawFilledRect(screen, x, y, vitalBarW, 6, color.RGBA{R: 20, G: 20, B: 30, A: 200}, false)
+	vector.DrawFilledRect(screen, x, y, vitalBarW*float32(p.level().Progress.Fraction(p.skillTree.Levels)), 6, xpColor, false)
+	text := fmt.Sprintf(p.settings.T("skills.level"), p.level().Progress.Level)
+	if free := p.skillTree.FreeStatPoints(p.level().Build, p.level().Progress.Level) + p.skillTree.FreeSkillPoints(p.level().Build, p.level().Progress.Level); free > 0 {
+		text += " (+)"
+	}
+	ebitenutil.DebugPrintAt(screen, text, vitalBarX+vitalBarW+6, int(y)-5)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"Game/audio"
+	"Game/audio/ebitenaudio"
+	"Game/ecs"
+	"Game/worldmap"
+	"log"
+	"time"
+)
+
+// 背景音乐与音效名称
+const (
+	musicMenu         = "bgm_menu"        // 菜单背景音乐
+	musicPlay         = "bgm_play"        // 游戏背景音乐
+	sfxClick          = "click"           // 按钮点击
+	sfxInventoryOpen  = "inventory_open"  // 打开背包
+	sfxInventoryClose = "inventory_close" // 关闭背包
+	sfxPickup         = "pickup"          // 拾取物品
+	sfxFootstep       = "footstep"        // 脚步声
+	sfxHit            = "hit"             // 攻击命中
+	ambientRiver      = "ambient_river"   // 河流环境音
+	ambientShop       = "ambient_shop"    // 商店环境音
+)
+
+// musicFade 切换场景时背景音乐交叉淡入淡出的时长
+const musicFade = 800 * time.Millisecond
+
+// defaultHearingRange 世界音效默认的可听距离（像素）
+const defaultHearingRange = 320.0
+
+// soundFiles 所有音频资源：名称 -> 文件路径
+var soundFiles = map[string]string{
+	musicMenu:         "sounds/bgm_menu.wav",
+	musicPlay:         "sounds/bgm_play.wav",
+	sfxClick:          "sounds/click.wav",
+	sfxInventoryOpen:  "sounds/inventory_open.wav",
+	sfxInventoryClose: "sounds/inventory_close.wav",
+	sfxPickup:         "sounds/pickup.wav",
+	sfxFootstep:       "sounds/footstep.wav",
+	sfxHit:            "sounds/hit.wav",
+	ambientRiver:      "sounds/ambient_river.wav",
+	ambientShop:       "sounds/ambient_shop.wav",
+}
+
+// Ambient 环境声源组件，由地图对象生成，离开地图时移除
+type Ambient struct {
+	Source *audio.Source
+}
+
+// Sound 游戏内统一的音频入口，加载或播放失败只记录日志，不影响游戏运行
+type Sound struct {
+	engine *audio.Engine
+}
+
+// NewSound 创建音频引擎并预加载所有音频资源
+func NewSound() *Sound {
+	s := &Sound{engine: audio.NewEngine(ebitenaudio.NewBackend())}
+	for name, path := range soundFiles {
+		if err := s.engine.LoadFile(name, path); err != nil {
+			log.Printf("加载音频失败 %s: %v", path, err)
+		}
+	}
+	return s
+}
+
+// Update 推进音乐淡入淡出，每帧调用一次
+func (s *Sound) Update() {
+	s.engine.Update(tickDuration())
+}
+
+// PlayMusic 交叉淡入切换背景音乐
+func (s *Sound) PlayMusic(name string) {
+	if err := s.engine.PlayMusic(name, musicFade); err != nil {
+		log.Printf("播放音乐失败 %s: %v", name, err)
+	}
+}
+
+// PlaySFX 播放一次音效
+func (s *Sound) PlaySFX(name string) {
+	if err := s.engine.PlaySFX(name); err != nil {
+		log.Printf("播放音效失败 %s: %v", name, err)
+	}
+}
+
+// SetListener 设置听者位置（通常为玩家中心）
+func (s *Sound) SetListener(x, y float64) {
+	s.engine.SetListener(x, y)
+}
+
+// AddAmbient 在世界坐标添加循环的环境声源（如河流、商店），radius 为可听距离
+func (s *Sound) AddAmbient(name string, x, y, radius float64) *audio.Source {
+	src := &audio.Source{
+		Clip:        name,
+		X:           x,
+		Y:           y,
+		Volume:      1,
+		MinDistance: radius / 4,
+		MaxDistance: radius,
+		Falloff:     audio.FalloffQuadratic,
+		Loop:        true,
+	}
+	s.engine.AddSource(src)
+	return src
+}
+
+// RemoveAmbient 移除环境声源
+func (s *Sound) RemoveAmbient(src *audio.Source) {
+	s.engine.RemoveSource(src)
+}
+
+// PlaySFXAt 在世界坐标播放一次性音效，音量和声像随与玩家的距离变化
+func (s *Sound) PlaySFXAt(name string, x, y float64) {
+	s.engine.PlayAt(name, x, y, defaultHearingRange)
+}
+
+// SetWorldPaused 离开游戏界面时暂停所有世界声源
+func (s *Sound) SetWorldPaused(paused bool) {
+	s.engine.SetSourcesPaused(paused)
+}
+
+// ApplySettings 应用设置中的音量与静音
+func (s *Sound) ApplySettings(settings *Settings) {
+	s.engine.SetMasterVolume(settings.MasterVolume)
+	s.engine.SetBusVolume(audio.BusMusic, settings.MusicVolume)
+	s.engine.SetBusVolume(audio.BusSFX, settings.SFXVolume)
+	s.engine.SetMuted(settings.Muted)
+}
+
+// spawnAmbient 在地图对象区域的中心放置环境声源，可听距离为属性 radius（省略时为 defaultHearingRange）
+func (p *PlayScreen) spawnAmbient(o *worldmap.Object) ecs.Entity {
+	x, y := o.X+o.Width/2, o.Y+o.Height/2
+	radius := defaultHearingRange
+	if r := o.Int("radius"); r > 0 {
+		radius = float64(r)
+	}
+	e := p.world.Spawn()
+	ecs.Add(p.world, e, ecs.Position{X: x, Y: y})
+	ecs.Add(p.world, e, Ambient{Source: p.sound.AddAmbient(o.String("sound"), x, y, radius)})
+	return e
+}
+
+// removeAmbients 移除当前地图上的所有环境声源
+func (p *PlayScreen) removeAmbients() {
+	ecs.Each(p.world, func(e ecs.Entity, a *Ambient) {
+		p.sound.RemoveAmbient(a.Source)
+	})
+}
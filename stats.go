@@ -0,0 +1,61 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/status"
+)
+
+// Stats 角色属性；作为组件挂在实体上时表示基础属性
+type Stats struct {
+	Attack  float64 `json:"attack"`  // 攻击
+	Defense float64 `json:"defense"` // 防御
+	Speed   float64 `json:"speed"`   // 移动速度（像素/秒）
+	Crit    float64 `json:"crit"`    // 暴击率 0~1
+}
+
+// Add 返回两组属性之和
+func (s Stats) Add(o Stats) Stats {
+	return Stats{
+		Attack:  s.Attack + o.Attack,
+		Defense: s.Defense + o.Defense,
+		Speed:   s.Speed + o.Speed,
+		Crit:    s.Crit + o.Crit,
+	}
+}
+
+// statNames 属性名，技能和 stat 类型的状态按名字引用属性
+var statNames = []string{"attack", "defense", "speed", "crit"}
+
+// withStatuses 返回加上 stat 类型状态的限时加成后的属性
+func (s Stats) withStatuses(set *status.Set) Stats {
+	return s.Add(Stats{
+		Attack:  set.StatBonus("attack"),
+		Defense: set.StatBonus("defense"),
+		Speed:   set.StatBonus("speed"),
+		Crit:    set.StatBonus("crit"),
+	})
+}
+
+// playerBaseStats 玩家的基础属性
+var playerBaseStats = Stats{Attack: 5, Defense: 0, Speed: playerSpeed, Crit: 0.05}
+
+// statSheet 计算实体的最终属性：基础属性 + 属性点和被动技能 + 装备 + 限时加成，移动速度再乘以减速和加速状态的倍率
+func statSheet(w *ecs.World, e ecs.Entity) Stats {
+	var stats Stats
+	if base := ecs.Get[Stats](w, e); base != nil {
+		stats = *base
+	}
+	if bonus := ecs.Get[StatBonus](w, e); bonus != nil {
+		stats = stats.Add(bonus.Stats)
+	}
+	if equipment := ecs.Get[Equipment](w, e); equipment != nil {
+		stats = stats.Add(equipment.Stats())
+	}
+	if s := ecs.Get[Statuses](w, e); s != nil {
+		stats = stats.withStatuses(&s.Set)
+		stats.Speed *= s.SpeedMultiplier()
+	}
+	stats.Speed = max(stats.Speed, 0)
+	stats.Crit = clamp01(stats.Crit)
+	return stats
+}
@@ -0,0 +1,305 @@
+// Package status 限时状态效果（中毒、燃烧、减速、加速、再生、眩晕、属性加成等）：叠加规则、按间隔结算和免疫
+// 以逻辑帧（tick）计时，不读取真实时间，同样的输入在回放和无界面测试中得到完全相同的结果
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+)
+
+// 施加状态失败的原因
+var (
+	ErrUnknown = errors.New("status: 状态不存在")
+	ErrImmune  = errors.New("status: 目标对该状态免疫")
+	ErrWeaker  = errors.New("status: 已有更强的同种状态")
+)
+
+// Kind 状态的作用方式
+type Kind string
+
+const (
+	Damage Kind = "damage" // 每隔 Interval 造成 Power 点伤害
+	Heal   Kind = "heal"   // 每隔 Interval 恢复 Power 点生命值
+	Speed  Kind = "speed"  // 移动速度乘以 1 + Power（减速为负数）
+	Stun   Kind = "stun"   // 不能移动、攻击和施放技能
+	Stat   Kind = "stat"   // 属性 Stat 加上 Power（药剂、技能的限时加成）
+)
+
+// Stacking 同种状态再次施加时的规则
+type Stacking string
+
+const (
+	Refresh   Stacking = "refresh"   // 重置持续时间，强度换成新的
+	Stack     Stacking = "stack"     // 层数加一（不超过 MaxStacks）并重置持续时间，效果按层数叠加
+	Strongest Stacking = "strongest" // 只保留更强的一个；强度相同时取更长的持续时间
+)
+
+// Def 一种状态的定义
+type Def struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Icon       string   `json:"icon"`  // 状态栏图标上的缩写
+	Color      [3]uint8 `json:"color"` // 图标颜色
+	Kind       Kind     `json:"kind"`
+	Stacking   Stacking `json:"stacking"`
+	MaxStacks  int      `json:"maxStacks"`  // Stack 规则的最大层数，省略时为 1
+	Interval   float64  `json:"interval"`   // Damage 和 Heal 每次结算的间隔（秒）
+	Power      float64  `json:"power"`      // 默认强度，施加时可以覆盖
+	DamageType string   `json:"damageType"` // Damage 的伤害类型，按目标的抗性减免
+	Stat       string   `json:"stat"`       // Stat 加成的属性名，由游戏校验
+
+	interval int // 结算间隔（帧）
+}
+
+// Catalog 所有状态定义：id -> 定义
+type Catalog map[string]*Def
+
+// Load 读取状态数据文件，tps 为每秒的逻辑帧数，用于把秒换算成帧
+func Load(path string, tps int) (Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data, tps)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse 解析状态数据并校验，返回第一个发现的问题
+func Parse(data []byte, tps int) (Catalog, error) {
+	var defs []*Def
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, err
+	}
+	c := Catalog{}
+	for _, d := range defs {
+		if d.MaxStacks == 0 {
+			d.MaxStacks = 1
+		}
+		if err := d.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := c[d.ID]; ok {
+			return nil, fmt.Errorf("状态 %s: id 重复", d.ID)
+		}
+		d.interval = Ticks(d.Interval, tps)
+		c[d.ID] = d
+	}
+	return c, nil
+}
+
+// Ticks 把秒换算成帧（四舍五入，至少为 1）
+func Ticks(seconds float64, tps int) int {
+	return max(int(math.Round(seconds*float64(tps))), 1)
+}
+
+// validate 检查状态定义
+func (d *Def) validate() error {
+	if d.ID == "" {
+		return fmt.Errorf("状态缺少 id")
+	}
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("状态 %s: "+format, append([]any{d.ID}, args...)...)
+	}
+	if d.Name == "" || d.Icon == "" {
+		return fail("缺少 name 或 icon")
+	}
+	switch d.Kind {
+	case Damage, Heal:
+		if d.Interval <= 0 {
+			return fail("interval 必须大于 0")
+		}
+		if d.Power <= 0 {
+			return fail("power 必须大于 0")
+		}
+	case Speed:
+		if d.Power == 0 || d.Power <= -1 {
+			return fail("power 必须大于 -1 且不为 0")
+		}
+	case Stun:
+	case Stat:
+		if d.Stat == "" {
+			return fail("缺少 stat")
+		}
+		if d.Power == 0 {
+			return fail("power 不能为 0")
+		}
+	default:
+		return fail("未知的类型 %q", d.Kind)
+	}
+	if d.DamageType != "" && d.Kind != Damage {
+		return fail("只有 damage 类型的状态可以有 damageType")
+	}
+	if d.Stat != "" && d.Kind != Stat {
+		return fail("只有 stat 类型的状态可以有 stat")
+	}
+	switch d.Stacking {
+	case Refresh, Strongest:
+		if d.MaxStacks != 1 {
+			return fail("只有 stack 规则可以有多层")
+		}
+	case Stack:
+		if d.MaxStacks < 1 {
+			return fail("maxStacks 必须大于 0")
+		}
+	default:
+		return fail("未知的叠加规则 %q", d.Stacking)
+	}
+	return nil
+}
+
+// Instance 实体身上的一个状态
+type Instance struct {
+	Def       *Def
+	Stacks    int     // 层数
+	Power     float64 // 每层的强度
+	Remaining int     // 剩余帧数
+	Duration  int     // 这次施加时的总帧数，用于绘制剩余时间的比例
+	next      int     // 距离下一次结算的帧数
+}
+
+// Strength 叠加层数后的强度
+func (i *Instance) Strength() float64 {
+	return i.Power * float64(i.Stacks)
+}
+
+// Pulse 一次按间隔结算的伤害或治疗
+type Pulse struct {
+	Def    *Def
+	Amount float64
+}
+
+// Set 一个实体身上的所有状态和免疫
+type Set struct {
+	Immune []string // 免疫的状态 id
+
+	list []*Instance
+}
+
+// check 返回施加状态时的错误，以及已有的同种状态
+func (s *Set) check(d *Def, power float64) (*Instance, error) {
+	if d == nil {
+		return nil, ErrUnknown
+	}
+	if slices.Contains(s.Immune, d.ID) {
+		return nil, ErrImmune
+	}
+	i := slices.IndexFunc(s.list, func(in *Instance) bool { return in.Def == d })
+	if i < 0 {
+		return nil, nil
+	}
+	old := s.list[i]
+	if d.Stacking == Strongest && math.Abs(power) < math.Abs(old.Power) {
+		return old, ErrWeaker
+	}
+	return old, nil
+}
+
+// CanApply 检查能否施加状态，不修改 Set
+func (s *Set) CanApply(d *Def, power float64) error {
+	_, err := s.check(d, power)
+	return err
+}
+
+// Apply 施加状态，持续 ticks 帧；power 为 0 时使用定义中的默认强度
+func (s *Set) Apply(d *Def, power float64, ticks int) error {
+	if d != nil && power == 0 {
+		power = d.Power
+	}
+	old, err := s.check(d, power)
+	if err != nil {
+		return err
+	}
+	ticks = max(ticks, 1)
+	if old == nil {
+		s.list = append(s.list, &Instance{Def: d, Stacks: 1, Power: power, Remaining: ticks, Duration: ticks, next: d.interval})
+		return nil
+	}
+	switch d.Stacking {
+	case Stack:
+		old.Stacks = min(old.Stacks+1, d.MaxStacks)
+		old.Power = power
+	case Strongest:
+		if math.Abs(power) == math.Abs(old.Power) {
+			ticks = max(ticks, old.Remaining)
+		}
+		old.Power = power
+	default: // Refresh
+		old.Power = power
+	}
+	old.Remaining, old.Duration = ticks, ticks
+	return nil
+}
+
+// Remove 移除状态，返回是否有这个状态
+func (s *Set) Remove(id string) bool {
+	n := len(s.list)
+	s.list = slices.DeleteFunc(s.list, func(in *Instance) bool { return in.Def.ID == id })
+	return len(s.list) < n
+}
+
+// Clear 移除所有状态
+func (s *Set) Clear() {
+	s.list = nil
+}
+
+// Has 是否有状态 id
+func (s *Set) Has(id string) bool {
+	return slices.ContainsFunc(s.list, func(in *Instance) bool { return in.Def.ID == id })
+}
+
+// Active 按施加顺序返回所有状态
+func (s *Set) Active() []*Instance {
+	return s.list
+}
+
+// Tick 推进一帧：按施加顺序返回这一帧到期的伤害和治疗，并移除结束的状态
+// 持续时间的最后一帧恰好到达间隔时仍会结算
+func (s *Set) Tick() []Pulse {
+	var pulses []Pulse
+	for _, in := range s.list {
+		if in.Def.Kind == Damage || in.Def.Kind == Heal {
+			in.next--
+			if in.next <= 0 {
+				pulses = append(pulses, Pulse{Def: in.Def, Amount: in.Strength()})
+				in.next = in.Def.interval
+			}
+		}
+		in.Remaining--
+	}
+	s.list = slices.DeleteFunc(s.list, func(in *Instance) bool { return in.Remaining <= 0 })
+	return pulses
+}
+
+// SpeedMultiplier 所有速度状态叠加后的移动速度倍率（不小于 0）
+func (s *Set) SpeedMultiplier() float64 {
+	m := 1.0
+	for _, in := range s.list {
+		if in.Def.Kind == Speed {
+			m += in.Strength()
+		}
+	}
+	return max(m, 0)
+}
+
+// StatBonus 所有 stat 类型的状态对属性 name 的加成之和
+func (s *Set) StatBonus(name string) float64 {
+	total := 0.0
+	for _, in := range s.list {
+		if in.Def.Kind == Stat && in.Def.Stat == name {
+			total += in.Strength()
+		}
+	}
+	return total
+}
+
+// Stunned 是否处于眩晕
+func (s *Set) Stunned() bool {
+	return slices.ContainsFunc(s.list, func(in *Instance) bool { return in.Def.Kind == Stun })
+}
@@ -0,0 +1,225 @@
+package status
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testTPS 测试中每秒 10 帧，间隔 1 秒即 10 帧
+const testTPS = 10
+
+const testDefs = `[
+	{"id": "poison", "name": "Poison", "icon": "P", "kind": "damage", "stacking": "stack", "maxStacks": 3, "interval": 1, "power": 2, "damageType": "poison"},
+	{"id": "burn", "name": "Burn", "icon": "B", "kind": "damage", "stacking": "refresh", "interval": 0.5, "power": 3},
+	{"id": "slow", "name": "Slow", "icon": "S", "kind": "speed", "stacking": "strongest", "power": -0.4},
+	{"id": "haste", "name": "Haste", "icon": "H", "kind": "speed", "stacking": "strongest", "power": 0.3},
+	{"id": "regen", "name": "Regen", "icon": "R", "kind": "heal", "stacking": "refresh", "interval": 1, "power": 4},
+	{"id": "stun", "name": "Stun", "icon": "X", "kind": "stun", "stacking": "strongest"},
+	{"id": "might", "name": "Might", "icon": "M", "kind": "stat", "stat": "attack", "stacking": "refresh", "power": 8},
+	{"id": "fury", "name": "Fury", "icon": "F", "kind": "stat", "stat": "attack", "stacking": "stack", "maxStacks": 2, "power": 3},
+	{"id": "swift", "name": "Swift", "icon": "W", "kind": "stat", "stat": "speed", "stacking": "strongest", "power": 48}
+]`
+
+func mustCatalog(t *testing.T) Catalog {
+	t.Helper()
+	c, err := Parse([]byte(testDefs), testTPS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// run 推进 n 帧，返回每次结算所在的帧（从 1 开始）和数值
+func run(s *Set, n int) map[int][]float64 {
+	pulses := map[int][]float64{}
+	for tick := 1; tick <= n; tick++ {
+		for _, p := range s.Tick() {
+			pulses[tick] = append(pulses[tick], p.Amount)
+		}
+	}
+	return pulses
+}
+
+func TestTickInterval(t *testing.T) {
+	c := mustCatalog(t)
+	var s Set
+	if err := s.Apply(c["poison"], 0, 30); err != nil {
+		t.Fatal(err)
+	}
+	got := run(&s, 40)
+	want := map[int][]float64{10: {2}, 20: {2}, 30: {2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("中毒结算 = %v，应为 %v", got, want)
+	}
+	if s.Has("poison") {
+		t.Fatal("持续时间结束后应移除状态")
+	}
+}
+
+func TestStacking(t *testing.T) {
+	c := mustCatalog(t)
+	var s Set
+
+	// stack：层数叠加但不超过上限，每次施加重置持续时间
+	for range 5 {
+		if err := s.Apply(c["poison"], 0, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if in := s.Active()[0]; in.Stacks != 3 || in.Strength() != 6 {
+		t.Fatalf("中毒层数 = %d，强度 = %v", in.Stacks, in.Strength())
+	}
+
+	// refresh：强度换成新的，持续时间重置
+	s.Apply(c["burn"], 5, 20)
+	run(&s, 5)
+	s.Apply(c["burn"], 1, 20)
+	if in := s.Active()[1]; in.Power != 1 || in.Remaining != 20 {
+		t.Fatalf("燃烧刷新后 = %+v", in)
+	}
+
+	// strongest：更弱的被拒绝，更强的替换，同样强时取更长的持续时间
+	s.Apply(c["slow"], -0.5, 10)
+	if err := s.Apply(c["slow"], -0.2, 50); !errors.Is(err, ErrWeaker) {
+		t.Fatalf("更弱的减速应返回 ErrWeaker，实际 %v", err)
+	}
+	s.Apply(c["slow"], -0.5, 5)
+	if in := s.Active()[2]; in.Remaining != 10 {
+		t.Fatalf("同样强的减速应保留更长的持续时间，实际 %d", in.Remaining)
+	}
+	s.Apply(c["slow"], -0.6, 4)
+	if in := s.Active()[2]; in.Power != -0.6 || in.Remaining != 4 {
+		t.Fatalf("更强的减速应替换，实际 %+v", in)
+	}
+}
+
+func TestImmunity(t *testing.T) {
+	c := mustCatalog(t)
+	s := Set{Immune: []string{"stun"}}
+	if err := s.CanApply(c["stun"], 0); !errors.Is(err, ErrImmune) {
+		t.Fatalf("CanApply 应返回 ErrImmune，实际 %v", err)
+	}
+	if err := s.Apply(c["stun"], 0, 10); !errors.Is(err, ErrImmune) || s.Stunned() {
+		t.Fatalf("免疫的状态不应生效: %v", err)
+	}
+	if err := s.Apply(c["nope"], 0, 10); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("不存在的状态应返回 ErrUnknown，实际 %v", err)
+	}
+}
+
+func TestModifiers(t *testing.T) {
+	c := mustCatalog(t)
+	var s Set
+	if s.SpeedMultiplier() != 1 || s.Stunned() {
+		t.Fatal("没有状态时速度倍率应为 1 且不眩晕")
+	}
+	s.Apply(c["slow"], 0, 10)
+	s.Apply(c["haste"], 0, 20)
+	if m := s.SpeedMultiplier(); m < 0.899 || m > 0.901 {
+		t.Fatalf("减速和加速叠加后倍率 = %v", m)
+	}
+	s.Apply(c["slow"], -3, 10)
+	if s.SpeedMultiplier() != 0 {
+		t.Fatalf("速度倍率不应小于 0，实际 %v", s.SpeedMultiplier())
+	}
+	s.Apply(c["stun"], 0, 3)
+	run(&s, 2)
+	if !s.Stunned() {
+		t.Fatal("眩晕还没结束")
+	}
+	run(&s, 1)
+	if s.Stunned() {
+		t.Fatal("眩晕应在 3 帧后结束")
+	}
+	if !s.Remove("haste") || s.Remove("haste") || s.Has("haste") {
+		t.Fatal("Remove 应只移除一次")
+	}
+	s.Clear()
+	if len(s.Active()) != 0 {
+		t.Fatal("Clear 后不应有状态")
+	}
+}
+
+// TestStatBonus 属性加成按属性名汇总，同样遵守叠加规则和免疫
+func TestStatBonus(t *testing.T) {
+	c := mustCatalog(t)
+	var s Set
+	if s.StatBonus("attack") != 0 {
+		t.Fatal("没有状态时不应有加成")
+	}
+	s.Apply(c["might"], 0, 10)
+	s.Apply(c["might"], 0, 10)
+	s.Apply(c["fury"], 0, 10)
+	s.Apply(c["fury"], 0, 10)
+	s.Apply(c["fury"], 0, 10)
+	if b := s.StatBonus("attack"); b != 8+3*2 {
+		t.Fatalf("攻击加成 = %v; want 14（刷新不叠加，层数最多 2 层）", b)
+	}
+	if s.StatBonus("defense") != 0 {
+		t.Fatal("其他属性不应有加成")
+	}
+	s.Apply(c["swift"], 0, 10)
+	if err := s.Apply(c["swift"], 20, 10); !errors.Is(err, ErrWeaker) || s.StatBonus("speed") != 48 {
+		t.Fatalf("更弱的加成不应覆盖更强的: %v, %v", err, s.StatBonus("speed"))
+	}
+	if s.SpeedMultiplier() != 1 {
+		t.Fatal("属性加成不应改变速度倍率")
+	}
+	run(&s, 10)
+	if s.StatBonus("attack") != 0 || s.StatBonus("speed") != 0 {
+		t.Fatal("到期后加成应移除")
+	}
+	s.Immune = []string{"might"}
+	if err := s.Apply(c["might"], 0, 10); !errors.Is(err, ErrImmune) {
+		t.Fatalf("免疫的加成应返回 ErrImmune，实际 %v", err)
+	}
+}
+
+// TestDeterministic 同样的施加顺序得到完全相同的结算序列
+func TestDeterministic(t *testing.T) {
+	c := mustCatalog(t)
+	play := func() map[int][]float64 {
+		var s Set
+		s.Apply(c["regen"], 0, 25)
+		s.Apply(c["burn"], 0, 12)
+		s.Apply(c["poison"], 0, 40)
+		s.Apply(c["poison"], 0, 40)
+		return run(&s, 50)
+	}
+	first := play()
+	if !reflect.DeepEqual(first, play()) {
+		t.Fatal("两次结算结果不同")
+	}
+	if !reflect.DeepEqual(first[10], []float64{4, 3, 4}) || !reflect.DeepEqual(first[5], []float64{3}) {
+		t.Fatalf("第 10 帧应按施加顺序结算再生、燃烧和两层中毒，第 5 帧结算燃烧: %v", first)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct{ data, want string }{
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "magic", "stacking": "refresh"}]`, `"magic"`},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "damage", "stacking": "refresh", "power": 1}]`, "interval"},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "speed", "stacking": "refresh", "power": -1}]`, "power"},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "stun", "stacking": "refresh", "maxStacks": 3}]`, "多层"},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "stun", "stacking": "pile"}]`, `"pile"`},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "stun", "stacking": "refresh", "damageType": "fire"}]`, "damageType"},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "stat", "stacking": "refresh", "power": 1}]`, "stat"},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "speed", "stat": "attack", "stacking": "refresh", "power": 1}]`, "stat"},
+		{`[{"id": "a", "icon": "A", "kind": "stun", "stacking": "refresh"}]`, "name"},
+		{`[{"id": "a", "name": "A", "icon": "A", "kind": "stun", "stacking": "refresh"}, {"id": "a", "name": "A", "icon": "A", "kind": "stun", "stacking": "refresh"}]`, "重复"},
+	} {
+		_, err := Parse([]byte(tc.data), testTPS)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: 错误 %v 应包含 %q", tc.data, err, tc.want)
+		}
+	}
+}
+
+// TestShippedStatuses 校验游戏自带的状态数据
+func TestShippedStatuses(t *testing.T) {
+	if _, err := Load("../data/statuses.json", 60); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"Game/combat"
+	"Game/ecs"
+	"Game/status"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log"
+	"slices"
+)
+
+// statusesPath 状态效果数据文件
+const statusesPath = "data/statuses.json"
+
+// 状态栏布局（经验条下方）
+const (
+	statusIconW   = 28
+	statusIconH   = 30
+	statusIconGap = 4
+	statusBarY    = vitalBarY + 3*(vitalBarH+vitalBarGap) + 14
+)
+
+// StatusDefs 所有状态定义：id -> 定义；在 init 之前加载，敌人和物品数据加载时可以引用
+var StatusDefs = loadStatusCatalog(statusesPath)
+
+// loadStatusCatalog 加载状态数据文件，数据有误或属性加成引用了未知的属性时直接退出；时间按逻辑帧数换算，保证结算结果可以复现
+func loadStatusCatalog(path string) status.Catalog {
+	catalog, err := status.Load(path, ebiten.TPS())
+	if err != nil {
+		log.Fatalf("加载状态数据失败: %v", err)
+	}
+	for _, d := range catalog {
+		if d.Kind == status.Stat && !slices.Contains(statNames, d.Stat) {
+			log.Fatalf("加载状态数据失败: 状态 %s: 未知的属性 %q", d.ID, d.Stat)
+		}
+	}
+	return catalog
+}
+
+// Statuses 实体身上的状态效果组件
+type Statuses struct {
+	status.Set
+}
+
+// StatusHit 攻击命中时按几率施加的状态
+type StatusHit struct {
+	Status  string  `json:"status"`
+	Chance  float64 `json:"chance"`  // 几率 0~1
+	Seconds float64 `json:"seconds"` // 持续时间（秒）
+	Power   float64 `json:"power"`   // 强度，省略时使用状态的默认强度
+}
+
+// validate 检查命中时施加的状态
+func (h StatusHit) validate() error {
+	if StatusDefs[h.Status] == nil {
+		return fmt.Errorf("状态 %q 不存在", h.Status)
+	}
+	if h.Chance <= 0 || h.Chance > 1 {
+		return fmt.Errorf("状态 %s 的 chance 必须在 0 到 1 之间", h.Status)
+	}
+	if h.Seconds <= 0 {
+		return fmt.Errorf("状态 %s 的 seconds 必须大于 0", h.Status)
+	}
+	return nil
+}
+
+// statusTicks 把秒换算成逻辑帧数
+func statusTicks(seconds float64) int {
+	return status.Ticks(seconds, ebiten.TPS())
+}
+
+// statusColor 状态图标的颜色
+func statusColor(d *status.Def) color.RGBA {
+	return color.RGBA{R: d.Color[0], G: d.Color[1], B: d.Color[2], A: 255}
+}
+
+// statusSet 返回实体的状态，没有状态组件时返回 nil
+func (p *PlayScreen) statusSet(e ecs.Entity) *status.Set {
+	if s := ecs.Get[Statuses](p.world, e); s != nil {
+		return &s.Set
+	}
+	return nil
+}
+
+// stunned 实体是否处于眩晕
+func (p *PlayScreen) stunned(e ecs.Entity) bool {
+	s := p.statusSet(e)
+	return s != nil && s.Stunned()
+}
+
+// applyStatus 对实体施加状态，成功时在实体头顶飘出状态名
+func (p *PlayScreen) applyStatus(e ecs.Entity, id string, power, seconds float64) error {
+	s := p.statusSet(e)
+	if s == nil {
+		return status.ErrUnknown
+	}
+	def := StatusDefs[id]
+	if err := s.Apply(def, power, statusTicks(seconds)); err != nil {
+		return err
+	}
+	x, y := p.entityBox(e).Center()
+	p.damageNumbers.Add(def.Name, x, y-16, statusColor(def), 1)
+	return nil
+}
+
+// applyStatusHits 攻击命中后按几率对目标施加状态；几率使用游戏的随机数，固定种子时可以复现
+func (p *PlayScreen) applyStatusHits(target ecs.Entity, hits []StatusHit) {
+	for _, h := range hits {
+		if p.rng.Float64() < h.Chance {
+			p.applyStatus(target, h.Status, h.Power, h.Seconds)
+		}
+	}
+}
+
+// updateStatuses 推进所有实体的状态一帧，结算持续伤害和治疗
+func (p *PlayScreen) updateStatuses() {
+	for _, e := range p.world.Query(ecs.MaskOf[Statuses](p.world)) {
+		for _, pulse := range ecs.Get[Statuses](p.world, e).Tick() {
+			p.statusPulse(e, pulse)
+			if !p.world.Alive(e) || (e == p.player && p.respawner().Dead) {
+				break
+			}
+		}
+	}
+}
+
+// statusPulse 结算一次持续伤害或治疗；持续伤害无视无敌时间，也不会击退
+func (p *PlayScreen) statusPulse(e ecs.Entity, pulse status.Pulse) {
+	health := ecs.Get[ecs.Health](p.world, e)
+	if health == nil {
+		return
+	}
+	x, y := p.entityBox(e).Center()
+	if pulse.Def.Kind == status.Heal {
+		amount := max(int(pulse.Amount), 1)
+		if e == p.player {
+			p.restoreVital(VitalHealth, amount)
+		} else if health.Current < health.Max {
+			health.Current = min(health.Current+amount, health.Max)
+			p.damageNumbers.Add(fmt.Sprintf("+%d", amount), x, y, vitalColors[VitalHealth], 1)
+		}
+		return
+	}
+
+	var resistances map[combat.DamageType]float64
+	if c := ecs.Get[Combatant](p.world, e); c != nil {
+		resistances = c.Resistances
+	}
+	hit := combat.Periodic(pulse.Amount, combat.DamageType(pulse.Def.DamageType), combat.Defender{Resistances: resistances})
+	if hit.Immune {
+		return
+	}
+	damage := min(hit.Damage, health.Current)
+	health.Current -= damage
+	if e == p.player {
+		p.vitalEvent(VitalEvent{Stat: VitalHealth, Amount: -damage})
+	}
+	p.damageNumbers.Add(fmt.Sprint(hit.Damage), x, y, statusColor(pulse.Def), 1)
+	if health.Current == 0 {
+		p.onKilled(e)
+	}
+}
+
+// statusIconRect 状态栏第 i 个图标
+func statusIconRect(i int) [4]int {
+	return [4]int{vitalBarX + i*(statusIconW+statusIconGap), statusBarY, statusIconW, statusIconH}
+}
+
+// drawStatusBar 在经验条下方绘制玩家的状态：缩写、层数和剩余秒数，底部的条表示剩余时间；鼠标悬停时显示名称
+func (p *PlayScreen) drawStatusBar(screen *ebiten.Image) {
+	cursorX, cursorY := ebiten.CursorPosition()
+	var hovered *status.Instance
+	for i, in := range p.statusSet(p.player).Active() {
+		r := statusIconRect(i)
+		x, y, w, h := float32(r[0]), float32(r[1]), float32(r[2]), float32(r[3])
+		clr := statusColor(in.Def)
+		vector.DrawFilledRect(screen, x, y, w, h, color.RGBA{R: clr.R / 4, G: clr.G / 4, B: clr.B / 4, A: 220}, false)
+		vector.DrawFilledRect(screen, x, y+h-3, w*float32(in.Remaining)/float32(in.Duration), 3, clr, false)
+		vector.StrokeRect(screen, x, y, w, h, 1, clr, false)
+		drawColoredText(screen, in.Def.Icon, r[0]+2, r[1], clr)
+		seconds := (in.Remaining + ebiten.TPS() - 1) / ebiten.TPS()
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%ds", seconds), r[0]+2, r[1]+12)
+		if in.Stacks > 1 {
+			ebitenutil.DebugPrintAt(screen, fmt.Sprintf("x%d", in.Stacks), r[0]+r[2]-12, r[1]+12)
+		}
+		if inRect(cursorX, cursorY, r) {
+			hovered = in
+		}
+	}
+	if hovered != nil {
+		lines := []tooltipLine{{text: hovered.Def.Name, clr: statusColor(hovered.Def)}}
+		if hovered.Stacks > 1 {
+			lines = append(lines, tooltipLine{text: fmt.Sprintf(p.settings.T("status.stacks"), hovered.Stacks)})
+		}
+		w, h := 0, 12+16*len(lines)
+		for _, line := range lines {
+			w = max(w, len(line.text)*6+22)
+		}
+		x, y := tooltipPosition(cursorX, cursorY, w, h, screen.Bounds().Dx(), screen.Bounds().Dy())
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), color.RGBA{R: 10, G: 10, B: 20, A: 235}, false)
+		vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, statusColor(hovered.Def), false)
+		drawTooltipLines(screen, lines, x+8, y+6)
+	}
+}
+
+// drawEnemyStatuses 在带有状态的敌人头顶绘制对应颜色的小方块
+func (p *PlayScreen) drawEnemyStatuses(screen *ebiten.Image) {
+	mask := ecs.MaskOf[Enemy](p.world) | ecs.MaskOf[Statuses](p.world) | ecs.MaskOf[ecs.Position](p.world)
+	for _, e := range p.world.Query(mask) {
+		box := p.entityBox(e)
+		for i, in := range ecs.Get[Statuses](p.world, e).Active() {
+			vector.DrawFilledRect(screen, float32(box.X)+float32(i*6), float32(box.Y)-7, 4, 4, statusColor(in.Def), false)
+		}
+	}
+}
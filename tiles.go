@@ -0,0 +1,75 @@
+package main
+
+import (
+	"Game/ecs"
+	"Game/nav"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+)
+
+// startMap 新游戏开始时所在的地图
+const startMap = "start"
+
+// 地图格子的标志位（gridData 中的值按位组合，0 表示空地）
+const (
+	tileSolid = 1 << iota // 不可通行（墙、岩石），会挡住移动、寻路和视线
+)
+
+// tileGrid 把 gridData 适配为寻路网格
+type tileGrid [][]int
+
+// Size 返回列数和行数
+func (g tileGrid) Size() (int, int) {
+	return len(g[0]), len(g)
+}
+
+// Blocked 格子是否不可通行，越界视为不可通行
+func (g tileGrid) Blocked(t nav.Point) bool {
+	if t.Y < 0 || t.Y >= len(g) || t.X < 0 || t.X >= len(g[t.Y]) {
+		return true
+	}
+	return g[t.Y][t.X]&tileSolid != 0
+}
+
+// tileBlocked 矩形是否压到了不可通行的格子（供移动系统使用）
+func (p *PlayScreen) tileBlocked(r ecs.Rect) bool {
+	grid := tileGrid(p.gridData)
+	size := float64(p.gridSize)
+	// 右边和下边减去一点，刚好贴着格子边缘时不算压到
+	for row := int(math.Floor(r.Y / size)); row <= int(math.Floor((r.Y+r.H-0.001)/size)); row++ {
+		for col := int(math.Floor(r.X / size)); col <= int(math.Floor((r.X+r.W-0.001)/size)); col++ {
+			if grid.Blocked(nav.Point{X: col, Y: row}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tileOf 返回世界坐标所在的格子
+func (p *PlayScreen) tileOf(x, y float64) nav.Point {
+	return nav.Point{X: int(math.Floor(x / float64(p.gridSize))), Y: int(math.Floor(y / float64(p.gridSize)))}
+}
+
+// tileCenter 返回格子中心的世界坐标
+func (p *PlayScreen) tileCenter(t nav.Point) (float64, float64) {
+	half := float64(p.gridSize) / 2
+	return float64(t.X*p.gridSize) + half, float64(t.Y*p.gridSize) + half
+}
+
+// drawTiles 绘制不可通行的格子
+func (p *PlayScreen) drawTiles(screen *ebiten.Image) {
+	size := float32(p.gridSize)
+	for row, cols := range p.gridData {
+		for col, tile := range cols {
+			if tile&tileSolid == 0 {
+				continue
+			}
+			x, y := float32(col)*size, float32(row)*size
+			vector.DrawFilledRect(screen, x, y, size, size, color.RGBA{R: 90, G: 80, B: 70, A: 255}, false)
+			vector.StrokeRect(screen, x+1, y+1, size-2, size-2, 2, color.RGBA{R: 60, G: 50, B: 45, A: 255}, false)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"strings"
+)
+
+// inRect 判断点 (x, y) 是否落在矩形 [x, y, width, height] 内
+func inRect(x, y int, rect [4]int) bool {
+	return x >= rect[0] && x <= rect[0]+rect[2] && y >= rect[1] && y <= rect[1]+rect[3]
+}
+
+// drawButton 绘制带悬停和点击效果的按钮，文字居中显示
+func drawButton(screen *ebiten.Image, rect [4]int, text string) {
+	btnX, btnY, btnW, btnH := rect[0], rect[1], rect[2], rect[3]
+
+	// 检查鼠标是否在按钮上
+	x, y := ebiten.CursorPosition()
+	mouseHover := inRect(x, y, rect)
+
+	// 根据鼠标状态设置按钮颜色和大小
+	btnColor := color.RGBA{R: 100, G: 200, B: 100, A: 255}
+	scale := 1.0
+	if mouseHover {
+		btnColor = color.RGBA{R: 150, G: 255, B: 150, A: 255} // 鼠标悬停时改变颜色
+		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			scale = 0.95 // 点击时缩小
+		} else {
+			scale = 1.05 // 悬停时放大
+		}
+	}
+
+	// 以按钮中心点为基准缩放
+	centerX := float64(btnX) + float64(btnW)/2
+	centerY := float64(btnY) + float64(btnH)/2
+	w := float64(btnW) * scale
+	h := float64(btnH) * scale
+	vector.DrawFilledRect(screen, float32(centerX-w/2), float32(centerY-h/2), float32(w), float32(h), btnColor, false)
+
+	// 按钮文字居中显示
+	const charWidth = 6   // 每个字符宽度为6像素
+	const charHeight = 16 // 字体高度为16像素
+	textX := int(centerX) - len(text)*charWidth/2
+	textY := int(centerY) - charHeight/2
+	ebitenutil.DebugPrintAt(screen, text, textX, textY)
+}
+
+// drawPanel 绘制半透明面板和双层边框（与背包界面风格一致）
+func drawPanel(screen *ebiten.Image, x, y, width, height int) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), color.RGBA{A: 200}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), 2, color.RGBA{R: 100, G: 100, B: 150, A: 255}, false)
+	vector.StrokeRect(screen, float32(x-1), float32(y-1), float32(width+2), float32(height+2), 1, color.RGBA{R: 200, G: 200, B: 255, A: 255}, false)
+}
+
+// drawCenteredText 在指定宽度内水平居中绘制文字
+func drawCenteredText(screen *ebiten.Image, text string, x, y, width int) {
+	ebitenutil.DebugPrintAt(screen, text, x+(width-len(text)*6)/2, y)
+}
+
+// wrapText 按单词把文字折成每行最多 maxChars 个字符，超长的单词会被截断到下一行
+func wrapText(text string, maxChars int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for len(word) > maxChars {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, word[:maxChars])
+				word = word[maxChars:]
+			}
+			switch {
+			case line == "":
+				line = word
+			case len(line)+1+len(word) <= maxChars:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawItemIcon 把物品图标缩放到 size×size 绘制在 (x, y)
+func drawItemIcon(screen *ebiten.Image, data *ItemData, x, y, size int) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(size)/float64(data.Image.Bounds().Dx()), float64(size)/float64(data.Image.Bounds().Dy()))
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(data.Image, op)
+}
+
+// textImages drawColoredText 用到的文字图片缓存
+var textImages = map[string]*ebiten.Image{}
+
+// drawColoredText 绘制指定颜色的文字；DebugPrint 只能画白色文字，先画到缓存的小图片上，绘制时再着色
+func drawColoredText(screen *ebiten.Image, text string, x, y int, clr color.Color) {
+	img := textImages[text]
+	if img == nil {
+		img = ebiten.NewImage(len(text)*6+2, 16)
+		ebitenutil.DebugPrint(img, text)
+		textImages[text] = img
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(clr)
+	screen.DrawImage(img, op)
+}
+
+// tooltipPosition 返回 w×h 提示框的位置：默认在光标右下方，超出屏幕时翻到光标的另一侧，并限制在屏幕内
+func tooltipPosition(cursorX, cursorY, w, h, screenW, screenH int) (int, int) {
+	x, y := cursorX+16, cursorY+16
+	if x+w > screenW {
+		x = cursorX - w - 8
+	}
+	if y+h > screenH {
+		y = cursorY - h - 8
+	}
+	return max(min(x, screenW-w), 0), max(min(y, screenH-h), 0)
+}
@@ -0,0 +1,103 @@
+// Package vitals 角色的生命值、法力值、体力等数值池：按规则自动恢复，受到伤害或消耗后暂停恢复一段时间
+package vitals
+
+import "time"
+
+// Regen 恢复规则：每秒恢复 PerSecond 点，数值减少后等待 Delay 才重新开始恢复
+type Regen struct {
+	PerSecond float64
+	Delay     time.Duration
+	wait      time.Duration // 剩余的等待时间
+	partial   float64       // 不足 1 点的恢复量，累积到下一次
+}
+
+// Interrupt 数值减少时调用，重新开始等待
+func (r *Regen) Interrupt() {
+	r.wait = r.Delay
+	r.partial = 0
+}
+
+// Waiting 是否还在等待恢复
+func (r *Regen) Waiting() bool {
+	return r.wait > 0
+}
+
+// Tick 推进 dt 时间，返回 current 恢复后的值（不超过 max）
+func (r *Regen) Tick(dt time.Duration, current, max int) int {
+	if r.wait > 0 {
+		if dt <= r.wait {
+			r.wait -= dt
+			return current
+		}
+		dt -= r.wait
+		r.wait = 0
+	}
+	if current >= max || r.PerSecond <= 0 {
+		r.partial = 0
+		return current
+	}
+	r.partial += r.PerSecond * dt.Seconds()
+	whole := int(r.partial)
+	r.partial -= float64(whole)
+	return min(current+whole, max)
+}
+
+// Pool 一种数值（法力、体力等）的当前值、上限和恢复规则
+type Pool struct {
+	Current, Max int
+	Regen        Regen
+}
+
+// NewPool 创建已满的数值池
+func NewPool(max int, regen Regen) Pool {
+	return Pool{Current: max, Max: max, Regen: regen}
+}
+
+// Spend 消耗 n 点，不够时不消耗并返回 false；消耗后暂停恢复
+func (p *Pool) Spend(n int) bool {
+	if n < 0 || p.Current < n {
+		return false
+	}
+	if n > 0 {
+		p.Current -= n
+		p.Regen.Interrupt()
+	}
+	return true
+}
+
+// Drain 最多减少 n 点（不够时减到 0），返回实际减少的量；减少后暂停恢复
+func (p *Pool) Drain(n int) int {
+	n = min(max(n, 0), p.Current)
+	if n > 0 {
+		p.Current -= n
+		p.Regen.Interrupt()
+	}
+	return n
+}
+
+// Restore 恢复 n 点（不超过上限），返回实际恢复的量
+func (p *Pool) Restore(n int) int {
+	n = min(max(n, 0), p.Max-p.Current)
+	p.Current += n
+	return n
+}
+
+// Fill 恢复到上限并清除恢复等待
+func (p *Pool) Fill() {
+	p.Current = p.Max
+	p.Regen.wait = 0
+	p.Regen.partial = 0
+}
+
+// Update 按恢复规则推进 dt 时间
+func (p *Pool) Update(dt time.Duration) {
+	p.Current = p.Regen.Tick(dt, p.Current, p.Max)
+}
+
+// Fraction 返回当前值占上限的比例（0~1）
+func (p *Pool) Fraction() float64 {
+	if p.Max <= 0 {
+		return 0
+	}
+	return float64(p.Current) / float64(p.Max)
+}
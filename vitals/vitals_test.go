@@ -0,0 +1,65 @@
+package vitals
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegenTick(t *testing.T) {
+	r := Regen{PerSecond: 2.5, Delay: time.Second}
+	current := 10
+	// 每秒 2.5 点：不足 1 点的部分累积到下一次
+	for range 4 {
+		current = r.Tick(200*time.Millisecond, current, 100)
+	}
+	if current != 12 {
+		t.Fatalf("0.8 秒后应恢复到 12，实际 %d", current)
+	}
+
+	r.Interrupt()
+	if !r.Waiting() {
+		t.Fatal("中断后应等待恢复")
+	}
+	if got := r.Tick(900*time.Millisecond, current, 100); got != current {
+		t.Fatalf("等待期间不应恢复，实际 %d", got)
+	}
+	// 等待剩下的 0.1 秒后，余下的 0.4 秒按每秒 2.5 点恢复 1 点
+	if got := r.Tick(500*time.Millisecond, current, 100); got != current+1 {
+		t.Fatalf("等待结束后应恢复 1 点，实际 %d", got)
+	}
+	if got := r.Tick(time.Minute, 99, 100); got != 100 {
+		t.Fatalf("恢复不应超过上限，实际 %d", got)
+	}
+}
+
+func TestPool(t *testing.T) {
+	p := NewPool(50, Regen{PerSecond: 10, Delay: 500 * time.Millisecond})
+	if !p.Spend(20) || p.Current != 30 {
+		t.Fatalf("消耗后应为 30，实际 %d", p.Current)
+	}
+	if p.Spend(31) || p.Current != 30 {
+		t.Fatalf("不够时不应消耗，实际 %d", p.Current)
+	}
+	if n := p.Drain(40); n != 30 || p.Current != 0 {
+		t.Fatalf("Drain 应减到 0 并返回 30，实际 %d, %d", n, p.Current)
+	}
+	p.Update(500 * time.Millisecond)
+	if p.Current != 0 {
+		t.Fatalf("等待期间不应恢复，实际 %d", p.Current)
+	}
+	p.Update(time.Second)
+	if p.Current != 10 {
+		t.Fatalf("1 秒后应恢复 10 点，实际 %d", p.Current)
+	}
+	if n := p.Restore(100); n != 40 || p.Current != 50 {
+		t.Fatalf("Restore 不应超过上限，实际 %d, %d", n, p.Current)
+	}
+	p.Drain(5)
+	p.Fill()
+	if p.Current != 50 || p.Regen.Waiting() {
+		t.Fatal("Fill 应恢复到上限并清除等待")
+	}
+	if got := p.Fraction(); got != 1 {
+		t.Fatalf("Fraction 应为 1，实际 %v", got)
+	}
+}
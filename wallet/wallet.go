@@ -0,0 +1,134 @@
+// Package wallet 货币钱包：金币等货币不占背包格子，余额用 int64 保存并在加减时检查溢出，
+// 每次变化都记录到交易日志中，方便排查金币来源和去向
+package wallet
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+)
+
+// 修改余额失败的原因
+var (
+	ErrAmount       = errors.New("wallet: 金额必须大于 0")
+	ErrInsufficient = errors.New("wallet: 余额不足")
+	ErrOverflow     = errors.New("wallet: 余额超出上限")
+)
+
+// Transaction 一次余额变化
+type Transaction struct {
+	Seq      int    // 序号，从 1 开始递增
+	Currency int64  // 货币（物品 id）
+	Amount   int64  // 变化量，花费为负数
+	Balance  int64  // 变化后的余额
+	Reason   string // 来源或用途，如 "pickup"、"shop"
+}
+
+// Wallet 每种货币的余额和最近的交易日志
+type Wallet struct {
+	balances map[int64]int64
+	log      []Transaction
+	logSize  int // 日志最多保留的条数
+	seq      int
+}
+
+// New 创建空钱包，交易日志最多保留 logSize 条
+func New(logSize int) *Wallet {
+	return &Wallet{balances: map[int64]int64{}, logSize: max(logSize, 1)}
+}
+
+// Balance 返回货币的余额
+func (w *Wallet) Balance(currency int64) int64 {
+	return w.balances[currency]
+}
+
+// Add 增加余额，结果超过 int64 上限时不做修改并返回 ErrOverflow
+func (w *Wallet) Add(currency, amount int64, reason string) error {
+	if amount <= 0 {
+		return ErrAmount
+	}
+	return w.Apply(map[int64]int64{currency: amount}, reason)
+}
+
+// Spend 减少余额，余额不足时不做修改并返回 ErrInsufficient
+func (w *Wallet) Spend(currency, amount int64, reason string) error {
+	if amount <= 0 {
+		return ErrAmount
+	}
+	return w.Apply(map[int64]int64{currency: -amount}, reason)
+}
+
+// Check 检查一组变化（货币 -> 变化量）能否全部完成，不修改余额
+func (w *Wallet) Check(changes map[int64]int64) error {
+	for currency, delta := range changes {
+		if _, err := add(w.balances[currency], delta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply 原子地完成一组变化：任何一项余额不足或溢出时都不做修改
+// 每项变化按货币 id 的顺序记入日志，变化量为 0 的项忽略
+func (w *Wallet) Apply(changes map[int64]int64, reason string) error {
+	if err := w.Check(changes); err != nil {
+		return err
+	}
+	for _, currency := range slices.Sorted(maps.Keys(changes)) {
+		delta := changes[currency]
+		if delta == 0 {
+			continue
+		}
+		balance, _ := add(w.balances[currency], delta)
+		if balance == 0 {
+			delete(w.balances, currency)
+		} else {
+			w.balances[currency] = balance
+		}
+		w.record(Transaction{Currency: currency, Amount: delta, Balance: balance, Reason: reason})
+	}
+	return nil
+}
+
+// record 记录一条交易，超过上限时丢弃最早的记录
+func (w *Wallet) record(t Transaction) {
+	w.seq++
+	t.Seq = w.seq
+	if len(w.log) >= w.logSize {
+		w.log = slices.Delete(w.log, 0, len(w.log)-w.logSize+1)
+	}
+	w.log = append(w.log, t)
+}
+
+// Log 返回最近的交易，从早到晚
+func (w *Wallet) Log() []Transaction {
+	return slices.Clone(w.log)
+}
+
+// Balances 返回所有余额不为 0 的货币（用于存档）
+func (w *Wallet) Balances() map[int64]int64 {
+	return maps.Clone(w.balances)
+}
+
+// Load 用存档中的余额替换当前余额并清空交易日志，负数余额视为 0
+func (w *Wallet) Load(balances map[int64]int64) {
+	w.balances = map[int64]int64{}
+	for currency, balance := range balances {
+		if balance > 0 {
+			w.balances[currency] = balance
+		}
+	}
+	w.log = nil
+}
+
+// add 计算余额加上变化量的结果，结果为负数时返回 ErrInsufficient，溢出时返回 ErrOverflow
+func add(balance, delta int64) (int64, error) {
+	if delta > 0 && balance > math.MaxInt64-delta {
+		return 0, ErrOverflow
+	}
+	if balance+delta < 0 {
+		return 0, ErrInsufficient
+	}
+	return balance + delta, nil
+}
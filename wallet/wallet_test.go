@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const gold, token = 1001, 1002
+
+func TestAddSpend(t *testing.T) {
+	w := New(10)
+	if err := w.Add(gold, 100, "pickup"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Spend(gold, 30, "shop"); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Balance(gold); got != 70 {
+		t.Fatalf("余额 = %d, 期望 70", got)
+	}
+	if err := w.Spend(gold, 71, "shop"); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("余额不足时 err = %v", err)
+	}
+	if err := w.Add(gold, 0, "x"); !errors.Is(err, ErrAmount) {
+		t.Fatalf("金额为 0 时 err = %v", err)
+	}
+	if err := w.Spend(gold, -5, "x"); !errors.Is(err, ErrAmount) {
+		t.Fatalf("金额为负数时 err = %v", err)
+	}
+	if got := w.Balance(gold); got != 70 {
+		t.Fatalf("失败的操作改变了余额: %d", got)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	w := New(10)
+	if err := w.Add(gold, math.MaxInt64-1, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Add(gold, 2, "test"); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("溢出时 err = %v", err)
+	}
+	if err := w.Add(gold, 1, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Balance(gold); got != math.MaxInt64 {
+		t.Fatalf("余额 = %d", got)
+	}
+	if err := w.Add(gold, math.MaxInt64, "test"); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("溢出时 err = %v", err)
+	}
+}
+
+func TestApplyAtomic(t *testing.T) {
+	w := New(10)
+	w.Add(gold, 50, "start")
+	w.Add(token, 5, "start")
+	// 金币够但代币不够：都不修改
+	if err := w.Apply(map[int64]int64{gold: -20, token: -6}, "buy"); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("err = %v", err)
+	}
+	if w.Balance(gold) != 50 || w.Balance(token) != 5 {
+		t.Fatalf("失败的交易改变了余额: %v", w.Balances())
+	}
+	if err := w.Apply(map[int64]int64{gold: -20, token: 3}, "trade"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Balance(gold) != 30 || w.Balance(token) != 8 {
+		t.Fatalf("余额 = %v", w.Balances())
+	}
+}
+
+func TestLog(t *testing.T) {
+	w := New(3)
+	w.Add(gold, 10, "a")
+	w.Add(gold, 20, "b")
+	w.Spend(gold, 5, "c")
+	w.Spend(gold, 100, "fail")
+	w.Add(token, 1, "d")
+	log := w.Log()
+	if len(log) != 3 {
+		t.Fatalf("日志条数 = %d, 期望 3", len(log))
+	}
+	want := []Transaction{
+		{Seq: 2, Currency: gold, Amount: 20, Balance: 30, Reason: "b"},
+		{Seq: 3, Currency: gold, Amount: -5, Balance: 25, Reason: "c"},
+		{Seq: 4, Currency: token, Amount: 1, Balance: 1, Reason: "d"},
+	}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Errorf("日志[%d] = %+v, 期望 %+v", i, log[i], want[i])
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	w := New(10)
+	w.Add(gold, 10, "a")
+	w.Add(token, 3, "a")
+	w.Spend(token, 3, "b")
+	saved := w.Balances()
+	if len(saved) != 1 || saved[gold] != 10 {
+		t.Fatalf("存档余额 = %v", saved)
+	}
+	other := New(10)
+	other.Load(map[int64]int64{gold: 10, token: -4})
+	if other.Balance(gold) != 10 || other.Balance(token) != 0 || len(other.Log()) != 0 {
+		t.Fatalf("读档后余额 = %v, 日志 %v", other.Balances(), other.Log())
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"Game/combat"
+	"Game/ecs"
+	"Game/wallet"
+	"github.com/hajimehoshi/ebiten/v2"
+	"time"
+)
+
+// 实体绘制层，数值大的绘制在上面
+const (
+	layerGround     = iota // 地面装饰
+	layerItems             // 地上的物品
+	layerCharacters        // 角色（玩家、NPC、敌人）
+)
+
+// playerSpeed 玩家移动速度（像素/秒）
+const playerSpeed = 96.0
+
+// playerSize 玩家绘制和碰撞尺寸（像素）
+const playerSize = 32
+
+// Story 剧情进度资源：对话设置的标记、击败的敌人和学会的配方，随存档保存
+type Story struct {
+	Flags   map[string]bool // 对话等设置的剧情标记
+	Kills   map[string]int  // 每种敌人被击败的次数，用于首杀掉落
+	Recipes map[string]bool // 已学会的合成配方
+}
+
+// newStory 创建新游戏的剧情进度
+func newStory() Story {
+	return Story{Flags: map[string]bool{}, Kills: map[string]int{}, Recipes: map[string]bool{}}
+}
+
+// story 返回剧情进度资源
+func (p *PlayScreen) story() *Story {
+	return ecs.Resource[Story](p.world)
+}
+
+// initWorld 创建实体世界和玩家实体
+func (p *PlayScreen) initWorld() {
+	p.world = ecs.NewWorld()
+	p.movement = &ecs.MovementSystem{
+		// 限制实体在屏幕范围内（使用逻辑屏幕尺寸，与窗口缩放无关）
+		Bounds:  ecs.Rect{W: screenWidth, H: screenHeight},
+		Blocked: p.tileBlocked,
+	}
+
+	p.player = p.world.Spawn()
+	ecs.Add(p.world, p.player, ecs.Position{})
+	ecs.Add(p.world, p.player, ecs.Velocity{})
+	ecs.Add(p.world, p.player, ecs.Sprite{
+		Image:  p.mainChar,
+		Width:  playerSize,
+		Height: playerSize,
+		Layer:  layerCharacters,
+	})
+	ecs.Add(p.world, p.player, ecs.Collider{W: playerSize, H: playerSize, Solid: true})
+	ecs.Add(p.world, p.player, ecs.Health{Current: playerConfig.Health.Max, Max: playerConfig.Health.Max})
+	ecs.Add(p.world, p.player, newVitals(playerConfig))
+	ecs.Add(p.world, p.player, Statuses{})
+	ecs.Add(p.world, p.player, playerBaseStats)
+	ecs.Add(p.world, p.player, Equipment{Slots: map[EquipSlot]*item{}})
+	ecs.Add(p.world, p.player, Combatant{
+		Invulnerability: combat.Invulnerability{Duration: playerInvulnerability},
+	})
+	ecs.Add(p.world, p.player, Bag{Cooldowns: map[int64]time.Duration{}})
+	ecs.Add(p.world, p.player, Purse{Wallet: wallet.New(walletLogSize)})
+	ecs.SetResource(p.world, newStory())
+}
+
+// updateWorld 运行实体系统
+func (p *PlayScreen) updateWorld() {
+	p.movement.Update(p.world, tickDuration())
+}
+
+// playerSpeed 返回玩家当前移动速度（基础速度加上装备和限时加成）
+func (p *PlayScreen) playerSpeed() float64 {
+	return statSheet(p.world, p.player).Speed
+}
+
+// playerPos 返回玩家位置（左上角）
+func (p *PlayScreen) playerPos() *ecs.Position {
+	return ecs.Get[ecs.Position](p.world, p.player)
+}
+
+// playerCenter 返回玩家中心点的世界坐标
+func (p *PlayScreen) playerCenter() (float64, float64) {
+	pos := p.playerPos()
+	return pos.X + playerSize/2, pos.Y + playerSize/2
+}
+
+// DrawEntities 按层和纵坐标顺序（ecs.RenderOrder）绘制所有实体的精灵
+func (p *PlayScreen) DrawEntities(screen *ebiten.Image) {
+	for _, e := range ecs.RenderOrder(p.world) {
+		pos, sp := ecs.Get[ecs.Position](p.world, e), ecs.Get[ecs.Sprite](p.world, e)
+		img, ok := sp.Image.(*ebiten.Image)
+		if !ok || img == nil {
+			continue
+		}
+		r := sp.DrawRect()
+		op := &ebiten.DrawImageOptions{}
+		if sp.Width > 0 && sp.Height > 0 {
+			op.GeoM.Scale(sp.Width/float64(r.Dx()), sp.Height/float64(r.Dy()))
+		}
+		op.GeoM.Translate(pos.X+sp.OffsetX, pos.Y+sp.OffsetY)
+		screen.DrawImage(img.SubImage(r).(*ebiten.Image), op)
+	}
+}
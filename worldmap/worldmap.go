@@ -0,0 +1,353 @@
+// Package worldmap 地图数据：读取 Tiled 导出的 JSON 地图（墙壁图块层和对象层），出生点、传送点和门，以及地图之间连接的校验
+// 对象只保留 Tiled 中的类型和自定义属性，敌人、宝箱、NPC 等具体含义由游戏解释；程序生成的楼层用 New 构造同样的地图
+package worldmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 地图之间连接用到的对象类型
+const (
+	TypeSpawn = "spawn" // 出生点：名字为出生点 id，传送点和门通过它指定到达的位置
+	TypeWarp  = "warp"  // 传送点：走进区域就切换到属性 map 的出生点 spawn
+	TypeDoor  = "door"  // 门：面对门按交互键切换地图，属性和传送点相同
+)
+
+// WallLayer 墙壁图块层的名字，其中不为 0 的图块不可通行
+const WallLayer = "walls"
+
+// Property Tiled 的自定义属性
+type Property struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// Object 对象层中的一个对象；位置和尺寸为像素，读取时换算成格子
+type Object struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`  // 对象类型（Tiled 1.9 起为 class）
+	Class      string     `json:"class"` // 读取时合并到 Type
+	X          float64    `json:"x"`
+	Y          float64    `json:"y"`
+	Width      float64    `json:"width"`
+	Height     float64    `json:"height"`
+	Properties []Property `json:"properties"`
+
+	Col, Row   int `json:"-"` // 左上角所在的格子
+	Cols, Rows int `json:"-"` // 占据的格子数，点对象为 1×1
+}
+
+// Layer 图块层或对象层
+type Layer struct {
+	Name    string    `json:"name"`
+	Type    string    `json:"type"` // tilelayer 或 objectgroup
+	Width   int       `json:"width"`
+	Height  int       `json:"height"`
+	Data    []int     `json:"data"`
+	Objects []*Object `json:"objects"`
+}
+
+// Map 一张地图
+type Map struct {
+	ID         string     `json:"-"` // 文件名（不含扩展名）
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	TileWidth  int        `json:"tilewidth"`
+	TileHeight int        `json:"tileheight"`
+	Properties []Property `json:"properties"`
+	Layers     []*Layer   `json:"layers"`
+
+	Objects []*Object `json:"-"` // 所有对象层中的对象，按出现顺序
+	solid   []bool
+}
+
+// Load 读取地图文件，地图 id 为文件名
+func Load(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	m, err := Parse(id, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// LoadDir 读取目录中所有的 .json 地图：地图 id -> 地图
+func LoadDir(dir string) (map[string]*Map, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	maps := make(map[string]*Map, len(paths))
+	for _, path := range paths {
+		m, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		maps[m.ID] = m
+	}
+	return maps, nil
+}
+
+// Parse 解析地图数据，收集对象并把位置换算成格子
+func Parse(id string, data []byte) (*Map, error) {
+	m := &Map{ID: id}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if err := m.init(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// New 用墙壁图块和对象创建地图（例如程序生成的地图），和读取的地图一样有墙壁层和对象层；
+// 对象的位置和尺寸为像素
+func New(id string, width, height, tileSize int, walls []int, objects []*Object, props []Property) (*Map, error) {
+	m := &Map{
+		ID: id, Width: width, Height: height, TileWidth: tileSize, TileHeight: tileSize, Properties: props,
+		Layers: []*Layer{
+			{Name: WallLayer, Type: "tilelayer", Width: width, Height: height, Data: walls},
+			{Name: "objects", Type: "objectgroup", Objects: objects},
+		},
+	}
+	if err := m.init(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// init 检查尺寸，合并墙壁图块层，收集对象并把位置换算成格子
+func (m *Map) init() error {
+	if m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 {
+		return fmt.Errorf("地图尺寸和图块尺寸必须大于 0")
+	}
+	m.solid = make([]bool, m.Width*m.Height)
+	for _, l := range m.Layers {
+		switch l.Type {
+		case "tilelayer":
+			if l.Name != WallLayer {
+				continue
+			}
+			if len(l.Data) != m.Width*m.Height {
+				return fmt.Errorf("图块层 %s 有 %d 个图块，应为 %d×%d", l.Name, len(l.Data), m.Width, m.Height)
+			}
+			for i, gid := range l.Data {
+				m.solid[i] = m.solid[i] || gid != 0
+			}
+		case "objectgroup":
+			for _, o := range l.Objects {
+				if o.Type == "" {
+					o.Type = o.Class
+				}
+				o.Col = int(math.Floor(o.X / float64(m.TileWidth)))
+				o.Row = int(math.Floor(o.Y / float64(m.TileHeight)))
+				o.Cols = max(int(math.Ceil(o.Width/float64(m.TileWidth))), 1)
+				o.Rows = max(int(math.Ceil(o.Height/float64(m.TileHeight))), 1)
+				m.Objects = append(m.Objects, o)
+			}
+		}
+	}
+	return nil
+}
+
+// Name 地图的显示名称（属性 name），没有时为地图 id
+func (m *Map) Name() string {
+	for _, p := range m.Properties {
+		if p.Name == "name" {
+			return propString(p.Value)
+		}
+	}
+	return m.ID
+}
+
+// InBounds 格子是否在地图内
+func (m *Map) InBounds(col, row int) bool {
+	return col >= 0 && col < m.Width && row >= 0 && row < m.Height
+}
+
+// Solid 格子是否不可通行，越界视为不可通行
+func (m *Map) Solid(col, row int) bool {
+	return !m.InBounds(col, row) || m.solid[row*m.Width+col]
+}
+
+// ObjectsOf 按出现顺序返回某种类型的对象
+func (m *Map) ObjectsOf(typ string) []*Object {
+	var objects []*Object
+	for _, o := range m.Objects {
+		if o.Type == typ {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// Spawn 返回名为 name 的出生点，不存在时返回 nil
+func (m *Map) Spawn(name string) *Object {
+	for _, o := range m.ObjectsOf(TypeSpawn) {
+		if o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// WarpAt 返回覆盖格子 (col, row) 的传送点，没有时返回 nil
+func (m *Map) WarpAt(col, row int) *Object {
+	for _, o := range m.ObjectsOf(TypeWarp) {
+		if o.Covers(col, row) {
+			return o
+		}
+	}
+	return nil
+}
+
+// Covers 对象是否覆盖格子 (col, row)
+func (o *Object) Covers(col, row int) bool {
+	return col >= o.Col && col < o.Col+o.Cols && row >= o.Row && row < o.Row+o.Rows
+}
+
+// String 返回属性的文字形式，没有该属性时返回空字符串
+func (o *Object) String(name string) string {
+	for _, p := range o.Properties {
+		if p.Name == name {
+			return propString(p.Value)
+		}
+	}
+	return ""
+}
+
+// Int 返回整数属性，没有该属性或不是整数时返回 0
+func (o *Object) Int(name string) int64 {
+	n, _ := strconv.ParseInt(o.String(name), 10, 64)
+	return n
+}
+
+// Bool 返回布尔属性
+func (o *Object) Bool(name string) bool {
+	return o.String(name) == "true"
+}
+
+// Target 传送点或门通往的地图和出生点
+func (o *Object) Target() (mapID, spawn string) {
+	return o.String("map"), o.String("spawn")
+}
+
+// propString 把属性值转换为文字，数字不带多余的小数
+func propString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// Validate 检查所有地图：对象在地图内、出生点不重复且可以站立、传送点和门指向存在的地图和出生点、
+// 到达的出生点不在传送点上（否则会来回传送），以及所有地图都能从起始地图到达；返回所有发现的问题
+func Validate(maps map[string]*Map, start string) []error {
+	var errs []error
+	if maps[start] == nil {
+		errs = append(errs, fmt.Errorf("起始地图 %q 不存在", start))
+	}
+	for _, id := range sortedIDs(maps) {
+		m := maps[id]
+		fail := func(o *Object, format string, args ...any) {
+			errs = append(errs, fmt.Errorf("地图 %s 对象 %d (%s %q): "+format, append([]any{id, o.ID, o.Type, o.Name}, args...)...))
+		}
+		spawns := map[string]bool{}
+		for _, o := range m.Objects {
+			if o.Type == "" {
+				fail(o, "缺少类型")
+			}
+			if !m.InBounds(o.Col, o.Row) || !m.InBounds(o.Col+o.Cols-1, o.Row+o.Rows-1) {
+				fail(o, "超出地图范围")
+				continue
+			}
+			switch o.Type {
+			case TypeSpawn:
+				if o.Name == "" {
+					fail(o, "出生点缺少名字")
+				}
+				if spawns[o.Name] {
+					fail(o, "出生点名字重复")
+				}
+				spawns[o.Name] = true
+				if m.Solid(o.Col, o.Row) {
+					fail(o, "出生点在墙上")
+				}
+				if m.WarpAt(o.Col, o.Row) != nil {
+					fail(o, "出生点在传送点上")
+				}
+			case TypeWarp, TypeDoor:
+				if o.Type == TypeDoor && (o.Cols != 1 || o.Rows != 1) {
+					fail(o, "门只能占一格")
+				}
+				target, spawn := o.Target()
+				switch {
+				case maps[target] == nil:
+					fail(o, "目标地图 %q 不存在", target)
+				case maps[target].Spawn(spawn) == nil:
+					fail(o, "目标地图 %s 中没有出生点 %q", target, spawn)
+				}
+			}
+		}
+	}
+	if maps[start] != nil {
+		reached := Reachable(maps, start)
+		for _, id := range sortedIDs(maps) {
+			if !reached[id] {
+				errs = append(errs, fmt.Errorf("地图 %s 无法从起始地图 %s 到达", id, start))
+			}
+		}
+	}
+	return errs
+}
+
+// Reachable 返回从地图 start 出发经过传送点和门能到达的所有地图（包括 start）
+func Reachable(maps map[string]*Map, start string) map[string]bool {
+	reached := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		m := maps[queue[0]]
+		queue = queue[1:]
+		for _, o := range m.Objects {
+			if o.Type != TypeWarp && o.Type != TypeDoor {
+				continue
+			}
+			if target, _ := o.Target(); maps[target] != nil && !reached[target] {
+				reached[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	return reached
+}
+
+// sortedIDs 按字母顺序返回所有地图 id，保证错误的顺序稳定
+func sortedIDs(maps map[string]*Map) []string {
+	ids := make([]string, 0, len(maps))
+	for id := range maps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
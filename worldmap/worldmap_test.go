@@ -0,0 +1,151 @@
+package worldmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testMap 生成 w×h、图块 32 像素的地图；walls 为不可通行的格子下标，objects 为对象层 JSON
+func testMap(w, h int, walls []int, objects string) []byte {
+	data := make([]string, w*h)
+	for i := range data {
+		data[i] = "0"
+	}
+	for _, i := range walls {
+		data[i] = "1"
+	}
+	return fmt.Appendf(nil, `{
+		"width": %d, "height": %d, "tilewidth": 32, "tileheight": 32,
+		"properties": [{"name": "name", "type": "string", "value": "Test"}],
+		"layers": [
+			{"type": "tilelayer", "name": "walls", "width": %d, "height": %d, "data": [%s]},
+			{"type": "objectgroup", "name": "objects", "objects": [%s]}
+		]
+	}`, w, h, w, h, strings.Join(data, ","), objects)
+}
+
+func mustParse(t *testing.T, id string, data []byte) *Map {
+	t.Helper()
+	m, err := Parse(id, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	m := mustParse(t, "a", testMap(4, 3, []int{5}, `
+		{"id": 1, "name": "home", "type": "spawn", "x": 32, "y": 64},
+		{"id": 2, "class": "warp", "x": 96, "y": 0, "width": 32, "height": 96,
+		 "properties": [{"name": "map", "type": "string", "value": "b"}, {"name": "count", "type": "int", "value": 3}, {"name": "locked", "type": "bool", "value": true}]}`))
+	if m.Name() != "Test" {
+		t.Fatalf("Name = %q", m.Name())
+	}
+	if !m.Solid(1, 1) || m.Solid(0, 0) || !m.Solid(-1, 0) || !m.Solid(4, 0) {
+		t.Fatal("墙壁或越界的格子判断错误")
+	}
+	spawn := m.Spawn("home")
+	if spawn == nil || spawn.Col != 1 || spawn.Row != 2 || spawn.Cols != 1 || spawn.Rows != 1 {
+		t.Fatalf("出生点 = %+v", spawn)
+	}
+	warp := m.WarpAt(3, 2)
+	if warp == nil || warp.Type != TypeWarp || warp.Rows != 3 || m.WarpAt(2, 2) != nil {
+		t.Fatalf("传送点 = %+v", warp)
+	}
+	if target, _ := warp.Target(); target != "b" || warp.Int("count") != 3 || !warp.Bool("locked") || warp.String("none") != "" {
+		t.Fatal("属性读取错误")
+	}
+	if len(m.ObjectsOf(TypeSpawn)) != 1 || m.Spawn("none") != nil {
+		t.Fatal("ObjectsOf 或 Spawn 结果错误")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse("a", []byte(`{"width": 2, "height": 2, "tilewidth": 32, "tileheight": 32,
+		"layers": [{"type": "tilelayer", "name": "walls", "data": [0, 0, 0]}]}`)); err == nil || !strings.Contains(err.Error(), "3") {
+		t.Fatalf("图块数量不对应报错，实际 %v", err)
+	}
+	if _, err := Parse("a", []byte(`{"width": 2, "height": 2}`)); err == nil {
+		t.Fatal("缺少图块尺寸应报错")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	maps := map[string]*Map{
+		"a": mustParse(t, "a", testMap(4, 3, []int{0}, `
+			{"id": 1, "name": "home", "type": "spawn", "x": 32, "y": 32},
+			{"id": 2, "name": "home", "type": "spawn", "x": 0, "y": 0},
+			{"id": 3, "type": "warp", "x": 96, "y": 0, "properties": [{"name": "map", "value": "b"}, {"name": "spawn", "value": "gate"}]},
+			{"id": 4, "type": "door", "x": 64, "y": 0, "width": 64, "properties": [{"name": "map", "value": "c"}, {"name": "spawn", "value": "x"}]},
+			{"id": 5, "type": "enemy", "x": 200, "y": 0},
+			{"id": 6, "x": 0, "y": 64}`)),
+		"b": mustParse(t, "b", testMap(4, 3, nil, `
+			{"id": 1, "name": "gate", "type": "spawn", "x": 0, "y": 0},
+			{"id": 2, "type": "warp", "x": 0, "y": 0, "properties": [{"name": "map", "value": "a"}, {"name": "spawn", "value": "nope"}]}`)),
+		"island": mustParse(t, "island", testMap(2, 2, nil, `{"id": 1, "name": "home", "type": "spawn", "x": 0, "y": 0}`)),
+	}
+	var got []string
+	for _, err := range Validate(maps, "a") {
+		got = append(got, err.Error())
+	}
+	all := strings.Join(got, "\n")
+	for _, want := range []string{
+		"出生点名字重复", "出生点在墙上", `目标地图 "c" 不存在`, "门只能占一格", "超出地图范围", "缺少类型",
+		"出生点在传送点上", `没有出生点 "nope"`, "地图 island 无法从起始地图 a 到达",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("缺少错误 %q，实际:\n%s", want, all)
+		}
+	}
+	if errs := Validate(maps, "none"); len(errs) == 0 || !strings.Contains(errs[0].Error(), "起始地图") {
+		t.Fatalf("起始地图不存在应报错，实际 %v", errs)
+	}
+	if reached := Reachable(maps, "a"); !reached["a"] || !reached["b"] || reached["island"] {
+		t.Fatalf("Reachable = %v", reached)
+	}
+}
+
+// TestShippedMaps 校验游戏自带的地图：能读取、连接正确且都能从起始地图到达；
+// 地下城楼层在启动时生成（由 dungeon 包测试），通往 data/dungeons.json 中楼层的传送点和门
+// 用只有入口和出口出生点的空地图代替目标
+func TestShippedMaps(t *testing.T) {
+	maps, err := LoadDir("../data/maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(maps) < 2 {
+		t.Fatalf("只找到 %d 张地图", len(maps))
+	}
+	data, err := os.ReadFile("../data/dungeons.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dungeons map[string]struct {
+		Floors []struct{ ID string }
+	}
+	if err := json.Unmarshal(data, &dungeons); err != nil {
+		t.Fatal(err)
+	}
+	floors := map[string]bool{}
+	for _, d := range dungeons {
+		for _, f := range d.Floors {
+			floors[f.ID] = true
+		}
+	}
+	for _, m := range maps {
+		for _, o := range m.Objects {
+			target, _ := o.Target()
+			if (o.Type == TypeWarp || o.Type == TypeDoor) && floors[target] && maps[target] == nil {
+				maps[target] = mustParse(t, target, testMap(3, 1, nil, `
+					{"id": 1, "name": "entrance", "type": "spawn", "x": 0, "y": 0, "width": 32, "height": 32},
+					{"id": 2, "name": "exit", "type": "spawn", "x": 64, "y": 0, "width": 32, "height": 32}`))
+			}
+		}
+	}
+	for _, err := range Validate(maps, "start") {
+		t.Error(err)
+	}
+}